@@ -0,0 +1,76 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/checkpoint"
+)
+
+func newCheckpointCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "create [flags] CONTAINER CHECKPOINT",
+		Short:             "Create a checkpoint from a running container",
+		Args:              cobra.ExactArgs(2),
+		RunE:              checkpointCreateAction,
+		ValidArgsFunction: checkpointShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().Bool("leave-running", false, "Leave the container running after checkpoint")
+	cmd.Flags().String("checkpoint-dir", "", "Use a custom checkpoint storage directory")
+	return cmd
+}
+
+func processCheckpointCreateOptions(cmd *cobra.Command) (types.CheckpointCreateOptions, error) {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return types.CheckpointCreateOptions{}, err
+	}
+	leaveRunning, err := cmd.Flags().GetBool("leave-running")
+	if err != nil {
+		return types.CheckpointCreateOptions{}, err
+	}
+	checkpointDir, err := cmd.Flags().GetString("checkpoint-dir")
+	if err != nil {
+		return types.CheckpointCreateOptions{}, err
+	}
+	return types.CheckpointCreateOptions{
+		Stdout:        cmd.OutOrStdout(),
+		GOptions:      globalOptions,
+		LeaveRunning:  leaveRunning,
+		CheckpointDir: checkpointDir,
+	}, nil
+}
+
+func checkpointCreateAction(cmd *cobra.Command, args []string) error {
+	options, err := processCheckpointCreateOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return checkpoint.Create(ctx, client, args[0], args[1], options)
+}
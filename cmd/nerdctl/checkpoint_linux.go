@@ -0,0 +1,45 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+)
+
+func newCheckpointCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Annotations:   map[string]string{Category: Management},
+		Use:           "checkpoint",
+		Short:         "Manage checkpoints",
+		RunE:          unknownSubcommandAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(
+		newCheckpointCreateCommand(),
+		newCheckpointLsCommand(),
+		newCheckpointRmCommand(),
+	)
+	return cmd
+}
+
+func checkpointShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		return shellCompleteContainerNames(cmd, nil)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
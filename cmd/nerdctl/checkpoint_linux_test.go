@@ -0,0 +1,75 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"os/exec"
+	"testing"
+
+	"github.com/containerd/nerdctl/v2/pkg/rootlessutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestCheckpointCreateRestore(t *testing.T) {
+	if _, err := exec.LookPath("criu"); err != nil {
+		t.Skip("test requires criu")
+	}
+	if rootlessutil.IsRootless() {
+		t.Skip("test skipped for rootless containers")
+	}
+	base := testutil.NewBase(t)
+	containerName := testutil.Identifier(t)
+	checkpointName := "checkpoint-1"
+
+	base.Cmd("run", "-d", "--name", containerName, testutil.AlpineImage,
+		"sh", "-c", "i=0; while true; do echo $i; i=$((i+1)); sleep 1; done").AssertOK()
+	defer base.Cmd("rm", "-f", containerName).Run()
+
+	base.Cmd("checkpoint", "create", containerName, checkpointName).AssertOutExactly(checkpointName + "\n")
+	base.Cmd("checkpoint", "create", containerName, checkpointName).AssertFail()
+	base.Cmd("checkpoint", "ls", containerName).AssertOutContains(checkpointName)
+	base.Cmd("ps", "-a", "--filter", "name="+containerName).AssertOutContains("Exited")
+
+	base.Cmd("start", "--checkpoint", checkpointName, containerName).AssertOK()
+	base.EnsureContainerStarted(containerName)
+	// The networks are set up again by the OCI hook on restore
+	base.Cmd("exec", containerName, "ip", "addr", "show", "eth0").AssertOutContains("inet ")
+
+	base.Cmd("checkpoint", "rm", containerName, checkpointName).AssertOK()
+	base.Cmd("checkpoint", "rm", containerName, checkpointName).AssertFail()
+	base.Cmd("checkpoint", "ls", containerName).AssertOutNotContains(checkpointName)
+}
+
+func TestCheckpointLeaveRunning(t *testing.T) {
+	if _, err := exec.LookPath("criu"); err != nil {
+		t.Skip("test requires criu")
+	}
+	if rootlessutil.IsRootless() {
+		t.Skip("test skipped for rootless containers")
+	}
+	base := testutil.NewBase(t)
+	containerName := testutil.Identifier(t)
+	checkpointDir := t.TempDir()
+
+	base.Cmd("run", "-d", "--name", containerName, testutil.AlpineImage, "sleep", "infinity").AssertOK()
+	defer base.Cmd("rm", "-f", containerName).Run()
+
+	base.Cmd("checkpoint", "create", "--leave-running", "--checkpoint-dir", checkpointDir, containerName, "checkpoint-1").AssertOK()
+	base.Cmd("exec", containerName, "true").AssertOK()
+	base.Cmd("checkpoint", "ls", containerName).AssertOutNotContains("checkpoint-1")
+	base.Cmd("checkpoint", "ls", "--checkpoint-dir", checkpointDir, containerName).AssertOutContains("checkpoint-1")
+}
@@ -0,0 +1,71 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/checkpoint"
+)
+
+func newCheckpointLsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "ls [flags] CONTAINER",
+		Aliases:           []string{"list"},
+		Short:             "List checkpoints for a container",
+		Args:              cobra.ExactArgs(1),
+		RunE:              checkpointLsAction,
+		ValidArgsFunction: checkpointShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().String("checkpoint-dir", "", "Use a custom checkpoint storage directory")
+	return cmd
+}
+
+func processCheckpointListOptions(cmd *cobra.Command) (types.CheckpointListOptions, error) {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return types.CheckpointListOptions{}, err
+	}
+	checkpointDir, err := cmd.Flags().GetString("checkpoint-dir")
+	if err != nil {
+		return types.CheckpointListOptions{}, err
+	}
+	return types.CheckpointListOptions{
+		Stdout:        cmd.OutOrStdout(),
+		GOptions:      globalOptions,
+		CheckpointDir: checkpointDir,
+	}, nil
+}
+
+func checkpointLsAction(cmd *cobra.Command, args []string) error {
+	options, err := processCheckpointListOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return checkpoint.List(ctx, client, args[0], options)
+}
@@ -24,6 +24,7 @@ import (
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
 )
@@ -161,3 +162,15 @@ func shellCompletePlatforms(cmd *cobra.Command, args []string, toComplete string
 	}
 	return candidates, cobra.ShellCompDirectiveNoFileComp
 }
+
+func shellCompleteContextNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	contexts, err := newContextStore().List()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	candidates := []string{contextstore.DefaultContextName}
+	for _, c := range contexts {
+		candidates = append(candidates, c.Name)
+	}
+	return candidates, cobra.ShellCompDirectiveNoFileComp
+}
@@ -63,6 +63,11 @@ func newComposeCommand() *cobra.Command {
 		newComposeUnpauseCommand(),
 		newComposeTopCommand(),
 		newComposeCreateCommand(),
+		newComposeWatchCommand(),
+		newComposeListCommand(),
+		newComposeEventsCommand(),
+		newComposeStatsCommand(),
+		newComposeWaitCommand(),
 	)
 
 	return composeCommand
@@ -155,3 +155,34 @@ image: hello-world
 
 	base.ComposeCmd("-f", comp.YAMLFullPath(), "--env-file", envFile, "config").AssertOutContains("image: hello-world")
 }
+
+func TestComposeConfigWithInclude(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	const dockerComposeYAML = `
+include:
+  - path: sub/docker-compose.yml
+    env_file: sub/custom.env
+services:
+  hello1:
+    image: alpine:3.13
+    depends_on:
+      - hello2
+`
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	assert.NilError(t, os.Mkdir(filepath.Join(comp.Dir(), "sub"), 0755))
+	comp.WriteFile("sub/docker-compose.yml", `
+services:
+  hello2:
+    image: ${image}
+    volumes:
+      - ./data:/data
+`)
+	comp.WriteFile("sub/custom.env", "image=alpine:3.14\n")
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "config", "--services").AssertOutContainsAll("hello1\n", "hello2\n")
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "config").AssertOutContainsAll("alpine:3.14", filepath.Join(comp.Dir(), "sub", "data"))
+}
@@ -0,0 +1,51 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestComposeList(t *testing.T) {
+	base := testutil.NewBase(t)
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").AssertOK()
+
+	base.ComposeCmd("ls").AssertOutContains(projectName)
+	base.ComposeCmd("ls", "--filter", "name="+projectName).AssertOutContains("running(1)")
+	base.ComposeCmd("ls", "--format", "json").AssertOutContains(comp.YAMLFullPath())
+	base.ComposeCmd("ls", "--quiet", "--filter", "name="+projectName).AssertOutExactly(projectName + "\n")
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "stop").AssertOK()
+	base.ComposeCmd("ls").AssertOutNotContains(projectName)
+	base.ComposeCmd("ls", "--all").AssertOutContains(projectName)
+}
@@ -35,6 +35,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/cmd/compose"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/portutil"
 )
@@ -64,7 +65,7 @@ type composeContainerPrintable struct {
 	Project  string
 	Service  string
 	State    string
-	Health   string // placeholder, lack containerd support.
+	Health   string
 	ExitCode uint32
 	// `Publishers` stores docker-compatible ports and used for json output.
 	// `Ports` stores formatted ports and only used for console output.
@@ -245,6 +246,9 @@ func composeContainerPrintableTab(ctx context.Context, container containerd.Cont
 	status := formatter.ContainerStatus(ctx, container)
 	if status == "Up" {
 		status = "running" // corresponds to Docker Compose v2.0.1
+		if health := composeContainerHealth(info.Labels); health != "" {
+			status += " (" + health + ")"
+		}
 	}
 	image, err := container.Image(ctx)
 	if err != nil {
@@ -302,12 +306,26 @@ func composeContainerPrintableJSON(ctx context.Context, container containerd.Con
 		Project:    info.Labels[labels.ComposeProject],
 		Service:    info.Labels[labels.ComposeService],
 		State:      state,
-		Health:     "",
+		Health:     composeContainerHealth(info.Labels),
 		ExitCode:   exitCode,
 		Publishers: formatPublishers(info.Labels),
 	}, nil
 }
 
+// composeContainerHealth returns the health status of the container,
+// or an empty string if the container has no healthcheck.
+func composeContainerHealth(containerLabels map[string]string) string {
+	health, err := healthcheck.Status(containerLabels)
+	if err != nil {
+		log.L.Warn(err)
+		return ""
+	}
+	if health == healthcheck.NoHealthcheck {
+		return ""
+	}
+	return health
+}
+
 // PortPublisher hold status about published port
 // Use this to match the json output with docker compose
 // FYI: https://github.com/docker/compose/blob/v2.13.0/pkg/api/api.go#L305C27-L311
@@ -26,8 +26,11 @@ import (
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/compose"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
@@ -84,7 +87,7 @@ func composeStartAction(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("service %q has no container to start", svcName)
 		}
 
-		if err := startContainers(ctx, client, containers); err != nil {
+		if err := startContainers(ctx, client, containers, globalOptions); err != nil {
 			return err
 		}
 	}
@@ -92,7 +95,7 @@ func composeStartAction(cmd *cobra.Command, args []string) error {
 	return nil
 }
 
-func startContainers(ctx context.Context, client *containerd.Client, containers []containerd.Container) error {
+func startContainers(ctx context.Context, client *containerd.Client, containers []containerd.Container, globalOptions types.GlobalCommandOptions) error {
 	eg, ctx := errgroup.WithContext(ctx)
 	for _, c := range containers {
 		c := c
@@ -113,6 +116,9 @@ func startContainers(ctx context.Context, client *containerd.Client, containers
 			if err := containerutil.Start(ctx, c, false, client, ""); err != nil {
 				return err
 			}
+			if err := container.StartHealthMonitor(ctx, c, globalOptions); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to start the healthcheck monitor of container %q", c.ID())
+			}
 			info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
 			if err != nil {
 				return err
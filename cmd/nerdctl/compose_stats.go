@@ -0,0 +1,111 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/compose"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+)
+
+func newComposeStatsCommand() *cobra.Command {
+	var composeStatsCommand = &cobra.Command{
+		Use:           "stats [flags] [SERVICE...]",
+		Short:         "Display a live stream of resource usage statistics of service containers",
+		RunE:          composeStatsAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	composeStatsCommand.Flags().BoolP("all", "a", false, "Show all containers (default shows just running)")
+	composeStatsCommand.Flags().String("format", "", "Pretty-print images using a Go template, e.g, '{{json .}}'")
+	composeStatsCommand.Flags().Bool("no-stream", false, "Disable streaming stats and only pull the first result")
+	composeStatsCommand.Flags().Bool("no-trunc", false, "Do not truncate output")
+	return composeStatsCommand
+}
+
+func composeStatsAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return err
+	}
+	noStream, err := cmd.Flags().GetBool("no-stream")
+	if err != nil {
+		return err
+	}
+	noTrunc, err := cmd.Flags().GetBool("no-trunc")
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	options, err := getComposeOptions(cmd, globalOptions.DebugFull, globalOptions.Experimental)
+	if err != nil {
+		return err
+	}
+	c, err := compose.New(client, globalOptions, options, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+	serviceNames, err := c.ServiceNames(args...)
+	if err != nil {
+		return err
+	}
+	containers, err := c.Containers(ctx, serviceNames...)
+	if err != nil {
+		return err
+	}
+	var ids []string
+	for _, c := range containers {
+		if !all {
+			cStatus, err := containerutil.ContainerStatus(ctx, c)
+			if err != nil || cStatus.Status != containerd.Running {
+				continue
+			}
+		}
+		ids = append(ids, c.ID())
+	}
+	if len(ids) == 0 {
+		// container.Stats shows all the containers in the namespace when no container is specified
+		return nil
+	}
+	return container.Stats(ctx, client, ids, types.ContainerStatsOptions{
+		Stdout:   cmd.OutOrStdout(),
+		Stderr:   cmd.ErrOrStderr(),
+		GOptions: globalOptions,
+		All:      all,
+		Format:   format,
+		NoStream: noStream,
+		NoTrunc:  noTrunc,
+	})
+}
@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/containerd/nerdctl/v2/pkg/clientutil"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/compose"
@@ -46,6 +47,12 @@ func newComposeUpCommand() *cobra.Command {
 	composeUpCommand.Flags().Bool("quiet-pull", false, "Pull without printing progress information")
 	composeUpCommand.Flags().Bool("remove-orphans", false, "Remove containers for services not defined in the Compose file.")
 	composeUpCommand.Flags().StringArray("scale", []string{}, "Scale SERVICE to NUM instances. Overrides the `scale` setting in the Compose file if present.")
+	composeUpCommand.Flags().Bool("force-recreate", false, "Recreate containers even if their configuration and image haven't changed.")
+	composeUpCommand.Flags().Bool("no-recreate", false, "If containers already exist, don't recreate them. Incompatible with --force-recreate.")
+	composeUpCommand.Flags().Bool("no-deps", false, "Don't start linked services.")
+	composeUpCommand.Flags().Bool("always-recreate-deps", false, "Recreate dependent containers. Incompatible with --no-recreate.")
+	composeUpCommand.Flags().Bool("wait", false, "Wait for services to be running|healthy. Implies detached mode.")
+	composeUpCommand.Flags().Int("wait-timeout", 0, "Maximum duration in seconds to wait for the project to be running|healthy")
 	return composeUpCommand
 }
 
@@ -100,6 +107,45 @@ func composeUpAction(cmd *cobra.Command, services []string) error {
 	if err != nil {
 		return err
 	}
+	forceRecreate, err := cmd.Flags().GetBool("force-recreate")
+	if err != nil {
+		return err
+	}
+	noRecreate, err := cmd.Flags().GetBool("no-recreate")
+	if err != nil {
+		return err
+	}
+	if forceRecreate && noRecreate {
+		return errors.New("--force-recreate and --no-recreate can not be combined")
+	}
+	noDeps, err := cmd.Flags().GetBool("no-deps")
+	if err != nil {
+		return err
+	}
+	alwaysRecreateDeps, err := cmd.Flags().GetBool("always-recreate-deps")
+	if err != nil {
+		return err
+	}
+	if alwaysRecreateDeps && noRecreate {
+		return errors.New("--always-recreate-deps and --no-recreate can not be combined")
+	}
+	wait, err := cmd.Flags().GetBool("wait")
+	if err != nil {
+		return err
+	}
+	if wait && abortOnContainerExit {
+		return errors.New("--wait and --abort-on-container-exit can not be combined")
+	}
+	waitTimeout, err := cmd.Flags().GetInt("wait-timeout")
+	if err != nil {
+		return err
+	}
+	if waitTimeout < 0 {
+		return fmt.Errorf("invalid --wait-timeout %d", waitTimeout)
+	}
+	if cmd.Flags().Changed("wait-timeout") && !wait {
+		return errors.New("--wait-timeout requires --wait")
+	}
 	scale := make(map[string]int)
 	for _, s := range scaleSlice {
 		parts := strings.Split(s, "=")
@@ -130,7 +176,7 @@ func composeUpAction(cmd *cobra.Command, services []string) error {
 
 	uo := composer.UpOptions{
 		AbortOnContainerExit: abortOnContainerExit,
-		Detach:               detach,
+		Detach:               detach || wait,
 		NoBuild:              noBuild,
 		NoColor:              noColor,
 		NoLogPrefix:          noLogPrefix,
@@ -139,6 +185,12 @@ func composeUpAction(cmd *cobra.Command, services []string) error {
 		QuietPull:            quietPull,
 		RemoveOrphans:        removeOrphans,
 		Scale:                scale,
+		ForceRecreate:        forceRecreate,
+		NoRecreate:           noRecreate,
+		NoDeps:               noDeps,
+		AlwaysRecreateDeps:   alwaysRecreateDeps,
+		Wait:                 wait,
+		WaitTimeout:          time.Duration(waitTimeout) * time.Second,
 	}
 	return c.Up(ctx, uo, services)
 }
@@ -628,3 +628,172 @@ services:
 	}
 	c.Assert(expected)
 }
+
+func TestComposeUpRecreate(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  db:
+    image: %s
+    command: "sleep infinity"
+  app:
+    image: %s
+    command: "sleep infinity"
+    depends_on:
+      - db
+`, testutil.AlpineImage, testutil.AlpineImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	dbContainer := serviceparser.DefaultContainerName(projectName, "db", "1")
+	appContainer := serviceparser.DefaultContainerName(projectName, "app", "1")
+	containerID := func(name string) string {
+		return strings.TrimSpace(base.Cmd("inspect", "--format={{.Id}}", name).Run().Stdout())
+	}
+
+	// --no-deps does not start the dependencies
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--no-deps", "app").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+	base.Cmd("inspect", dbContainer).AssertFail()
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	dbID, appID := containerID(dbContainer), containerID(appContainer)
+
+	// the containers whose config has not changed are not recreated
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	assert.Equal(t, containerID(dbContainer), dbID)
+	assert.Equal(t, containerID(appContainer), appID)
+
+	// --force-recreate only recreates the specified services
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--force-recreate", "app").AssertOK()
+	assert.Equal(t, containerID(dbContainer), dbID)
+	assert.Assert(t, containerID(appContainer) != appID)
+
+	// --always-recreate-deps recreates the dependencies
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--always-recreate-deps", "app").AssertOK()
+	assert.Assert(t, containerID(dbContainer) != dbID)
+	dbID, appID = containerID(dbContainer), containerID(appContainer)
+
+	// the diverged containers are recreated, unless --no-recreate is specified
+	comp.WriteFile("docker-compose.yaml", strings.Replace(dockerComposeYAML, "depends_on", "environment:\n      - FOO=bar\n    depends_on", 1))
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--no-recreate").AssertOK()
+	assert.Equal(t, containerID(appContainer), appID)
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	assert.Equal(t, containerID(dbContainer), dbID)
+	assert.Assert(t, containerID(appContainer) != appID)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--force-recreate", "--no-recreate").AssertFail()
+}
+
+func TestComposeUpDependsOnCondition(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  init:
+    image: %[1]s
+    command: "true"
+  db:
+    image: %[1]s
+    command: "sh -c 'sleep 3 && touch /tmp/ready && sleep infinity'"
+    healthcheck:
+      test: ["CMD", "test", "-f", "/tmp/ready"]
+      interval: 1s
+  broken:
+    image: %[1]s
+    command: "false"
+  app:
+    image: %[1]s
+    command: "sleep infinity"
+    depends_on:
+      init:
+        condition: service_completed_successfully
+      db:
+        condition: service_healthy
+      broken:
+        condition: service_completed_successfully
+        required: false
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "--wait", "--wait-timeout=60").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	appContainer := serviceparser.DefaultContainerName(projectName, "app", "1")
+	dbContainer := serviceparser.DefaultContainerName(projectName, "db", "1")
+	base.Cmd("inspect", "--format={{.State.Running}}", appContainer).AssertOutExactly("true\n")
+	base.Cmd("inspect", "--format={{.State.Health.Status}}", dbContainer).AssertOutExactly("healthy\n")
+	// `broken` is only an optional dependency, so `--wait` skips it even though it failed
+	brokenContainer := serviceparser.DefaultContainerName(projectName, "broken", "1")
+	base.Cmd("inspect", "--format={{.State.Status}} {{.State.ExitCode}}", brokenContainer).AssertOutExactly("exited 1\n")
+}
+
+func TestComposeUpDependsOnConditionNoDeps(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  db:
+    image: %[1]s
+    command: "sleep infinity"
+    healthcheck:
+      test: ["CMD", "true"]
+      interval: 1s
+  app:
+    image: %[1]s
+    command: "sleep infinity"
+    depends_on:
+      db:
+        condition: service_healthy
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	// The condition of the dependency that is not selected with --no-deps is not waited for
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d", "--no-deps", "app").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	appContainer := serviceparser.DefaultContainerName(projectName, "app", "1")
+	dbContainer := serviceparser.DefaultContainerName(projectName, "db", "1")
+	base.Cmd("inspect", "--format={{.State.Running}}", appContainer).AssertOutExactly("true\n")
+	base.Cmd("inspect", dbContainer).AssertFail()
+}
+
+func TestComposeUpDependsOnConditionFailure(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  init:
+    image: %[1]s
+    command: "false"
+  app:
+    image: %[1]s
+    command: "sleep infinity"
+    depends_on:
+      init:
+        condition: service_completed_successfully
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertFail()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	appContainer := serviceparser.DefaultContainerName(projectName, "app", "1")
+	base.Cmd("inspect", appContainer).AssertFail()
+}
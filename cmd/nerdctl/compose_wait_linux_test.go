@@ -0,0 +1,75 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestComposeWait(t *testing.T) {
+	base := testutil.NewBase(t)
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sh -c 'sleep 1; exit 3'"
+  svc1:
+    image: %s
+    command: "sleep infinity"
+`, testutil.CommonImage, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "up", "-d").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "wait", "svc0").
+		AssertOutExactly(serviceparser.DefaultContainerName(projectName, "svc0", "1") + " 3\n")
+
+	// svc1 never exits, so the project is removed when svc0 (already exited) is waited
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "wait", "--down-project", "svc0", "svc1").AssertOK()
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "ps", "-a").AssertOutNotContains("svc1")
+}
+
+func TestComposeWaitCreated(t *testing.T) {
+	base := testutil.NewBase(t)
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc0:
+    image: %s
+    command: "sleep infinity"
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "create").AssertOK()
+	defer base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+
+	// the container has never been started, so it is reported without waiting
+	base.ComposeCmd("-f", comp.YAMLFullPath(), "wait", "svc0").
+		AssertOutExactly(serviceparser.DefaultContainerName(projectName, "svc0", "1") + " 0\n")
+}
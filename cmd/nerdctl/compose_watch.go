@@ -0,0 +1,80 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/compose"
+	"github.com/containerd/nerdctl/v2/pkg/composer"
+)
+
+func newComposeWatchCommand() *cobra.Command {
+	var composeWatchCommand = &cobra.Command{
+		Use:           "watch [flags] [SERVICE...]",
+		Short:         "Watch build context for service and rebuild/refresh containers when files are updated",
+		RunE:          composeWatchAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	composeWatchCommand.Flags().Bool("no-up", false, "Do not build & start services before watching")
+	composeWatchCommand.Flags().Bool("quiet", false, "Pull without printing progress information")
+	return composeWatchCommand
+}
+
+func composeWatchAction(cmd *cobra.Command, services []string) error {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	noUp, err := cmd.Flags().GetBool("no-up")
+	if err != nil {
+		return err
+	}
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	options, err := getComposeOptions(cmd, globalOptions.DebugFull, globalOptions.Experimental)
+	if err != nil {
+		return err
+	}
+	options.Services = services
+	c, err := compose.New(client, globalOptions, options, cmd.OutOrStdout(), cmd.ErrOrStderr())
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	wo := composer.WatchOptions{
+		NoUp:  noUp,
+		Quiet: quiet,
+	}
+	return c.Watch(ctx, wo, services)
+}
@@ -0,0 +1,90 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestComposeWatchSync(t *testing.T) {
+	base := testutil.NewBase(t)
+
+	var dockerComposeYAML = fmt.Sprintf(`
+services:
+  svc:
+    image: %s
+    command: "sleep infinity"
+    develop:
+      watch:
+        - path: ./src
+          action: sync
+          target: /app
+          ignore:
+            - "*.tmp"
+`, testutil.CommonImage)
+
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+	assert.NilError(t, os.Mkdir(filepath.Join(comp.Dir(), "src"), 0o755))
+	projectName := comp.ProjectName()
+	t.Logf("projectName=%q", projectName)
+	svcContainer := serviceparser.DefaultContainerName(projectName, "svc", "1")
+
+	result := base.ComposeCmd("-f", comp.YAMLFullPath(), "watch").Start()
+	defer func() {
+		syscall.Kill(result.Cmd.Process.Pid, syscall.SIGTERM)
+		result.Cmd.Wait()
+		base.ComposeCmd("-f", comp.YAMLFullPath(), "down", "-v").Run()
+	}()
+
+	// wait for the container to be up and the watcher to start
+	waitFor := func(cond func() bool) bool {
+		for i := 0; i < 30; i++ {
+			if cond() {
+				return true
+			}
+			time.Sleep(time.Second)
+		}
+		return false
+	}
+	if !waitFor(func() bool {
+		return base.Cmd("exec", svcContainer, "true").Run().ExitCode == 0 &&
+			strings.Contains(result.Stderr(), "Watching")
+	}) {
+		t.Fatalf("compose watch did not start: %s", result.Stderr())
+	}
+
+	comp.WriteFile("src/hello.txt", "hello")
+	comp.WriteFile("src/ignored.tmp", "ignored")
+	if !waitFor(func() bool {
+		return base.Cmd("exec", svcContainer, "cat", "/app/hello.txt").Run().Stdout() == "hello"
+	}) {
+		t.Fatalf("the file was not synced: %s", result.Stderr())
+	}
+	base.Cmd("exec", svcContainer, "test", "-e", "/app/ignored.tmp").AssertFail()
+}
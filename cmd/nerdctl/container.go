@@ -52,6 +52,7 @@ func newContainerCommand() *cobra.Command {
 		newContainerPruneCommand(),
 		newStatsCommand(),
 		newAttachCommand(),
+		newExportCommand(),
 	)
 	addCpCommand(containerCommand)
 	return containerCommand
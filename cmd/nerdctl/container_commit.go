@@ -36,8 +36,16 @@ func newCommitCommand() *cobra.Command {
 	}
 	commitCommand.Flags().StringP("author", "a", "", `Author (e.g., "nerdctl contributor <nerdctl-dev@example.com>")`)
 	commitCommand.Flags().StringP("message", "m", "", "Commit message")
-	commitCommand.Flags().StringArrayP("change", "c", nil, "Apply Dockerfile instruction to the created image (supported directives: [CMD, ENTRYPOINT])")
+	commitCommand.Flags().StringArrayP("change", "c", nil, "Apply Dockerfile instruction to the created image (supported directives: [CMD, ENTRYPOINT, ENV, EXPOSE, LABEL, ONBUILD, STOPSIGNAL, USER, VOLUME, WORKDIR])")
 	commitCommand.Flags().BoolP("pause", "p", true, "Pause container during commit")
+	commitCommand.Flags().String("compression", "gzip", "Compression of the committed layer (gzip|zstd|uncompressed)")
+	commitCommand.RegisterFlagCompletionFunc("compression", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"gzip", "zstd", "uncompressed"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	commitCommand.Flags().String("format", "docker", "Format of the image manifest (docker|oci). zstd compression requires oci")
+	commitCommand.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"docker", "oci"}, cobra.ShellCompDirectiveNoFileComp
+	})
 	return commitCommand
 }
 
@@ -63,14 +71,24 @@ func processCommitCommandOptions(cmd *cobra.Command) (types.ContainerCommitOptio
 	if err != nil {
 		return types.ContainerCommitOptions{}, err
 	}
+	compression, err := cmd.Flags().GetString("compression")
+	if err != nil {
+		return types.ContainerCommitOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.ContainerCommitOptions{}, err
+	}
 
 	return types.ContainerCommitOptions{
-		Stdout:   cmd.OutOrStdout(),
-		GOptions: globalOptions,
-		Author:   author,
-		Message:  message,
-		Pause:    pause,
-		Change:   change,
+		Stdout:      cmd.OutOrStdout(),
+		GOptions:    globalOptions,
+		Author:      author,
+		Message:     message,
+		Pause:       pause,
+		Change:      change,
+		Compression: compression,
+		Format:      format,
 	}, nil
 
 }
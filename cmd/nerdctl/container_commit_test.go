@@ -53,3 +53,32 @@ func TestCommit(t *testing.T) {
 		base.Cmd("rmi", testImage).Run()
 	}
 }
+
+func TestCommitWithChanges(t *testing.T) {
+	t.Parallel()
+	base := testutil.NewBase(t)
+	testContainer := testutil.Identifier(t)
+	testImage := testutil.Identifier(t) + "-img"
+	defer base.Cmd("rm", "-f", testContainer).Run()
+	defer base.Cmd("rmi", testImage).Run()
+
+	base.Cmd("run", "-d", "--name", testContainer, testutil.CommonImage, "sleep", "infinity").AssertOK()
+	base.EnsureContainerStarted(testContainer)
+	base.Cmd("exec", testContainer, "mkdir", "/app").AssertOK()
+	base.Cmd(
+		"commit",
+		"--pause=false",
+		"--compression=zstd",
+		"--format=oci",
+		"-c", `ENV GREETING="hello commit"`,
+		"-c", "WORKDIR /app",
+		"-c", "LABEL com.example.commit=true",
+		"-c", "EXPOSE 8080/udp",
+		"-c", "USER nobody",
+		"-c", `CMD echo "$GREETING" from "$(pwd)" as "$(whoami)"`,
+		testContainer, testImage).AssertOK()
+
+	base.Cmd("run", "--rm", testImage).AssertOutExactly("hello commit from /app as nobody\n")
+	base.Cmd("image", "inspect", "--format", "{{.Config.Labels}} {{.Config.ExposedPorts}}", testImage).
+		AssertOutExactly("map[com.example.commit:true] map[8080/udp:{}]\n")
+}
@@ -0,0 +1,46 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+)
+
+// TestNewCreateOptions ensures that container.NewCreateOptions is in sync with the default values of the flags.
+func TestNewCreateOptions(t *testing.T) {
+	t.Setenv("NERDCTL_TOML", filepath.Join(t.TempDir(), "nerdctl.toml"))
+	app, err := newApp()
+	assert.NilError(t, err)
+	cmd, _, err := app.Find([]string{"create"})
+	assert.NilError(t, err)
+	assert.NilError(t, cmd.ParseFlags(nil))
+	opt, err := processContainerCreateOptions(cmd)
+	assert.NilError(t, err)
+
+	expected := container.NewCreateOptions(opt.GOptions)
+	// set by the CLI
+	expected.NerdctlCmd, expected.NerdctlArgs = opt.NerdctlCmd, opt.NerdctlArgs
+	opt.Stdout, opt.Stderr = nil, nil
+	opt.ImagePullOpt.Stdout, opt.ImagePullOpt.Stderr = nil, nil
+	assert.DeepEqual(t, opt, expected, cmpopts.EquateEmpty())
+}
@@ -0,0 +1,88 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/mattn/go-isatty"
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+)
+
+func newExportCommand() *cobra.Command {
+	var exportCommand = &cobra.Command{
+		Use:               "export [flags] CONTAINER",
+		Args:              cobra.ExactArgs(1),
+		Short:             "Export a container's filesystem as a tar archive (streamed to STDOUT by default)",
+		RunE:              exportAction,
+		ValidArgsFunction: exportShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	exportCommand.Flags().StringP("output", "o", "", "Write to a file, instead of STDOUT")
+	return exportCommand
+}
+
+func exportAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	options := types.ContainerExportOptions{
+		GOptions: globalOptions,
+	}
+
+	output := cmd.OutOrStdout()
+	outputPath, err := cmd.Flags().GetString("output")
+	if err != nil {
+		return err
+	} else if outputPath != "" {
+		f, err := os.OpenFile(outputPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0644)
+		if err != nil {
+			return err
+		}
+		output = f
+		defer f.Close()
+	} else if out, ok := output.(*os.File); ok && isatty.IsTerminal(out.Fd()) {
+		return fmt.Errorf("cowardly refusing to save to a terminal. Use the -o flag or redirect")
+	}
+	options.Stdout = output
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	if err = container.Export(ctx, client, args[0], options); err != nil && outputPath != "" {
+		os.Remove(outputPath)
+	}
+	return err
+}
+
+func exportShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		// show container names
+		return shellCompleteContainerNames(cmd, nil)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
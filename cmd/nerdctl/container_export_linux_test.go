@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestExportImport(t *testing.T) {
+	t.Parallel()
+	base := testutil.NewBase(t)
+	testContainer := testutil.Identifier(t)
+	testImage := testutil.Identifier(t) + "-img"
+	archive := filepath.Join(t.TempDir(), "rootfs.tar")
+	defer base.Cmd("rm", "-f", testContainer).Run()
+	defer base.Cmd("rmi", testImage).Run()
+
+	base.Cmd("run", "-d", "--name", testContainer, testutil.CommonImage,
+		"sh", "-euxc", "echo hello-test-export > /foo").AssertOK()
+	base.Cmd("wait", testContainer).AssertOK()
+	base.Cmd("export", "-o", archive, testContainer).AssertOK()
+
+	base.Cmd("import",
+		"-c", `CMD ["/foo"]`,
+		"-c", `ENTRYPOINT ["cat"]`,
+		"-m", "imported from "+testContainer,
+		archive, testImage).AssertOK()
+	base.Cmd("run", "--rm", testImage).AssertOutExactly("hello-test-export\n")
+	base.Cmd("image", "history", testImage).AssertOutContains("imported from " + testContainer)
+}
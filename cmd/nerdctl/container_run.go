@@ -254,6 +254,16 @@ func setCreateFlags(cmd *cobra.Command) {
 	cmd.Flags().StringArray("log-opt", nil, "Log driver options")
 	// #endregion
 
+	// #region healthcheck flags
+	cmd.Flags().String("health-cmd", "", "Command to run to check health")
+	cmd.Flags().Duration("health-interval", 0, "Time between running the check (ms|s|m|h) (default 0s)")
+	cmd.Flags().Duration("health-timeout", 0, "Maximum time to allow one check to run (ms|s|m|h) (default 0s)")
+	cmd.Flags().Int("health-retries", 0, "Consecutive failures needed to report unhealthy")
+	cmd.Flags().Duration("health-start-period", 0, "Start period for the container to initialize before starting health-retries countdown (ms|s|m|h) (default 0s)")
+	cmd.Flags().Duration("health-start-interval", 0, "Time between running the check during the start period (ms|s|m|h) (default 0s)")
+	cmd.Flags().Bool("no-healthcheck", false, "Disable any container-specified HEALTHCHECK")
+	// #endregion
+
 	// shared memory flags
 	cmd.Flags().String("shm-size", "", "Size of /dev/shm")
 	cmd.Flags().String("pidfile", "", "file path to write the task's pid")
@@ -412,6 +422,9 @@ func runAction(cmd *cobra.Command, args []string) error {
 	if err := task.Start(ctx); err != nil {
 		return err
 	}
+	if err := container.StartHealthMonitor(ctx, c, createOpt.GOptions); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to start the healthcheck monitor of container %q", id)
+	}
 
 	if createOpt.Detach {
 		fmt.Fprintln(createOpt.Stdout, id)
@@ -0,0 +1,77 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestRunHealthcheck(t *testing.T) {
+	t.Parallel()
+	base := testutil.NewBase(t)
+	healthyName := testutil.Identifier(t) + "-healthy"
+	unhealthyName := testutil.Identifier(t) + "-unhealthy"
+	defer base.Cmd("rm", "-f", healthyName, unhealthyName).Run()
+
+	base.Cmd("run", "-d", "--name", healthyName,
+		"--health-cmd", "true", "--health-interval", "1s", "--health-retries", "1",
+		testutil.CommonImage, "sleep", "infinity").AssertOK()
+	base.Cmd("run", "-d", "--name", unhealthyName,
+		"--health-cmd", `["CMD","false"]`, "--health-interval", "1s", "--health-retries", "1",
+		testutil.CommonImage, "sleep", "infinity").AssertOK()
+
+	waitHealth := func(name, expected string) {
+		for i := 0; i < 30; i++ {
+			inspect := base.InspectContainer(name)
+			if inspect.State.Health != nil && inspect.State.Health.Status == expected {
+				return
+			}
+			time.Sleep(time.Second)
+		}
+		t.Fatalf("container %q did not become %s", name, expected)
+	}
+	waitHealth(healthyName, healthcheck.Healthy)
+	waitHealth(unhealthyName, healthcheck.Unhealthy)
+
+	inspect := base.InspectContainer(healthyName)
+	assert.DeepEqual(t, inspect.Config.Healthcheck.Test, []string{healthcheck.TestCmdShell, "true"})
+
+	base.Cmd("ps", "--filter", "health=healthy", "--format", "{{.Names}}").AssertOutContains(healthyName)
+	base.Cmd("ps", "--filter", "health=healthy", "--format", "{{.Names}}").AssertOutNotContains(unhealthyName)
+	base.Cmd("ps", "--filter", "name="+unhealthyName, "--format", "{{.Status}}").AssertOutContains("(unhealthy)")
+}
+
+func TestRunNoHealthcheck(t *testing.T) {
+	t.Parallel()
+	base := testutil.NewBase(t)
+	testContainerName := testutil.Identifier(t)
+	defer base.Cmd("rm", "-f", testContainerName).Run()
+
+	base.Cmd("run", "-d", "--name", testContainerName, "--no-healthcheck", "--health-cmd", "true",
+		testutil.CommonImage, "sleep", "infinity").AssertFail()
+	base.Cmd("run", "-d", "--name", testContainerName, "--no-healthcheck",
+		testutil.CommonImage, "sleep", "infinity").AssertOK()
+	inspect := base.InspectContainer(testContainerName)
+	assert.Assert(t, inspect.State.Health == nil)
+	assert.Assert(t, inspect.Config.Healthcheck == nil)
+}
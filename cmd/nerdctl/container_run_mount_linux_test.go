@@ -83,6 +83,27 @@ func TestRunVolume(t *testing.T) {
 	).AssertOutExactly("str1str3")
 }
 
+func TestRunVolumeSubpathReplacedWithSymlink(t *testing.T) {
+	t.Parallel()
+	base := testutil.NewBase(t)
+	tID := testutil.Identifier(t)
+	volName := tID
+	defer base.Cmd("volume", "rm", "-f", volName).Run()
+	base.Cmd("volume", "create", volName).AssertOK()
+	base.Cmd("run", "--rm", "-v", volName+":/mnt", testutil.AlpineImage, "mkdir", "/mnt/sub").AssertOK()
+
+	containerName := tID
+	defer base.Cmd("rm", "-f", containerName).Run()
+	base.Cmd("create", "--name", containerName,
+		"--mount", fmt.Sprintf("type=volume,src=%s,dst=/mnt,volume-subpath=sub", volName),
+		testutil.AlpineImage, "ls", "/mnt/etc/shadow").AssertOK()
+
+	// Replace the subpath with a symlink to the root of the host
+	base.Cmd("run", "--rm", "-v", volName+":/mnt", testutil.AlpineImage,
+		"sh", "-euxc", "rmdir /mnt/sub && ln -s / /mnt/sub").AssertOK()
+	base.Cmd("start", "-a", containerName).AssertFail()
+}
+
 func TestRunAnonymousVolume(t *testing.T) {
 	t.Parallel()
 	base := testutil.NewBase(t)
@@ -39,6 +39,8 @@ func newStartCommand() *cobra.Command {
 	startCommand.Flags().SetInterspersed(false)
 	startCommand.Flags().BoolP("attach", "a", false, "Attach STDOUT/STDERR and forward signals")
 	startCommand.Flags().String("detach-keys", consoleutil.DefaultDetachKeys, "Override the default detach keys")
+	startCommand.Flags().String("checkpoint", "", "Restore from this checkpoint")
+	startCommand.Flags().String("checkpoint-dir", "", "Use a custom checkpoint storage directory")
 
 	return startCommand
 }
@@ -56,11 +58,21 @@ func processContainerStartOptions(cmd *cobra.Command) (types.ContainerStartOptio
 	if err != nil {
 		return types.ContainerStartOptions{}, err
 	}
+	checkpoint, err := cmd.Flags().GetString("checkpoint")
+	if err != nil {
+		return types.ContainerStartOptions{}, err
+	}
+	checkpointDir, err := cmd.Flags().GetString("checkpoint-dir")
+	if err != nil {
+		return types.ContainerStartOptions{}, err
+	}
 	return types.ContainerStartOptions{
-		Stdout:     cmd.OutOrStdout(),
-		GOptions:   globalOptions,
-		Attach:     attach,
-		DetachKeys: detachKeys,
+		Stdout:        cmd.OutOrStdout(),
+		GOptions:      globalOptions,
+		Attach:        attach,
+		DetachKeys:    detachKeys,
+		Checkpoint:    checkpoint,
+		CheckpointDir: checkpointDir,
 	}, nil
 }
 
@@ -0,0 +1,141 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+)
+
+func newContextCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Annotations:   map[string]string{Category: Management},
+		Use:           "context",
+		Short:         "Manage contexts",
+		Long:          "A context is a named set of nerdctl.toml properties, such as the containerd address and namespace",
+		RunE:          unknownSubcommandAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.AddCommand(
+		newContextCreateCommand(),
+		newContextLsCommand(),
+		newContextUseCommand(),
+		newContextInspectCommand(),
+		newContextRmCommand(),
+	)
+	return cmd
+}
+
+// newContextStore returns the store of the contexts, located next to nerdctl.toml.
+func newContextStore() contextstore.Store {
+	return contextstore.New(filepath.Join(filepath.Dir(nerdctlTOMLPath()), "contexts"))
+}
+
+// currentContextName returns the name of the context specified with `--context` or $NERDCTL_CONTEXT,
+// or the context set with `nerdctl context use`.
+func currentContextName(cmd *cobra.Command) (string, error) {
+	name, err := cmd.Flags().GetString("context")
+	if err != nil {
+		return "", err
+	}
+	if name != "" {
+		return name, nil
+	}
+	return newContextStore().Current()
+}
+
+// applyContext overrides the global options with the properties of the current context.
+//
+// The properties are applied in the following precedence:
+//  1. CLI flag
+//  2. Env var
+//  3. Context
+//  4. nerdctl.toml
+//  5. Built-in default value
+func applyContext(cmd *cobra.Command, globalOptions *types.GlobalCommandOptions) error {
+	name, err := currentContextName(cmd)
+	if err != nil {
+		return err
+	}
+	if name == contextstore.DefaultContextName {
+		return nil
+	}
+	c, err := newContextStore().Get(name)
+	if err != nil {
+		return fmt.Errorf("failed to load context %q (Hint: specify `--context=default` to ignore the current context): %w", name, err)
+	}
+	for _, p := range []struct {
+		flags []string
+		env   string
+		dst   *string
+		src   string
+	}{
+		{[]string{"address", "host", "a", "H"}, "CONTAINERD_ADDRESS", &globalOptions.Address, c.Address},
+		{[]string{"namespace", "n"}, "CONTAINERD_NAMESPACE", &globalOptions.Namespace, c.Namespace},
+		{[]string{"snapshotter", "storage-driver"}, "CONTAINERD_SNAPSHOTTER", &globalOptions.Snapshotter, c.Snapshotter},
+		{[]string{"data-root"}, "", &globalOptions.DataRoot, c.DataRoot},
+		{[]string{"cni-path"}, "CNI_PATH", &globalOptions.CNIPath, c.CNIPath},
+		{[]string{"cni-netconfpath"}, "NETCONFPATH", &globalOptions.CNINetConfPath, c.CNINetConfPath},
+	} {
+		if p.src != "" && !globalFlagChanged(cmd, p.flags...) && !envSet(p.env) {
+			*p.dst = p.src
+			if err := cmd.Flags().Set(p.flags[0], p.src); err != nil {
+				return err
+			}
+		}
+	}
+	if len(c.HostsDir) > 0 && !globalFlagChanged(cmd, "hosts-dir") {
+		globalOptions.HostsDir = c.HostsDir
+		if err := cmd.Flags().Set("hosts-dir", strings.Join(c.HostsDir, ",")); err != nil {
+			return err
+		}
+	}
+	// The properties of the context are now pinned as the global flags, so that the nerdctl processes
+	// executed with the flags from globalFlags (e.g., the OCI hook of the containers, and `nerdctl build` of compose)
+	// use the same properties even if the current context is changed or removed afterwards.
+	return cmd.Flags().Set("context", contextstore.DefaultContextName)
+}
+
+// globalFlagChanged returns true if any of the global flags (including the aliases) is specified.
+func globalFlagChanged(cmd *cobra.Command, names ...string) bool {
+	rootCmd := cmd.Root()
+	for _, name := range names {
+		for _, flags := range []*pflag.FlagSet{cmd.Flags(), rootCmd.Flags(), rootCmd.PersistentFlags()} {
+			if f := flags.Lookup(name); f != nil && f.Changed {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func envSet(env string) bool {
+	if env == "" {
+		return false
+	}
+	_, ok := os.LookupEnv(env)
+	return ok
+}
@@ -0,0 +1,90 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/context"
+)
+
+func newContextCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "create [flags] CONTEXT",
+		Short: "Create a context",
+		Long: `Create a context.
+
+The properties that are not specified are copied from the context specified with --from,
+or inherited from nerdctl.toml.`,
+		Args:          cobra.ExactArgs(1),
+		RunE:          contextCreateAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().String("description", "", "Description of the context")
+	cmd.Flags().String("from", "", "Create the context from an existing context")
+	cmd.RegisterFlagCompletionFunc("from", shellCompleteContextNames)
+	// These flags shadow the global flags of the same names
+	cmd.Flags().String("address", "", `containerd address, optionally with "unix://" prefix`)
+	cmd.Flags().String("namespace", "", "containerd namespace")
+	cmd.Flags().String("snapshotter", "", "containerd snapshotter")
+	cmd.Flags().String("data-root", "", "Root directory of persistent nerdctl state")
+	cmd.Flags().String("cni-path", "", "cni plugins binary directory")
+	cmd.Flags().String("cni-netconfpath", "", "cni config directory")
+	cmd.Flags().StringSlice("hosts-dir", nil, "A directory that contains <HOST:PORT>/hosts.toml (containerd style) or <HOST:PORT>/{ca.cert, cert.pem, key.pem} (docker style)")
+	return cmd
+}
+
+func processContextCreateOptions(cmd *cobra.Command) (types.ContextCreateOptions, error) {
+	options := types.ContextCreateOptions{
+		Stdout: cmd.OutOrStdout(),
+	}
+	var err error
+	for _, f := range []struct {
+		name string
+		dst  *string
+	}{
+		{"description", &options.Description},
+		{"from", &options.From},
+		{"address", &options.Address},
+		{"namespace", &options.Namespace},
+		{"snapshotter", &options.Snapshotter},
+		{"data-root", &options.DataRoot},
+		{"cni-path", &options.CNIPath},
+		{"cni-netconfpath", &options.CNINetConfPath},
+	} {
+		if *f.dst, err = cmd.Flags().GetString(f.name); err != nil {
+			return types.ContextCreateOptions{}, err
+		}
+	}
+	if options.HostsDir, err = cmd.Flags().GetStringSlice("hosts-dir"); err != nil {
+		return types.ContextCreateOptions{}, err
+	}
+	if options.DefaultConfig, err = loadConfig(nerdctlTOMLPath()); err != nil {
+		return types.ContextCreateOptions{}, err
+	}
+	return options, nil
+}
+
+func contextCreateAction(cmd *cobra.Command, args []string) error {
+	options, err := processContextCreateOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return context.Create(newContextStore(), args[0], options)
+}
@@ -0,0 +1,70 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/context"
+)
+
+func newContextInspectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "inspect [flags] [CONTEXT...]",
+		Short:             "Display detailed information on one or more contexts",
+		Long:              "Display detailed information on one or more contexts. The current context is inspected if no context is specified.",
+		RunE:              contextInspectAction,
+		ValidArgsFunction: shellCompleteContextNames,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().StringP("format", "f", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func processContextInspectOptions(cmd *cobra.Command) (types.ContextInspectOptions, error) {
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.ContextInspectOptions{}, err
+	}
+	current, err := currentContextName(cmd)
+	if err != nil {
+		return types.ContextInspectOptions{}, err
+	}
+	defaultConfig, err := loadConfig(nerdctlTOMLPath())
+	if err != nil {
+		return types.ContextInspectOptions{}, err
+	}
+	return types.ContextInspectOptions{
+		Stdout:        cmd.OutOrStdout(),
+		Current:       current,
+		DefaultConfig: defaultConfig,
+		Format:        format,
+	}, nil
+}
+
+func contextInspectAction(cmd *cobra.Command, args []string) error {
+	options, err := processContextInspectOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return context.Inspect(newContextStore(), args, options)
+}
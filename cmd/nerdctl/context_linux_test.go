@@ -0,0 +1,85 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestContext(t *testing.T) {
+	testutil.DockerIncompatible(t)
+	base := testutil.NewBase(t)
+	// isolate the contexts from the host
+	base.Env = append(base.Env, "NERDCTL_TOML="+filepath.Join(t.TempDir(), "nerdctl.toml"))
+
+	contextName := testutil.Identifier(t)
+	contextNamespace := testutil.Identifier(t) + "-ns"
+	volumeName := testutil.Identifier(t) + "-vol"
+	base.Cmd("context", "create", contextName, "--description=test", "--namespace="+contextNamespace).AssertOutExactly(contextName + "\n")
+	base.Cmd("context", "create", contextName).AssertFail()
+	base.Cmd("context", "ls", "-q").AssertOutExactly("default\n" + contextName + "\n")
+	base.Cmd("context", "inspect", contextName, "--format={{.Namespace}}").AssertOutExactly(contextNamespace + "\n")
+
+	// base.Args contains "--namespace", which takes precedence over the context
+	ctxBase := *base
+	ctxBase.Args = []string{"--context=" + contextName}
+	ctxBase.Cmd("volume", "create", volumeName).AssertOK()
+	defer ctxBase.Cmd("volume", "rm", "-f", volumeName).Run()
+	base.Cmd("volume", "ls", "-q").AssertOutNotContains(volumeName)
+	base.Cmd("--context="+contextName, "volume", "ls", "-q").AssertOutNotContains(volumeName)
+	ctxBase.Cmd("volume", "ls", "-q").AssertOutContains(volumeName)
+
+	base.Cmd("context", "use", contextName).AssertOutExactly(contextName + "\n")
+	base.Cmd("context", "ls").AssertOutContains(contextName + " *")
+	base.Cmd("context", "rm", contextName).AssertFail()
+	base.Cmd("context", "rm", "-f", contextName).AssertOutExactly(contextName + "\n")
+	base.Cmd("context", "ls", "-q").AssertOutExactly("default\n")
+	base.Cmd("context", "rm", "default").AssertFail()
+}
+
+func TestContextRunContainer(t *testing.T) {
+	testutil.DockerIncompatible(t)
+	base := testutil.NewBase(t)
+	// isolate the contexts from the host
+	base.Env = append(base.Env, "NERDCTL_TOML="+filepath.Join(t.TempDir(), "nerdctl.toml"))
+
+	contextName := testutil.Identifier(t)
+	contextNamespace := testutil.Identifier(t) + "-ns"
+	contextDataRoot := t.TempDir()
+	base.Cmd("context", "create", contextName, "--namespace="+contextNamespace, "--data-root="+contextDataRoot).AssertOK()
+	base.Cmd("context", "use", contextName).AssertOK()
+
+	// base.Args contains "--namespace", which takes precedence over the context.
+	// The context is chosen with the current context file, so the OCI hook has to get the properties
+	// of the context from the global flags.
+	ctxBase := *base
+	ctxBase.Args = nil
+	containerName := testutil.Identifier(t)
+	defer ctxBase.Cmd("rm", "-f", containerName).Run()
+	ctxBase.Cmd("run", "-d", "--name", containerName, testutil.CommonImage, "sleep", "infinity").AssertOK()
+	ip := strings.TrimSpace(ctxBase.Cmd("inspect", "--format={{.NetworkSettings.IPAddress}}", containerName).Out())
+	assert.Assert(t, ip != "")
+	// /etc/hosts is written by the OCI hook, to the data root of the context
+	ctxBase.Cmd("exec", containerName, "cat", "/etc/hosts").AssertOutContains(ip)
+	base.Cmd("ps", "-a", "--format={{.Names}}").AssertOutNotContains(containerName)
+}
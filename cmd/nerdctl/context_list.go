@@ -0,0 +1,76 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/context"
+)
+
+func newContextLsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "ls",
+		Aliases:       []string{"list"},
+		Short:         "List contexts",
+		Args:          cobra.NoArgs,
+		RunE:          contextLsAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().BoolP("quiet", "q", false, "Only show context names")
+	cmd.Flags().String("format", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	cmd.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "table", "wide"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return cmd
+}
+
+func processContextListOptions(cmd *cobra.Command) (types.ContextListOptions, error) {
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return types.ContextListOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.ContextListOptions{}, err
+	}
+	current, err := currentContextName(cmd)
+	if err != nil {
+		return types.ContextListOptions{}, err
+	}
+	defaultConfig, err := loadConfig(nerdctlTOMLPath())
+	if err != nil {
+		return types.ContextListOptions{}, err
+	}
+	return types.ContextListOptions{
+		Stdout:        cmd.OutOrStdout(),
+		Current:       current,
+		DefaultConfig: defaultConfig,
+		Quiet:         quiet,
+		Format:        format,
+	}, nil
+}
+
+func contextLsAction(cmd *cobra.Command, args []string) error {
+	options, err := processContextListOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return context.List(newContextStore(), options)
+}
@@ -0,0 +1,51 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/context"
+)
+
+func newContextRmCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "rm [flags] CONTEXT [CONTEXT...]",
+		Aliases:           []string{"remove"},
+		Short:             "Remove one or more contexts",
+		Args:              cobra.MinimumNArgs(1),
+		RunE:              contextRmAction,
+		ValidArgsFunction: shellCompleteContextNames,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	cmd.Flags().BoolP("force", "f", false, "Force the removal of a context in use")
+	return cmd
+}
+
+func contextRmAction(cmd *cobra.Command, args []string) error {
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+	options := types.ContextRemoveOptions{
+		Stdout: cmd.OutOrStdout(),
+		Force:  force,
+	}
+	return context.Remove(newContextStore(), args, options)
+}
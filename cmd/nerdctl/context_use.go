@@ -0,0 +1,46 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/context"
+)
+
+func newContextUseCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:               "use CONTEXT",
+		Short:             "Set the current context",
+		Long:              "Set the current context. The current context can be overridden with --context or $NERDCTL_CONTEXT.",
+		Args:              cobra.ExactArgs(1),
+		RunE:              contextUseAction,
+		ValidArgsFunction: shellCompleteContextNames,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	return cmd
+}
+
+func contextUseAction(cmd *cobra.Command, args []string) error {
+	options := types.ContextUseOptions{
+		Stdout: cmd.OutOrStdout(),
+		Stderr: cmd.ErrOrStderr(),
+	}
+	return context.Use(newContextStore(), args[0], options)
+}
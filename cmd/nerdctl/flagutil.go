@@ -119,7 +119,7 @@ func processRootCmdFlags(cmd *cobra.Command) (types.GlobalCommandOptions, error)
 	if err != nil {
 		return types.GlobalCommandOptions{}, err
 	}
-	return types.GlobalCommandOptions{
+	globalOptions := types.GlobalCommandOptions{
 		Debug:            debug,
 		DebugFull:        debugFull,
 		Address:          address,
@@ -133,5 +133,9 @@ func processRootCmdFlags(cmd *cobra.Command) (types.GlobalCommandOptions, error)
 		HostsDir:         hostsDir,
 		Experimental:     experimental,
 		HostGatewayIP:    hostGatewayIP,
-	}, nil
+	}
+	if err := applyContext(cmd, &globalOptions); err != nil {
+		return types.GlobalCommandOptions{}, err
+	}
+	return globalOptions, nil
 }
@@ -38,6 +38,7 @@ func newImageCommand() *cobra.Command {
 		newPushCommand(),
 		newLoadCommand(),
 		newSaveCommand(),
+		newImportCommand(),
 		newTagCommand(),
 		imageRmCommand(),
 		newImageConvertCommand(),
@@ -45,6 +46,9 @@ func newImageCommand() *cobra.Command {
 		newImageEncryptCommand(),
 		newImageDecryptCommand(),
 		newImagePruneCommand(),
+		newImageMirrorCommand(),
+		newImageAttachCommand(),
+		newImageReferrersCommand(),
 	)
 	return cmd
 }
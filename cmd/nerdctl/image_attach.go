@@ -0,0 +1,99 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func newImageAttachCommand() *cobra.Command {
+	shortHelp := "Attach a file to an image in a registry, as an OCI artifact that refers to the image"
+	longHelp := shortHelp + `
+
+The artifact is pushed to the repository of the image, with the image as its subject.
+When the registry does not support the referrers API, the "sha256-<hex>" tag of the referrers tag schema is updated.
+The digest of the artifact is printed.
+
+Example:
+  nerdctl image attach --artifact-type application/spdx+json registry.example.com/foo:latest sbom.spdx.json
+`
+	var imageAttachCommand = &cobra.Command{
+		Use:               "attach [flags] IMAGE FILE",
+		Short:             shortHelp,
+		Long:              longHelp,
+		Args:              IsExactArgs(2),
+		RunE:              imageAttachAction,
+		ValidArgsFunction: imageAttachShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	imageAttachCommand.Flags().String("artifact-type", "", "Artifact type of the artifact (required)")
+	imageAttachCommand.Flags().String("media-type", "application/octet-stream", "Media type of the file")
+	imageAttachCommand.Flags().StringArray("annotation", nil, "Add an annotation to the artifact manifest (KEY=VALUE)")
+	imageAttachCommand.Flags().BoolP("quiet", "q", false, "Only show the digest of the artifact")
+	return imageAttachCommand
+}
+
+func processImageAttachOptions(cmd *cobra.Command) (types.ImageAttachOptions, error) {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageAttachOptions{}, err
+	}
+	artifactType, err := cmd.Flags().GetString("artifact-type")
+	if err != nil {
+		return types.ImageAttachOptions{}, err
+	}
+	mediaType, err := cmd.Flags().GetString("media-type")
+	if err != nil {
+		return types.ImageAttachOptions{}, err
+	}
+	annotations, err := cmd.Flags().GetStringArray("annotation")
+	if err != nil {
+		return types.ImageAttachOptions{}, err
+	}
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return types.ImageAttachOptions{}, err
+	}
+	return types.ImageAttachOptions{
+		Stdout:       cmd.OutOrStdout(),
+		GOptions:     globalOptions,
+		ArtifactType: artifactType,
+		MediaType:    mediaType,
+		Annotations:  annotations,
+		Quiet:        quiet,
+	}, nil
+}
+
+func imageAttachAction(cmd *cobra.Command, args []string) error {
+	options, err := processImageAttachOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return image.Attach(cmd.Context(), args[0], args[1], options)
+}
+
+func imageAttachShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		// show image names
+		return shellCompleteImageNames(cmd)
+	}
+	return nil, cobra.ShellCompDirectiveDefault
+}
@@ -0,0 +1,89 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func newImportCommand() *cobra.Command {
+	var importCommand = &cobra.Command{
+		Use:           "import [flags] file|URL|- [REPOSITORY[:TAG]]",
+		Args:          cobra.RangeArgs(1, 2),
+		Short:         "Import the contents from a tarball to create a filesystem image",
+		RunE:          importAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	importCommand.Flags().StringArrayP("change", "c", nil, "Apply Dockerfile instruction to the created image (supported directives: [CMD, ENTRYPOINT, ENV, EXPOSE, LABEL, ONBUILD, STOPSIGNAL, USER, VOLUME, WORKDIR])")
+	importCommand.Flags().StringP("message", "m", "", "Set commit message for imported image")
+	importCommand.Flags().String("platform", "", "Set platform if server is multi-platform capable")
+	importCommand.RegisterFlagCompletionFunc("platform", shellCompletePlatforms)
+	return importCommand
+}
+
+func processImageImportOptions(cmd *cobra.Command, args []string) (types.ImageImportOptions, error) {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageImportOptions{}, err
+	}
+	change, err := cmd.Flags().GetStringArray("change")
+	if err != nil {
+		return types.ImageImportOptions{}, err
+	}
+	message, err := cmd.Flags().GetString("message")
+	if err != nil {
+		return types.ImageImportOptions{}, err
+	}
+	platform, err := cmd.Flags().GetString("platform")
+	if err != nil {
+		return types.ImageImportOptions{}, err
+	}
+	reference := ""
+	if len(args) > 1 {
+		reference = args[1]
+	}
+	return types.ImageImportOptions{
+		Stdout:    cmd.OutOrStdout(),
+		Stdin:     cmd.InOrStdin(),
+		GOptions:  globalOptions,
+		Source:    args[0],
+		Reference: reference,
+		Message:   message,
+		Change:    change,
+		Platform:  platform,
+	}, nil
+}
+
+func importAction(cmd *cobra.Command, args []string) error {
+	options, err := processImageImportOptions(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return image.Import(ctx, client, options)
+}
@@ -39,6 +39,7 @@ Properties:
 - PLATFORM:   Platform
 - SIZE:       Size of the unpacked snapshots
 - BLOB SIZE:  Size of the blobs (such as layer tarballs) in the content store
+- AVAILABLE:  Whether all the blobs of the platform are in the content store (--tree only)
 `
 	var imagesCommand = &cobra.Command{
 		Use:                   "images [flags] [REPOSITORY[:TAG]]",
@@ -62,7 +63,8 @@ Properties:
 	})
 	imagesCommand.Flags().Bool("digests", false, "Show digests (compatible with Docker, unlike ID)")
 	imagesCommand.Flags().Bool("names", false, "Show image names")
-	imagesCommand.Flags().BoolP("all", "a", true, "(unimplemented yet, always true)")
+	imagesCommand.Flags().BoolP("all", "a", false, "Show all images (by default, the untagged images that share the digest with a tagged image are hidden)")
+	imagesCommand.Flags().Bool("tree", false, "List the platforms and attestations of each image as a tree")
 
 	return imagesCommand
 }
@@ -109,6 +111,14 @@ func processImageListOptions(cmd *cobra.Command, args []string) (types.ImageList
 	if err != nil {
 		return types.ImageListOptions{}, err
 	}
+	all, err := cmd.Flags().GetBool("all")
+	if err != nil {
+		return types.ImageListOptions{}, err
+	}
+	tree, err := cmd.Flags().GetBool("tree")
+	if err != nil {
+		return types.ImageListOptions{}, err
+	}
 	return types.ImageListOptions{
 		GOptions:         globalOptions,
 		Quiet:            quiet,
@@ -118,7 +128,8 @@ func processImageListOptions(cmd *cobra.Command, args []string) (types.ImageList
 		NameAndRefFilter: filters,
 		Digests:          digests,
 		Names:            names,
-		All:              true,
+		All:              all,
+		Tree:             tree,
 		Stdout:           cmd.OutOrStdout(),
 	}, nil
 
@@ -129,10 +140,6 @@ func imagesAction(cmd *cobra.Command, args []string) error {
 	if err != nil {
 		return err
 	}
-	if !options.All {
-		options.All = true
-	}
-
 	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
 	if err != nil {
 		return err
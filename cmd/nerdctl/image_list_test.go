@@ -17,6 +17,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 	"slices"
 	"strings"
@@ -24,6 +25,7 @@ import (
 
 	"github.com/containerd/nerdctl/v2/pkg/tabutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/platforms"
 	"gotest.tools/v3/assert"
 )
 
@@ -76,6 +78,49 @@ func TestImages(t *testing.T) {
 	})
 }
 
+func TestImagesTree(t *testing.T) {
+	t.Parallel()
+	testutil.DockerIncompatible(t)
+	base := testutil.NewBase(t)
+	platform := platforms.FormatAll(platforms.Normalize(platforms.DefaultSpec()))
+
+	type treeImage struct {
+		Names     []string
+		Manifests []struct {
+			Platform  string
+			Kind      string
+			Available bool
+		}
+	}
+	readTree := func(args ...string) treeImage {
+		var img treeImage
+		out := base.Cmd(append([]string{"images", "--tree", "--format", "json"}, args...)...).Out()
+		assert.NilError(t, json.Unmarshal([]byte(out), &img), out)
+		return img
+	}
+
+	// Only the current platform is pulled
+	base.Cmd("pull", testutil.CommonImage).AssertOK()
+	base.Cmd("images", "--tree", testutil.CommonImage).AssertOutContains("└─ " + platform)
+
+	img := readTree(testutil.CommonImage)
+	assert.DeepEqual(t, img.Names, []string{testutil.CommonImage})
+	assert.Equal(t, len(img.Manifests), 1)
+	assert.Equal(t, img.Manifests[0].Platform, platform)
+	assert.Assert(t, img.Manifests[0].Available)
+
+	// The unavailable platforms are shown with --all
+	img = readTree("--all", testutil.CommonImage)
+	assert.Assert(t, len(img.Manifests) > 1)
+	available := 0
+	for _, m := range img.Manifests {
+		if m.Available {
+			available++
+		}
+	}
+	assert.Equal(t, available, 1)
+}
+
 func TestImagesFilter(t *testing.T) {
 	testutil.RequiresBuild(t)
 	t.Parallel()
@@ -0,0 +1,118 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func newImageMirrorCommand() *cobra.Command {
+	shortHelp := "Copy an image from a registry to another registry, without unpacking it"
+	longHelp := shortHelp + `
+
+The content is fetched into the content store, and pushed from there.
+When the source and the destination are on the same registry, the blobs are mounted across the repositories.
+
+Example:
+  nerdctl image mirror --all-platforms --referrers docker.io/library/alpine:3.20 registry.example.com/library/alpine:3.20
+`
+	var imageMirrorCommand = &cobra.Command{
+		Use:               "mirror [flags] SOURCE DESTINATION",
+		Short:             shortHelp,
+		Long:              longHelp,
+		Args:              IsExactArgs(2),
+		RunE:              imageMirrorAction,
+		ValidArgsFunction: imageMirrorShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	// #region platform flags
+	// platform is defined as StringSlice, not StringArray, to allow specifying "--platform=amd64,arm64"
+	imageMirrorCommand.Flags().StringSlice("platform", []string{}, "Copy content for a specific platform")
+	imageMirrorCommand.RegisterFlagCompletionFunc("platform", shellCompletePlatforms)
+	imageMirrorCommand.Flags().Bool("all-platforms", false, "Copy content for all platforms, without changing the digest of the image")
+	// #endregion
+
+	imageMirrorCommand.Flags().Bool("referrers", false, "Copy the referrers of the image, such as signatures and SBOMs (requires --all-platforms for multi-platform images)")
+	imageMirrorCommand.Flags().BoolP("quiet", "q", false, "Suppress verbose output")
+	imageMirrorCommand.Flags().Bool(allowNonDistFlag, false, "Allow pushing images with non-distributable blobs")
+	return imageMirrorCommand
+}
+
+func processImageMirrorOptions(cmd *cobra.Command) (types.ImageMirrorOptions, error) {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageMirrorOptions{}, err
+	}
+	platform, err := cmd.Flags().GetStringSlice("platform")
+	if err != nil {
+		return types.ImageMirrorOptions{}, err
+	}
+	allPlatforms, err := cmd.Flags().GetBool("all-platforms")
+	if err != nil {
+		return types.ImageMirrorOptions{}, err
+	}
+	referrers, err := cmd.Flags().GetBool("referrers")
+	if err != nil {
+		return types.ImageMirrorOptions{}, err
+	}
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return types.ImageMirrorOptions{}, err
+	}
+	allowNonDist, err := cmd.Flags().GetBool(allowNonDistFlag)
+	if err != nil {
+		return types.ImageMirrorOptions{}, err
+	}
+	return types.ImageMirrorOptions{
+		Stdout:                         cmd.OutOrStdout(),
+		Stderr:                         cmd.ErrOrStderr(),
+		GOptions:                       globalOptions,
+		Platforms:                      platform,
+		AllPlatforms:                   allPlatforms,
+		Referrers:                      referrers,
+		Quiet:                          quiet,
+		AllowNondistributableArtifacts: allowNonDist,
+	}, nil
+}
+
+func imageMirrorAction(cmd *cobra.Command, args []string) error {
+	options, err := processImageMirrorOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return image.Mirror(ctx, client, args[0], args[1], options)
+}
+
+func imageMirrorShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) < 2 {
+		// show image names
+		return shellCompleteImageNames(cmd)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
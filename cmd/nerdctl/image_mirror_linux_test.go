@@ -0,0 +1,78 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/testregistry"
+)
+
+func TestImageMirror(t *testing.T) {
+	testutil.DockerIncompatible(t)
+	base := testutil.NewBase(t)
+	reg := testregistry.NewWithNoAuth(base, 0, false)
+	defer reg.Cleanup(nil)
+
+	tag := strings.Split(testutil.CommonImage, ":")[1]
+	srcRef := fmt.Sprintf("%s:%d/%s-src:%s", reg.IP.String(), reg.Port, testutil.Identifier(t), tag)
+	dstRef := fmt.Sprintf("%s:%d/%s-dst:%s", reg.IP.String(), reg.Port, testutil.Identifier(t), tag)
+
+	base.Cmd("pull", testutil.CommonImage).AssertOK()
+	base.Cmd("tag", testutil.CommonImage, srcRef).AssertOK()
+	base.Cmd("--insecure-registry", "push", srcRef).AssertOK()
+	base.Cmd("rmi", srcRef).AssertOK()
+
+	base.Cmd("--insecure-registry", "image", "mirror", srcRef, dstRef).AssertOK()
+	// The images created for mirroring are removed
+	base.Cmd("image", "inspect", srcRef).AssertFail()
+	base.Cmd("image", "inspect", dstRef).AssertFail()
+
+	base.Cmd("--insecure-registry", "pull", dstRef).AssertOK()
+	defer base.Cmd("rmi", dstRef).Run()
+	base.Cmd("run", "--rm", dstRef, "echo", "mirrored").AssertOutExactly("mirrored\n")
+}
+
+func TestImageMirrorRestoresExistingImage(t *testing.T) {
+	testutil.DockerIncompatible(t)
+	base := testutil.NewBase(t)
+	reg := testregistry.NewWithNoAuth(base, 0, false)
+	defer reg.Cleanup(nil)
+
+	tag := strings.Split(testutil.CommonImage, ":")[1]
+	srcRef := fmt.Sprintf("%s:%d/%s-src:%s", reg.IP.String(), reg.Port, testutil.Identifier(t), tag)
+	dstRef := fmt.Sprintf("%s:%d/%s-dst:%s", reg.IP.String(), reg.Port, testutil.Identifier(t), tag)
+
+	base.Cmd("pull", testutil.CommonImage).AssertOK()
+	base.Cmd("tag", testutil.CommonImage, srcRef).AssertOK()
+	base.Cmd("--insecure-registry", "push", srcRef).AssertOK()
+	base.Cmd("rmi", srcRef).AssertOK()
+
+	// The local image with the destination name is a different image
+	base.Cmd("pull", testutil.BusyboxImage).AssertOK()
+	base.Cmd("tag", testutil.BusyboxImage, dstRef).AssertOK()
+	defer base.Cmd("rmi", dstRef).Run()
+	busyboxID := base.Cmd("image", "inspect", "--format={{.ID}}", testutil.BusyboxImage).Out()
+
+	base.Cmd("--insecure-registry", "image", "mirror", srcRef, dstRef).AssertOK()
+	// The local image is restored to its previous target
+	base.Cmd("image", "inspect", "--format={{.ID}}", dstRef).AssertOutExactly(busyboxID)
+	base.Cmd("image", "inspect", srcRef).AssertFail()
+}
@@ -64,6 +64,8 @@ func newPullCommand() *cobra.Command {
 	// #endregion
 
 	pullCommand.Flags().BoolP("quiet", "q", false, "Suppress verbose output")
+	pullCommand.Flags().BoolP("all-tags", "a", false, "Pull all the tags of the repository")
+	pullCommand.Flags().Bool("with-referrers", false, "Fetch the referrers of the image, such as signatures and SBOMs, into the content store")
 
 	pullCommand.Flags().String("ipfs-address", "", "multiaddr of IPFS API (default uses $IPFS_PATH env variable if defined or local directory ~/.ipfs)")
 
@@ -112,6 +114,16 @@ func processPullCommandFlags(cmd *cobra.Command) (types.ImagePullOptions, error)
 		return types.ImagePullOptions{}, err
 	}
 
+	allTags, err := cmd.Flags().GetBool("all-tags")
+	if err != nil {
+		return types.ImagePullOptions{}, err
+	}
+
+	withReferrers, err := cmd.Flags().GetBool("with-referrers")
+	if err != nil {
+		return types.ImagePullOptions{}, err
+	}
+
 	verifyOptions, err := processImageVerifyOptions(cmd)
 	if err != nil {
 		return types.ImagePullOptions{}, err
@@ -127,8 +139,10 @@ func processPullCommandFlags(cmd *cobra.Command) (types.ImagePullOptions, error)
 		RFlags: types.RemoteSnapshotterFlags{
 			SociIndexDigest: sociIndexDigest,
 		},
-		Stdout: cmd.OutOrStdout(),
-		Stderr: cmd.OutOrStderr(),
+		AllTags:       allTags,
+		WithReferrers: withReferrers,
+		Stdout:        cmd.OutOrStdout(),
+		Stderr:        cmd.OutOrStderr(),
 	}, nil
 }
 
@@ -64,6 +64,7 @@ func newPushCommand() *cobra.Command {
 	// #endregion
 
 	pushCommand.Flags().BoolP("quiet", "q", false, "Suppress verbose output")
+	pushCommand.Flags().BoolP("all-tags", "a", false, "Push all the local tags of the repository")
 
 	pushCommand.Flags().Bool(allowNonDistFlag, false, "Allow pushing images with non-distributable blobs")
 
@@ -103,6 +104,10 @@ func processImagePushOptions(cmd *cobra.Command) (types.ImagePushOptions, error)
 	if err != nil {
 		return types.ImagePushOptions{}, err
 	}
+	allTags, err := cmd.Flags().GetBool("all-tags")
+	if err != nil {
+		return types.ImagePushOptions{}, err
+	}
 	signOptions, err := processImageSignOptions(cmd)
 	if err != nil {
 		return types.ImagePushOptions{}, err
@@ -122,6 +127,7 @@ func processImagePushOptions(cmd *cobra.Command) (types.ImagePushOptions, error)
 		IpfsAddress:                    ipfsAddress,
 		Quiet:                          quiet,
 		AllowNondistributableArtifacts: allowNonDist,
+		AllTags:                        allTags,
 		Stdout:                         cmd.OutOrStdout(),
 	}, nil
 }
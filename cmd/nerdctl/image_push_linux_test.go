@@ -190,3 +190,29 @@ func TestPushSoci(t *testing.T) {
 
 	base.Cmd("--snapshotter=soci", "--insecure-registry", "push", "--soci-span-size=2097152", "--soci-min-layer-size=20971520", testImageRef).AssertOK()
 }
+
+func TestPushPullAllTags(t *testing.T) {
+	testutil.RequiresBuild(t)
+	base := testutil.NewBase(t)
+	reg := testregistry.NewWithNoAuth(base, 0, false)
+	defer reg.Cleanup(nil)
+
+	base.Cmd("pull", testutil.CommonImage).AssertOK()
+	repo := fmt.Sprintf("%s:%d/%s", reg.IP.String(), reg.Port, testutil.Identifier(t))
+	tags := []string{"v1", "v2", "v3"}
+	for _, tag := range tags {
+		base.Cmd("tag", testutil.CommonImage, repo+":"+tag).AssertOK()
+		defer base.Cmd("rmi", repo+":"+tag).Run()
+	}
+
+	base.Cmd("--insecure-registry", "push", "--all-tags", repo+":v1").AssertFail()
+	base.Cmd("--insecure-registry", "push", "--all-tags", repo).AssertOK()
+
+	for _, tag := range tags {
+		base.Cmd("rmi", repo+":"+tag).AssertOK()
+	}
+	base.Cmd("--insecure-registry", "pull", "--all-tags", repo).AssertOK()
+	for _, tag := range tags {
+		base.Cmd("image", "inspect", repo+":"+tag).AssertOK()
+	}
+}
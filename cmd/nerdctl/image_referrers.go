@@ -0,0 +1,92 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+)
+
+func newImageReferrersCommand() *cobra.Command {
+	shortHelp := "List the referrers of an image in a registry, such as signatures, attestations and SBOMs"
+	longHelp := shortHelp + `
+
+When the registry does not support the referrers API, the "sha256-<hex>" tag of the referrers tag schema is read instead.
+The signatures, attestations and SBOMs pushed with the cosign tags are listed too, unless --artifact-type is specified.
+`
+	var imageReferrersCommand = &cobra.Command{
+		Use:               "referrers [flags] IMAGE",
+		Short:             shortHelp,
+		Long:              longHelp,
+		Args:              IsExactArgs(1),
+		RunE:              imageReferrersAction,
+		ValidArgsFunction: imageReferrersShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	imageReferrersCommand.Flags().String("artifact-type", "", "Only list the referrers of the artifact type")
+	imageReferrersCommand.Flags().BoolP("quiet", "q", false, "Only show digests")
+	imageReferrersCommand.Flags().String("format", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	imageReferrersCommand.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json", "table"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return imageReferrersCommand
+}
+
+func processImageReferrersOptions(cmd *cobra.Command) (types.ImageReferrersOptions, error) {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return types.ImageReferrersOptions{}, err
+	}
+	artifactType, err := cmd.Flags().GetString("artifact-type")
+	if err != nil {
+		return types.ImageReferrersOptions{}, err
+	}
+	quiet, err := cmd.Flags().GetBool("quiet")
+	if err != nil {
+		return types.ImageReferrersOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.ImageReferrersOptions{}, err
+	}
+	return types.ImageReferrersOptions{
+		Stdout:       cmd.OutOrStdout(),
+		GOptions:     globalOptions,
+		ArtifactType: artifactType,
+		Format:       format,
+		Quiet:        quiet,
+	}, nil
+}
+
+func imageReferrersAction(cmd *cobra.Command, args []string) error {
+	options, err := processImageReferrersOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return image.Referrers(cmd.Context(), args[0], options)
+}
+
+func imageReferrersShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) == 0 {
+		// show image names
+		return shellCompleteImageNames(cmd)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
@@ -0,0 +1,84 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"archive/tar"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/testregistry"
+)
+
+func TestImageAttachReferrers(t *testing.T) {
+	testutil.DockerIncompatible(t)
+	base := testutil.NewBase(t)
+	reg := testregistry.NewWithNoAuth(base, 0, false)
+	defer reg.Cleanup(nil)
+
+	tag := strings.Split(testutil.CommonImage, ":")[1]
+	ref := fmt.Sprintf("%s:%d/%s:%s", reg.IP.String(), reg.Port, testutil.Identifier(t), tag)
+
+	base.Cmd("pull", testutil.CommonImage).AssertOK()
+	base.Cmd("tag", testutil.CommonImage, ref).AssertOK()
+	defer base.Cmd("rmi", ref).Run()
+	base.Cmd("--insecure-registry", "push", ref).AssertOK()
+
+	tmp := t.TempDir()
+	sbom := filepath.Join(tmp, "sbom.spdx.json")
+	err := os.WriteFile(sbom, []byte(`{"spdxVersion":"SPDX-2.3"}`), 0644)
+	assert.NilError(t, err)
+
+	artifact := strings.TrimSpace(base.Cmd("--insecure-registry", "image", "attach", "-q",
+		"--artifact-type", "application/spdx+json", "--annotation", "foo=bar", ref, sbom).Out())
+	assert.Assert(t, strings.HasPrefix(artifact, "sha256:"), artifact)
+
+	base.Cmd("--insecure-registry", "image", "referrers", "-q", ref).AssertOutExactly(artifact + "\n")
+	base.Cmd("--insecure-registry", "image", "referrers", "--format", "{{.ArtifactType}} {{index .Annotations \"foo\"}}", ref).
+		AssertOutExactly("application/spdx+json bar\n")
+	base.Cmd("--insecure-registry", "image", "referrers", "-q", "--artifact-type", "application/vnd.example", ref).AssertOutExactly("")
+
+	base.Cmd("rmi", ref).AssertOK()
+	base.Cmd("--insecure-registry", "pull", "--with-referrers", ref).AssertOK()
+
+	archive := filepath.Join(tmp, "archive.tar")
+	base.Cmd("save", "--with-referrers", "-o", archive, ref).AssertOK()
+	f, err := os.Open(archive)
+	assert.NilError(t, err)
+	defer f.Close()
+	tr := tar.NewReader(f)
+	var index []byte
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		assert.NilError(t, err)
+		if hdr.Name == "index.json" {
+			index, err = io.ReadAll(tr)
+			assert.NilError(t, err)
+		}
+	}
+	assert.Assert(t, strings.Contains(string(index), artifact), string(index))
+}
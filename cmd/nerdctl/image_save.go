@@ -47,6 +47,8 @@ func newSaveCommand() *cobra.Command {
 	saveCommand.Flags().Bool("all-platforms", false, "Export content for all platforms")
 	// #endregion
 
+	saveCommand.Flags().Bool("with-referrers", false, "Export the referrers of the images that were fetched with \"nerdctl pull --with-referrers\"")
+
 	return saveCommand
 }
 
@@ -64,11 +66,16 @@ func processImageSaveOptions(cmd *cobra.Command) (types.ImageSaveOptions, error)
 	if err != nil {
 		return types.ImageSaveOptions{}, err
 	}
+	withReferrers, err := cmd.Flags().GetBool("with-referrers")
+	if err != nil {
+		return types.ImageSaveOptions{}, err
+	}
 
 	return types.ImageSaveOptions{
-		GOptions:     globalOptions,
-		AllPlatforms: allPlatforms,
-		Platform:     platform,
+		GOptions:      globalOptions,
+		AllPlatforms:  allPlatforms,
+		Platform:      platform,
+		WithReferrers: withReferrers,
 	}, err
 }
 
@@ -31,6 +31,7 @@ func newInternalCommand() *cobra.Command {
 
 	internalCommand.AddCommand(
 		newInternalOCIHookCommandCommand(),
+		newInternalHealthcheckMonitorCommand(),
 	)
 
 	return internalCommand
@@ -0,0 +1,49 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+)
+
+func newInternalHealthcheckMonitorCommand() *cobra.Command {
+	var internalHealthcheckMonitorCommand = &cobra.Command{
+		Use:           "healthcheck-monitor CONTAINER",
+		Short:         "Run the healthcheck probes of a container",
+		Args:          cobra.ExactArgs(1),
+		RunE:          internalHealthcheckMonitorAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	return internalHealthcheckMonitorCommand
+}
+
+func internalHealthcheckMonitorAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), globalOptions.Namespace, globalOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return container.HealthMonitor(ctx, client, args[0])
+}
@@ -59,5 +59,6 @@ func internalOCIHookAction(cmd *cobra.Command, args []string) error {
 		dataStore,
 		cniPath,
 		cniNetconfpath,
+		globalOptions.Address,
 	)
 }
@@ -136,7 +136,17 @@ func xmain() error {
 	return app.Execute()
 }
 
-func initRootCmdFlags(rootCmd *cobra.Command, tomlPath string) (*pflag.FlagSet, error) {
+// nerdctlTOMLPath returns the path of nerdctl.toml, which can be overridden with $NERDCTL_TOML.
+func nerdctlTOMLPath() string {
+	if v, ok := os.LookupEnv("NERDCTL_TOML"); ok {
+		return v
+	}
+	return ncdefaults.NerdctlTOML()
+}
+
+// loadConfig loads nerdctl.toml on top of the built-in defaults.
+// loadConfig does not fail if the file does not exist.
+func loadConfig(tomlPath string) (*config.Config, error) {
 	cfg := config.New()
 	if r, err := os.Open(tomlPath); err == nil {
 		log.L.Debugf("Loading config from %q", tomlPath)
@@ -152,6 +162,14 @@ func initRootCmdFlags(rootCmd *cobra.Command, tomlPath string) (*pflag.FlagSet,
 			return nil, err
 		}
 	}
+	return cfg, nil
+}
+
+func initRootCmdFlags(rootCmd *cobra.Command, tomlPath string) (*pflag.FlagSet, error) {
+	cfg, err := loadConfig(tomlPath)
+	if err != nil {
+		return nil, err
+	}
 	aliasToBeInherited := pflag.NewFlagSet(rootCmd.Name(), pflag.ExitOnError)
 
 	rootCmd.PersistentFlags().Bool("debug", cfg.Debug, "debug mode")
@@ -174,16 +192,16 @@ func initRootCmdFlags(rootCmd *cobra.Command, tomlPath string) (*pflag.FlagSet,
 	rootCmd.PersistentFlags().StringSlice("hosts-dir", cfg.HostsDir, "A directory that contains <HOST:PORT>/hosts.toml (containerd style) or <HOST:PORT>/{ca.cert, cert.pem, key.pem} (docker style)")
 	// Experimental enable experimental feature, see in https://github.com/containerd/nerdctl/blob/main/docs/experimental.md
 	AddPersistentBoolFlag(rootCmd, "experimental", nil, nil, cfg.Experimental, "NERDCTL_EXPERIMENTAL", "Control experimental: https://github.com/containerd/nerdctl/blob/main/docs/experimental.md")
+	// no "-c" shorthand, as it conflicts with subcommands such as `nerdctl import -c`
+	AddPersistentStringFlag(rootCmd, "context", nil, nil, nil, aliasToBeInherited, "", "NERDCTL_CONTEXT", `Name of the context to use (overrides the context set with "nerdctl context use")`)
+	rootCmd.RegisterFlagCompletionFunc("context", shellCompleteContextNames)
 	AddPersistentStringFlag(rootCmd, "host-gateway-ip", nil, nil, nil, aliasToBeInherited, cfg.HostGatewayIP, "NERDCTL_HOST_GATEWAY_IP", "IP address that the special 'host-gateway' string in --add-host resolves to. Defaults to the IP address of the host. It has no effect without setting --add-host")
 	return aliasToBeInherited, nil
 }
 
 func newApp() (*cobra.Command, error) {
 
-	tomlPath := ncdefaults.NerdctlTOML()
-	if v, ok := os.LookupEnv("NERDCTL_TOML"); ok {
-		tomlPath = v
-	}
+	tomlPath := nerdctlTOMLPath()
 
 	short := "nerdctl is a command line interface for containerd"
 	long := fmt.Sprintf(`%s
@@ -261,6 +279,7 @@ Config file ($NERDCTL_TOML): %s
 		newWaitCommand(),
 		newRenameCommand(),
 		newAttachCommand(),
+		newExportCommand(),
 		// #endregion
 
 		// Build
@@ -272,6 +291,7 @@ Config file ($NERDCTL_TOML): %s
 		newPushCommand(),
 		newLoadCommand(),
 		newSaveCommand(),
+		newImportCommand(),
 		newTagCommand(),
 		newRmiCommand(),
 		newHistoryCommand(),
@@ -298,6 +318,8 @@ Config file ($NERDCTL_TOML): %s
 		newSystemCommand(),
 		newNamespaceCommand(),
 		newBuilderCommand(),
+		newManifestCommand(),
+		newContextCommand(),
 		// #endregion
 
 		// Internal
@@ -316,6 +338,7 @@ Config file ($NERDCTL_TOML): %s
 		newIPFSCommand(),
 	)
 	addApparmorCommand(rootCmd)
+	addCheckpointCommand(rootCmd)
 	addCpCommand(rootCmd)
 
 	// add aliasToBeInherited to subCommand(s) InheritedFlags
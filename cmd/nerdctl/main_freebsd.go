@@ -35,3 +35,7 @@ func addApparmorCommand(rootCmd *cobra.Command) {
 func addCpCommand(rootCmd *cobra.Command) {
 	// NOP
 }
+
+func addCheckpointCommand(rootCmd *cobra.Command) {
+	// NOP
+}
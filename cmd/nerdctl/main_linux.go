@@ -38,10 +38,10 @@ func appNeedsRootlessParentMain(cmd *cobra.Command, args []string) bool {
 		return true
 	}
 	switch commands[1] {
-	// completion, login, logout, version: false, because it shouldn't require the daemon to be running
+	// completion, context, login, logout, version: false, because it shouldn't require the daemon to be running
 	// apparmor: false, because it requires the initial mount namespace to access /sys/kernel/security
 	// cp, compose cp: false, because it requires the initial mount namespace to inspect file owners
-	case "", "completion", "login", "logout", "apparmor", "cp", "version":
+	case "", "completion", "context", "login", "logout", "apparmor", "cp", "version":
 		return false
 	case "container":
 		if len(commands) < 3 {
@@ -81,3 +81,7 @@ func addApparmorCommand(rootCmd *cobra.Command) {
 func addCpCommand(rootCmd *cobra.Command) {
 	rootCmd.AddCommand(newCpCommand())
 }
+
+func addCheckpointCommand(rootCmd *cobra.Command) {
+	rootCmd.AddCommand(newCheckpointCommand())
+}
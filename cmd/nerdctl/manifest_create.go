@@ -0,0 +1,67 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/manifest"
+)
+
+func newManifestCreateCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "create [flags] MANIFEST_LIST MANIFEST [MANIFEST...]",
+		Short:         "Create a local manifest list for annotating and pushing to a registry",
+		Args:          cobra.MinimumNArgs(2),
+		RunE:          manifestCreateAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().BoolP("amend", "a", false, "Amend an existing manifest list")
+	cmd.Flags().Bool("insecure", false, "Allow communication with an insecure registry")
+	return cmd
+}
+
+func processManifestCreateOptions(cmd *cobra.Command) (types.ManifestCreateOptions, error) {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return types.ManifestCreateOptions{}, err
+	}
+	amend, err := cmd.Flags().GetBool("amend")
+	if err != nil {
+		return types.ManifestCreateOptions{}, err
+	}
+	insecure, err := cmd.Flags().GetBool("insecure")
+	if err != nil {
+		return types.ManifestCreateOptions{}, err
+	}
+	return types.ManifestCreateOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Amend:    amend,
+		Insecure: insecure,
+	}, nil
+}
+
+func manifestCreateAction(cmd *cobra.Command, args []string) error {
+	options, err := processManifestCreateOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return manifest.Create(cmd.Context(), args[0], args[1:], options)
+}
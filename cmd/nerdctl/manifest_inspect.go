@@ -0,0 +1,67 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/manifest"
+)
+
+func newManifestInspectCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "inspect [flags] [MANIFEST_LIST] MANIFEST",
+		Short:         "Display an image manifest, or manifest list",
+		Args:          cobra.RangeArgs(1, 2),
+		RunE:          manifestInspectAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().Bool("insecure", false, "Allow communication with an insecure registry")
+	cmd.Flags().BoolP("verbose", "v", false, "Output additional info including layers and platform")
+	return cmd
+}
+
+func processManifestInspectOptions(cmd *cobra.Command) (types.ManifestInspectOptions, error) {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return types.ManifestInspectOptions{}, err
+	}
+	insecure, err := cmd.Flags().GetBool("insecure")
+	if err != nil {
+		return types.ManifestInspectOptions{}, err
+	}
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		return types.ManifestInspectOptions{}, err
+	}
+	return types.ManifestInspectOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Insecure: insecure,
+		Verbose:  verbose,
+	}, nil
+}
+
+func manifestInspectAction(cmd *cobra.Command, args []string) error {
+	options, err := processManifestInspectOptions(cmd)
+	if err != nil {
+		return err
+	}
+	return manifest.Inspect(cmd.Context(), args, options)
+}
@@ -0,0 +1,55 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+	"github.com/containerd/nerdctl/v2/pkg/testutil/testregistry"
+)
+
+func TestManifest(t *testing.T) {
+	testutil.DockerIncompatible(t)
+	base := testutil.NewBase(t)
+	reg := testregistry.NewWithNoAuth(base, 0, false)
+	defer reg.Cleanup(nil)
+
+	repo := fmt.Sprintf("%s:%d/%s", reg.IP.String(), reg.Port, testutil.Identifier(t))
+	listRef := repo + ":latest"
+	imageRefs := []string{repo + ":image-a", repo + ":image-b"}
+	defer base.Cmd("manifest", "rm", listRef).Run()
+
+	base.Cmd("pull", testutil.CommonImage).AssertOK()
+	for _, imageRef := range imageRefs {
+		base.Cmd("tag", testutil.CommonImage, imageRef).AssertOK()
+		base.Cmd("--insecure-registry", "push", imageRef).AssertOK()
+		defer base.Cmd("rmi", imageRef).Run()
+	}
+
+	base.Cmd("manifest", "create", "--insecure", listRef, imageRefs[0], imageRefs[1]).AssertOutContains("Created manifest list")
+	base.Cmd("manifest", "create", "--insecure", listRef, imageRefs[0]).AssertFail()
+	base.Cmd("manifest", "annotate", "--os", "freebsd", "--arch", "riscv64", listRef, imageRefs[1]).AssertOK()
+	base.Cmd("manifest", "inspect", listRef).AssertOutContains(`"architecture": "riscv64"`)
+	base.Cmd("manifest", "inspect", "-v", listRef, imageRefs[1]).AssertOutContains(`"os": "freebsd"`)
+
+	base.Cmd("manifest", "push", "--insecure", "--purge", listRef).AssertOK()
+	base.Cmd("manifest", "inspect", listRef).AssertFail()
+	base.Cmd("manifest", "inspect", "--insecure", listRef).AssertOutContains(`"architecture": "riscv64"`)
+	base.Cmd("manifest", "inspect", "--insecure", "-v", listRef).AssertOutContains(`"os": "freebsd"`)
+}
@@ -0,0 +1,75 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/manifest"
+)
+
+func newManifestPushCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "push [flags] MANIFEST_LIST",
+		Short:         "Push a manifest list to a repository",
+		Args:          cobra.ExactArgs(1),
+		RunE:          manifestPushAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	cmd.Flags().Bool("insecure", false, "Allow push to an insecure registry")
+	cmd.Flags().BoolP("purge", "p", false, "Remove the local manifest list after push")
+	return cmd
+}
+
+func processManifestPushOptions(cmd *cobra.Command) (types.ManifestPushOptions, error) {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return types.ManifestPushOptions{}, err
+	}
+	insecure, err := cmd.Flags().GetBool("insecure")
+	if err != nil {
+		return types.ManifestPushOptions{}, err
+	}
+	purge, err := cmd.Flags().GetBool("purge")
+	if err != nil {
+		return types.ManifestPushOptions{}, err
+	}
+	return types.ManifestPushOptions{
+		Stdout:   cmd.OutOrStdout(),
+		GOptions: globalOptions,
+		Insecure: insecure,
+		Purge:    purge,
+	}, nil
+}
+
+func manifestPushAction(cmd *cobra.Command, args []string) error {
+	options, err := processManifestPushOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return manifest.Push(ctx, client, args[0], options)
+}
@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/manifest"
+)
+
+func newManifestRmCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:           "rm [flags] MANIFEST_LIST [MANIFEST_LIST...]",
+		Short:         "Delete one or more manifest lists from local storage",
+		Args:          cobra.MinimumNArgs(1),
+		RunE:          manifestRmAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	return cmd
+}
+
+func manifestRmAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	options := types.ManifestRemoveOptions{
+		GOptions: globalOptions,
+	}
+	return manifest.Remove(cmd.Context(), args, options)
+}
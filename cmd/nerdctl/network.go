@@ -35,6 +35,8 @@ func newNetworkCommand() *cobra.Command {
 		newNetworkCreateCommand(),
 		newNetworkRmCommand(),
 		newNetworkPruneCommand(),
+		newNetworkConnectCommand(),
+		newNetworkDisconnectCommand(),
 	)
 	return networkCommand
 }
@@ -0,0 +1,69 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/network"
+
+	"github.com/spf13/cobra"
+)
+
+func newNetworkConnectCommand() *cobra.Command {
+	networkConnectCommand := &cobra.Command{
+		Use:               "connect [flags] NETWORK CONTAINER",
+		Short:             "Connect a container to a network",
+		Args:              cobra.ExactArgs(2),
+		RunE:              networkConnectAction,
+		ValidArgsFunction: networkConnectShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	return networkConnectCommand
+}
+
+func networkConnectAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	options := types.NetworkConnectOptions{
+		GOptions:  globalOptions,
+		Network:   args[0],
+		Container: args[1],
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return network.Connect(ctx, client, options)
+}
+
+func networkConnectShellComplete(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	switch len(args) {
+	case 0:
+		return shellCompleteNetworkNames(cmd, []string{"host", "none"})
+	case 1:
+		return shellCompleteContainerNames(cmd, nil)
+	}
+	return nil, cobra.ShellCompDirectiveNoFileComp
+}
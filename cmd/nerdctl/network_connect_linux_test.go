@@ -0,0 +1,84 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestNetworkConnectDisconnect(t *testing.T) {
+	base := testutil.NewBase(t)
+	networkName := testutil.Identifier(t)
+	containerName := testutil.Identifier(t) + "-container"
+
+	base.Cmd("network", "create", networkName).AssertOK()
+	defer base.Cmd("network", "rm", networkName).Run()
+
+	base.Cmd("run", "-d", "--name", containerName, testutil.AlpineImage, "sleep", "infinity").AssertOK()
+	defer base.Cmd("rm", "-f", containerName).Run()
+
+	base.Cmd("network", "connect", networkName, containerName).AssertOK()
+	base.Cmd("network", "connect", networkName, containerName).AssertFail()
+	base.Cmd("exec", containerName, "ip", "link", "show", "eth1").AssertOK()
+	// The network is in use, so it cannot be removed
+	base.Cmd("network", "rm", networkName).AssertFail()
+
+	// The network is kept across restarts
+	base.Cmd("restart", containerName).AssertOK()
+	base.Cmd("exec", containerName, "ip", "link", "show", "eth1").AssertOK()
+
+	base.Cmd("network", "disconnect", networkName, containerName).AssertOK()
+	base.Cmd("network", "disconnect", networkName, containerName).AssertFail()
+	base.Cmd("exec", containerName, "ip", "link", "show", "eth1").AssertFail()
+	base.Cmd("exec", containerName, "ip", "link", "show", "eth0").AssertOK()
+}
+
+func TestNetworkConnectStoppedContainer(t *testing.T) {
+	base := testutil.NewBase(t)
+	networkName := testutil.Identifier(t)
+	containerName := testutil.Identifier(t) + "-container"
+
+	base.Cmd("network", "create", networkName).AssertOK()
+	defer base.Cmd("network", "rm", networkName).Run()
+
+	base.Cmd("create", "--name", containerName, testutil.AlpineImage, "sleep", "infinity").AssertOK()
+	defer base.Cmd("rm", "-f", containerName).Run()
+
+	base.Cmd("network", "connect", networkName, containerName).AssertOK()
+	base.Cmd("start", containerName).AssertOK()
+	base.Cmd("exec", containerName, "ip", "link", "show", "eth1").AssertOK()
+
+	// The container cannot be disconnected from its last network
+	base.Cmd("network", "disconnect", networkName, containerName).AssertOK()
+	base.Cmd("network", "disconnect", "bridge", containerName).AssertFail()
+}
+
+func TestNetworkConnectStaticAddress(t *testing.T) {
+	base := testutil.NewBase(t)
+	networkName := testutil.Identifier(t)
+	containerName := testutil.Identifier(t) + "-container"
+
+	base.Cmd("network", "create", networkName).AssertOK()
+	defer base.Cmd("network", "rm", networkName).Run()
+
+	// The static MAC address would be duplicated on both networks
+	base.Cmd("create", "--name", containerName, "--mac-address", "02:42:ac:11:00:42", testutil.AlpineImage, "sleep", "infinity").AssertOK()
+	defer base.Cmd("rm", "-f", containerName).Run()
+	base.Cmd("network", "connect", networkName, containerName).AssertFail()
+}
@@ -0,0 +1,65 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/network"
+
+	"github.com/spf13/cobra"
+)
+
+func newNetworkDisconnectCommand() *cobra.Command {
+	networkDisconnectCommand := &cobra.Command{
+		Use:               "disconnect [flags] NETWORK CONTAINER",
+		Short:             "Disconnect a container from a network",
+		Args:              cobra.ExactArgs(2),
+		RunE:              networkDisconnectAction,
+		ValidArgsFunction: networkConnectShellComplete,
+		SilenceUsage:      true,
+		SilenceErrors:     true,
+	}
+	networkDisconnectCommand.Flags().BoolP("force", "f", false, "Force the container to disconnect from a network")
+	return networkDisconnectCommand
+}
+
+func networkDisconnectAction(cmd *cobra.Command, args []string) error {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return err
+	}
+	force, err := cmd.Flags().GetBool("force")
+	if err != nil {
+		return err
+	}
+
+	options := types.NetworkDisconnectOptions{
+		GOptions:  globalOptions,
+		Network:   args[0],
+		Container: args[1],
+		Force:     force,
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return network.Disconnect(ctx, client, options)
+}
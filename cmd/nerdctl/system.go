@@ -32,6 +32,8 @@ func newSystemCommand() *cobra.Command {
 		newEventsCommand(),
 		newInfoCommand(),
 		newSystemPruneCommand(),
+		newSystemDfCommand(),
+		newSystemServeCommand(),
 	)
 	return systemCommand
 }
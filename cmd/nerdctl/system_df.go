@@ -0,0 +1,86 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+)
+
+func newSystemDfCommand() *cobra.Command {
+	systemDfCommand := &cobra.Command{
+		Use:           "df [flags]",
+		Short:         "Show disk usage",
+		Args:          cobra.NoArgs,
+		RunE:          systemDfAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	systemDfCommand.Flags().BoolP("verbose", "v", false, "Show detailed information on space usage")
+	systemDfCommand.Flags().String("format", "", "Format the output using the given Go template, e.g, '{{json .}}'")
+	systemDfCommand.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return []string{"json"}, cobra.ShellCompDirectiveNoFileComp
+	})
+	return systemDfCommand
+}
+
+func processSystemDfOptions(cmd *cobra.Command) (types.SystemDiskUsageOptions, error) {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return types.SystemDiskUsageOptions{}, err
+	}
+	verbose, err := cmd.Flags().GetBool("verbose")
+	if err != nil {
+		return types.SystemDiskUsageOptions{}, err
+	}
+	format, err := cmd.Flags().GetString("format")
+	if err != nil {
+		return types.SystemDiskUsageOptions{}, err
+	}
+	buildkitHost, err := getBuildkitHost(cmd, globalOptions.Namespace)
+	if err != nil {
+		log.L.WithError(err).Debug("BuildKit is not running. Build caches will not be accounted.")
+		buildkitHost = ""
+	}
+	return types.SystemDiskUsageOptions{
+		Stdout:       cmd.OutOrStdout(),
+		Stderr:       cmd.ErrOrStderr(),
+		GOptions:     globalOptions,
+		Verbose:      verbose,
+		Format:       format,
+		BuildKitHost: buildkitHost,
+	}, nil
+}
+
+func systemDfAction(cmd *cobra.Command, _ []string) error {
+	options, err := processSystemDfOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	return system.DiskUsage(ctx, client, options)
+}
@@ -0,0 +1,59 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestSystemDf(t *testing.T) {
+	testutil.DockerIncompatible(t)
+	t.Parallel()
+	namespaceID := testutil.Identifier(t)
+	base := testutil.NewBaseWithNamespace(t, namespaceID)
+	defer base.Cmd("namespace", "remove", namespaceID).Run()
+
+	vID := testutil.Identifier(t)
+	base.Cmd("volume", "create", vID).AssertOK()
+	defer base.Cmd("volume", "rm", vID).Run()
+
+	tID := testutil.Identifier(t)
+	base.Cmd("run", "-d", "-v", fmt.Sprintf("%s:/volume", vID), "--name", tID, testutil.CommonImage,
+		"sh", "-c", "head -c 1048576 /dev/zero > /volume/data; head -c 1048576 /dev/zero > /data; sleep infinity").AssertOK()
+	defer base.Cmd("rmi", "-f", testutil.CommonImage).Run()
+	defer base.Cmd("rm", "-f", tID).Run()
+	base.EnsureContainerStarted(tID)
+
+	base.Cmd("system", "df", "--format", "{{.Type}} {{.TotalCount}} {{.Active}}").AssertOutContainsAll(
+		"Images 1 1",
+		"Containers 1 1",
+		"Local Volumes 1 1",
+	)
+	base.Cmd("system", "df", "-v").AssertOutContainsAll(
+		"Images space usage:",
+		testutil.ImageRepo(testutil.CommonImage),
+		"Containers space usage:",
+		tID,
+		"Local Volumes space usage:",
+		vID,
+		"Build cache usage:",
+	)
+	base.Cmd("system", "df", "-v", "--format", "{{range .Volumes}}{{.Name}} {{.Links}}{{end}}").AssertOutContains(vID + " 1")
+}
@@ -25,13 +25,10 @@ import (
 )
 
 func newEventsCommand() *cobra.Command {
-	shortHelp := `Get real time events from the server`
-	longHelp := shortHelp + "\nNOTE: The output format is not compatible with Docker."
 	var eventsCommand = &cobra.Command{
 		Use:           "events",
 		Args:          cobra.NoArgs,
-		Short:         shortHelp,
-		Long:          longHelp,
+		Short:         "Get real time events from the server",
 		RunE:          eventsAction,
 		SilenceUsage:  true,
 		SilenceErrors: true,
@@ -40,6 +37,9 @@ func newEventsCommand() *cobra.Command {
 	eventsCommand.RegisterFlagCompletionFunc("format", func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
 		return []string{"json"}, cobra.ShellCompDirectiveNoFileComp
 	})
+	eventsCommand.Flags().StringSliceP("filter", "f", nil, "Filter output based on conditions provided")
+	eventsCommand.Flags().String("since", "", "Show all events created since timestamp")
+	eventsCommand.Flags().String("until", "", "Stream events until this timestamp")
 	return eventsCommand
 }
 
@@ -52,10 +52,25 @@ func processSystemEventsOptions(cmd *cobra.Command) (types.SystemEventsOptions,
 	if err != nil {
 		return types.SystemEventsOptions{}, err
 	}
+	filters, err := cmd.Flags().GetStringSlice("filter")
+	if err != nil {
+		return types.SystemEventsOptions{}, err
+	}
+	since, err := cmd.Flags().GetString("since")
+	if err != nil {
+		return types.SystemEventsOptions{}, err
+	}
+	until, err := cmd.Flags().GetString("until")
+	if err != nil {
+		return types.SystemEventsOptions{}, err
+	}
 	return types.SystemEventsOptions{
 		Stdout:   cmd.OutOrStdout(),
 		GOptions: globalOptions,
 		Format:   format,
+		Filters:  filters,
+		Since:    since,
+		Until:    until,
 	}, nil
 }
 
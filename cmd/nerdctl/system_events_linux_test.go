@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"testing"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestEventsReplay(t *testing.T) {
+	testutil.DockerIncompatible(t)
+	t.Parallel()
+	base := testutil.NewBase(t)
+	containerName := testutil.Identifier(t)
+	defer base.Cmd("rm", "-f", containerName).Run()
+
+	base.Cmd("run", "--name", containerName, testutil.CommonImage, "sh", "-c", "exit 3").AssertFail()
+	base.Cmd("events", "--since", "5m", "--until", "0s",
+		"--filter", "container="+containerName, "--format", "{{.Action}} {{.Actor.Attributes.name}}").AssertOutContainsAll(
+		"create "+containerName,
+		"start "+containerName,
+		"die "+containerName,
+	)
+	base.Cmd("events", "--since", "5m", "--until", "0s",
+		"--filter", "container="+containerName, "--filter", "event=die", "--format", "{{json .}}").AssertOutContainsAll(
+		`"Type":"container"`,
+		`"Action":"die"`,
+		`"exitCode":"3"`,
+	)
+	base.Cmd("events", "--since", "5m", "--until", "0s",
+		"--filter", "container="+containerName, "--filter", "type=image").AssertOutExactly("")
+}
@@ -0,0 +1,88 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/apiserver"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/defaults"
+)
+
+func newSystemServeCommand() *cobra.Command {
+	systemServeCommand := &cobra.Command{
+		Use:   "serve [flags]",
+		Short: "Serve the Docker Engine API on a unix socket",
+		Long: `Serve a subset of the Docker Engine API on a unix socket, so that the tools that only speak the Docker API can use nerdctl.
+
+Example:
+  nerdctl system serve --socket=/tmp/nerdctl.sock &
+  DOCKER_HOST=unix:///tmp/nerdctl.sock docker ps
+
+The server serves the containerd namespace specified with --namespace.
+See docs/command-reference.md for the supported endpoints.
+`,
+		Args:          cobra.NoArgs,
+		RunE:          systemServeAction,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+	}
+	systemServeCommand.Flags().String("socket", defaults.APISocket(), "Path of the unix socket to listen on")
+	return systemServeCommand
+}
+
+func processSystemServeOptions(cmd *cobra.Command) (types.SystemServeOptions, error) {
+	globalOptions, err := processRootCmdFlags(cmd)
+	if err != nil {
+		return types.SystemServeOptions{}, err
+	}
+	socket, err := cmd.Flags().GetString("socket")
+	if err != nil {
+		return types.SystemServeOptions{}, err
+	}
+	nerdctlCmd, nerdctlArgs := globalFlags(cmd)
+	return types.SystemServeOptions{
+		Stdout:      cmd.OutOrStdout(),
+		GOptions:    globalOptions,
+		Socket:      socket,
+		NerdctlCmd:  nerdctlCmd,
+		NerdctlArgs: nerdctlArgs,
+	}, nil
+}
+
+func systemServeAction(cmd *cobra.Command, _ []string) error {
+	options, err := processSystemServeOptions(cmd)
+	if err != nil {
+		return err
+	}
+
+	client, ctx, cancel, err := clientutil.NewClient(cmd.Context(), options.GOptions.Namespace, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return apiserver.Serve(ctx, client, options)
+}
@@ -0,0 +1,93 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestSystemServe(t *testing.T) {
+	testutil.DockerIncompatible(t)
+	base := testutil.NewBase(t)
+	sock := filepath.Join(t.TempDir(), "docker.sock")
+	testContainer := testutil.Identifier(t)
+	defer base.Cmd("rm", "-f", testContainer).Run()
+
+	result := base.Cmd("system", "serve", "--socket", sock).Start()
+	defer func() {
+		syscall.Kill(result.Cmd.Process.Pid, syscall.SIGTERM)
+		result.Cmd.Wait()
+	}()
+
+	client := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", sock)
+			},
+		},
+	}
+	get := func(path string) (int, []byte) {
+		resp, err := client.Get("http://localhost" + path)
+		assert.NilError(t, err)
+		defer resp.Body.Close()
+		b, err := io.ReadAll(resp.Body)
+		assert.NilError(t, err)
+		return resp.StatusCode, b
+	}
+
+	// Wait for the server to start
+	var err error
+	for i := 0; i < 30; i++ {
+		var resp *http.Response
+		if resp, err = client.Get("http://localhost/_ping"); err == nil {
+			resp.Body.Close()
+			break
+		}
+		time.Sleep(500 * time.Millisecond)
+	}
+	assert.NilError(t, err)
+
+	base.Cmd("run", "-d", "--name", testContainer, testutil.CommonImage, "sleep", "infinity").AssertOK()
+
+	status, body := get("/v1.43/containers/" + testContainer + "/json")
+	assert.Equal(t, status, http.StatusOK)
+	var inspected struct {
+		Name  string
+		State struct {
+			Running bool
+		}
+	}
+	assert.NilError(t, json.Unmarshal(body, &inspected))
+	assert.Equal(t, inspected.Name, "/"+testContainer)
+	assert.Assert(t, inspected.State.Running)
+
+	status, _ = get("/v1.43/containers/no-such-container/json")
+	assert.Equal(t, status, http.StatusNotFound)
+}
@@ -83,3 +83,12 @@ type BuilderPruneOptions struct {
 	// All will remove all unused images and all build cache, not just dangling ones
 	All bool
 }
+
+// BuilderDiskUsageOptions specifies options for reading the disk usage of the build cache.
+type BuilderDiskUsageOptions struct {
+	Stderr io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// BuildKitHost is the buildkit host
+	BuildKitHost string
+}
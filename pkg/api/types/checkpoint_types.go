@@ -0,0 +1,47 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "io"
+
+// CheckpointCreateOptions specifies options for `nerdctl checkpoint create`.
+type CheckpointCreateOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// LeaveRunning leaves the container running after the checkpoint is created
+	LeaveRunning bool
+	// CheckpointDir is the directory to store the checkpoint in, instead of the state dir of the container
+	CheckpointDir string
+}
+
+// CheckpointListOptions specifies options for `nerdctl checkpoint ls`.
+type CheckpointListOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// CheckpointDir is the directory to look up the checkpoints in, instead of the state dir of the container
+	CheckpointDir string
+}
+
+// CheckpointRemoveOptions specifies options for `nerdctl checkpoint rm`.
+type CheckpointRemoveOptions struct {
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// CheckpointDir is the directory to look up the checkpoint in, instead of the state dir of the container
+	CheckpointDir string
+}
@@ -30,6 +30,10 @@ type ContainerStartOptions struct {
 	Attach bool
 	// The key sequence for detaching a container.
 	DetachKeys string
+	// Checkpoint is the name of the checkpoint to restore the container from
+	Checkpoint string
+	// CheckpointDir is the directory to look up the checkpoint in, instead of the state dir of the container
+	CheckpointDir string
 }
 
 // ContainerKillOptions specifies options for `nerdctl (container) kill`.
@@ -262,6 +266,23 @@ type ContainerCreateOptions struct {
 	IPFSAddress string
 	// #endregion
 
+	// #region for healthcheck flags
+	// HealthCmd specifies the command to run to check health
+	HealthCmd string
+	// HealthInterval specifies the time between running the check (0 means the default)
+	HealthInterval time.Duration
+	// HealthTimeout specifies the maximum time to allow one check to run (0 means the default)
+	HealthTimeout time.Duration
+	// HealthRetries specifies the consecutive failures needed to report unhealthy (0 means the default)
+	HealthRetries int
+	// HealthStartPeriod specifies the start period for the container to initialize before starting health-retries countdown
+	HealthStartPeriod time.Duration
+	// HealthStartInterval specifies the time between running the check during the start period (0 means the default)
+	HealthStartInterval time.Duration
+	// NoHealthcheck disables any container-specified HEALTHCHECK
+	NoHealthcheck bool
+	// #endregion
+
 	// ImagePullOpt specifies image pull options which holds the ImageVerifyOptions for verifying the image.
 	ImagePullOpt ImagePullOptions
 }
@@ -349,10 +370,21 @@ type ContainerCommitOptions struct {
 	Author string
 	// Commit message
 	Message string
-	// Apply Dockerfile instruction to the created image (supported directives: [CMD, ENTRYPOINT])
+	// Apply Dockerfile instruction to the created image (supported directives: [CMD, ENTRYPOINT, ENV, EXPOSE, LABEL, ONBUILD, STOPSIGNAL, USER, VOLUME, WORKDIR])
 	Change []string
 	// Pause container during commit
 	Pause bool
+	// Compression of the committed layer (gzip, zstd, or uncompressed)
+	Compression string
+	// Format of the image manifest (docker or oci)
+	Format string
+}
+
+// ContainerExportOptions specifies options for `nerdctl (container) export`.
+type ContainerExportOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
 }
 
 // ContainerDiffOptions specifies options for `nerdctl (container) diff`.
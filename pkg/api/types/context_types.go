@@ -0,0 +1,86 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import (
+	"io"
+
+	"github.com/containerd/nerdctl/v2/pkg/config"
+)
+
+// ContextCreateOptions specifies options for `nerdctl context create`.
+type ContextCreateOptions struct {
+	Stdout io.Writer
+	// Description of the context
+	Description string
+	// From is the name of the context to copy the properties from
+	From string
+	// DefaultConfig is the config loaded from nerdctl.toml, used when copying from the default context
+	DefaultConfig *config.Config
+	// Address is the containerd address
+	Address string
+	// Namespace is the containerd namespace
+	Namespace string
+	// Snapshotter is the containerd snapshotter
+	Snapshotter string
+	// DataRoot is the root directory of persistent nerdctl state
+	DataRoot string
+	// CNIPath is the CNI plugins binary directory
+	CNIPath string
+	// CNINetConfPath is the CNI config directory
+	CNINetConfPath string
+	// HostsDir are the directories that contain hosts.toml or certs
+	HostsDir []string
+}
+
+// ContextListOptions specifies options for `nerdctl context ls`.
+type ContextListOptions struct {
+	Stdout io.Writer
+	// Current is the name of the context in use
+	Current string
+	// DefaultConfig is the config loaded from nerdctl.toml.
+	// The empty properties of the contexts are inherited from it.
+	DefaultConfig *config.Config
+	// Quiet only shows the names
+	Quiet bool
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+}
+
+// ContextUseOptions specifies options for `nerdctl context use`.
+type ContextUseOptions struct {
+	Stdout io.Writer
+	Stderr io.Writer
+}
+
+// ContextInspectOptions specifies options for `nerdctl context inspect`.
+type ContextInspectOptions struct {
+	Stdout io.Writer
+	// Current is the name of the context in use, inspected when no name is specified
+	Current string
+	// DefaultConfig is the config loaded from nerdctl.toml, which is shown as the default context
+	DefaultConfig *config.Config
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+}
+
+// ContextRemoveOptions specifies options for `nerdctl context rm`.
+type ContextRemoveOptions struct {
+	Stdout io.Writer
+	// Force removes the context even if it is in use
+	Force bool
+}
@@ -41,8 +41,10 @@ type ImageListOptions struct {
 	Digests bool
 	// Names show image names
 	Names bool
-	// All (unimplemented yet, always true)
+	// All shows the intermediate images, and the unavailable platforms and attestations of --tree
 	All bool
+	// Tree shows the platforms and attestations of each image as a tree
+	Tree bool
 }
 
 // ImageConvertOptions specifies options for `nerdctl image convert`.
@@ -179,6 +181,51 @@ type ImagePushOptions struct {
 	Quiet bool
 	// AllowNondistributableArtifacts allow pushing non-distributable artifacts
 	AllowNondistributableArtifacts bool
+	// AllTags push all the local tags of the repository
+	AllTags bool
+}
+
+// ImageMirrorOptions specifies options for `nerdctl image mirror`.
+type ImageMirrorOptions struct {
+	Stdout   io.Writer
+	Stderr   io.Writer
+	GOptions GlobalCommandOptions
+	// Platforms copy content for a specific platform
+	Platforms []string
+	// AllPlatforms copy content for all platforms
+	AllPlatforms bool
+	// Referrers copy the referrers of the image, such as signatures and SBOMs
+	Referrers bool
+	// Suppress verbose output
+	Quiet bool
+	// AllowNondistributableArtifacts allow pushing non-distributable artifacts
+	AllowNondistributableArtifacts bool
+}
+
+// ImageAttachOptions specifies options for `nerdctl image attach`.
+type ImageAttachOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// ArtifactType is the artifact type of the attached artifact
+	ArtifactType string
+	// MediaType is the media type of the attached file
+	MediaType string
+	// Annotations of the artifact manifest, in the form of "KEY=VALUE"
+	Annotations []string
+	// Quiet only show the digest of the artifact
+	Quiet bool
+}
+
+// ImageReferrersOptions specifies options for `nerdctl image referrers`.
+type ImageReferrersOptions struct {
+	Stdout   io.Writer
+	GOptions GlobalCommandOptions
+	// ArtifactType filters the referrers by the artifact type
+	ArtifactType string
+	// Format the output using the given Go template, e.g, '{{json .}}'
+	Format string
+	// Quiet only show digests
+	Quiet bool
 }
 
 // RemoteSnapshotterFlags are used for pulling with remote snapshotters
@@ -206,6 +253,10 @@ type ImagePullOptions struct {
 	IPFSAddress string
 	// Flags to pass into remote snapshotters
 	RFlags RemoteSnapshotterFlags
+	// AllTags pull all the tags of the repository
+	AllTags bool
+	// WithReferrers fetch the referrers of the image, such as signatures and SBOMs
+	WithReferrers bool
 }
 
 // ImageTagOptions specifies options for `nerdctl (image) tag`.
@@ -240,6 +291,23 @@ type ImagePruneOptions struct {
 	Force bool
 }
 
+// ImageImportOptions specifies options for `nerdctl (image) import`.
+type ImageImportOptions struct {
+	Stdout   io.Writer
+	Stdin    io.Reader
+	GOptions GlobalCommandOptions
+	// Source is the tar archive to import: a file path, an http(s) URL, or "-" for STDIN
+	Source string
+	// Reference is the name of the imported image. The image is untagged if empty.
+	Reference string
+	// Message is the commit message of the imported image
+	Message string
+	// Change applies Dockerfile instructions to the imported image (supported directives: [CMD, ENTRYPOINT, ENV, EXPOSE, LABEL, ONBUILD, STOPSIGNAL, USER, VOLUME, WORKDIR])
+	Change []string
+	// Platform is the platform of the imported image
+	Platform string
+}
+
 // ImageSaveOptions specifies options for `nerdctl (image) save`.
 type ImageSaveOptions struct {
 	Stdout   io.Writer
@@ -248,6 +316,8 @@ type ImageSaveOptions struct {
 	AllPlatforms bool
 	// Export content for a specific platform
 	Platform []string
+	// WithReferrers export the referrers of the images fetched with `nerdctl pull --with-referrers`
+	WithReferrers bool
 }
 
 // ImageSignOptions contains options for signing an image. It contains options from
@@ -0,0 +1,74 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package types
+
+import "io"
+
+// ManifestCreateOptions specifies options for `nerdctl manifest create`.
+type ManifestCreateOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Amend amends an existing manifest list, instead of failing
+	Amend bool
+	// Insecure allows communication with an insecure registry, in addition to GOptions.InsecureRegistry
+	Insecure bool
+}
+
+// ManifestAnnotateOptions specifies options for `nerdctl manifest annotate`.
+type ManifestAnnotateOptions struct {
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// OS sets the operating system of the entry
+	OS string
+	// Arch sets the architecture of the entry
+	Arch string
+	// Variant sets the architecture variant of the entry
+	Variant string
+	// OSVersion sets the operating system version of the entry
+	OSVersion string
+	// OSFeatures sets the operating system features of the entry
+	OSFeatures []string
+}
+
+// ManifestInspectOptions specifies options for `nerdctl manifest inspect`.
+type ManifestInspectOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Insecure allows communication with an insecure registry, in addition to GOptions.InsecureRegistry
+	Insecure bool
+	// Verbose prints the references, descriptors and manifests of the entries
+	Verbose bool
+}
+
+// ManifestPushOptions specifies options for `nerdctl manifest push`.
+type ManifestPushOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Insecure allows communication with an insecure registry, in addition to GOptions.InsecureRegistry
+	Insecure bool
+	// Purge removes the local manifest list after a successful push
+	Purge bool
+}
+
+// ManifestRemoveOptions specifies options for `nerdctl manifest rm`.
+type ManifestRemoveOptions struct {
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+}
@@ -37,6 +37,12 @@ type SystemEventsOptions struct {
 	GOptions GlobalCommandOptions
 	// Format the output using the given Go template, e.g, '{{json .}}
 	Format string
+	// Filters filter the events, e.g. "type=container", "event=start", "container=foo", "image=alpine", "label=foo=bar", "namespace=default"
+	Filters []string
+	// Since shows the events created since the timestamp, replaying the past events
+	Since string
+	// Until streams the events until the timestamp
+	Until string
 }
 
 // SystemPruneOptions specifies options for `nerdctl system prune`.
@@ -54,3 +60,30 @@ type SystemPruneOptions struct {
 	// NetworkDriversToKeep the network drivers which need to keep
 	NetworkDriversToKeep []string
 }
+
+// SystemDiskUsageOptions specifies options for `nerdctl system df`.
+type SystemDiskUsageOptions struct {
+	Stdout io.Writer
+	Stderr io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Verbose shows detailed information on space usage
+	Verbose bool
+	// Format the output using the given Go template, e.g, '{{json .}}
+	Format string
+	// BuildKitHost the address of BuildKit host. The build cache is not accounted when empty.
+	BuildKitHost string
+}
+
+// SystemServeOptions specifies options for `nerdctl system serve`.
+type SystemServeOptions struct {
+	Stdout io.Writer
+	// GOptions is the global options
+	GOptions GlobalCommandOptions
+	// Socket is the path of the unix socket to listen on
+	Socket string
+	// NerdctlCmd is the command name of nerdctl, used for the OCI hooks of the containers created via the API
+	NerdctlCmd string
+	// NerdctlArgs is the global arguments of nerdctl, used for the OCI hooks of the containers created via the API
+	NerdctlArgs []string
+}
@@ -0,0 +1,237 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package apiserver implements a subset of the Docker Engine API on top of pkg/cmd.
+//
+// The server serves a single containerd namespace. The request and response bodies
+// use the types of the Docker Engine API, and the inspect endpoints return the
+// dockercompat types, as `nerdctl inspect` does.
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"regexp"
+	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/docker/docker/api"
+	"github.com/docker/docker/api/types/filters"
+	"github.com/docker/docker/api/types/versions"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/version"
+)
+
+// Options specifies options for the API server.
+type Options struct {
+	// GOptions is the global options. The server serves GOptions.Namespace.
+	GOptions types.GlobalCommandOptions
+	// NerdctlCmd is the command name of nerdctl, used for the OCI hooks of the created containers
+	NerdctlCmd string
+	// NerdctlArgs is the global arguments of nerdctl, used for the OCI hooks of the created containers
+	NerdctlArgs []string
+}
+
+// Server is an http.Handler that serves the Docker Engine API.
+type Server struct {
+	client  *containerd.Client
+	options Options
+	mux     *http.ServeMux
+}
+
+// New returns a new Server.
+func New(client *containerd.Client, options Options) *Server {
+	s := &Server{
+		client:  client,
+		options: options,
+		mux:     http.NewServeMux(),
+	}
+	s.registerSystemRoutes()
+	s.registerContainerRoutes()
+	s.registerImageRoutes()
+	s.registerNetworkRoutes()
+	s.registerVolumeRoutes()
+	s.mux.Handle("/", s.handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errorWithStatus(http.StatusNotFound, errors.New("page not found"))
+	}))
+	return s
+}
+
+// versionPrefix matches the optional API version prefix of the request path, e.g. "/v1.43".
+var versionPrefix = regexp.MustCompile(`^/v([0-9]+\.[0-9]+)(/|$)`)
+
+// ServeHTTP implements http.Handler.
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Api-Version", api.DefaultVersion)
+	w.Header().Set("Ostype", runtime.GOOS)
+	w.Header().Set("Server", "nerdctl/"+version.GetVersion())
+	if m := versionPrefix.FindStringSubmatch(r.URL.Path); m != nil {
+		if versions.LessThan(m[1], api.MinSupportedAPIVersion) {
+			writeError(w, errorWithStatus(http.StatusBadRequest,
+				fmt.Errorf("client version %s is too old. Minimum supported API version is %s", m[1], api.MinSupportedAPIVersion)))
+			return
+		}
+		r = r.Clone(r.Context())
+		r.URL.Path = strings.TrimPrefix(r.URL.Path, "/v"+m[1])
+		r.URL.RawPath = ""
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// handlerFunc is an HTTP handler that returns an error.
+type handlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// handler converts the handlerFunc to http.Handler.
+// The request context is bound to the namespace of the server, and the error is written
+// as a Docker-compatible error response.
+func (s *Server) handler(fn handlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := namespaces.WithNamespace(r.Context(), s.options.GOptions.Namespace)
+		log.G(ctx).Debugf("%s %s", r.Method, r.URL)
+		if err := fn(w, r.WithContext(ctx)); err != nil {
+			log.G(ctx).WithError(err).Debugf("%s %s failed", r.Method, r.URL)
+			writeError(w, err)
+		}
+	})
+}
+
+// statusError is an error with an HTTP status code.
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+func errorWithStatus(status int, err error) error {
+	return &statusError{status: status, err: err}
+}
+
+// statusCode returns the HTTP status code for the error.
+func statusCode(err error) int {
+	var se *statusError
+	switch {
+	case errors.As(err, &se):
+		return se.status
+	case errdefs.IsNotFound(err):
+		return http.StatusNotFound
+	case errdefs.IsAlreadyExists(err), errdefs.IsFailedPrecondition(err):
+		return http.StatusConflict
+	case errdefs.IsInvalidArgument(err):
+		return http.StatusBadRequest
+	case errdefs.IsNotImplemented(err):
+		return http.StatusNotImplemented
+	default:
+		return http.StatusInternalServerError
+	}
+}
+
+// writeError writes the error in the same format as the Docker Engine API.
+func writeError(w http.ResponseWriter, err error) {
+	writeJSON(w, statusCode(err), map[string]string{"message": err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) error {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	return json.NewEncoder(w).Encode(v)
+}
+
+// decodeBody decodes the JSON request body into v.
+// An empty body is not an error.
+func decodeBody(r *http.Request, v any) error {
+	if r.Body == nil || r.ContentLength == 0 {
+		return nil
+	}
+	if err := json.NewDecoder(r.Body).Decode(v); err != nil {
+		return errorWithStatus(http.StatusBadRequest, fmt.Errorf("failed to parse the request body: %w", err))
+	}
+	return nil
+}
+
+// boolValue returns the boolean value of the query parameter `k`.
+// As in the Docker Engine API, an empty value, "0", "no", "false", and "none" are false.
+func boolValue(r *http.Request, k string) bool {
+	switch strings.ToLower(strings.TrimSpace(r.FormValue(k))) {
+	case "", "0", "no", "false", "none":
+		return false
+	default:
+		return true
+	}
+}
+
+// intValue returns the integer value of the query parameter `k`, or `def` if the parameter is not set.
+func intValue(r *http.Request, k string, def int) (int, error) {
+	s := r.FormValue(k)
+	if s == "" {
+		return def, nil
+	}
+	i, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, errorWithStatus(http.StatusBadRequest, fmt.Errorf("invalid value for %q: %w", k, err))
+	}
+	return i, nil
+}
+
+// filterValues converts the "filters" query parameter to the "key=value" strings of the `--filter` flags.
+func filterValues(r *http.Request) ([]string, error) {
+	args, err := filters.FromJSON(r.FormValue("filters"))
+	if err != nil {
+		return nil, errorWithStatus(http.StatusBadRequest, err)
+	}
+	var res []string
+	for _, k := range args.Keys() {
+		for _, v := range args.Get(k) {
+			res = append(res, k+"="+v)
+		}
+	}
+	sort.Strings(res)
+	return res, nil
+}
+
+// flushWriter flushes the response after each write, for streaming endpoints.
+// flushWriter is safe for concurrent use, as the stdout and the stderr of the logs are
+// written to the same response.
+type flushWriter struct {
+	mu sync.Mutex
+	w  http.ResponseWriter
+}
+
+func (fw *flushWriter) Write(p []byte) (int, error) {
+	fw.mu.Lock()
+	defer fw.mu.Unlock()
+	n, err := fw.w.Write(p)
+	if f, ok := fw.w.(http.Flusher); ok {
+		f.Flush()
+	}
+	return n, err
+}
@@ -0,0 +1,111 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+func TestServeHTTP(t *testing.T) {
+	srv := New(nil, Options{GOptions: types.GlobalCommandOptions{Namespace: "default"}})
+	testCases := []struct {
+		method string
+		path   string
+		status int
+	}{
+		{http.MethodGet, "/_ping", http.StatusOK},
+		{http.MethodHead, "/_ping", http.StatusOK},
+		{http.MethodGet, "/v1.43/_ping", http.StatusOK},
+		{http.MethodGet, "/v1.12/_ping", http.StatusBadRequest},
+		{http.MethodGet, "/v1.43/no-such-endpoint", http.StatusNotFound},
+		{http.MethodPost, "/_ping", http.StatusNotFound},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.method+" "+tc.path, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			srv.ServeHTTP(rec, httptest.NewRequest(tc.method, tc.path, nil))
+			assert.Equal(t, rec.Code, tc.status)
+			assert.Assert(t, rec.Header().Get("Api-Version") != "")
+			if tc.status >= http.StatusBadRequest {
+				var msg struct {
+					Message string `json:"message"`
+				}
+				assert.NilError(t, json.Unmarshal(rec.Body.Bytes(), &msg))
+				assert.Assert(t, msg.Message != "")
+			}
+		})
+	}
+}
+
+func TestStatusCode(t *testing.T) {
+	assert.Equal(t, statusCode(errors.New("unknown")), http.StatusInternalServerError)
+	assert.Equal(t, statusCode(fmt.Errorf("wrapped: %w", errdefs.ErrNotFound)), http.StatusNotFound)
+	assert.Equal(t, statusCode(errdefs.ErrAlreadyExists), http.StatusConflict)
+	assert.Equal(t, statusCode(errdefs.ErrInvalidArgument), http.StatusBadRequest)
+	assert.Equal(t, statusCode(errdefs.ErrNotImplemented), http.StatusNotImplemented)
+	assert.Equal(t, statusCode(errorWithStatus(http.StatusNotModified, errdefs.ErrNotFound)), http.StatusNotModified)
+}
+
+func TestFilterValues(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, `/containers/json?filters={"status":{"running":true},"label":{"a=b":true,"c":true}}`, nil)
+	filters, err := filterValues(r)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, filters, []string{"label=a=b", "label=c", "status=running"})
+
+	r = httptest.NewRequest(http.MethodGet, "/containers/json?filters=invalid", nil)
+	_, err = filterValues(r)
+	assert.Equal(t, statusCode(err), http.StatusBadRequest)
+}
+
+func TestRestartPolicy(t *testing.T) {
+	assert.Equal(t, restartPolicy(dockercontainer.RestartPolicy{}), "no")
+	assert.Equal(t, restartPolicy(dockercontainer.RestartPolicy{Name: dockercontainer.RestartPolicyAlways}), "always")
+	assert.Equal(t, restartPolicy(dockercontainer.RestartPolicy{Name: dockercontainer.RestartPolicyOnFailure}), "on-failure")
+	assert.Equal(t, restartPolicy(dockercontainer.RestartPolicy{Name: dockercontainer.RestartPolicyOnFailure, MaximumRetryCount: 3}), "on-failure:3")
+}
+
+func TestMountString(t *testing.T) {
+	assert.Equal(t, mountString(mount.Mount{
+		Type:     mount.TypeBind,
+		Source:   "/src",
+		Target:   "/dst",
+		ReadOnly: true,
+		BindOptions: &mount.BindOptions{
+			Propagation: mount.PropagationRShared,
+		},
+	}), "type=bind,source=/src,target=/dst,readonly,bind-propagation=rshared")
+	assert.Equal(t, mountString(mount.Mount{
+		Type:   mount.TypeTmpfs,
+		Target: "/tmp",
+		TmpfsOptions: &mount.TmpfsOptions{
+			SizeBytes: 1024,
+			Mode:      01777,
+		},
+	}), "type=tmpfs,target=/tmp,tmpfs-size=1024,tmpfs-mode=1777")
+}
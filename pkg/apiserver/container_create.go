@@ -0,0 +1,357 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	"github.com/docker/go-connections/nat"
+
+	gocni "github.com/containerd/go-cni"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+	"github.com/containerd/nerdctl/v2/pkg/portutil"
+)
+
+func (s *Server) createContainer(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	var req dockercontainer.CreateRequest
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	if req.Config == nil || req.Image == "" {
+		return errorWithStatus(http.StatusBadRequest, errors.New("config cannot be empty in order to create a container"))
+	}
+	name := strings.TrimPrefix(r.FormValue("name"), "/")
+	if name != "" {
+		if err := s.checkNameConflict(ctx, name); err != nil {
+			return err
+		}
+	}
+	args, options, netOpts, err := s.createOptions(req, name, r.FormValue("platform"))
+	if err != nil {
+		return errorWithStatus(http.StatusBadRequest, err)
+	}
+	netManager, err := containerutil.NewNetworkingOptionsManager(options.GOptions, netOpts, s.client)
+	if err != nil {
+		return errorWithStatus(http.StatusBadRequest, err)
+	}
+	c, gc, err := container.Create(ctx, s.client, args, netManager, options)
+	if err != nil {
+		if gc != nil {
+			gc()
+		}
+		return err
+	}
+	return writeJSON(w, http.StatusCreated, dockercontainer.CreateResponse{
+		ID:       c.ID(),
+		Warnings: []string{},
+	})
+}
+
+// createOptions converts the request of `POST /containers/create` to the arguments and the options
+// of container.Create, in the same way as the flags of `nerdctl create`.
+func (s *Server) createOptions(req dockercontainer.CreateRequest, name, platform string) ([]string, types.ContainerCreateOptions, types.NetworkOptions, error) {
+	cfg := req.Config
+	hc := req.HostConfig
+	if hc == nil {
+		hc = &dockercontainer.HostConfig{}
+	}
+
+	options := container.NewCreateOptions(s.options.GOptions)
+	options.Stdout = io.Discard
+	options.Stderr = io.Discard
+	options.NerdctlCmd = s.options.NerdctlCmd
+	options.NerdctlArgs = s.options.NerdctlArgs
+	options.ImagePullOpt.Stdout = io.Discard
+	options.ImagePullOpt.Stderr = io.Discard
+	options.Platform = platform
+	if name != "" {
+		options.NameChanged = true
+		options.Name = name
+	}
+
+	// Config
+	args := append([]string{cfg.Image}, cfg.Cmd...)
+	if cfg.Entrypoint != nil {
+		options.EntrypointChanged = true
+		for _, e := range cfg.Entrypoint {
+			// `"Entrypoint": [""]` resets the entrypoint of the image
+			if e != "" {
+				options.Entrypoint = append(options.Entrypoint, e)
+			}
+		}
+	}
+	options.TTY = cfg.Tty
+	options.Interactive = cfg.OpenStdin
+	options.User = cfg.User
+	options.Workdir = cfg.WorkingDir
+	options.Env = cfg.Env
+	options.Label = kvStrings(cfg.Labels)
+	if cfg.StopSignal != "" {
+		options.StopSignal = cfg.StopSignal
+	}
+	if cfg.StopTimeout != nil {
+		options.StopTimeout = *cfg.StopTimeout
+	}
+	if h := cfg.Healthcheck; h != nil {
+		if len(h.Test) > 0 {
+			if h.Test[0] == "NONE" {
+				options.NoHealthcheck = true
+			} else {
+				b, err := json.Marshal(h.Test)
+				if err != nil {
+					return nil, options, types.NetworkOptions{}, err
+				}
+				options.HealthCmd = string(b)
+			}
+		}
+		if !options.NoHealthcheck {
+			options.HealthInterval = h.Interval
+			options.HealthTimeout = h.Timeout
+			options.HealthStartPeriod = h.StartPeriod
+			options.HealthStartInterval = h.StartInterval
+			options.HealthRetries = h.Retries
+		}
+	}
+
+	// HostConfig
+	options.Volume = hc.Binds
+	options.VolumesFrom = hc.VolumesFrom
+	for _, m := range hc.Mounts {
+		options.Mount = append(options.Mount, mountString(m))
+	}
+	for dst, opts := range hc.Tmpfs {
+		if opts != "" {
+			dst += ":" + opts
+		}
+		options.Tmpfs = append(options.Tmpfs, dst)
+	}
+	sort.Strings(options.Tmpfs)
+	options.Restart = restartPolicy(hc.RestartPolicy)
+	options.Rm = hc.AutoRemove
+	if hc.LogConfig.Type != "" {
+		options.LogDriver = hc.LogConfig.Type
+	}
+	options.LogOpt = kvStrings(hc.LogConfig.Config)
+	options.Privileged = hc.Privileged
+	options.ReadOnly = hc.ReadonlyRootfs
+	options.CapAdd = append(options.CapAdd, hc.CapAdd...)
+	options.CapDrop = append(options.CapDrop, hc.CapDrop...)
+	options.SecurityOpt = append(options.SecurityOpt, hc.SecurityOpt...)
+	options.GroupAdd = append(options.GroupAdd, hc.GroupAdd...)
+	options.Sysctl = kvStrings(hc.Sysctls)
+	options.Annotations = kvStrings(hc.Annotations)
+	options.Pid = string(hc.PidMode)
+	options.IPC = string(hc.IpcMode)
+	if hc.CgroupnsMode != "" {
+		options.Cgroupns = string(hc.CgroupnsMode)
+	}
+	if hc.Runtime != "" {
+		options.Runtime = hc.Runtime
+	}
+	if hc.ShmSize > 0 {
+		options.ShmSize = strconv.FormatInt(hc.ShmSize, 10)
+	}
+	if hc.OomScoreAdj != 0 {
+		options.OomScoreAdjChanged = true
+		options.OomScoreAdj = hc.OomScoreAdj
+	}
+	if hc.Init != nil {
+		options.InitProcessFlag = *hc.Init
+	}
+
+	// HostConfig.Resources
+	res := hc.Resources
+	if res.NanoCPUs > 0 {
+		options.CPUs = float64(res.NanoCPUs) / 1e9
+	}
+	if res.CPUQuota != 0 {
+		options.CPUQuota = res.CPUQuota
+	}
+	options.CPUPeriod = uint64(res.CPUPeriod)
+	options.CPUShares = uint64(res.CPUShares)
+	options.CPUSetCPUs = res.CpusetCpus
+	options.CPUSetMems = res.CpusetMems
+	if res.Memory > 0 {
+		options.Memory = strconv.FormatInt(res.Memory, 10)
+	}
+	if res.MemoryReservation > 0 {
+		options.MemoryReservationChanged = true
+		options.MemoryReservation = strconv.FormatInt(res.MemoryReservation, 10)
+	}
+	if res.MemorySwap != 0 {
+		options.MemorySwap = strconv.FormatInt(res.MemorySwap, 10)
+	}
+	if res.MemorySwappiness != nil {
+		options.MemorySwappiness64Changed = true
+		options.MemorySwappiness64 = *res.MemorySwappiness
+	}
+	if res.OomKillDisable != nil {
+		options.OomKillDisable = *res.OomKillDisable
+	}
+	if res.PidsLimit != nil && *res.PidsLimit > 0 {
+		options.PidsLimit = *res.PidsLimit
+	}
+	options.BlkioWeight = res.BlkioWeight
+	options.CgroupParent = res.CgroupParent
+	for _, d := range res.Devices {
+		dev := d.PathOnHost
+		if d.PathInContainer != "" {
+			dev += ":" + d.PathInContainer
+		}
+		if d.CgroupPermissions != "" {
+			dev += ":" + d.CgroupPermissions
+		}
+		options.Device = append(options.Device, dev)
+	}
+	for _, u := range res.Ulimits {
+		options.Ulimit = append(options.Ulimit, fmt.Sprintf("%s=%d:%d", u.Name, u.Soft, u.Hard))
+	}
+
+	netOpts, err := networkOptions(req, hc)
+	if err != nil {
+		return nil, options, netOpts, err
+	}
+	return args, options, netOpts, nil
+}
+
+// networkOptions converts the request to the networking options, in the same way as the networking flags of `nerdctl create`.
+func networkOptions(req dockercontainer.CreateRequest, hc *dockercontainer.HostConfig) (types.NetworkOptions, error) {
+	netOpts := types.NetworkOptions{
+		Hostname:             req.Hostname,
+		MACAddress:           req.MacAddress, //nolint:staticcheck // deprecated, but still sent by old clients
+		DNSServers:           hc.DNS,
+		DNSResolvConfOptions: hc.DNSOptions,
+		DNSSearchDomains:     hc.DNSSearch,
+		AddHost:              hc.ExtraHosts,
+		UTSNamespace:         string(hc.UTSMode),
+		PortMappings:         []gocni.PortMapping{},
+	}
+	switch mode := string(hc.NetworkMode); mode {
+	case "", "default":
+		netOpts.NetworkSlice = []string{netutil.DefaultNetworkName}
+	default:
+		netOpts.NetworkSlice = []string{mode}
+	}
+	if req.NetworkingConfig != nil {
+		for name, ep := range req.NetworkingConfig.EndpointsConfig {
+			if ep == nil {
+				continue
+			}
+			if name == netOpts.NetworkSlice[0] || len(req.NetworkingConfig.EndpointsConfig) == 1 {
+				if ep.IPAMConfig != nil {
+					netOpts.IPAddress = ep.IPAMConfig.IPv4Address
+					netOpts.IP6Address = ep.IPAMConfig.IPv6Address
+				}
+				if ep.MacAddress != "" {
+					netOpts.MACAddress = ep.MacAddress
+				}
+			}
+		}
+	}
+
+	portMap := hc.PortBindings
+	if hc.PublishAllPorts {
+		portMap = make(nat.PortMap)
+		for p, b := range hc.PortBindings {
+			portMap[p] = b
+		}
+		for p := range req.ExposedPorts {
+			if _, ok := portMap[p]; !ok {
+				portMap[p] = []nat.PortBinding{{}}
+			}
+		}
+	}
+	for p, bindings := range portMap {
+		if len(bindings) == 0 {
+			bindings = []nat.PortBinding{{}}
+		}
+		for _, b := range bindings {
+			s := p.Port() + "/" + p.Proto()
+			switch {
+			case b.HostIP != "":
+				s = b.HostIP + ":" + b.HostPort + ":" + s
+			case b.HostPort != "":
+				s = b.HostPort + ":" + s
+			}
+			pm, err := portutil.ParseFlagP(s)
+			if err != nil {
+				return netOpts, err
+			}
+			netOpts.PortMappings = append(netOpts.PortMappings, pm...)
+		}
+	}
+	return netOpts, nil
+}
+
+// restartPolicy converts the restart policy to the value of `--restart`.
+func restartPolicy(p dockercontainer.RestartPolicy) string {
+	switch {
+	case p.Name == "":
+		return "no"
+	case p.IsOnFailure() && p.MaximumRetryCount > 0:
+		return fmt.Sprintf("%s:%d", p.Name, p.MaximumRetryCount)
+	default:
+		return string(p.Name)
+	}
+}
+
+// mountString converts the mount to the value of `--mount`.
+func mountString(m mount.Mount) string {
+	fields := []string{"type=" + string(m.Type)}
+	if m.Source != "" {
+		fields = append(fields, "source="+m.Source)
+	}
+	fields = append(fields, "target="+m.Target)
+	if m.ReadOnly {
+		fields = append(fields, "readonly")
+	}
+	if m.BindOptions != nil && m.BindOptions.Propagation != "" {
+		fields = append(fields, "bind-propagation="+string(m.BindOptions.Propagation))
+	}
+	if m.TmpfsOptions != nil {
+		if m.TmpfsOptions.SizeBytes > 0 {
+			fields = append(fields, fmt.Sprintf("tmpfs-size=%d", m.TmpfsOptions.SizeBytes))
+		}
+		if m.TmpfsOptions.Mode != 0 {
+			fields = append(fields, fmt.Sprintf("tmpfs-mode=%o", m.TmpfsOptions.Mode))
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// kvStrings converts the map to the sorted "key=value" strings.
+func kvStrings(m map[string]string) []string {
+	res := make([]string, 0, len(m))
+	for k, v := range m {
+		res = append(res, k+"="+v)
+	}
+	sort.Strings(res)
+	return res
+}
@@ -0,0 +1,621 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	dockertypes "github.com/docker/docker/api/types"
+	dockercontainer "github.com/docker/docker/api/types/container"
+	"github.com/docker/docker/api/types/mount"
+	dockernetwork "github.com/docker/docker/api/types/network"
+	"github.com/docker/docker/pkg/stdcopy"
+	"github.com/docker/go-connections/nat"
+
+	apievents "github.com/containerd/containerd/api/events"
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/events"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/typeurl/v2"
+)
+
+func (s *Server) registerContainerRoutes() {
+	s.mux.Handle("GET /containers/json", s.handler(s.listContainers))
+	s.mux.Handle("POST /containers/create", s.handler(s.createContainer))
+	s.mux.Handle("GET /containers/{id}/json", s.handler(s.inspectContainer))
+	s.mux.Handle("GET /containers/{id}/logs", s.handler(s.containerLogs))
+	s.mux.Handle("POST /containers/{id}/start", s.handler(s.startContainer))
+	s.mux.Handle("POST /containers/{id}/stop", s.handler(s.stopContainer))
+	s.mux.Handle("POST /containers/{id}/restart", s.handler(s.restartContainer))
+	s.mux.Handle("POST /containers/{id}/kill", s.handler(s.killContainer))
+	s.mux.Handle("POST /containers/{id}/pause", s.handler(s.pauseContainer))
+	s.mux.Handle("POST /containers/{id}/unpause", s.handler(s.unpauseContainer))
+	s.mux.Handle("POST /containers/{id}/rename", s.handler(s.renameContainer))
+	s.mux.Handle("POST /containers/{id}/wait", s.handler(s.waitContainer))
+	s.mux.Handle("DELETE /containers/{id}", s.handler(s.removeContainer))
+}
+
+// container returns the container identified by the ID, the ID prefix, or the name.
+func (s *Server) container(ctx context.Context, req string) (containerd.Container, error) {
+	var found containerd.Container
+	walker := &containerwalker.ContainerWalker{
+		Client: s.client,
+		OnFound: func(ctx context.Context, f containerwalker.Found) error {
+			if f.MatchCount > 1 {
+				return errorWithStatus(http.StatusBadRequest, fmt.Errorf("multiple IDs found with provided prefix: %s", f.Req))
+			}
+			found = f.Container
+			return nil
+		},
+	}
+	n, err := walker.Walk(ctx, strings.TrimPrefix(req, "/"))
+	if err != nil {
+		return nil, err
+	}
+	if n == 0 {
+		return nil, errorWithStatus(http.StatusNotFound, fmt.Errorf("no such container: %s", req))
+	}
+	return found, nil
+}
+
+// inspectContainers returns the dockercompat inspection of the containers.
+func (s *Server) inspectContainers(ctx context.Context, ids []string, size bool) ([]dockercompat.Container, error) {
+	var buf bytes.Buffer
+	if err := container.Inspect(ctx, s.client, ids, types.ContainerInspectOptions{
+		Stdout:   &buf,
+		GOptions: s.options.GOptions,
+		Mode:     "dockercompat",
+		Size:     size,
+	}); err != nil {
+		return nil, err
+	}
+	var res []dockercompat.Container
+	if buf.Len() == 0 {
+		return res, nil
+	}
+	if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+func (s *Server) listContainers(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	filters, err := filterValues(r)
+	if err != nil {
+		return err
+	}
+	limit, err := intValue(r, "limit", -1)
+	if err != nil {
+		return err
+	}
+	items, err := container.List(ctx, s.client, types.ContainerListOptions{
+		GOptions: s.options.GOptions,
+		All:      boolValue(r, "all") || limit > 0,
+		LastN:    limit,
+		Filters:  filters,
+	})
+	if err != nil {
+		return err
+	}
+	res := make([]dockertypes.Container, 0, len(items))
+	if len(items) == 0 {
+		return writeJSON(w, http.StatusOK, res)
+	}
+	ids := make([]string, len(items))
+	for i, item := range items {
+		ids[i] = item.ID
+	}
+	inspected, err := s.inspectContainers(ctx, ids, boolValue(r, "size"))
+	if err != nil {
+		return err
+	}
+	byID := make(map[string]*dockercompat.Container, len(inspected))
+	for i := range inspected {
+		byID[inspected[i].ID] = &inspected[i]
+	}
+	for _, item := range items {
+		// The container may have been removed in the meantime
+		if c, ok := byID[item.ID]; ok {
+			res = append(res, containerSummary(item, c))
+		}
+	}
+	return writeJSON(w, http.StatusOK, res)
+}
+
+// containerSummary converts the container to the item of `GET /containers/json`.
+func containerSummary(item container.ListItem, c *dockercompat.Container) dockertypes.Container {
+	res := dockertypes.Container{
+		ID:      c.ID,
+		Names:   []string{"/" + c.Name},
+		Image:   c.Image,
+		Command: strings.Join(append([]string{c.Path}, c.Args...), " "),
+		Created: item.CreatedAt.Unix(),
+		Labels:  map[string]string{},
+		Status:  item.Status,
+	}
+	if c.SizeRw != nil {
+		res.SizeRw = *c.SizeRw
+	}
+	if c.SizeRootFs != nil {
+		res.SizeRootFs = *c.SizeRootFs
+	}
+	if c.State != nil {
+		res.State = c.State.Status
+	}
+	if c.Config != nil {
+		for k, v := range c.Config.Labels {
+			if !strings.HasPrefix(k, labels.Prefix) {
+				res.Labels[k] = v
+			}
+		}
+	}
+	var networks []string
+	if err := json.Unmarshal([]byte(item.LabelsMap[labels.Networks]), &networks); err == nil && len(networks) > 0 {
+		res.HostConfig.NetworkMode = networks[0]
+	}
+	res.NetworkSettings = &dockertypes.SummaryNetworkSettings{
+		Networks: map[string]*dockernetwork.EndpointSettings{},
+	}
+	if ns := c.NetworkSettings; ns != nil {
+		for name, n := range ns.Networks {
+			res.NetworkSettings.Networks[name] = &dockernetwork.EndpointSettings{
+				IPAddress:           n.IPAddress,
+				IPPrefixLen:         n.IPPrefixLen,
+				GlobalIPv6Address:   n.GlobalIPv6Address,
+				GlobalIPv6PrefixLen: n.GlobalIPv6PrefixLen,
+				MacAddress:          n.MacAddress,
+			}
+		}
+		if ns.Ports != nil {
+			res.Ports = summaryPorts(*ns.Ports)
+		}
+	}
+	if res.Ports == nil {
+		res.Ports = []dockertypes.Port{}
+	}
+	for _, m := range c.Mounts {
+		res.Mounts = append(res.Mounts, dockertypes.MountPoint{
+			Type:        mount.Type(m.Type),
+			Name:        m.Name,
+			Source:      m.Source,
+			Destination: m.Destination,
+			Driver:      m.Driver,
+			Mode:        m.Mode,
+			RW:          m.RW,
+			Propagation: mount.Propagation(m.Propagation),
+		})
+	}
+	return res
+}
+
+func summaryPorts(portMap nat.PortMap) []dockertypes.Port {
+	var res []dockertypes.Port
+	for p, bindings := range portMap {
+		for _, b := range bindings {
+			hostPort, _ := strconv.ParseUint(b.HostPort, 10, 16)
+			res = append(res, dockertypes.Port{
+				IP:          b.HostIP,
+				PrivatePort: uint16(p.Int()),
+				PublicPort:  uint16(hostPort),
+				Type:        p.Proto(),
+			})
+		}
+	}
+	return res
+}
+
+func (s *Server) inspectContainer(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	c, err := s.container(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	inspected, err := s.inspectContainers(ctx, []string{c.ID()}, boolValue(r, "size"))
+	if err != nil {
+		return err
+	}
+	if len(inspected) == 0 {
+		return errorWithStatus(http.StatusNotFound, fmt.Errorf("no such container: %s", r.PathValue("id")))
+	}
+	inspected[0].Name = "/" + inspected[0].Name
+	return writeJSON(w, http.StatusOK, inspected[0])
+}
+
+// isRunning returns true if the task of the container is running or paused.
+func isRunning(ctx context.Context, c containerd.Container) (bool, error) {
+	task, err := c.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return false, err
+	}
+	return status.Status != containerd.Stopped, nil
+}
+
+// stopTimeout returns the value of the "t" query parameter.
+func stopTimeout(r *http.Request) (*time.Duration, error) {
+	t, err := intValue(r, "t", -1)
+	if err != nil || t < 0 {
+		return nil, err
+	}
+	timeout := time.Duration(t) * time.Second
+	return &timeout, nil
+}
+
+func (s *Server) startContainer(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	c, err := s.container(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	if running, err := isRunning(ctx, c); err != nil {
+		return err
+	} else if running {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	if err := container.Start(ctx, s.client, []string{c.ID()}, types.ContainerStartOptions{
+		Stdout:   io.Discard,
+		GOptions: s.options.GOptions,
+	}); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) stopContainer(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	c, err := s.container(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	timeout, err := stopTimeout(r)
+	if err != nil {
+		return err
+	}
+	if running, err := isRunning(ctx, c); err != nil {
+		return err
+	} else if !running {
+		w.WriteHeader(http.StatusNotModified)
+		return nil
+	}
+	if err := container.Stop(ctx, s.client, []string{c.ID()}, types.ContainerStopOptions{
+		Stdout:   io.Discard,
+		Stderr:   io.Discard,
+		GOptions: s.options.GOptions,
+		Timeout:  timeout,
+	}); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) restartContainer(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	c, err := s.container(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	timeout, err := stopTimeout(r)
+	if err != nil {
+		return err
+	}
+	if err := container.Restart(ctx, s.client, []string{c.ID()}, types.ContainerRestartOptions{
+		Stdout:  io.Discard,
+		GOption: s.options.GOptions,
+		Timeout: timeout,
+	}); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) killContainer(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	c, err := s.container(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	if running, err := isRunning(ctx, c); err != nil {
+		return err
+	} else if !running {
+		return errorWithStatus(http.StatusConflict, fmt.Errorf("container %s is not running", r.PathValue("id")))
+	}
+	signal := r.FormValue("signal")
+	if signal == "" {
+		signal = "SIGKILL"
+	}
+	if err := container.Kill(ctx, s.client, []string{c.ID()}, types.ContainerKillOptions{
+		Stdout:     io.Discard,
+		Stderr:     io.Discard,
+		GOptions:   s.options.GOptions,
+		KillSignal: signal,
+	}); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) pauseContainer(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	c, err := s.container(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	if err := container.Pause(ctx, s.client, []string{c.ID()}, types.ContainerPauseOptions{
+		Stdout:   io.Discard,
+		GOptions: s.options.GOptions,
+	}); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) unpauseContainer(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	c, err := s.container(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	if err := container.Unpause(ctx, s.client, []string{c.ID()}, types.ContainerUnpauseOptions{
+		Stdout:   io.Discard,
+		GOptions: s.options.GOptions,
+	}); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+func (s *Server) renameContainer(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	c, err := s.container(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	name := strings.TrimPrefix(r.FormValue("name"), "/")
+	if name == "" {
+		return errorWithStatus(http.StatusBadRequest, fmt.Errorf("missing the new name"))
+	}
+	if err := s.checkNameConflict(ctx, name); err != nil {
+		return err
+	}
+	if err := container.Rename(ctx, s.client, c.ID(), name, types.ContainerRenameOptions{
+		Stdout:   io.Discard,
+		GOptions: s.options.GOptions,
+	}); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// checkNameConflict returns an error if the container name is already in use.
+func (s *Server) checkNameConflict(ctx context.Context, name string) error {
+	containers, err := s.client.Containers(ctx, fmt.Sprintf("labels.%q==%s", labels.Name, name))
+	if err != nil {
+		return err
+	}
+	if len(containers) > 0 {
+		return errorWithStatus(http.StatusConflict,
+			fmt.Errorf("the container name %q is already in use by container %q", "/"+name, containers[0].ID()))
+	}
+	return nil
+}
+
+func (s *Server) removeContainer(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	c, err := s.container(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	force := boolValue(r, "force")
+	if !force {
+		if running, err := isRunning(ctx, c); err != nil {
+			return err
+		} else if running {
+			return errorWithStatus(http.StatusConflict,
+				fmt.Errorf("cannot remove container %s: the container is running, stop the container before removing or force remove", r.PathValue("id")))
+		}
+	}
+	if err := container.Remove(ctx, s.client, []string{c.ID()}, types.ContainerRemoveOptions{
+		Stdout:   io.Discard,
+		GOptions: s.options.GOptions,
+		Force:    force,
+		Volumes:  boolValue(r, "v"),
+	}); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
+
+// waitContainer waits for the container to exit.
+// The status code is sent immediately, as the clients (e.g., `docker run`) wait for the response
+// header before starting the container.
+func (s *Server) waitContainer(w http.ResponseWriter, r *http.Request) error {
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	c, err := s.container(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	condition := r.FormValue("condition")
+	switch condition {
+	case "", "not-running", "next-exit", "removed":
+	default:
+		return errorWithStatus(http.StatusBadRequest, fmt.Errorf("invalid condition: %q", condition))
+	}
+	// Subscribe before checking the status, so that the exit is not missed
+	eventsCh, errCh := s.client.EventService().Subscribe(ctx,
+		fmt.Sprintf(`topic=="/tasks/exit",event.container_id==%q`, c.ID()),
+		fmt.Sprintf(`topic=="/containers/delete",event.id==%q`, c.ID()))
+	running, err := isRunning(ctx, c)
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+
+	var res dockercontainer.WaitResponse
+	var waitErr error
+	switch {
+	case running:
+		var buf bytes.Buffer
+		waitErr = container.Wait(ctx, s.client, []string{c.ID()}, types.ContainerWaitOptions{
+			Stdout:   &buf,
+			GOptions: s.options.GOptions,
+		})
+		if waitErr == nil {
+			res.StatusCode, waitErr = strconv.ParseInt(strings.TrimSpace(buf.String()), 10, 64)
+		}
+	case condition == "" || condition == "not-running":
+		var inspected []dockercompat.Container
+		inspected, waitErr = s.inspectContainers(ctx, []string{c.ID()}, false)
+		if waitErr == nil && len(inspected) > 0 && inspected[0].State != nil {
+			res.StatusCode = int64(inspected[0].State.ExitCode)
+		}
+	default:
+		res.StatusCode, waitErr = waitExitEvent(ctx, c.ID(), eventsCh, errCh)
+	}
+	if waitErr == nil && condition == "removed" {
+		waitErr = waitRemoveEvent(ctx, s.client, c.ID(), eventsCh, errCh)
+	}
+	if waitErr != nil {
+		res.Error = &dockercontainer.WaitExitError{Message: waitErr.Error()}
+	}
+	return json.NewEncoder(w).Encode(res)
+}
+
+// waitExitEvent waits for the "/tasks/exit" event of the init process of the container.
+func waitExitEvent(ctx context.Context, id string, eventsCh <-chan *events.Envelope, errCh <-chan error) (int64, error) {
+	for {
+		select {
+		case e := <-eventsCh:
+			v, err := typeurl.UnmarshalAny(e.Event)
+			if err != nil {
+				return -1, err
+			}
+			if ev, ok := v.(*apievents.TaskExit); ok && ev.ContainerID == id && ev.ID == id {
+				return int64(ev.ExitStatus), nil
+			}
+		case err := <-errCh:
+			return -1, err
+		}
+	}
+}
+
+// waitRemoveEvent waits for the "/containers/delete" event of the container.
+func waitRemoveEvent(ctx context.Context, client *containerd.Client, id string, eventsCh <-chan *events.Envelope, errCh <-chan error) error {
+	if _, err := client.LoadContainer(ctx, id); errdefs.IsNotFound(err) {
+		return nil
+	}
+	for {
+		select {
+		case e := <-eventsCh:
+			if e.Topic == "/containers/delete" {
+				return nil
+			}
+		case err := <-errCh:
+			return err
+		}
+	}
+}
+
+func (s *Server) containerLogs(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	c, err := s.container(ctx, r.PathValue("id"))
+	if err != nil {
+		return err
+	}
+	stdout, stderr := boolValue(r, "stdout"), boolValue(r, "stderr")
+	if !stdout && !stderr {
+		return errorWithStatus(http.StatusBadRequest, fmt.Errorf("you must choose at least one stream"))
+	}
+	var tail uint
+	if t := r.FormValue("tail"); t != "" && t != "all" {
+		n, err := strconv.ParseUint(t, 10, 32)
+		if err != nil {
+			return errorWithStatus(http.StatusBadRequest, fmt.Errorf("invalid value for \"tail\": %w", err))
+		}
+		tail = uint(n)
+	}
+	spec, err := c.Spec(ctx)
+	if err != nil {
+		return err
+	}
+	tty := spec.Process != nil && spec.Process.Terminal
+
+	fw := &flushWriter{w: w}
+	var outW, errW io.Writer = fw, fw
+	if tty {
+		w.Header().Set("Content-Type", "application/vnd.docker.raw-stream")
+	} else {
+		w.Header().Set("Content-Type", "application/vnd.docker.multiplexed-stream")
+		outW = stdcopy.NewStdWriter(fw, stdcopy.Stdout)
+		errW = stdcopy.NewStdWriter(fw, stdcopy.Stderr)
+	}
+	if !stdout {
+		outW = io.Discard
+	}
+	if !stderr {
+		errW = io.Discard
+	}
+	w.WriteHeader(http.StatusOK)
+	// The status code has already been sent, so the error can only be written to the stream
+	if err := container.Logs(ctx, s.client, c.ID(), types.ContainerLogsOptions{
+		Stdout:     outW,
+		Stderr:     errW,
+		GOptions:   s.options.GOptions,
+		Follow:     boolValue(r, "follow"),
+		Timestamps: boolValue(r, "timestamps"),
+		Tail:       tail,
+		Since:      r.FormValue("since"),
+		Until:      r.FormValue("until"),
+	}); err != nil && ctx.Err() == nil {
+		fmt.Fprintf(errW, "Error: %v\n", err)
+	}
+	return nil
+}
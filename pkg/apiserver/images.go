@@ -0,0 +1,271 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	dockerimage "github.com/docker/docker/api/types/image"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/imagewalker"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+)
+
+func (s *Server) registerImageRoutes() {
+	s.mux.Handle("GET /images/json", s.handler(s.listImages))
+	s.mux.Handle("POST /images/create", s.handler(s.pullImage))
+	// The image name may contain slashes, so the suffix of the path is dispatched by the handlers
+	s.mux.Handle("GET /images/{name...}", s.handler(s.inspectImage))
+	s.mux.Handle("POST /images/{name...}", s.handler(s.tagImage))
+	s.mux.Handle("DELETE /images/{name...}", s.handler(s.removeImage))
+}
+
+// imageName returns the image name of the path "/images/<name>/<suffix>".
+func imageName(r *http.Request, suffix string) (string, bool) {
+	if suffix == "" {
+		return r.PathValue("name"), true
+	}
+	return strings.CutSuffix(r.PathValue("name"), "/"+suffix)
+}
+
+// checkImage returns an error if the image does not exist.
+func (s *Server) checkImage(ctx context.Context, name string) error {
+	walker := &imagewalker.ImageWalker{
+		Client: s.client,
+		OnFound: func(ctx context.Context, found imagewalker.Found) error {
+			return nil
+		},
+	}
+	n, err := walker.Walk(ctx, name)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return errorWithStatus(http.StatusNotFound, fmt.Errorf("no such image: %s", name))
+	}
+	return nil
+}
+
+// inspectImage returns the dockercompat inspection of the image.
+func (s *Server) inspectImageByName(ctx context.Context, name string) (*dockercompat.Image, error) {
+	var buf bytes.Buffer
+	if err := image.Inspect(ctx, s.client, []string{name}, types.ImageInspectOptions{
+		Stdout:   &buf,
+		GOptions: s.options.GOptions,
+		Mode:     "dockercompat",
+	}); err != nil {
+		return nil, err
+	}
+	var res []dockercompat.Image
+	if buf.Len() > 0 {
+		if err := json.Unmarshal(buf.Bytes(), &res); err != nil {
+			return nil, err
+		}
+	}
+	if len(res) == 0 {
+		return nil, errorWithStatus(http.StatusNotFound, fmt.Errorf("no such image: %s", name))
+	}
+	return &res[0], nil
+}
+
+func (s *Server) listImages(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	filters, err := filterValues(r)
+	if err != nil {
+		return err
+	}
+	imageList, err := image.List(ctx, s.client, filters, nil)
+	if err != nil {
+		return err
+	}
+	res := []dockerimage.Summary{}
+	// The images that share the same target are listed once, with multiple RepoTags
+	seen := make(map[string]bool)
+	for _, img := range imageList {
+		dgst := img.Target.Digest.String()
+		if seen[dgst] {
+			continue
+		}
+		seen[dgst] = true
+		summary := dockerimage.Summary{
+			ID:          dgst,
+			Created:     img.CreatedAt.Unix(),
+			Labels:      map[string]string{},
+			RepoTags:    []string{},
+			RepoDigests: []string{},
+			Containers:  -1,
+			SharedSize:  -1,
+		}
+		if inspected, err := s.inspectImageByName(ctx, img.Name); err == nil {
+			summary.ID = inspected.ID
+			summary.ParentID = inspected.Parent
+			summary.RepoTags = append(summary.RepoTags, inspected.RepoTags...)
+			summary.RepoDigests = append(summary.RepoDigests, inspected.RepoDigests...)
+			summary.Size = inspected.Size
+			if created, err := time.Parse(time.RFC3339Nano, inspected.Created); err == nil {
+				summary.Created = created.Unix()
+			}
+			if inspected.Config != nil && inspected.Config.Labels != nil {
+				summary.Labels = inspected.Config.Labels
+			}
+		}
+		res = append(res, summary)
+	}
+	return writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) inspectImage(w http.ResponseWriter, r *http.Request) error {
+	name, ok := imageName(r, "json")
+	if !ok {
+		return errorWithStatus(http.StatusNotFound, errors.New("page not found"))
+	}
+	inspected, err := s.inspectImageByName(r.Context(), name)
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, inspected)
+}
+
+func (s *Server) tagImage(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	name, ok := imageName(r, "tag")
+	if !ok {
+		return errorWithStatus(http.StatusNotFound, errors.New("page not found"))
+	}
+	repo := r.FormValue("repo")
+	if repo == "" {
+		return errorWithStatus(http.StatusBadRequest, errors.New("repository name must have at least one component"))
+	}
+	if tag := r.FormValue("tag"); tag != "" {
+		repo += ":" + tag
+	}
+	if err := s.checkImage(ctx, name); err != nil {
+		return err
+	}
+	if err := image.Tag(ctx, s.client, types.ImageTagOptions{
+		GOptions: s.options.GOptions,
+		Source:   name,
+		Target:   repo,
+	}); err != nil {
+		return errorWithStatus(http.StatusBadRequest, err)
+	}
+	w.WriteHeader(http.StatusCreated)
+	return nil
+}
+
+func (s *Server) removeImage(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	name, _ := imageName(r, "")
+	if err := s.checkImage(ctx, name); err != nil {
+		return err
+	}
+	var buf bytes.Buffer
+	if err := image.Remove(ctx, s.client, []string{name}, types.ImageRemoveOptions{
+		Stdout:   &buf,
+		GOptions: s.options.GOptions,
+		Force:    boolValue(r, "force"),
+	}); err != nil {
+		// image.Remove does not return typed errors for the images used by containers
+		if strings.Contains(err.Error(), "conflict:") {
+			return errorWithStatus(http.StatusConflict, err)
+		}
+		return err
+	}
+	// Parse the same output as `nerdctl rmi`
+	res := []dockerimage.DeleteResponse{}
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		if v, ok := strings.CutPrefix(scanner.Text(), "Untagged: "); ok {
+			res = append(res, dockerimage.DeleteResponse{Untagged: v})
+		} else if v, ok := strings.CutPrefix(scanner.Text(), "Deleted: "); ok {
+			res = append(res, dockerimage.DeleteResponse{Deleted: v})
+		}
+	}
+	return writeJSON(w, http.StatusOK, res)
+}
+
+// progressMessage is a message of the progress stream, compatible with the Docker Engine API.
+type progressMessage struct {
+	Status string `json:"status,omitempty"`
+	ID     string `json:"id,omitempty"`
+	Error  *struct {
+		Message string `json:"message,omitempty"`
+	} `json:"errorDetail,omitempty"`
+	ErrorMessage string `json:"error,omitempty"`
+}
+
+// pullImage pulls the image with `nerdctl pull`.
+// The progress of the pull is not streamed; the stream only contains the status messages.
+func (s *Server) pullImage(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	ref := r.FormValue("fromImage")
+	if ref == "" {
+		if r.FormValue("fromSrc") != "" {
+			return errorWithStatus(http.StatusNotImplemented, errors.New("importing an image is not supported"))
+		}
+		return errorWithStatus(http.StatusBadRequest, errors.New("fromImage must be specified"))
+	}
+	tag := r.FormValue("tag")
+	switch {
+	case strings.HasPrefix(tag, "sha256:"):
+		ref += "@" + tag
+	case tag != "":
+		ref += ":" + tag
+	}
+	var platforms []string
+	if p := r.FormValue("platform"); p != "" {
+		platforms = append(platforms, p)
+	}
+	ociSpecPlatform, err := platformutil.NewOCISpecPlatformSlice(false, platforms)
+	if err != nil {
+		return errorWithStatus(http.StatusBadRequest, err)
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	enc := json.NewEncoder(&flushWriter{w: w})
+	enc.Encode(progressMessage{Status: "Pulling from " + r.FormValue("fromImage"), ID: tag})
+	// The status code has already been sent, so the error is written to the stream
+	if err := image.Pull(ctx, s.client, ref, types.ImagePullOptions{
+		Stdout:          io.Discard,
+		Stderr:          io.Discard,
+		GOptions:        s.options.GOptions,
+		VerifyOptions:   types.ImageVerifyOptions{Provider: "none"},
+		OCISpecPlatform: ociSpecPlatform,
+		Mode:            "always",
+		Quiet:           true,
+	}); err != nil {
+		msg := progressMessage{ErrorMessage: err.Error()}
+		msg.Error = &struct {
+			Message string `json:"message,omitempty"`
+		}{Message: err.Error()}
+		return enc.Encode(msg)
+	}
+	return enc.Encode(progressMessage{Status: "Status: Downloaded newer image for " + ref})
+}
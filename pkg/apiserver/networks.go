@@ -0,0 +1,263 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strings"
+
+	dockernetwork "github.com/docker/docker/api/types/network"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/network"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+)
+
+func (s *Server) registerNetworkRoutes() {
+	s.mux.Handle("GET /networks", s.handler(s.listNetworks))
+	s.mux.Handle("POST /networks/create", s.handler(s.createNetwork))
+	s.mux.Handle("GET /networks/{id}", s.handler(s.inspectNetwork))
+	s.mux.Handle("DELETE /networks/{id}", s.handler(s.removeNetwork))
+}
+
+// pseudoNetworks are the networks that are not backed by CNI configs, and their drivers.
+var pseudoNetworks = map[string]string{
+	"host": "host",
+	"none": "null",
+}
+
+// dockerNetwork converts the network to the Docker-compatible network.
+func dockerNetwork(n *native.Network) (*dockernetwork.Inspect, error) {
+	compat, err := dockercompat.NetworkFromNative(n)
+	if err != nil {
+		return nil, err
+	}
+	res := &dockernetwork.Inspect{
+		Name:       compat.Name,
+		ID:         compat.ID,
+		Scope:      "local",
+		IPAM:       dockernetwork.IPAM{Driver: "default", Config: []dockernetwork.IPAMConfig{}},
+		Containers: map[string]dockernetwork.EndpointResource{},
+		Options:    map[string]string{},
+		Labels:     map[string]string{},
+	}
+	for _, c := range compat.IPAM.Config {
+		res.IPAM.Config = append(res.IPAM.Config, dockernetwork.IPAMConfig{
+			Subnet:  c.Subnet,
+			IPRange: c.IPRange,
+			Gateway: c.Gateway,
+		})
+	}
+	if compat.Labels != nil {
+		res.Labels = compat.Labels
+	}
+	// The driver is the type of the main CNI plugin
+	var cni struct {
+		Plugins []struct {
+			Type string `json:"type"`
+		} `json:"plugins"`
+	}
+	if err := json.Unmarshal(n.CNI, &cni); err == nil && len(cni.Plugins) > 0 {
+		res.Driver = cni.Plugins[0].Type
+	}
+	return res, nil
+}
+
+// inspectNetworks returns the Docker-compatible inspection of the CNI networks.
+func (s *Server) inspectNetworks(ctx context.Context, names []string) ([]*dockernetwork.Inspect, error) {
+	var buf bytes.Buffer
+	err := network.Inspect(ctx, types.NetworkInspectOptions{
+		Stdout:   &buf,
+		GOptions: s.options.GOptions,
+		Mode:     "native",
+		Networks: names,
+	})
+	// network.Inspect prints the found networks, even on error
+	var nativeNetworks []native.Network
+	if buf.Len() > 0 {
+		if jsonErr := json.Unmarshal(buf.Bytes(), &nativeNetworks); jsonErr != nil {
+			return nil, jsonErr
+		}
+	}
+	if len(nativeNetworks) == 0 && err != nil {
+		return nil, err
+	}
+	res := make([]*dockernetwork.Inspect, 0, len(nativeNetworks))
+	for i := range nativeNetworks {
+		n, err := dockerNetwork(&nativeNetworks[i])
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, n)
+	}
+	return res, nil
+}
+
+// networkNames returns the names of the networks, including the pseudo networks.
+func (s *Server) networkNames(ctx context.Context, filters []string) ([]string, error) {
+	var buf bytes.Buffer
+	if err := network.List(ctx, types.NetworkListOptions{
+		Stdout:   &buf,
+		GOptions: s.options.GOptions,
+		Format:   "{{.Name}}",
+		Filters:  filters,
+	}); err != nil {
+		return nil, err
+	}
+	var names []string
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		if name := strings.TrimSpace(scanner.Text()); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names, scanner.Err()
+}
+
+func (s *Server) listNetworks(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	filters, err := filterValues(r)
+	if err != nil {
+		return err
+	}
+	names, err := s.networkNames(ctx, filters)
+	if err != nil {
+		return err
+	}
+	var cniNames []string
+	res := []*dockernetwork.Inspect{}
+	for _, name := range names {
+		if driver, ok := pseudoNetworks[name]; ok {
+			res = append(res, &dockernetwork.Inspect{
+				Name:       name,
+				Scope:      "local",
+				Driver:     driver,
+				Containers: map[string]dockernetwork.EndpointResource{},
+				Options:    map[string]string{},
+				Labels:     map[string]string{},
+			})
+			continue
+		}
+		cniNames = append(cniNames, name)
+	}
+	if len(cniNames) > 0 {
+		inspected, err := s.inspectNetworks(ctx, cniNames)
+		if err != nil {
+			return err
+		}
+		res = append(inspected, res...)
+	}
+	return writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) inspectNetwork(w http.ResponseWriter, r *http.Request) error {
+	id := r.PathValue("id")
+	if _, ok := pseudoNetworks[id]; ok {
+		return errorWithStatus(http.StatusNotImplemented, fmt.Errorf("inspecting the pseudo network %q is not supported", id))
+	}
+	inspected, err := s.inspectNetworks(r.Context(), []string{id})
+	if err != nil || len(inspected) == 0 {
+		return errorWithStatus(http.StatusNotFound, fmt.Errorf("network %s not found", id))
+	}
+	return writeJSON(w, http.StatusOK, inspected[0])
+}
+
+func (s *Server) createNetwork(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	var req dockernetwork.CreateRequest
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	if req.Name == "" {
+		return errorWithStatus(http.StatusBadRequest, errors.New("network name must be specified"))
+	}
+	names, err := s.networkNames(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if slices.Contains(names, req.Name) {
+		return errorWithStatus(http.StatusConflict, fmt.Errorf("network with name %s already exists", req.Name))
+	}
+	options := types.NetworkCreateOptions{
+		GOptions:   s.options.GOptions,
+		Name:       req.Name,
+		Driver:     req.Driver,
+		Options:    req.Options,
+		IPAMDriver: "default",
+		Labels:     kvStrings(req.Labels),
+	}
+	if options.Driver == "" {
+		// The default network driver has the same name as the default network ("bridge", or "nat" on Windows)
+		options.Driver = netutil.DefaultNetworkName
+	}
+	if req.EnableIPv6 != nil {
+		options.IPv6 = *req.EnableIPv6
+	}
+	if ipam := req.IPAM; ipam != nil {
+		if ipam.Driver != "" {
+			options.IPAMDriver = ipam.Driver
+		}
+		options.IPAMOptions = ipam.Options
+		for _, c := range ipam.Config {
+			options.Subnets = append(options.Subnets, c.Subnet)
+			if c.Gateway != "" {
+				options.Gateway = c.Gateway
+			}
+			if c.IPRange != "" {
+				options.IPRange = c.IPRange
+			}
+		}
+	}
+	var buf bytes.Buffer
+	if err := network.Create(options, &buf); err != nil {
+		return errorWithStatus(http.StatusBadRequest, err)
+	}
+	return writeJSON(w, http.StatusCreated, dockernetwork.CreateResponse{
+		ID: strings.TrimSpace(buf.String()),
+	})
+}
+
+func (s *Server) removeNetwork(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	id := r.PathValue("id")
+	if _, ok := pseudoNetworks[id]; ok {
+		return errorWithStatus(http.StatusForbidden, fmt.Errorf("%s is a pre-defined network and cannot be removed", id))
+	}
+	if inspected, err := s.inspectNetworks(ctx, []string{id}); err != nil || len(inspected) == 0 {
+		return errorWithStatus(http.StatusNotFound, fmt.Errorf("network %s not found", id))
+	}
+	if err := network.Remove(ctx, s.client, types.NetworkRemoveOptions{
+		Stdout:   io.Discard,
+		GOptions: s.options.GOptions,
+		Networks: []string{id},
+	}); err != nil {
+		// The network is in use, or managed outside nerdctl
+		return errorWithStatus(http.StatusForbidden, err)
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
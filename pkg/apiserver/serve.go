@@ -0,0 +1,85 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+// Serve serves the Docker Engine API on the unix socket, until the context is cancelled.
+func Serve(ctx context.Context, client *containerd.Client, options types.SystemServeOptions) error {
+	sock := options.Socket
+	if sock == "" {
+		return errors.New("socket path must be specified")
+	}
+	if err := os.MkdirAll(filepath.Dir(sock), 0755); err != nil {
+		return err
+	}
+	// Remove the stale socket, but do not steal the socket of a running server
+	if conn, err := net.Dial("unix", sock); err == nil {
+		conn.Close()
+		return fmt.Errorf("socket %q is already in use", sock)
+	}
+	if err := os.Remove(sock); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	l, err := net.Listen("unix", sock)
+	if err != nil {
+		return err
+	}
+	defer l.Close()
+	if err := os.Chmod(sock, 0660); err != nil {
+		return err
+	}
+
+	srv := &http.Server{
+		Handler: New(client, Options{
+			GOptions:    options.GOptions,
+			NerdctlCmd:  options.NerdctlCmd,
+			NerdctlArgs: options.NerdctlArgs,
+		}),
+		// The streaming requests (e.g., `GET /events`) are cancelled with ctx
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+		ReadHeaderTimeout: 30 * time.Second,
+	}
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- srv.Serve(l)
+	}()
+	fmt.Fprintf(options.Stdout, "Serving the Docker Engine API on unix://%s (namespace %q)\n", sock, options.GOptions.Namespace)
+
+	select {
+	case err := <-errCh:
+		return err
+	case <-ctx.Done():
+		log.G(ctx).Debug("shutting down the API server")
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		return srv.Shutdown(shutdownCtx)
+	}
+}
@@ -0,0 +1,135 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"runtime"
+
+	"github.com/docker/docker/api"
+	dockertypes "github.com/docker/docker/api/types"
+
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+	"github.com/containerd/nerdctl/v2/pkg/infoutil"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/version"
+)
+
+func (s *Server) registerSystemRoutes() {
+	s.mux.Handle("GET /_ping", s.handler(s.ping))
+	s.mux.Handle("HEAD /_ping", s.handler(s.ping))
+	s.mux.Handle("GET /version", s.handler(s.version))
+	s.mux.Handle("GET /info", s.handler(s.info))
+	s.mux.Handle("GET /events", s.handler(s.events))
+}
+
+func (s *Server) ping(w http.ResponseWriter, r *http.Request) error {
+	w.Header().Set("Cache-Control", "no-cache, no-store, must-revalidate")
+	w.Header().Set("Pragma", "no-cache")
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	if r.Method == http.MethodGet {
+		_, err := io.WriteString(w, "OK")
+		return err
+	}
+	return nil
+}
+
+func (s *Server) version(w http.ResponseWriter, r *http.Request) error {
+	v := dockertypes.Version{
+		Version:       version.GetVersion(),
+		APIVersion:    api.DefaultVersion,
+		MinAPIVersion: api.MinSupportedAPIVersion,
+		GitCommit:     version.GetRevision(),
+		GoVersion:     runtime.Version(),
+		Os:            runtime.GOOS,
+		Arch:          runtime.GOARCH,
+	}
+	v.Platform.Name = "nerdctl"
+	v.Components = append(v.Components, dockertypes.ComponentVersion{
+		Name:    "nerdctl",
+		Version: v.Version,
+		Details: map[string]string{"GitCommit": v.GitCommit},
+	})
+	sv, err := infoutil.ServerVersion(r.Context(), s.client)
+	if err != nil {
+		return err
+	}
+	for _, c := range sv.Components {
+		v.Components = append(v.Components, dockertypes.ComponentVersion{
+			Name:    c.Name,
+			Version: c.Version,
+			Details: c.Details,
+		})
+	}
+	return writeJSON(w, http.StatusOK, v)
+}
+
+func (s *Server) info(w http.ResponseWriter, r *http.Request) error {
+	var buf bytes.Buffer
+	if err := system.Info(r.Context(), s.client, types.SystemInfoOptions{
+		Stdout:   &buf,
+		Stderr:   io.Discard,
+		GOptions: s.options.GOptions,
+		Mode:     "dockercompat",
+		Format:   "json",
+	}); err != nil {
+		return err
+	}
+	var info dockercompat.Info
+	if err := json.Unmarshal(buf.Bytes(), &info); err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, info)
+}
+
+// events streams the events until the client disconnects, or until the "until" timestamp.
+func (s *Server) events(w http.ResponseWriter, r *http.Request) error {
+	filters, err := filterValues(r)
+	if err != nil {
+		return err
+	}
+	// The server does not expose the other namespaces
+	filters = append(filters, "namespace="+s.options.GOptions.Namespace)
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	fw := &flushWriter{w: w}
+	if f, ok := w.(http.Flusher); ok {
+		f.Flush()
+	}
+	err = system.Events(r.Context(), s.client, types.SystemEventsOptions{
+		Stdout:   fw,
+		GOptions: s.options.GOptions,
+		Format:   "json",
+		Filters:  filters,
+		Since:    r.FormValue("since"),
+		Until:    r.FormValue("until"),
+	})
+	// The status code has already been sent, so the error can only be logged
+	if err != nil && !errors.Is(r.Context().Err(), context.Canceled) {
+		log.G(r.Context()).WithError(err).Warn("failed to stream the events")
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package apiserver
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+
+	dockervolume "github.com/docker/docker/api/types/volume"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/native"
+)
+
+func (s *Server) registerVolumeRoutes() {
+	s.mux.Handle("GET /volumes", s.handler(s.listVolumes))
+	s.mux.Handle("POST /volumes/create", s.handler(s.createVolume))
+	s.mux.Handle("GET /volumes/{name}", s.handler(s.inspectVolume))
+	s.mux.Handle("DELETE /volumes/{name}", s.handler(s.removeVolume))
+}
+
+// dockerVolume converts the volume to the Docker-compatible volume.
+func dockerVolume(vol native.Volume) *dockervolume.Volume {
+	res := &dockervolume.Volume{
+		Name:       vol.Name,
+		Driver:     "local",
+		Mountpoint: vol.Mountpoint,
+		Labels:     map[string]string{},
+		Options:    map[string]string{},
+		Scope:      "local",
+	}
+	if vol.Labels != nil {
+		res.Labels = *vol.Labels
+	}
+	return res
+}
+
+func (s *Server) volumes(filters []string) (map[string]native.Volume, error) {
+	gOpts := s.options.GOptions
+	return volume.Volumes(gOpts.Namespace, gOpts.DataRoot, gOpts.Address, false, filters)
+}
+
+func (s *Server) volume(name string) (*native.Volume, error) {
+	vols, err := s.volumes(nil)
+	if err != nil {
+		return nil, err
+	}
+	vol, ok := vols[name]
+	if !ok {
+		return nil, errorWithStatus(http.StatusNotFound, fmt.Errorf("no such volume: %s", name))
+	}
+	return &vol, nil
+}
+
+func (s *Server) listVolumes(w http.ResponseWriter, r *http.Request) error {
+	filters, err := filterValues(r)
+	if err != nil {
+		return err
+	}
+	vols, err := s.volumes(filters)
+	if err != nil {
+		return err
+	}
+	res := dockervolume.ListResponse{
+		Volumes:  []*dockervolume.Volume{},
+		Warnings: []string{},
+	}
+	for _, vol := range vols {
+		res.Volumes = append(res.Volumes, dockerVolume(vol))
+	}
+	sort.Slice(res.Volumes, func(i, j int) bool {
+		return res.Volumes[i].Name < res.Volumes[j].Name
+	})
+	return writeJSON(w, http.StatusOK, res)
+}
+
+func (s *Server) createVolume(w http.ResponseWriter, r *http.Request) error {
+	var req dockervolume.CreateOptions
+	if err := decodeBody(r, &req); err != nil {
+		return err
+	}
+	if req.Driver != "" && req.Driver != "local" {
+		return errorWithStatus(http.StatusBadRequest, fmt.Errorf("unsupported volume driver: %q", req.Driver))
+	}
+	if len(req.DriverOpts) > 0 {
+		return errorWithStatus(http.StatusBadRequest, fmt.Errorf("volume driver options are not supported"))
+	}
+	vol, err := volume.Create(req.Name, types.VolumeCreateOptions{
+		Stdout:   io.Discard,
+		GOptions: s.options.GOptions,
+		Labels:   kvStrings(req.Labels),
+	})
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusCreated, dockerVolume(*vol))
+}
+
+func (s *Server) inspectVolume(w http.ResponseWriter, r *http.Request) error {
+	vol, err := s.volume(r.PathValue("name"))
+	if err != nil {
+		return err
+	}
+	return writeJSON(w, http.StatusOK, dockerVolume(*vol))
+}
+
+func (s *Server) removeVolume(w http.ResponseWriter, r *http.Request) error {
+	ctx := r.Context()
+	name := r.PathValue("name")
+	if _, err := s.volume(name); err != nil {
+		return err
+	}
+	// volume.Remove only logs the reason of the failure, so the usage is checked here
+	containers, err := s.client.Containers(ctx)
+	if err != nil {
+		return err
+	}
+	used, err := volume.UsedVolumes(ctx, containers)
+	if err != nil {
+		return err
+	}
+	if _, ok := used[name]; ok {
+		return errorWithStatus(http.StatusConflict, fmt.Errorf("volume %q is in use", name))
+	}
+	if err := volume.Remove(ctx, s.client, []string{name}, types.VolumeRemoveOptions{
+		Stdout:   io.Discard,
+		GOptions: s.options.GOptions,
+	}); err != nil {
+		return err
+	}
+	w.WriteHeader(http.StatusNoContent)
+	return nil
+}
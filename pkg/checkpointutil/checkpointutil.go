@@ -0,0 +1,88 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package checkpointutil provides utilities for the CRIU checkpoints of containers.
+//
+// A checkpoint is a directory of CRIU images, named after the checkpoint.
+// By default, the checkpoints are stored in the "checkpoints" directory under the container state dir,
+// so that they are removed along with the container.
+package checkpointutil
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd/v2/pkg/identifiers"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+const checkpointsDir = "checkpoints"
+
+// Dir returns the directory that contains the checkpoints of the container with `containerLabels`.
+// If `checkpointDir` is not empty, it is returned as an absolute path.
+func Dir(containerLabels map[string]string, checkpointDir string) (string, error) {
+	if checkpointDir != "" {
+		return filepath.Abs(checkpointDir)
+	}
+	stateDir := containerLabels[labels.StateDir]
+	if stateDir == "" {
+		return "", fmt.Errorf("label %q is not set", labels.StateDir)
+	}
+	return filepath.Join(stateDir, checkpointsDir), nil
+}
+
+// Path returns the path of the checkpoint `name` in `dir`.
+func Path(dir, name string) (string, error) {
+	if err := identifiers.Validate(name); err != nil {
+		return "", fmt.Errorf("invalid checkpoint name: %w", err)
+	}
+	return filepath.Join(dir, name), nil
+}
+
+// Exists returns true if the checkpoint directory `path` exists.
+func Exists(path string) (bool, error) {
+	st, err := os.Stat(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	if !st.IsDir() {
+		return false, fmt.Errorf("%q is not a directory", path)
+	}
+	return true, nil
+}
+
+// List returns the names of the checkpoints in `dir`.
+func List(dir string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if e.IsDir() {
+			names = append(names, e.Name())
+		}
+	}
+	return names, nil
+}
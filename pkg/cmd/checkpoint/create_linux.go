@@ -0,0 +1,175 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	runcoptions "github.com/containerd/containerd/api/types/runc/options"
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/cio"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/checkpointutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
+)
+
+// Create creates the checkpoint `name` of the running container `req` with CRIU.
+// Unless options.LeaveRunning is set, the container is stopped after the checkpoint is created.
+func Create(ctx context.Context, client *containerd.Client, req, name string, options types.CheckpointCreateOptions) error {
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			return createCheckpoint(ctx, found.Container, name, options)
+		},
+	}
+	if n, err := walker.Walk(ctx, req); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no such container %s", req)
+	}
+	_, err := fmt.Fprintln(options.Stdout, name)
+	return err
+}
+
+func createCheckpoint(ctx context.Context, container containerd.Container, name string, options types.CheckpointCreateOptions) error {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	dir, err := checkpointutil.Dir(lab, options.CheckpointDir)
+	if err != nil {
+		return err
+	}
+	path, err := checkpointutil.Path(dir, name)
+	if err != nil {
+		return err
+	}
+	if exists, err := checkpointutil.Exists(path); err != nil {
+		return err
+	} else if exists {
+		return fmt.Errorf("checkpoint with name %s already exists for container %s", name, container.ID())
+	}
+
+	task, err := container.Task(ctx, cio.Load)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return fmt.Errorf("container %s is not running", container.ID())
+		}
+		return err
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return err
+	}
+	switch status.Status {
+	case containerd.Running, containerd.Paused:
+	default:
+		return fmt.Errorf("container %s is not running", container.ID())
+	}
+
+	// The network namespace of a CNI container is checkpointed empty, as the interfaces
+	// are connected to the host. The OCI hook sets up the networks again on restore.
+	emptyNamespaces, err := checkpointEmptyNamespaces(lab)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return err
+	}
+
+	exit := !options.LeaveRunning
+	var statusC <-chan containerd.ExitStatus
+	if exit {
+		// Prevent the restart manager from restarting the container stopped by the checkpoint
+		if err := containerutil.UpdateExplicitlyStoppedLabel(ctx, container, true); err != nil {
+			return err
+		}
+		statusC, err = task.Wait(ctx)
+		if err != nil {
+			return err
+		}
+	}
+	if _, err := task.Checkpoint(ctx, withCheckpointOptions(exit, emptyNamespaces), containerd.WithCheckpointImagePath(path)); err != nil {
+		if removeErr := os.RemoveAll(path); removeErr != nil {
+			log.G(ctx).WithError(removeErr).Warnf("failed to remove checkpoint %q", path)
+		}
+		if exit {
+			if labelErr := containerutil.UpdateExplicitlyStoppedLabel(ctx, container, false); labelErr != nil {
+				log.G(ctx).WithError(labelErr).Warnf("failed to update the labels of container %s", container.ID())
+			}
+		}
+		return fmt.Errorf("failed to checkpoint container %s: %w", container.ID(), err)
+	}
+	if exit {
+		select {
+		case <-statusC:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		// Delete the task so that the postStop hook releases the networks before the container is restored
+		if _, err := task.Delete(ctx); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func checkpointEmptyNamespaces(containerLabels map[string]string) ([]string, error) {
+	networksJSON, ok := containerLabels[labels.Networks]
+	if !ok {
+		return nil, nil
+	}
+	var networks []string
+	if err := json.Unmarshal([]byte(networksJSON), &networks); err != nil {
+		return nil, err
+	}
+	netType, err := nettype.Detect(networks)
+	if err != nil {
+		return nil, err
+	}
+	if netType == nettype.CNI {
+		return []string{"network"}, nil
+	}
+	return nil, nil
+}
+
+func withCheckpointOptions(exit bool, emptyNamespaces []string) containerd.CheckpointTaskOpts {
+	return func(r *containerd.CheckpointTaskInfo) error {
+		if r.Options == nil {
+			r.Options = &runcoptions.CheckpointOptions{}
+		}
+		opts, ok := r.Options.(*runcoptions.CheckpointOptions)
+		if !ok {
+			return errors.New("invalid runtime v2 checkpoint options format")
+		}
+		opts.Exit = exit
+		opts.EmptyNamespaces = emptyNamespaces
+		return nil
+	}
+}
@@ -0,0 +1,63 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"text/tabwriter"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/checkpointutil"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+)
+
+// List prints the checkpoints of the container `req`.
+func List(ctx context.Context, client *containerd.Client, req string, options types.CheckpointListOptions) error {
+	var names []string
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			lab, err := found.Container.Labels(ctx)
+			if err != nil {
+				return err
+			}
+			dir, err := checkpointutil.Dir(lab, options.CheckpointDir)
+			if err != nil {
+				return err
+			}
+			names, err = checkpointutil.List(dir)
+			return err
+		},
+	}
+	if n, err := walker.Walk(ctx, req); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no such container %s", req)
+	}
+
+	w := tabwriter.NewWriter(options.Stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "CHECKPOINT NAME")
+	for _, name := range names {
+		fmt.Fprintln(w, name)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,64 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package checkpoint
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/checkpointutil"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+)
+
+// Remove removes the checkpoint `name` of the container `req`.
+func Remove(ctx context.Context, client *containerd.Client, req, name string, options types.CheckpointRemoveOptions) error {
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			lab, err := found.Container.Labels(ctx)
+			if err != nil {
+				return err
+			}
+			dir, err := checkpointutil.Dir(lab, options.CheckpointDir)
+			if err != nil {
+				return err
+			}
+			path, err := checkpointutil.Path(dir, name)
+			if err != nil {
+				return err
+			}
+			if exists, err := checkpointutil.Exists(path); err != nil {
+				return err
+			} else if !exists {
+				return fmt.Errorf("checkpoint %s does not exist for container %s", name, found.Container.ID())
+			}
+			return os.RemoveAll(path)
+		},
+	}
+	if n, err := walker.Walk(ctx, req); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no such container %s", req)
+	}
+	return nil
+}
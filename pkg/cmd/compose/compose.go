@@ -42,6 +42,7 @@ import (
 
 // New returns a new *composer.Composer.
 func New(client *containerd.Client, globalOptions types.GlobalCommandOptions, options composer.Options, stdout, stderr io.Writer) (*composer.Composer, error) {
+	options.GOptions = globalOptions
 	cniEnv, err := netutil.NewCNIEnv(globalOptions.CNIPath, globalOptions.CNINetConfPath, netutil.WithNamespace(globalOptions.Namespace), netutil.WithDefaultNetwork())
 	if err != nil {
 		return nil, err
@@ -140,7 +141,7 @@ func New(client *containerd.Client, globalOptions types.GlobalCommandOptions, op
 			return err
 		}
 
-		imageVerifyOptions := imageVerifyOptionsFromCompose(ps)
+		imageVerifyOptions := composer.ImageVerifyOptions(ps)
 		ref, err := signutil.Verify(ctx, imageName, globalOptions.HostsDir, globalOptions.Experimental, imageVerifyOptions)
 		if err != nil {
 			return err
@@ -152,30 +153,3 @@ func New(client *containerd.Client, globalOptions types.GlobalCommandOptions, op
 
 	return composer.New(options, client)
 }
-
-func imageVerifyOptionsFromCompose(ps *serviceparser.Service) types.ImageVerifyOptions {
-	var opt types.ImageVerifyOptions
-	if verifier, ok := ps.Unparsed.Extensions[serviceparser.ComposeVerify]; ok {
-		opt.Provider = verifier.(string)
-	} else {
-		opt.Provider = "none"
-	}
-
-	// for cosign, if key is given, use key mode, otherwise use keyless mode.
-	if keyVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignPublicKey]; ok {
-		opt.CosignKey = keyVal.(string)
-	}
-	if ciVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateIdentity]; ok {
-		opt.CosignCertificateIdentity = ciVal.(string)
-	}
-	if cirVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateIdentityRegexp]; ok {
-		opt.CosignCertificateIdentityRegexp = cirVal.(string)
-	}
-	if coiVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateOidcIssuer]; ok {
-		opt.CosignCertificateOidcIssuer = coiVal.(string)
-	}
-	if coirVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateOidcIssuerRegexp]; ok {
-		opt.CosignCertificateOidcIssuerRegexp = coirVal.(string)
-	}
-	return opt
-}
@@ -45,7 +45,9 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
 	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/defaults"
 	"github.com/containerd/nerdctl/v2/pkg/flagutil"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/idgen"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
 	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
@@ -61,6 +63,39 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
+// DefaultInitBinary is the init binary used when InitProcessFlag is set without InitBinary.
+const DefaultInitBinary = "tini"
+
+// NewCreateOptions returns the options with the same default values as the flags of `nerdctl create`.
+// NewCreateOptions is useful for creating containers without parsing the CLI flags.
+func NewCreateOptions(globalOptions types.GlobalCommandOptions) types.ContainerCreateOptions {
+	return types.ContainerCreateOptions{
+		GOptions:           globalOptions,
+		Detach:             true,
+		Restart:            "no",
+		Pull:               "missing",
+		StopSignal:         "SIGTERM",
+		Isolation:          "default",
+		CPUQuota:           -1,
+		MemorySwappiness64: -1,
+		PidsLimit:          -1,
+		Cgroupns:           defaults.CgroupnsMode(),
+		GroupAdd:           []string{},
+		SecurityOpt:        []string{},
+		CapAdd:             []string{},
+		CapDrop:            []string{},
+		Systemd:            "false",
+		Runtime:            defaults.Runtime,
+		LogDriver:          "json-file",
+		ImagePullOpt: types.ImagePullOptions{
+			GOptions: globalOptions,
+			VerifyOptions: types.ImageVerifyOptions{
+				Provider: "none",
+			},
+		},
+	}
+}
+
 // Create will create a container.
 func Create(ctx context.Context, client *containerd.Client, args []string, netManager containerutil.NetworkOptionsManager, options types.ContainerCreateOptions) (containerd.Container, func(), error) {
 	// Acquire an exclusive lock on the volume store until we are done to avoid being raced by other volume operations
@@ -175,6 +210,11 @@ func Create(ctx context.Context, client *containerd.Client, args []string, netMa
 	}
 	opts = append(opts, mountOpts...)
 
+	internalLabels.healthcheck, err = generateHealthcheck(ctx, ensuredImage, options)
+	if err != nil {
+		return nil, nil, err
+	}
+
 	// Always set internalLabels.logURI
 	// to support restart the container that run with "-it", like
 	//
@@ -435,6 +475,10 @@ func generateRootfsOpts(args []string, id string, ensured *imgutil.EnsuredImage,
 		options.InitProcessFlag = true
 	}
 	if options.InitProcessFlag {
+		if options.InitBinary == nil {
+			initBinary := DefaultInitBinary
+			options.InitBinary = &initBinary
+		}
 		binaryPath, err := exec.LookPath(*options.InitBinary)
 		if err != nil {
 			if errors.Is(err, exec.ErrNotFound) {
@@ -596,6 +640,53 @@ func withStop(stopSignal string, stopTimeout int, ensuredImage *imgutil.EnsuredI
 	}
 }
 
+// generateHealthcheck merges the healthcheck flags with the HEALTHCHECK of the image.
+func generateHealthcheck(ctx context.Context, ensuredImage *imgutil.EnsuredImage, options types.ContainerCreateOptions) (*healthcheck.Healthcheck, error) {
+	healthFlagsChanged := options.HealthCmd != "" || options.HealthInterval != 0 || options.HealthTimeout != 0 ||
+		options.HealthRetries != 0 || options.HealthStartPeriod != 0 || options.HealthStartInterval != 0
+	if options.NoHealthcheck {
+		if healthFlagsChanged {
+			return nil, errors.New("--no-healthcheck conflicts with --health-* options")
+		}
+		return nil, nil
+	}
+	hc := &healthcheck.Healthcheck{
+		Interval:      options.HealthInterval,
+		Timeout:       options.HealthTimeout,
+		StartPeriod:   options.HealthStartPeriod,
+		StartInterval: options.HealthStartInterval,
+		Retries:       options.HealthRetries,
+	}
+	if options.HealthCmd != "" {
+		hc.Test = parseHealthCmd(options.HealthCmd)
+	}
+	if err := hc.Validate(); err != nil {
+		return nil, err
+	}
+	if ensuredImage != nil {
+		imageHC, err := healthcheck.FromImage(ctx, ensuredImage.Image)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read the HEALTHCHECK of the image: %w", err)
+		}
+		hc = healthcheck.Merge(hc, imageHC)
+	}
+	return hc, nil
+}
+
+// parseHealthCmd parses the value of --health-cmd.
+// The value is executed with the shell ("CMD-SHELL"), unless it is a JSON array
+// that starts with "CMD", "CMD-SHELL", or "NONE" (nerdctl extension, used by Compose).
+func parseHealthCmd(s string) []string {
+	var test []string
+	if strings.HasPrefix(s, "[") && json.Unmarshal([]byte(s), &test) == nil && len(test) > 0 {
+		switch test[0] {
+		case healthcheck.TestCmd, healthcheck.TestCmdShell, healthcheck.TestNone:
+			return test
+		}
+	}
+	return []string{healthcheck.TestCmdShell, s}
+}
+
 type internalLabels struct {
 	// labels from cmd options
 	namespace  string
@@ -622,6 +713,8 @@ type internalLabels struct {
 	ipc string
 	// log
 	logURI string
+	// healthcheck
+	healthcheck *healthcheck.Healthcheck
 }
 
 // WithInternalLabels sets the internal labels for a container.
@@ -685,6 +778,20 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 			return nil, err
 		}
 		m[labels.Mounts] = string(mountPointsJSON)
+
+		var subpaths []mountutil.VolumeSubpath
+		for _, mp := range internalLabels.mountPoints {
+			if mp.VolumeSubpath != nil {
+				subpaths = append(subpaths, *mp.VolumeSubpath)
+			}
+		}
+		if len(subpaths) > 0 {
+			subpathsJSON, err := json.Marshal(subpaths)
+			if err != nil {
+				return nil, err
+			}
+			m[labels.VolumeSubpaths] = string(subpathsJSON)
+		}
 	}
 
 	if internalLabels.macAddress != "" {
@@ -699,6 +806,14 @@ func withInternalLabels(internalLabels internalLabels) (containerd.NewContainerO
 		m[labels.IPC] = internalLabels.ipc
 	}
 
+	if !internalLabels.healthcheck.Disabled() {
+		healthcheckJSON, err := json.Marshal(internalLabels.healthcheck)
+		if err != nil {
+			return nil, err
+		}
+		m[labels.HealthCheck] = string(healthcheckJSON)
+	}
+
 	return containerd.WithAdditionalContainerLabels(m), nil
 }
 
@@ -0,0 +1,326 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package container
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+	"syscall"
+	"time"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/cio"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
+	"github.com/containerd/nerdctl/v2/pkg/idgen"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/lockutil"
+	"github.com/containerd/nerdctl/v2/pkg/ocihook/state"
+)
+
+// StartHealthMonitor resets the health state of the container and spawns a detached
+// `nerdctl internal healthcheck-monitor` process for it.
+// StartHealthMonitor is a no-op if the container has no healthcheck.
+//
+// StartHealthMonitor must be called after the task of the container has started, as the monitor
+// exits when the task is gone. Spawning a monitor for a container that is already monitored is harmless,
+// as the second monitor exits once it fails to take over the monitoring.
+func StartHealthMonitor(ctx context.Context, container containerd.Container, globalOptions types.GlobalCommandOptions) error {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	hc, err := healthcheck.FromLabels(lab)
+	if err != nil {
+		return err
+	}
+	if hc.Disabled() {
+		return nil
+	}
+	if stateDir := lab[labels.StateDir]; stateDir != "" {
+		hs := healthcheck.NewHealthState(stateDir)
+		if err := hs.WithLock(func() error {
+			if err := hs.Load(); err != nil {
+				return err
+			}
+			hs.Reset()
+			return hs.Save()
+		}); err != nil {
+			return err
+		}
+	}
+	return healthcheck.SpawnMonitor(ctx, globalOptions.Address, globalOptions.Namespace, container.ID())
+}
+
+// startHealthMonitorWhenRunning waits for the task of the container to run, and calls StartHealthMonitor.
+// It is used when the start blocks until the task exits, i.e., when attaching to the container.
+// The wait is aborted when ctx is done.
+func startHealthMonitorWhenRunning(ctx context.Context, container containerd.Container, globalOptions types.GlobalCommandOptions) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(100 * time.Millisecond):
+		}
+		task, err := container.Task(ctx, nil)
+		if err != nil {
+			continue
+		}
+		if status, err := task.Status(ctx); err != nil || status.Status != containerd.Running {
+			continue
+		}
+		if err := StartHealthMonitor(ctx, container, globalOptions); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to start the healthcheck monitor of container %q", container.ID())
+		}
+		return
+	}
+}
+
+// lockHealthMonitor takes the lock of the healthcheck monitor of the container.
+// When the container is already monitored, lockHealthMonitor retries until `wait` elapses, so that the monitor
+// of the previous task can notice the exit of the task and release the lock.
+func lockHealthMonitor(ctx context.Context, lockDir string, wait time.Duration) (*os.File, error) {
+	deadline := time.Now().Add(wait)
+	for {
+		locked, err := lockutil.TryLock(lockDir)
+		if !errors.Is(err, lockutil.ErrLocked) || time.Now().After(deadline) {
+			return locked, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(time.Second):
+		}
+	}
+}
+
+// HealthMonitor runs the healthcheck probes of the container `id` until the task of the container exits,
+// or the container is removed.
+// Only a single monitor can run for a container; HealthMonitor returns nil if the container
+// is still monitored by another monitor after a probe interval.
+func HealthMonitor(ctx context.Context, client *containerd.Client, id string) error {
+	container, err := client.LoadContainer(ctx, id)
+	if err != nil {
+		return err
+	}
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	hc, err := healthcheck.FromLabels(lab)
+	if err != nil {
+		return err
+	}
+	if hc.Disabled() {
+		return nil
+	}
+	stateDir := lab[labels.StateDir]
+	if stateDir == "" {
+		return fmt.Errorf("container %q lacks label %q", id, labels.StateDir)
+	}
+	lockDir := filepath.Join(stateDir, "healthcheck")
+	if err := os.MkdirAll(lockDir, 0700); err != nil {
+		return err
+	}
+	locked, err := lockHealthMonitor(ctx, lockDir, hc.ProbeInterval(false)+hc.ProbeTimeout())
+	if err != nil {
+		if errors.Is(err, lockutil.ErrLocked) {
+			log.G(ctx).Debugf("container %q is already monitored", id)
+			return nil
+		}
+		return err
+	}
+	defer lockutil.Unlock(locked)
+
+	var (
+		hs              = healthcheck.NewHealthState(stateDir)
+		lastPid         uint32
+		startedAt       time.Time
+		startPeriodDone bool
+		inStartPeriod   = hc.StartPeriod > 0
+		spawnedAt       = time.Now()
+		seenTask        bool
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(hc.ProbeInterval(inStartPeriod)):
+		}
+
+		if _, err := container.Info(ctx, containerd.WithoutRefreshedMetadata); err != nil {
+			if errdefs.IsNotFound(err) {
+				log.G(ctx).Debugf("container %q was removed, stopping healthcheck monitor", id)
+				return nil
+			}
+			log.G(ctx).WithError(err).Warnf("failed to inspect container %q", id)
+			continue
+		}
+		var status containerd.Status
+		task, err := container.Task(ctx, nil)
+		if err == nil {
+			status, err = task.Status(ctx)
+		}
+		if err != nil && !errdefs.IsNotFound(err) {
+			log.G(ctx).WithError(err).Warnf("failed to load the task of container %q", id)
+			continue
+		}
+		if err != nil || status.Status == containerd.Stopped {
+			// The OCI hook spawns the monitor while the task is being created,
+			// so the task may not be visible yet right after the spawn.
+			if !seenTask && time.Since(spawnedAt) < hc.ProbeInterval(false) {
+				continue
+			}
+			// A new monitor is spawned when the container is started again
+			log.G(ctx).Debugf("the task of container %q is gone, stopping healthcheck monitor", id)
+			return nil
+		}
+		seenTask = true
+		if status.Status != containerd.Running {
+			lastPid = 0
+			continue
+		}
+
+		newTask := task.Pid() != lastPid
+		if newTask {
+			lastPid = task.Pid()
+			startedAt = time.Now()
+			lf := state.NewLifecycleState(stateDir)
+			if err := lf.WithLock(lf.Load); err == nil && !lf.StartedAt.IsZero() {
+				startedAt = lf.StartedAt
+			}
+			startPeriodDone = false
+		}
+		inStartPeriod = !startPeriodDone && time.Since(startedAt) < hc.StartPeriod
+
+		result, err := runHealthcheckProbe(ctx, client, container, task, hc)
+		if err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to run the healthcheck probe of container %q", id)
+			continue
+		}
+		if result.ExitCode == 0 {
+			startPeriodDone = true
+		}
+		if err := hs.WithLock(func() error {
+			if err := hs.Load(); err != nil {
+				return err
+			}
+			if newTask {
+				hs.Reset()
+			}
+			hs.Update(hc, result, inStartPeriod)
+			return hs.Save()
+		}); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to update the health state of container %q", id)
+		}
+		inStartPeriod = !startPeriodDone && time.Since(startedAt) < hc.StartPeriod
+	}
+}
+
+// runHealthcheckProbe executes the healthcheck probe via the same task exec path as `nerdctl exec`.
+func runHealthcheckProbe(ctx context.Context, client *containerd.Client, container containerd.Container, task containerd.Task, hc *healthcheck.Healthcheck) (*healthcheck.HealthcheckResult, error) {
+	shell := []string{"/bin/sh", "-c"}
+	if runtime.GOOS == "windows" {
+		shell = []string{"cmd", "/S", "/C"}
+	}
+	probeArgs, err := hc.ProbeArgs(shell)
+	if err != nil {
+		return nil, err
+	}
+	pspec, err := generateExecProcessSpec(ctx, client, container, append([]string{container.ID()}, probeArgs...), types.ContainerExecOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	output := &limitedBuffer{limit: healthcheck.MaxOutputLen}
+	ioCreator := cio.NewCreator(cio.WithStreams(nil, output, output))
+	execID := "healthcheck-" + idgen.GenerateID()
+	start := time.Now()
+	process, err := task.Exec(ctx, execID, pspec, ioCreator)
+	if err != nil {
+		return nil, err
+	}
+	defer process.Delete(ctx)
+
+	statusC, err := process.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := process.Start(ctx); err != nil {
+		return nil, err
+	}
+
+	timeout := hc.ProbeTimeout()
+	select {
+	case status := <-statusC:
+		code, _, err := status.Result()
+		if err != nil {
+			return nil, err
+		}
+		if pio := process.IO(); pio != nil {
+			pio.Wait()
+		}
+		return &healthcheck.HealthcheckResult{
+			Start:    start,
+			End:      time.Now(),
+			ExitCode: int(code),
+			Output:   output.String(),
+		}, nil
+	case <-time.After(timeout):
+		if err := process.Kill(ctx, syscall.SIGKILL); err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to kill the healthcheck probe %q", execID)
+		}
+		<-statusC
+		return healthcheck.TimeoutResult(start, timeout), nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// limitedBuffer is a goroutine-safe buffer that discards the data beyond the limit.
+type limitedBuffer struct {
+	mu    sync.Mutex
+	buf   bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if room := b.limit - b.buf.Len(); room > 0 {
+		if len(p) > room {
+			b.buf.Write(p[:room])
+		} else {
+			b.buf.Write(p)
+		}
+	}
+	return len(p), nil
+}
+
+func (b *limitedBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
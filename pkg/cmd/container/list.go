@@ -33,6 +33,7 @@ import (
 	"github.com/containerd/nerdctl/v2/pkg/containerdutil"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
@@ -142,7 +143,7 @@ func prepareContainers(ctx context.Context, client *containerd.Client, container
 			Platform:  info.Labels[labels.Platform],
 			Names:     containerutil.GetContainerName(info.Labels),
 			Ports:     formatter.FormatPorts(info.Labels),
-			Status:    formatter.ContainerStatus(ctx, c),
+			Status:    healthStatus(formatter.ContainerStatus(ctx, c), info.Labels),
 			Runtime:   info.Runtime.Name,
 			Labels:    formatter.FormatLabels(info.Labels),
 			LabelsMap: info.Labels,
@@ -164,6 +165,27 @@ func prepareContainers(ctx context.Context, client *containerd.Client, container
 	return listItems, nil
 }
 
+// healthStatus appends the health status to the status of a running container, like Docker.
+// e.g., "Up 5 minutes (healthy)", "Up 5 seconds (health: starting)"
+func healthStatus(status string, containerLabels map[string]string) string {
+	if !strings.HasPrefix(status, "Up") {
+		return status
+	}
+	health, err := healthcheck.Status(containerLabels)
+	if err != nil {
+		log.L.Warn(err)
+		return status
+	}
+	switch health {
+	case healthcheck.NoHealthcheck:
+		return status
+	case healthcheck.Starting:
+		return status + " (health: starting)"
+	default:
+		return status + " (" + health + ")"
+	}
+}
+
 func getContainerNetworks(containerLables map[string]string) []string {
 	var networks []string
 	if names, ok := containerLables[labels.Networks]; ok {
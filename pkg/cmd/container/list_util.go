@@ -27,6 +27,7 @@ import (
 	"github.com/containerd/containerd/v2/core/containers"
 	"github.com/containerd/log"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 )
 
 func foldContainerFilters(ctx context.Context, containers []containerd.Container, filters []string) (*containerFilterContext, error) {
@@ -47,6 +48,7 @@ type containerFilterContext struct {
 	labelFilterFuncs   []func(map[string]string) bool
 	volumeFilterFuncs  []func([]*containerutil.ContainerVolume) bool
 	networkFilterFuncs []func([]string) bool
+	healthFilterFuncs  []func(string) bool
 }
 
 func (cl *containerFilterContext) MatchesFilters(ctx context.Context) []containerd.Container {
@@ -73,7 +75,7 @@ func (cl *containerFilterContext) foldFilters(ctx context.Context, filters []str
 		{"before", cl.foldBeforeFilter}, {"since", cl.foldSinceFilter},
 		{"network", cl.foldNetworkFilter}, {"label", cl.foldLabelFilter},
 		{"volume", cl.foldVolumeFilter}, {"status", cl.foldStatusFilter},
-		{"exited", cl.foldExitedFilter},
+		{"exited", cl.foldExitedFilter}, {"health", cl.foldHealthFilter},
 	}
 	for _, filter := range filters {
 		invalidFilter := true
@@ -128,6 +130,18 @@ func (cl *containerFilterContext) foldStatusFilter(_ context.Context, filter, va
 	return nil
 }
 
+func (cl *containerFilterContext) foldHealthFilter(_ context.Context, filter, value string) error {
+	switch value {
+	case healthcheck.Starting, healthcheck.Healthy, healthcheck.Unhealthy, healthcheck.NoHealthcheck:
+		cl.healthFilterFuncs = append(cl.healthFilterFuncs, func(health string) bool {
+			return value == health
+		})
+	default:
+		return fmt.Errorf("invalid filter '%s'", filter)
+	}
+	return nil
+}
+
 func (cl *containerFilterContext) foldBeforeFilter(ctx context.Context, filter, value string) error {
 	beforeC, err := idOrNameFilter(ctx, cl.containers, value)
 	if err == nil {
@@ -215,13 +229,14 @@ func (cl *containerFilterContext) foldNetworkFilter(_ context.Context, filter, v
 
 func (cl *containerFilterContext) matchesInfoFilters(ctx context.Context, container containerd.Container) bool {
 	if len(cl.idFilterFuncs)+len(cl.nameFilterFuncs)+len(cl.beforeFilterFuncs)+
-		len(cl.sinceFilterFuncs)+len(cl.labelFilterFuncs)+len(cl.volumeFilterFuncs)+len(cl.networkFilterFuncs) == 0 {
+		len(cl.sinceFilterFuncs)+len(cl.labelFilterFuncs)+len(cl.volumeFilterFuncs)+len(cl.networkFilterFuncs)+
+		len(cl.healthFilterFuncs) == 0 {
 		return true
 	}
 	info, _ := container.Info(ctx, containerd.WithoutRefreshedMetadata)
 	return cl.matchesIDFilter(info) && cl.matchesNameFilter(info) && cl.matchesBeforeFilter(info) &&
 		cl.matchesSinceFilter(info) && cl.matchesLabelFilter(info) && cl.matchesVolumeFilter(info) &&
-		cl.matchesNetworkFilter(info)
+		cl.matchesNetworkFilter(info) && cl.matchesHealthFilter(info)
 }
 
 func (cl *containerFilterContext) matchesTaskFilters(ctx context.Context, container containerd.Container) bool {
@@ -362,6 +377,24 @@ func (cl *containerFilterContext) matchesNetworkFilter(info containers.Container
 	return false
 }
 
+func (cl *containerFilterContext) matchesHealthFilter(info containers.Container) bool {
+	if len(cl.healthFilterFuncs) == 0 {
+		return true
+	}
+	health, err := healthcheck.Status(info.Labels)
+	if err != nil {
+		log.L.Warn(err)
+		return false
+	}
+	for _, healthFilterFunc := range cl.healthFilterFuncs {
+		if !healthFilterFunc(health) {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
 func idOrNameFilter(ctx context.Context, containers []containerd.Container, value string) (*containers.Container, error) {
 	for _, container := range containers {
 		info, err := container.Info(ctx, containerd.WithoutRefreshedMetadata)
@@ -49,6 +49,15 @@ func Logs(ctx context.Context, client *containerd.Client, container string, opti
 	stopChannel := make(chan os.Signal, 1)
 	// catch OS signals:
 	signal.Notify(stopChannel, syscall.SIGTERM, syscall.SIGINT)
+	defer signal.Stop(stopChannel)
+	// stop following when the context is cancelled, e.g., when the client of `nerdctl system serve` disconnects
+	go func() {
+		<-ctx.Done()
+		select {
+		case stopChannel <- os.Interrupt:
+		default:
+		}
+	}()
 
 	walker := &containerwalker.ContainerWalker{
 		Client: client,
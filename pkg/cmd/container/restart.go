@@ -21,6 +21,7 @@ import (
 	"fmt"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
@@ -40,6 +41,9 @@ func Restart(ctx context.Context, client *containerd.Client, containers []string
 			if err := containerutil.Start(ctx, found.Container, false, client, ""); err != nil {
 				return err
 			}
+			if err := StartHealthMonitor(ctx, found.Container, options.GOption); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to start the healthcheck monitor of container %q", found.Req)
+			}
 			_, err := fmt.Fprintln(options.Stdout, found.Req)
 			return err
 		},
@@ -227,7 +227,7 @@ func generateMountOpts(ctx context.Context, client *containerd.Client, ensuredIm
 			}
 
 			// Copying content in AnonymousVolume and namedVolume
-			if x.Type == "volume" {
+			if x.Type == "volume" && !x.NoCopy {
 				if err := copyExistingContents(target, x.Mount.Source); err != nil {
 					return nil, nil, nil, err
 				}
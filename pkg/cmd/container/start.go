@@ -18,10 +18,14 @@ package container
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime"
 
 	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/checkpointutil"
 	"github.com/containerd/nerdctl/v2/pkg/containerutil"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
 )
@@ -32,17 +36,34 @@ func Start(ctx context.Context, client *containerd.Client, reqs []string, option
 		return fmt.Errorf("you cannot start and attach multiple containers at once")
 	}
 
+	if options.Checkpoint != "" && runtime.GOOS != "linux" {
+		return errors.New("--checkpoint is only supported on linux")
+	}
+
 	walker := &containerwalker.ContainerWalker{
 		Client: client,
 		OnFound: func(ctx context.Context, found containerwalker.Found) error {
-			var err error
 			if found.MatchCount > 1 {
 				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
 			}
-			if err := containerutil.Start(ctx, found.Container, options.Attach, client, options.DetachKeys); err != nil {
+			taskOpts, err := checkpointRestoreOpts(ctx, found.Container, options)
+			if err != nil {
+				return err
+			}
+			if options.Attach {
+				// Start blocks until the task exits when attaching, so the health monitor is started
+				// once the task is running
+				monitorCtx, cancel := context.WithCancel(ctx)
+				defer cancel()
+				go startHealthMonitorWhenRunning(monitorCtx, found.Container, options.GOptions)
+			}
+			if err := containerutil.Start(ctx, found.Container, options.Attach, client, options.DetachKeys, taskOpts...); err != nil {
 				return err
 			}
 			if !options.Attach {
+				if err := StartHealthMonitor(ctx, found.Container, options.GOptions); err != nil {
+					log.G(ctx).WithError(err).Warnf("failed to start the healthcheck monitor of container %q", found.Req)
+				}
 				_, err := fmt.Fprintln(options.Stdout, found.Req)
 				if err != nil {
 					return err
@@ -54,3 +75,29 @@ func Start(ctx context.Context, client *containerd.Client, reqs []string, option
 
 	return walker.WalkAll(ctx, reqs, true)
 }
+
+// checkpointRestoreOpts returns the task options to restore the container from options.Checkpoint.
+// The container is restored with the OCI hooks of its spec, so the networks are set up again on restore.
+func checkpointRestoreOpts(ctx context.Context, container containerd.Container, options types.ContainerStartOptions) ([]containerd.NewTaskOpts, error) {
+	if options.Checkpoint == "" {
+		return nil, nil
+	}
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return nil, err
+	}
+	dir, err := checkpointutil.Dir(lab, options.CheckpointDir)
+	if err != nil {
+		return nil, err
+	}
+	path, err := checkpointutil.Path(dir, options.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	if exists, err := checkpointutil.Exists(path); err != nil {
+		return nil, err
+	} else if !exists {
+		return nil, fmt.Errorf("checkpoint %s does not exist for container %s", options.Checkpoint, container.ID())
+	}
+	return []containerd.NewTaskOpts{containerd.WithRestoreImagePath(path)}, nil
+}
@@ -0,0 +1,35 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package context implements `nerdctl context` commands.
+package context
+
+import (
+	"github.com/containerd/nerdctl/v2/pkg/config"
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+)
+
+const defaultContextDescription = "Current nerdctl.toml and environment based configuration"
+
+// getContext returns the context, including the default context that corresponds to defaultConfig.
+func getContext(store contextstore.Store, name string, defaultConfig *config.Config) (*contextstore.Context, error) {
+	if name == contextstore.DefaultContextName {
+		c := contextstore.FromConfig(name, defaultConfig)
+		c.Description = defaultContextDescription
+		return c, nil
+	}
+	return store.Get(name)
+}
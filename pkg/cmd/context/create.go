@@ -0,0 +1,62 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"fmt"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+)
+
+// Create creates a context. The properties that are not specified in options are
+// copied from the `From` context, or inherited from nerdctl.toml if `From` is empty.
+func Create(store contextstore.Store, name string, options types.ContextCreateOptions) error {
+	c := &contextstore.Context{}
+	if options.From != "" {
+		from, err := getContext(store, options.From, options.DefaultConfig)
+		if err != nil {
+			return err
+		}
+		*c = *from
+	}
+	c.Name = name
+	c.Description = options.Description
+	for _, f := range []struct {
+		dst *string
+		src string
+	}{
+		{&c.Address, options.Address},
+		{&c.Namespace, options.Namespace},
+		{&c.Snapshotter, options.Snapshotter},
+		{&c.DataRoot, options.DataRoot},
+		{&c.CNIPath, options.CNIPath},
+		{&c.CNINetConfPath, options.CNINetConfPath},
+	} {
+		if f.src != "" {
+			*f.dst = f.src
+		}
+	}
+	if len(options.HostsDir) > 0 {
+		c.HostsDir = options.HostsDir
+	}
+	if err := store.Create(c); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(options.Stdout, name)
+	return err
+}
@@ -0,0 +1,39 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+)
+
+// Inspect prints the contexts. The current context is inspected if names is empty.
+func Inspect(store contextstore.Store, names []string, options types.ContextInspectOptions) error {
+	if len(names) == 0 {
+		names = []string{options.Current}
+	}
+	result := make([]interface{}, len(names))
+	for i, name := range names {
+		c, err := getContext(store, name, options.DefaultConfig)
+		if err != nil {
+			return err
+		}
+		result[i] = c
+	}
+	return formatter.FormatSlice(options.Format, options.Stdout, result)
+}
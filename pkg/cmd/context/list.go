@@ -0,0 +1,111 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+)
+
+type contextPrintable struct {
+	Name        string
+	Description string
+	Address     string
+	Namespace   string
+	Snapshotter string
+	Current     bool
+}
+
+// List prints the contexts, including the default context.
+// The empty properties of the contexts are shown with the values inherited from nerdctl.toml.
+func List(store contextstore.Store, options types.ContextListOptions) error {
+	defaultContext, err := getContext(store, contextstore.DefaultContextName, options.DefaultConfig)
+	if err != nil {
+		return err
+	}
+	contexts, err := store.List()
+	if err != nil {
+		return err
+	}
+	contexts = append([]*contextstore.Context{defaultContext}, contexts...)
+
+	w := options.Stdout
+	var tmpl *template.Template
+	switch options.Format {
+	case "", "table", "wide":
+		w = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+		if !options.Quiet {
+			fmt.Fprintln(w, "NAME\tDESCRIPTION\tADDRESS\tNAMESPACE\tSNAPSHOTTER")
+		}
+	case "raw":
+		return errors.New("unsupported format: \"raw\"")
+	default:
+		if options.Quiet {
+			return errors.New("format and quiet must not be specified together")
+		}
+		tmpl, err = formatter.ParseTemplate(options.Format)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, c := range contexts {
+		p := contextPrintable{
+			Name:        c.Name,
+			Description: c.Description,
+			Address:     inherit(c.Address, defaultContext.Address),
+			Namespace:   inherit(c.Namespace, defaultContext.Namespace),
+			Snapshotter: inherit(c.Snapshotter, defaultContext.Snapshotter),
+			Current:     c.Name == options.Current,
+		}
+		if tmpl != nil {
+			var b bytes.Buffer
+			if err := tmpl.Execute(&b, p); err != nil {
+				return err
+			}
+			if _, err := fmt.Fprintln(w, b.String()); err != nil {
+				return err
+			}
+		} else if options.Quiet {
+			fmt.Fprintln(w, p.Name)
+		} else {
+			name := p.Name
+			if p.Current {
+				name += " *"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", name, p.Description, p.Address, p.Namespace, p.Snapshotter)
+		}
+	}
+	if f, ok := w.(formatter.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func inherit(v, defaultV string) string {
+	if v != "" {
+		return v
+	}
+	return defaultV
+}
@@ -0,0 +1,64 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+)
+
+// Remove removes the contexts. Removing the context selected by `nerdctl context use`
+// requires options.Force, and switches back to the default context.
+func Remove(store contextstore.Store, names []string, options types.ContextRemoveOptions) error {
+	current, err := store.Current()
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, name := range names {
+		if err := remove(store, name, current, options.Force); err != nil {
+			log.L.WithError(err).Errorf("failed to remove context %q", name)
+			errs = append(errs, err)
+			continue
+		}
+		fmt.Fprintln(options.Stdout, name)
+	}
+	if len(errs) > 0 {
+		return errors.New("failed to remove one or more contexts")
+	}
+	return nil
+}
+
+func remove(store contextstore.Store, name, current string, force bool) error {
+	if name == contextstore.DefaultContextName {
+		return fmt.Errorf("the default context cannot be removed: %w", errdefs.ErrInvalidArgument)
+	}
+	if name == current {
+		if !force {
+			return fmt.Errorf("context %q is in use, set -f flag to force remove: %w", name, errdefs.ErrFailedPrecondition)
+		}
+		if err := store.SetCurrent(contextstore.DefaultContextName); err != nil {
+			return err
+		}
+	}
+	return store.Remove(name)
+}
@@ -0,0 +1,34 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package context
+
+import (
+	"fmt"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/contextstore"
+)
+
+// Use selects the context to be used by the subsequent commands.
+func Use(store contextstore.Store, name string, options types.ContextUseOptions) error {
+	if err := store.SetCurrent(name); err != nil {
+		return err
+	}
+	fmt.Fprintln(options.Stdout, name)
+	fmt.Fprintf(options.Stderr, "Current context is now %q\n", name)
+	return nil
+}
@@ -0,0 +1,208 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	distributionref "github.com/distribution/reference"
+	"golang.org/x/sync/errgroup"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/jobs"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+)
+
+// allTagsConcurrency is the number of the tags that are pulled or pushed in parallel with --all-tags.
+const allTagsConcurrency = 3
+
+// parseRepository parses the repository name for --all-tags, which must not have a tag nor a digest.
+func parseRepository(rawRef string) (distributionref.Named, error) {
+	if _, _, err := referenceutil.ParseIPFSRefWithScheme(rawRef); err == nil {
+		return nil, errors.New("--all-tags is not supported on IPFS")
+	}
+	named, err := distributionref.ParseNormalizedNamed(rawRef)
+	if err != nil {
+		return nil, err
+	}
+	if !distributionref.IsNameOnly(named) {
+		return nil, errors.New("tag can't be used with --all-tags/-a")
+	}
+	return named, nil
+}
+
+// listRemoteTags lists the tags of the repository in the registry.
+//
+// When insecure is set, skips verifying certs, and also falls back to HTTP when the registry does not speak HTTPS
+func listRemoteTags(ctx context.Context, named distributionref.Named, gOptions types.GlobalCommandOptions) ([]string, error) {
+	refDomain := distributionref.Domain(named)
+	var dOpts []dockerconfigresolver.Opt
+	if gOptions.InsecureRegistry {
+		log.G(ctx).Warnf("skipping verifying HTTPS certs for %q", refDomain)
+		dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
+	}
+	dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(gOptions.HostsDir))
+	tags, err := dockerconfigresolver.ListTags(ctx, named, dOpts...)
+	if err != nil {
+		if !errutil.IsErrHTTPResponseToHTTPSClient(err) && !errutil.IsErrConnectionRefused(err) {
+			return nil, err
+		}
+		if gOptions.InsecureRegistry {
+			log.G(ctx).WithError(err).Warnf("server %q does not seem to support HTTPS, falling back to plain HTTP", refDomain)
+			dOpts = append(dOpts, dockerconfigresolver.WithPlainHTTP(true))
+			return dockerconfigresolver.ListTags(ctx, named, dOpts...)
+		}
+		log.G(ctx).WithError(err).Errorf("server %q does not seem to support HTTPS", refDomain)
+		log.G(ctx).Info("Hint: you may want to try --insecure-registry to allow plain HTTP (if you are in a trusted network)")
+		return nil, err
+	}
+	return tags, nil
+}
+
+// listLocalTags lists the references of the local images that are tagged in the repository.
+func listLocalTags(ctx context.Context, client *containerd.Client, named distributionref.Named) ([]string, error) {
+	imageList, err := client.ImageService().List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	var refs []string
+	for _, img := range imageList {
+		imgNamed, err := distributionref.ParseDockerRef(img.Name)
+		if err != nil || imgNamed.Name() != named.Name() {
+			continue
+		}
+		if _, ok := imgNamed.(distributionref.Tagged); ok {
+			refs = append(refs, imgNamed.String())
+		}
+	}
+	sort.Strings(refs)
+	return refs, nil
+}
+
+// forEachRef runs fn for each of the refs, with bounded concurrency.
+// The progress of all the refs is displayed together on out, unless out is nil.
+//
+// The failure of a ref does not abort the others; the failures are returned together after all the refs are done.
+func forEachRef(ctx context.Context, refs []string, status jobs.StatusInfoStatus, out io.Writer, fn func(ctx context.Context, ref string) error) error {
+	tracker := jobs.NewRefs(refs)
+	pctx, stopProgress := context.WithCancel(ctx)
+	progress := make(chan struct{})
+	go func() {
+		if out != nil {
+			jobs.ShowRefsProgress(pctx, tracker, out)
+		}
+		close(progress)
+	}()
+
+	var (
+		eg     errgroup.Group
+		mu     sync.Mutex
+		failed []error
+	)
+	eg.SetLimit(allTagsConcurrency)
+	for _, ref := range refs {
+		eg.Go(func() error {
+			tracker.Update(ref, status)
+			if err := fn(ctx, ref); err != nil {
+				tracker.Update(ref, jobs.StatusFailed)
+				mu.Lock()
+				failed = append(failed, fmt.Errorf("%s: %w", ref, err))
+				mu.Unlock()
+				return nil
+			}
+			tracker.Update(ref, jobs.StatusDone)
+			return nil
+		})
+	}
+	eg.Wait()
+	stopProgress()
+	<-progress
+
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to process %d of %d tags: %w", len(failed), len(refs), errors.Join(failed...))
+	}
+	return nil
+}
+
+// pullAllTags pulls all the tags of the repository `rawRef`.
+func pullAllTags(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePullOptions) error {
+	named, err := parseRepository(rawRef)
+	if err != nil {
+		return err
+	}
+	tags, err := listRemoteTags(ctx, named, options.GOptions)
+	if err != nil {
+		return err
+	}
+	if len(tags) == 0 {
+		return fmt.Errorf("no tags found in repository %q", named.Name())
+	}
+	refs := make([]string, len(tags))
+	for i, tag := range tags {
+		refs[i] = named.Name() + ":" + tag
+	}
+
+	var out io.Writer
+	if !options.Quiet {
+		out = options.Stderr
+	}
+	// The progress of each tag is not displayed, as the progress of all the tags is displayed together
+	tagOptions := options
+	tagOptions.AllTags = false
+	tagOptions.Quiet = true
+	return forEachRef(ctx, refs, jobs.StatusPulling, out, func(ctx context.Context, ref string) error {
+		_, err := EnsureImage(ctx, client, ref, tagOptions)
+		return err
+	})
+}
+
+// pushAllTags pushes all the local tags of the repository `rawRef`.
+func pushAllTags(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePushOptions) error {
+	named, err := parseRepository(rawRef)
+	if err != nil {
+		return err
+	}
+	refs, err := listLocalTags(ctx, client, named)
+	if err != nil {
+		return err
+	}
+	if len(refs) == 0 {
+		return fmt.Errorf("an image does not exist locally with the repository: %s", named.Name())
+	}
+
+	var out io.Writer
+	tagOptions := options
+	tagOptions.AllTags = false
+	if !options.Quiet {
+		// The progress of each tag is not displayed, as the progress of all the tags is displayed together
+		out = options.Stdout
+		tagOptions.Quiet = true
+		tagOptions.Stdout = io.Discard
+	}
+	return forEachRef(ctx, refs, jobs.StatusPushing, out, func(ctx context.Context, ref string) error {
+		return Push(ctx, client, ref, tagOptions)
+	})
+}
@@ -0,0 +1,235 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	distributionref "github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/referrers"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+// Attach attaches the file to the image `rawRef` in the registry, as an OCI artifact that refers to the image.
+// The artifact is pushed directly to the registry, without storing it in the content store.
+//
+// When the registry does not support the referrers API, the index of the referrers tag schema is updated.
+func Attach(ctx context.Context, rawRef, file string, options types.ImageAttachOptions) error {
+	if options.ArtifactType == "" {
+		return errors.New("--artifact-type must be specified")
+	}
+	mediaType := options.MediaType
+	if mediaType == "" {
+		mediaType = "application/octet-stream"
+	}
+	named, err := distributionref.ParseDockerRef(rawRef)
+	if err != nil {
+		return err
+	}
+	resolver, dOpts, err := registryResolver(ctx, named, options.GOptions)
+	if err != nil {
+		return err
+	}
+	_, subject, err := resolver.Resolve(ctx, named.String())
+	if err != nil {
+		return err
+	}
+
+	f, err := os.Open(file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	st, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	fileDigest, err := digest.FromReader(f)
+	if err != nil {
+		return err
+	}
+	layer := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    fileDigest,
+		Size:      st.Size(),
+		Annotations: map[string]string{
+			ocispec.AnnotationTitle: filepath.Base(file),
+		},
+	}
+
+	annotations := strutil.ConvertKVStringsToMap(options.Annotations)
+	if _, ok := annotations[ocispec.AnnotationCreated]; !ok {
+		annotations[ocispec.AnnotationCreated] = time.Now().UTC().Format(time.RFC3339)
+	}
+	manifest := ocispec.Manifest{
+		Versioned:    specs.Versioned{SchemaVersion: 2},
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: options.ArtifactType,
+		Config:       ocispec.DescriptorEmptyJSON,
+		Layers:       []ocispec.Descriptor{layer},
+		Subject: &ocispec.Descriptor{
+			MediaType: subject.MediaType,
+			Digest:    subject.Digest,
+			Size:      subject.Size,
+		},
+		Annotations: annotations,
+	}
+	manifestData, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	desc := ocispec.Descriptor{
+		MediaType:    manifest.MediaType,
+		ArtifactType: manifest.ArtifactType,
+		Digest:       digest.FromBytes(manifestData),
+		Size:         int64(len(manifestData)),
+		Annotations:  manifest.Annotations,
+	}
+
+	pusher, err := resolver.Pusher(ctx, named.Name()+"@"+desc.Digest.String())
+	if err != nil {
+		return err
+	}
+	if err := pushBlob(ctx, pusher, ocispec.DescriptorEmptyJSON, bytes.NewReader(ocispec.DescriptorEmptyJSON.Data)); err != nil {
+		return err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	if err := pushBlob(ctx, pusher, layer, f); err != nil {
+		return err
+	}
+	if err := pushBlob(ctx, pusher, desc, bytes.NewReader(manifestData)); err != nil {
+		return err
+	}
+
+	if _, err := dockerconfigresolver.ListReferrers(ctx, named, subject.Digest, "", dOpts...); err != nil {
+		if !errors.Is(err, dockerconfigresolver.ErrReferrersAPIUnsupported) {
+			return err
+		}
+		log.G(ctx).Debugf("the registry of %q does not support the referrers API, updating the referrers tag", named.Name())
+		if err := updateReferrersTag(ctx, resolver, named, subject.Digest, desc, dOpts); err != nil {
+			return fmt.Errorf("failed to update the referrers tag: %w", err)
+		}
+	}
+	if options.Quiet {
+		fmt.Fprintln(options.Stdout, desc.Digest)
+	} else {
+		fmt.Fprintf(options.Stdout, "Attached %s to %s@%s: %s\n", filepath.Base(file), named.Name(), subject.Digest, desc.Digest)
+	}
+	return nil
+}
+
+// referrersTagAttempts is the number of attempts to update the index of the referrers tag,
+// which may be overwritten concurrently by another client.
+const referrersTagAttempts = 5
+
+// updateReferrersTag adds the referrer to the index of the referrers tag schema, for the registries without the referrers API.
+//
+// The registry has no compare-and-swap for the tags, so the index is listed again after the push,
+// and the update is retried when a concurrent update has dropped the referrer.
+func updateReferrersTag(ctx context.Context, resolver remotes.Resolver, named distributionref.Named, subject digest.Digest, referrer ocispec.Descriptor, dOpts []dockerconfigresolver.Opt) error {
+	for attempt := 1; ; attempt++ {
+		existing, err := referrers.List(ctx, resolver, named, subject, "", dOpts...)
+		if err != nil {
+			return err
+		}
+		if attempt > 1 && containsDigest(existing, referrer.Digest) {
+			return nil
+		}
+		if attempt > referrersTagAttempts {
+			return fmt.Errorf("the referrers tag of %s@%s was concurrently updated %d times", named.Name(), subject, referrersTagAttempts)
+		}
+		if err := pushReferrersTag(ctx, resolver, named, subject, existing, referrer); err != nil {
+			return err
+		}
+		log.G(ctx).Debugf("updated the referrers tag of %s@%s (attempt %d)", named.Name(), subject, attempt)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Duration(attempt) * 100 * time.Millisecond):
+		}
+	}
+}
+
+// pushReferrersTag pushes the index of the referrers tag, with the existing referrers and the referrer.
+func pushReferrersTag(ctx context.Context, resolver remotes.Resolver, named distributionref.Named, subject digest.Digest, existing []ocispec.Descriptor, referrer ocispec.Descriptor) error {
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+	}
+	for _, desc := range existing {
+		if desc.Digest != referrer.Digest {
+			index.Manifests = append(index.Manifests, desc)
+		}
+	}
+	index.Manifests = append(index.Manifests, referrer)
+	indexData, err := json.Marshal(index)
+	if err != nil {
+		return err
+	}
+	desc := ocispec.Descriptor{
+		MediaType: index.MediaType,
+		Digest:    digest.FromBytes(indexData),
+		Size:      int64(len(indexData)),
+	}
+	pusher, err := resolver.Pusher(ctx, named.Name()+":"+referrers.FallbackTag(subject))
+	if err != nil {
+		return err
+	}
+	return pushBlob(ctx, pusher, desc, bytes.NewReader(indexData))
+}
+
+func containsDigest(descs []ocispec.Descriptor, dgst digest.Digest) bool {
+	for _, desc := range descs {
+		if desc.Digest == dgst {
+			return true
+		}
+	}
+	return false
+}
+
+// pushBlob pushes the blob or the manifest, unless it exists in the registry.
+func pushBlob(ctx context.Context, pusher remotes.Pusher, desc ocispec.Descriptor, r io.Reader) error {
+	w, err := pusher.Push(ctx, desc)
+	if err != nil {
+		if errdefs.IsAlreadyExists(err) {
+			return nil
+		}
+		return err
+	}
+	defer w.Close()
+	return content.Copy(ctx, w, r, desc.Size, desc.Digest)
+}
@@ -0,0 +1,255 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/identity"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/images/archive"
+	"github.com/containerd/containerd/v2/pkg/archive/compression"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/commit"
+	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
+	"github.com/containerd/platforms"
+)
+
+// Import creates a single-layer image from the tar archive options.Source, and prints the image ID.
+// The archive may be compressed.
+func Import(ctx context.Context, client *containerd.Client, options types.ImageImportOptions) error {
+	changes, err := commit.ParseChanges(options.Change)
+	if err != nil {
+		return err
+	}
+	platform := platforms.DefaultSpec()
+	if options.Platform != "" {
+		platform, err = platforms.Parse(options.Platform)
+		if err != nil {
+			return err
+		}
+	}
+	name := ""
+	if options.Reference != "" {
+		named, err := referenceutil.ParseDockerRef(options.Reference)
+		if err != nil {
+			return err
+		}
+		name = named.String()
+	}
+
+	in, err := openImportSource(ctx, options)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	ctx, done, err := client.WithLease(ctx)
+	if err != nil {
+		return err
+	}
+	defer done(ctx)
+
+	cs := client.ContentStore()
+	layerDesc, diffID, err := writeImportLayer(ctx, cs, in)
+	if err != nil {
+		return fmt.Errorf("failed to import layer: %w", err)
+	}
+
+	created := time.Now().UTC()
+	config := dockerspec.DockerOCIImage{
+		Image: ocispec.Image{
+			Created:  &created,
+			Platform: platforms.Normalize(platform),
+			RootFS: ocispec.RootFS{
+				Type:    "layers",
+				DiffIDs: []digest.Digest{diffID},
+			},
+			History: []ocispec.History{
+				{
+					Created: &created,
+					Comment: options.Message,
+				},
+			},
+		},
+	}
+	commit.ApplyChanges(&config.Config, changes)
+
+	manifestDesc, configDigest, err := writeImportManifest(ctx, cs, options.GOptions.Snapshotter, config, layerDesc)
+	if err != nil {
+		return err
+	}
+	if name == "" {
+		// Same as untagged images in `nerdctl load`
+		name = archive.DigestTranslator(options.GOptions.Snapshotter)(manifestDesc.Digest)
+	}
+
+	img := images.Image{
+		Name:      name,
+		Target:    manifestDesc,
+		CreatedAt: created,
+	}
+	if _, err := client.ImageService().Update(ctx, img); err != nil {
+		if !errdefs.IsNotFound(err) {
+			return err
+		}
+		if _, err := client.ImageService().Create(ctx, img); err != nil {
+			return fmt.Errorf("failed to create new image %s: %w", name, err)
+		}
+	}
+	image := containerd.NewImageWithPlatform(client, img, platforms.Only(platform))
+	if err := image.Unpack(ctx, options.GOptions.Snapshotter); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(options.Stdout, configDigest)
+	return err
+}
+
+func openImportSource(ctx context.Context, options types.ImageImportOptions) (io.ReadCloser, error) {
+	switch {
+	case options.Source == "-":
+		return io.NopCloser(options.Stdin), nil
+	case strings.HasPrefix(options.Source, "http://"), strings.HasPrefix(options.Source, "https://"):
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, options.Source, nil)
+		if err != nil {
+			return nil, err
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			return nil, fmt.Errorf("failed to download %s: %s", options.Source, resp.Status)
+		}
+		return resp.Body, nil
+	default:
+		return os.Open(options.Source)
+	}
+}
+
+// writeImportLayer writes the (possibly compressed) tar stream as a gzip layer to the content store.
+// writeImportLayer returns the layer descriptor and the diffID.
+func writeImportLayer(ctx context.Context, cs content.Store, r io.Reader) (ocispec.Descriptor, digest.Digest, error) {
+	decompressor, err := compression.DecompressStream(r)
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	defer decompressor.Close()
+
+	ref := fmt.Sprintf("import-layer-%d", time.Now().UnixNano())
+	w, err := content.OpenWriter(ctx, cs, content.WithRef(ref))
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	defer w.Close()
+
+	diffIDDigester := digest.Canonical.Digester()
+	gw := gzip.NewWriter(w)
+	if _, err := io.Copy(io.MultiWriter(gw, diffIDDigester.Hash()), decompressor); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	if err := gw.Close(); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	desc := ocispec.Descriptor{
+		MediaType: images.MediaTypeDockerSchema2LayerGzip,
+		Digest:    w.Digest(),
+		Size:      status.Offset,
+	}
+	diffID := diffIDDigester.Digest()
+	labelOpt := content.WithLabels(map[string]string{
+		"containerd.io/uncompressed": diffID.String(),
+	})
+	if err := w.Commit(ctx, desc.Size, desc.Digest, labelOpt); err != nil && !errdefs.IsAlreadyExists(err) {
+		return ocispec.Descriptor{}, "", err
+	}
+	return desc, diffID, nil
+}
+
+// writeImportManifest writes the image config and the manifest to the content store, in the same way as `nerdctl commit`.
+func writeImportManifest(ctx context.Context, cs content.Store, snName string, config dockerspec.DockerOCIImage, layerDesc ocispec.Descriptor) (ocispec.Descriptor, digest.Digest, error) {
+	configJSON, err := json.Marshal(config)
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	configDesc := ocispec.Descriptor{
+		MediaType: images.MediaTypeDockerSchema2Config,
+		Digest:    digest.FromBytes(configJSON),
+		Size:      int64(len(configJSON)),
+	}
+	// config should reference to snapshotter
+	labelOpt := content.WithLabels(map[string]string{
+		fmt.Sprintf("containerd.io/gc.ref.snapshot.%s", snName): identity.ChainID(config.RootFS.DiffIDs).String(),
+	})
+	if err := content.WriteBlob(ctx, cs, configDesc.Digest.String(), bytes.NewReader(configJSON), configDesc, labelOpt); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+
+	manifest := struct {
+		MediaType string `json:"mediaType,omitempty"`
+		ocispec.Manifest
+	}{
+		MediaType: images.MediaTypeDockerSchema2Manifest,
+		Manifest: ocispec.Manifest{
+			Versioned: specs.Versioned{
+				SchemaVersion: 2,
+			},
+			Config: configDesc,
+			Layers: []ocispec.Descriptor{layerDesc},
+		},
+	}
+	manifestJSON, err := json.MarshalIndent(manifest, "", "    ")
+	if err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	manifestDesc := ocispec.Descriptor{
+		MediaType: images.MediaTypeDockerSchema2Manifest,
+		Digest:    digest.FromBytes(manifestJSON),
+		Size:      int64(len(manifestJSON)),
+	}
+	// new manifest should reference the layers and config content
+	labelOpt = content.WithLabels(map[string]string{
+		"containerd.io/gc.ref.content.0": configDesc.Digest.String(),
+		"containerd.io/gc.ref.content.1": layerDesc.Digest.String(),
+	})
+	if err := content.WriteBlob(ctx, cs, manifestDesc.Digest.String(), bytes.NewReader(manifestJSON), manifestDesc, labelOpt); err != nil {
+		return ocispec.Descriptor{}, "", err
+	}
+	return manifestDesc, configDesc.Digest, nil
+}
@@ -51,6 +51,12 @@ func ListCommandHandler(ctx context.Context, client *containerd.Client, options
 	if err != nil {
 		return err
 	}
+	if !options.All {
+		imageList = filterIntermediate(imageList)
+	}
+	if options.Tree {
+		return printImageTree(ctx, client, imageList, options)
+	}
 	return printImages(ctx, client, imageList, options)
 }
 
@@ -0,0 +1,349 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+	"time"
+
+	"github.com/docker/go-units"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/identity"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/containerdutil"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/platforms"
+)
+
+const (
+	// The attestation manifests of BuildKit are stored in the index with these annotations.
+	annotationReferenceType   = "vnd.docker.reference.type"
+	annotationReferenceDigest = "vnd.docker.reference.digest"
+	attestationManifest       = "attestation-manifest"
+
+	// labelSnapshotRefPrefix is set on the config blob of the image by the unpacker, for each snapshotter.
+	labelSnapshotRefPrefix = "containerd.io/gc.ref.snapshot."
+)
+
+type treeManifestPrintable struct {
+	ID             string
+	Platform       string
+	Kind           string // "image" or "attestation"
+	AttestationFor string `json:",omitempty"` // the manifest digest of the attested image
+	Available      bool   // whether all the blobs of the manifest are in the content store
+	Size           string // the size of the unpacked snapshots of the current snapshotter.
+	BlobSize       string // the size of the blobs in the content store
+	// Snapshotters is the size of the unpacked snapshots, for each snapshotter that unpacked the manifest.
+	Snapshotters map[string]string `json:",omitempty"`
+
+	size, blobSize int64
+}
+
+type treeImagePrintable struct {
+	Names        []string
+	ID           string // image target digest, or its short form
+	Digest       string
+	CreatedAt    string
+	CreatedSince string
+	Size         string
+	BlobSize     string
+	Manifests    []treeManifestPrintable
+}
+
+// isTagged returns false for the untagged image names, such as "<repo>@<digest>",
+// or the config digest names created by the cri plugin.
+func isTagged(name string) bool {
+	if _, err := digest.Parse(name); err == nil {
+		return false
+	}
+	_, tag := imgutil.ParseRepoTag(name)
+	return tag != ""
+}
+
+// filterIntermediate removes the untagged images that share the target with a tagged image.
+// Dangling images, that are not referenced by any tagged image, are kept.
+func filterIntermediate(imageList []images.Image) []images.Image {
+	tagged := make(map[digest.Digest]bool)
+	for _, img := range imageList {
+		if isTagged(img.Name) {
+			tagged[img.Target.Digest] = true
+		}
+	}
+	var filtered []images.Image
+	for _, img := range imageList {
+		if isTagged(img.Name) || !tagged[img.Target.Digest] {
+			filtered = append(filtered, img)
+		}
+	}
+	return filtered
+}
+
+func printImageTree(ctx context.Context, client *containerd.Client, imageList []images.Image, options types.ImageListOptions) error {
+	w := options.Stdout
+	var tmpl *template.Template
+	switch options.Format {
+	case "", "table":
+		w = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+		fmt.Fprintln(w, "IMAGE\tID\tCREATED\tSIZE\tBLOB SIZE\tAVAILABLE")
+	case "raw", "wide":
+		return fmt.Errorf("unsupported format with --tree: %q", options.Format)
+	default:
+		var err error
+		tmpl, err = formatter.ParseTemplate(options.Format)
+		if err != nil {
+			return err
+		}
+	}
+	if options.Quiet {
+		return errors.New("--tree and --quiet must not be specified together")
+	}
+
+	printer := &treePrinter{
+		w:           w,
+		all:         options.All,
+		noTrunc:     options.NoTrunc,
+		tmpl:        tmpl,
+		client:      client,
+		store:       client.ContentStore(),
+		provider:    containerdutil.NewProvider(client),
+		snapshotter: options.GOptions.Snapshotter,
+	}
+
+	// The images that share the same target are printed once, with multiple names
+	var targets []digest.Digest
+	byTarget := make(map[digest.Digest][]images.Image)
+	for _, img := range imageList {
+		if _, ok := byTarget[img.Target.Digest]; !ok {
+			targets = append(targets, img.Target.Digest)
+		}
+		byTarget[img.Target.Digest] = append(byTarget[img.Target.Digest], img)
+	}
+	for _, target := range targets {
+		if err := printer.printImage(ctx, byTarget[target]); err != nil {
+			log.G(ctx).Warn(err)
+		}
+	}
+	if f, ok := w.(formatter.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+type treePrinter struct {
+	w            io.Writer
+	all, noTrunc bool
+	tmpl         *template.Template
+	client       *containerd.Client
+	store        content.Store
+	provider     content.Provider
+	snapshotter  string
+}
+
+func (x *treePrinter) shortID(dgst digest.Digest) string {
+	if x.noTrunc {
+		return dgst.String()
+	}
+	return dgst.Encoded()[:12]
+}
+
+// manifests returns the manifest descriptors of the image target.
+func (x *treePrinter) manifests(ctx context.Context, target ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+	if images.IsManifestType(target.MediaType) {
+		return []ocispec.Descriptor{target}, nil
+	}
+	if !images.IsIndexType(target.MediaType) {
+		return nil, fmt.Errorf("unknown media type: %s", target.MediaType)
+	}
+	indexData, err := containerdutil.ReadBlob(ctx, x.provider, target)
+	if err != nil {
+		return nil, err
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(indexData, &index); err != nil {
+		return nil, err
+	}
+	return index.Manifests, nil
+}
+
+// readTreeManifest reads the manifest of the descriptor, and looks up which of its blobs are available locally.
+func (x *treePrinter) readTreeManifest(ctx context.Context, desc ocispec.Descriptor) treeManifestPrintable {
+	m := treeManifestPrintable{
+		ID:           x.shortID(desc.Digest),
+		Kind:         "image",
+		Snapshotters: map[string]string{},
+	}
+	if desc.Annotations[annotationReferenceType] == attestationManifest {
+		m.Kind = "attestation"
+		if subject, err := digest.Parse(desc.Annotations[annotationReferenceDigest]); err == nil {
+			m.AttestationFor = x.shortID(subject)
+		}
+	}
+	if desc.Platform != nil {
+		m.Platform = platforms.FormatAll(platforms.Normalize(*desc.Platform))
+	}
+
+	// The blob size only counts the blobs that exist in the content store
+	exists := func(d ocispec.Descriptor) bool {
+		if _, err := x.store.Info(ctx, d.Digest); err != nil {
+			return false
+		}
+		m.blobSize += d.Size
+		return true
+	}
+	defer func() {
+		m.Size = units.HumanSize(float64(m.size))
+		m.BlobSize = units.HumanSize(float64(m.blobSize))
+	}()
+
+	if !exists(desc) {
+		return m
+	}
+	manifestData, err := containerdutil.ReadBlob(ctx, x.provider, desc)
+	if err != nil {
+		return m
+	}
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return m
+	}
+	available := exists(manifest.Config)
+	for _, layer := range manifest.Layers {
+		available = exists(layer) && available
+	}
+	m.Available = available
+	if m.Kind == "attestation" || !available {
+		return m
+	}
+
+	configData, err := containerdutil.ReadBlob(ctx, x.provider, manifest.Config)
+	if err != nil {
+		return m
+	}
+	var config ocispec.Image
+	if err := json.Unmarshal(configData, &config); err != nil {
+		return m
+	}
+	if m.Platform == "" {
+		m.Platform = platforms.FormatAll(platforms.Normalize(config.Platform))
+	}
+
+	// The unpacker labels the config blob with the chain ID of the snapshot, for each snapshotter
+	info, err := x.store.Info(ctx, manifest.Config.Digest)
+	if err != nil {
+		return m
+	}
+	chainID := identity.ChainID(config.RootFS.DiffIDs).String()
+	for k, v := range info.Labels {
+		snapshotter, ok := strings.CutPrefix(k, labelSnapshotRefPrefix)
+		if !ok || strings.Contains(snapshotter, "/") || v != chainID {
+			continue
+		}
+		_, usage, err := imgutil.ResourceUsage(ctx, x.client.SnapshotService(snapshotter), chainID)
+		if err != nil {
+			log.G(ctx).WithError(err).Debugf("failed to get the usage of %q in snapshotter %q", chainID, snapshotter)
+			continue
+		}
+		m.Snapshotters[snapshotter] = units.HumanSize(float64(usage.Size))
+		if snapshotter == x.snapshotter {
+			m.size = usage.Size
+		}
+	}
+	return m
+}
+
+func (x *treePrinter) printImage(ctx context.Context, imgs []images.Image) error {
+	img := imgs[0]
+	descs, err := x.manifests(ctx, img.Target)
+	if err != nil {
+		return err
+	}
+
+	p := treeImagePrintable{
+		ID:           x.shortID(img.Target.Digest),
+		Digest:       img.Target.Digest.String(),
+		CreatedAt:    img.CreatedAt.Round(time.Second).Local().String(),
+		CreatedSince: formatter.TimeSinceInHuman(img.CreatedAt),
+		Manifests:    []treeManifestPrintable{},
+	}
+	for _, i := range imgs {
+		p.Names = append(p.Names, i.Name)
+	}
+	sort.Strings(p.Names)
+
+	var size, blobSize int64
+	for _, desc := range descs {
+		m := x.readTreeManifest(ctx, desc)
+		// Without --all, only the platforms available locally are shown, like Docker
+		if !x.all && (m.Kind == "attestation" || !m.Available) {
+			continue
+		}
+		size += m.size
+		blobSize += m.blobSize
+		p.Manifests = append(p.Manifests, m)
+	}
+	p.Size = units.HumanSize(float64(size))
+	p.BlobSize = units.HumanSize(float64(blobSize))
+
+	if x.tmpl != nil {
+		var b bytes.Buffer
+		if err := x.tmpl.Execute(&b, p); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(x.w, b.String())
+		return err
+	}
+
+	if _, err := fmt.Fprintf(x.w, "%s\t%s\t%s\t%s\t%s\t\n", strings.Join(p.Names, ", "), p.ID, p.CreatedSince, p.Size, p.BlobSize); err != nil {
+		return err
+	}
+	for i, m := range p.Manifests {
+		branch := "├─ "
+		if i == len(p.Manifests)-1 {
+			branch = "└─ "
+		}
+		name := m.Platform
+		if m.Kind == "attestation" {
+			name = "attestation"
+			if m.AttestationFor != "" {
+				name += " for " + m.AttestationFor
+			}
+		}
+		if name == "" {
+			name = "<unknown>"
+		}
+		if _, err := fmt.Fprintf(x.w, "%s%s\t%s\t\t%s\t%s\t%t\n", branch, name, m.ID, m.Size, m.BlobSize, m.Available); err != nil {
+			return err
+		}
+	}
+	return nil
+}
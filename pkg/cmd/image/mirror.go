@@ -0,0 +1,313 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	distributionref "github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/containerdutil"
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/push"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/referrers"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
+	"github.com/containerd/platforms"
+)
+
+// Mirror copies the image `srcRawRef` to `dstRawRef`, from a registry to another registry.
+//
+// The content is fetched into the content store without unpacking the snapshots, and pushed from there.
+// The images created in the image store for mirroring are removed at the end, and the images that
+// existed before are restored to their previous targets.
+func Mirror(ctx context.Context, client *containerd.Client, srcRawRef, dstRawRef string, options types.ImageMirrorOptions) error {
+	srcNamed, err := distributionref.ParseDockerRef(srcRawRef)
+	if err != nil {
+		return err
+	}
+	dstNamed, err := distributionref.ParseDockerRef(dstRawRef)
+	if err != nil {
+		return err
+	}
+	if srcNamed.String() == dstNamed.String() {
+		return fmt.Errorf("the source and the destination must be different, got %q", srcNamed.String())
+	}
+	ociSpecPlatform, err := platformutil.NewOCISpecPlatformSlice(options.AllPlatforms, options.Platforms)
+	if err != nil {
+		return err
+	}
+
+	tmp := &tmpImages{client: client}
+	// The images must be removed even when ctx is cancelled (e.g., Ctrl-C)
+	defer tmp.remove(context.WithoutCancel(ctx))
+
+	unpack := false
+	if err := tmp.add(ctx, srcNamed.String()); err != nil {
+		return err
+	}
+	ensured, err := imgutil.EnsureImage(ctx, client, srcNamed.String(), types.ImagePullOptions{
+		Stdout:          options.Stdout,
+		Stderr:          options.Stderr,
+		GOptions:        options.GOptions,
+		VerifyOptions:   types.ImageVerifyOptions{Provider: "none"},
+		OCISpecPlatform: ociSpecPlatform,
+		Unpack:          &unpack,
+		Mode:            "always",
+		Quiet:           options.Quiet,
+	})
+	if err != nil {
+		return err
+	}
+	target := ensured.Image.Target()
+	if options.Referrers && !options.AllPlatforms && images.IsIndexType(target.MediaType) {
+		// The digest of the index changes when the platforms are reduced, and the referrers would refer to nothing
+		return errors.New("--referrers requires --all-platforms to mirror a multi-platform image")
+	}
+
+	if err := tmp.put(ctx, dstNamed.String(), target); err != nil {
+		return err
+	}
+	if err := Push(ctx, client, dstNamed.String(), types.ImagePushOptions{
+		Stdout:                         options.Stdout,
+		GOptions:                       options.GOptions,
+		Platforms:                      options.Platforms,
+		AllPlatforms:                   options.AllPlatforms,
+		Quiet:                          options.Quiet,
+		AllowNondistributableArtifacts: options.AllowNondistributableArtifacts,
+	}); err != nil {
+		return err
+	}
+
+	if !options.Referrers {
+		return nil
+	}
+	subjects, err := manifestDigests(ctx, client, target)
+	if err != nil {
+		return err
+	}
+	m := &referrersMirror{
+		client:  client,
+		tmp:     tmp,
+		src:     srcNamed,
+		dst:     dstNamed,
+		options: options,
+	}
+	return m.mirror(ctx, subjects)
+}
+
+// manifestDigests returns the digest of the target, and the digests of its manifests when the target is an index.
+func manifestDigests(ctx context.Context, client *containerd.Client, target ocispec.Descriptor) ([]digest.Digest, error) {
+	dgsts := []digest.Digest{target.Digest}
+	if !images.IsIndexType(target.MediaType) {
+		return dgsts, nil
+	}
+	children, err := images.Children(ctx, containerdutil.NewProvider(client), target)
+	if err != nil {
+		return nil, err
+	}
+	for _, child := range children {
+		if images.IsManifestType(child.MediaType) || images.IsIndexType(child.MediaType) {
+			dgsts = append(dgsts, child.Digest)
+		}
+	}
+	return dgsts, nil
+}
+
+// registryResolver returns the resolver for the registry of `named`, and the options of dockerconfigresolver for it.
+// The reference must exist in the registry.
+//
+// When insecure is set, skips verifying certs, and also falls back to HTTP when the registry does not speak HTTPS
+func registryResolver(ctx context.Context, named distributionref.Named, gOptions types.GlobalCommandOptions) (remotes.Resolver, []dockerconfigresolver.Opt, error) {
+	refDomain := distributionref.Domain(named)
+	var dOpts []dockerconfigresolver.Opt
+	if gOptions.InsecureRegistry {
+		dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
+	}
+	dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(gOptions.HostsDir))
+	resolver, err := dockerconfigresolver.New(ctx, refDomain, dOpts...)
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, _, err := resolver.Resolve(ctx, named.String()); err != nil {
+		if !gOptions.InsecureRegistry || (!errutil.IsErrHTTPResponseToHTTPSClient(err) && !errutil.IsErrConnectionRefused(err)) {
+			return nil, nil, err
+		}
+		log.G(ctx).WithError(err).Warnf("server %q does not seem to support HTTPS, falling back to plain HTTP", refDomain)
+		dOpts = append(dOpts, dockerconfigresolver.WithPlainHTTP(true))
+		resolver, err = dockerconfigresolver.New(ctx, refDomain, dOpts...)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+	return resolver, dOpts, nil
+}
+
+type referrersMirror struct {
+	client      *containerd.Client
+	tmp         *tmpImages
+	src, dst    distributionref.Named
+	srcResolver remotes.Resolver
+	srcOpts     []dockerconfigresolver.Opt
+	dstResolver remotes.Resolver
+	options     types.ImageMirrorOptions
+}
+
+// mirror copies the referrers of the subjects, and the referrers of the referrers.
+func (m *referrersMirror) mirror(ctx context.Context, subjects []digest.Digest) error {
+	var err error
+	m.srcResolver, m.srcOpts, err = registryResolver(ctx, m.src, m.options.GOptions)
+	if err != nil {
+		return err
+	}
+	m.dstResolver, _, err = registryResolver(ctx, m.dst, m.options.GOptions)
+	if err != nil {
+		return err
+	}
+
+	seen := make(map[digest.Digest]bool)
+	for len(subjects) > 0 {
+		subject := subjects[0]
+		subjects = subjects[1:]
+		if seen[subject] {
+			continue
+		}
+		seen[subject] = true
+
+		descs, err := referrers.List(ctx, m.srcResolver, m.src, subject, "", m.srcOpts...)
+		if err != nil {
+			return fmt.Errorf("failed to list the referrers of %s: %w", subject, err)
+		}
+		for _, desc := range descs {
+			if seen[desc.Digest] {
+				continue
+			}
+			if err := m.copy(ctx, "@"+desc.Digest.String()); err != nil {
+				return err
+			}
+			subjects = append(subjects, desc.Digest)
+		}
+
+		// The referrers of the registries without the referrers API, and the artifacts of cosign
+		tags, err := referrers.ExistingTags(ctx, m.srcResolver, m.src, subject)
+		if err != nil {
+			return fmt.Errorf("failed to resolve the referrers tags of %s: %w", subject, err)
+		}
+		for _, tag := range tags {
+			if err := m.copy(ctx, ":"+tag); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// copy copies the object (":<tag>" or "@<digest>") from the source repository to the destination repository.
+func (m *referrersMirror) copy(ctx context.Context, object string) error {
+	srcRef, dstRef := m.src.Name()+object, m.dst.Name()+object
+	log.G(ctx).Debugf("mirroring referrer %q to %q", srcRef, dstRef)
+	if err := m.tmp.add(ctx, srcRef); err != nil {
+		return err
+	}
+	img, err := m.client.Fetch(ctx, srcRef, containerd.WithResolver(m.srcResolver), containerd.WithPlatformMatcher(platforms.All))
+	if err != nil {
+		return fmt.Errorf("failed to fetch %q: %w", srcRef, err)
+	}
+	if err := m.tmp.put(ctx, dstRef, img.Target); err != nil {
+		return err
+	}
+	return push.Push(ctx, m.client, m.dstResolver, dockerconfigresolver.PushTracker, m.options.Stdout, dstRef, dstRef,
+		platforms.All, m.options.AllowNondistributableArtifacts, m.options.Quiet)
+}
+
+// tmpImages tracks the images created or updated in the image store, to revert them at the end.
+// The images that did not exist before are removed, and the images that existed before are restored.
+type tmpImages struct {
+	client  *containerd.Client
+	names   []string
+	tracked map[string]bool
+	// saved holds the images that existed before, keyed by name
+	saved map[string]images.Image
+}
+
+// add tracks the image, saving it if it exists.
+func (t *tmpImages) add(ctx context.Context, name string) error {
+	if t.tracked[name] {
+		return nil
+	}
+	img, err := t.client.ImageService().Get(ctx, name)
+	if err == nil {
+		if t.saved == nil {
+			t.saved = make(map[string]images.Image)
+		}
+		t.saved[name] = img
+	} else if !errdefs.IsNotFound(err) {
+		return err
+	}
+	if t.tracked == nil {
+		t.tracked = make(map[string]bool)
+	}
+	t.tracked[name] = true
+	t.names = append(t.names, name)
+	return nil
+}
+
+// put creates or updates the image, and tracks it.
+func (t *tmpImages) put(ctx context.Context, name string, target ocispec.Descriptor) error {
+	if err := t.add(ctx, name); err != nil {
+		return err
+	}
+	imageService := t.client.ImageService()
+	img := images.Image{Name: name, Target: target}
+	if _, err := imageService.Create(ctx, img); err != nil {
+		if !errdefs.IsAlreadyExists(err) {
+			return err
+		}
+		if _, err := imageService.Update(ctx, img, "target"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// remove removes the tracked images that did not exist before, and restores the targets of the others.
+func (t *tmpImages) remove(ctx context.Context) {
+	imageService := t.client.ImageService()
+	for i := len(t.names) - 1; i >= 0; i-- {
+		name := t.names[i]
+		if img, ok := t.saved[name]; ok {
+			if _, err := imageService.Update(ctx, img, "target"); err != nil {
+				log.G(ctx).WithError(err).Warnf("failed to restore the image %q", name)
+			}
+			continue
+		}
+		if err := imageService.Delete(ctx, name); err != nil && !errdefs.IsNotFound(err) {
+			log.G(ctx).WithError(err).Warnf("failed to remove the temporary image %q", name)
+		}
+	}
+}
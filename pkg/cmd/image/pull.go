@@ -19,12 +19,16 @@ package image
 import (
 	"context"
 	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 
+	distributionref "github.com/distribution/reference"
+
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/referrers"
 	"github.com/containerd/nerdctl/v2/pkg/ipfs"
 	"github.com/containerd/nerdctl/v2/pkg/referenceutil"
 	"github.com/containerd/nerdctl/v2/pkg/signutil"
@@ -32,14 +36,50 @@ import (
 
 // Pull pulls an image specified by `rawRef`.
 func Pull(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePullOptions) error {
-	_, err := EnsureImage(ctx, client, rawRef, options)
+	if options.AllTags {
+		return pullAllTags(ctx, client, rawRef, options)
+	}
+	ensured, err := EnsureImage(ctx, client, rawRef, options)
 	if err != nil {
 		return err
 	}
+	if options.WithReferrers {
+		return fetchReferrers(ctx, client, ensured, options)
+	}
 
 	return nil
 }
 
+// fetchReferrers fetches the referrers of the pulled image and of its manifests into the content store,
+// so that they can be exported with `nerdctl save --with-referrers`.
+func fetchReferrers(ctx context.Context, client *containerd.Client, ensured *imgutil.EnsuredImage, options types.ImagePullOptions) error {
+	named, err := distributionref.ParseDockerRef(ensured.Ref)
+	if err != nil {
+		return err
+	}
+	resolver, dOpts, err := registryResolver(ctx, named, options.GOptions)
+	if err != nil {
+		return err
+	}
+	subjects, err := manifestDigests(ctx, client, ensured.Image.Target())
+	if err != nil {
+		return err
+	}
+	ctx, done, err := client.WithLease(ctx)
+	if err != nil {
+		return err
+	}
+	defer done(ctx)
+	fetched, err := referrers.Fetch(ctx, client.ContentStore(), resolver, named, subjects, dOpts...)
+	if err != nil {
+		return err
+	}
+	if !options.Quiet {
+		fmt.Fprintf(options.Stdout, "%s: fetched %d referrers\n", ensured.Ref, len(fetched))
+	}
+	return nil
+}
+
 // EnsureImage pulls an image either from ipfs or from registry.
 func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePullOptions) (*imgutil.EnsuredImage, error) {
 	var ensured *imgutil.EnsuredImage
@@ -48,6 +88,9 @@ func EnsureImage(ctx context.Context, client *containerd.Client, rawRef string,
 		if options.VerifyOptions.Provider != "none" {
 			return nil, errors.New("--verify flag is not supported on IPFS as of now")
 		}
+		if options.WithReferrers {
+			return nil, errors.New("--with-referrers flag is not supported on IPFS")
+		}
 
 		var ipfsPath string
 		if options.IPFSAddress != "" {
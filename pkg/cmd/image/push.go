@@ -52,6 +52,9 @@ import (
 
 // Push pushes an image specified by `rawRef`.
 func Push(ctx context.Context, client *containerd.Client, rawRef string, options types.ImagePushOptions) error {
+	if options.AllTags {
+		return pushAllTags(ctx, client, rawRef, options)
+	}
 	if scheme, ref, err := referenceutil.ParseIPFSRefWithScheme(rawRef); err == nil {
 		if scheme != "ipfs" {
 			return fmt.Errorf("ipfs scheme is only supported but got %q", scheme)
@@ -0,0 +1,134 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package image
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"text/tabwriter"
+	"text/template"
+
+	distributionref "github.com/distribution/reference"
+	"github.com/docker/go-units"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/referrers"
+)
+
+type referrerPrintable struct {
+	Digest       string
+	ArtifactType string
+	MediaType    string
+	Size         string
+	Created      string
+	// Tag is the cosign tag of the referrer, for the referrers that do not declare the subject
+	Tag         string
+	Annotations map[string]string
+}
+
+// Referrers lists the referrers of the image `rawRef` in the registry, such as signatures, attestations and SBOMs.
+// The signatures, attestations and SBOMs pushed with the cosign tags are listed too, unless the artifact type is specified.
+func Referrers(ctx context.Context, rawRef string, options types.ImageReferrersOptions) error {
+	named, err := distributionref.ParseDockerRef(rawRef)
+	if err != nil {
+		return err
+	}
+	resolver, dOpts, err := registryResolver(ctx, named, options.GOptions)
+	if err != nil {
+		return err
+	}
+	_, subject, err := resolver.Resolve(ctx, named.String())
+	if err != nil {
+		return err
+	}
+	descs, err := referrers.List(ctx, resolver, named, subject.Digest, options.ArtifactType, dOpts...)
+	if err != nil {
+		return err
+	}
+	var printables []referrerPrintable
+	for _, desc := range descs {
+		printables = append(printables, newReferrerPrintable(desc, ""))
+	}
+	if options.ArtifactType == "" {
+		tags, err := referrers.ExistingTags(ctx, resolver, named, subject.Digest)
+		if err != nil {
+			return err
+		}
+		for _, tag := range tags {
+			if tag == referrers.FallbackTag(subject.Digest) {
+				continue
+			}
+			_, desc, err := resolver.Resolve(ctx, named.Name()+":"+tag)
+			if err != nil {
+				return err
+			}
+			printables = append(printables, newReferrerPrintable(desc, tag))
+		}
+	}
+
+	w := options.Stdout
+	var tmpl *template.Template
+	switch options.Format {
+	case "", "table":
+		w = tabwriter.NewWriter(w, 4, 8, 4, ' ', 0)
+		if !options.Quiet {
+			fmt.Fprintln(w, "DIGEST\tARTIFACT TYPE\tSIZE\tCREATED\tTAG")
+		}
+	case "raw", "wide":
+		return errors.New("unsupported format: \"raw\" and \"wide\" are not supported for referrers")
+	default:
+		if options.Quiet {
+			return errors.New("format and quiet must not be specified together")
+		}
+		tmpl, err = formatter.ParseTemplate(options.Format)
+		if err != nil {
+			return err
+		}
+	}
+	for _, p := range printables {
+		switch {
+		case tmpl != nil:
+			if err := tmpl.Execute(w, p); err != nil {
+				return err
+			}
+			fmt.Fprintln(w)
+		case options.Quiet:
+			fmt.Fprintln(w, p.Digest)
+		default:
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Digest, p.ArtifactType, p.Size, p.Created, p.Tag)
+		}
+	}
+	if f, ok := w.(formatter.Flusher); ok {
+		return f.Flush()
+	}
+	return nil
+}
+
+func newReferrerPrintable(desc ocispec.Descriptor, tag string) referrerPrintable {
+	return referrerPrintable{
+		Digest:       desc.Digest.String(),
+		ArtifactType: desc.ArtifactType,
+		MediaType:    desc.MediaType,
+		Size:         units.HumanSize(float64(desc.Size)),
+		Created:      desc.Annotations[ocispec.AnnotationCreated],
+		Tag:          tag,
+		Annotations:  desc.Annotations,
+	}
+}
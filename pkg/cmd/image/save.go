@@ -20,10 +20,13 @@ import (
 	"context"
 	"fmt"
 
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/images/archive"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/idutil/imagewalker"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/referrers"
 	"github.com/containerd/nerdctl/v2/pkg/platformutil"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
@@ -41,6 +44,7 @@ func Save(ctx context.Context, client *containerd.Client, images []string, optio
 	imageStore := client.ImageService()
 
 	savedImages := make(map[string]struct{})
+	var targets []ocispec.Descriptor
 	walker := &imagewalker.ImageWalker{
 		Client: client,
 		OnFound: func(ctx context.Context, found imagewalker.Found) error {
@@ -52,6 +56,7 @@ func Save(ctx context.Context, client *containerd.Client, images []string, optio
 			if _, ok := savedImages[imgDigest]; !ok {
 				savedImages[imgDigest] = struct{}{}
 				exportOpts = append(exportOpts, archive.WithImage(imageStore, imgName))
+				targets = append(targets, found.Image.Target)
 			}
 			return nil
 		},
@@ -62,5 +67,21 @@ func Save(ctx context.Context, client *containerd.Client, images []string, optio
 		return err
 	}
 
+	if options.WithReferrers {
+		for _, target := range targets {
+			subjects, err := manifestDigests(ctx, client, target)
+			if err != nil {
+				return err
+			}
+			descs, err := referrers.Local(ctx, client.ContentStore(), subjects)
+			if err != nil {
+				return err
+			}
+			for _, desc := range descs {
+				exportOpts = append(exportOpts, archive.WithManifest(desc))
+			}
+		}
+	}
+
 	return client.Export(ctx, options.Stdout, exportOpts...)
 }
@@ -0,0 +1,66 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"context"
+
+	distributionref "github.com/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+// Annotate sets the platform of the entry `imageRef` in the local manifest list `listRef`.
+func Annotate(ctx context.Context, listRef, imageRef string, options types.ManifestAnnotateOptions) error {
+	listNamed, err := distributionref.ParseDockerRef(listRef)
+	if err != nil {
+		return err
+	}
+	named, err := distributionref.ParseDockerRef(imageRef)
+	if err != nil {
+		return err
+	}
+	store, err := newStore(options.GOptions)
+	if err != nil {
+		return err
+	}
+	entry, err := store.Get(listNamed.String(), named.String())
+	if err != nil {
+		return err
+	}
+	if entry.Descriptor.Platform == nil {
+		entry.Descriptor.Platform = &ocispec.Platform{}
+	}
+	platform := entry.Descriptor.Platform
+	if options.OS != "" {
+		platform.OS = options.OS
+	}
+	if options.Arch != "" {
+		platform.Architecture = options.Arch
+	}
+	if options.Variant != "" {
+		platform.Variant = options.Variant
+	}
+	if options.OSVersion != "" {
+		platform.OSVersion = options.OSVersion
+	}
+	if len(options.OSFeatures) > 0 {
+		platform.OSFeatures = options.OSFeatures
+	}
+	return store.Save(listNamed.String(), entry)
+}
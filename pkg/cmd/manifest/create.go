@@ -0,0 +1,115 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	distributionref "github.com/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/manifeststore"
+)
+
+// Create creates a local manifest list `listRef` with the manifests of `imageRefs`, fetched from the registry.
+func Create(ctx context.Context, listRef string, imageRefs []string, options types.ManifestCreateOptions) error {
+	listNamed, err := distributionref.ParseDockerRef(listRef)
+	if err != nil {
+		return err
+	}
+	store, err := newStore(options.GOptions)
+	if err != nil {
+		return err
+	}
+	exists, err := store.Exists(listNamed.String())
+	if err != nil {
+		return err
+	}
+	if exists && !options.Amend {
+		return fmt.Errorf("refusing to amend an existing manifest list with no --amend flag")
+	}
+
+	insecure := options.Insecure || options.GOptions.InsecureRegistry
+	var entries []*manifeststore.Entry
+	for _, imageRef := range imageRefs {
+		named, err := distributionref.ParseDockerRef(imageRef)
+		if err != nil {
+			return err
+		}
+		if distributionref.Domain(named) != distributionref.Domain(listNamed) {
+			return fmt.Errorf("cannot use source images from a different registry than the target image: %s != %s",
+				distributionref.Domain(named), distributionref.Domain(listNamed))
+		}
+		var entry *manifeststore.Entry
+		if err := withResolver(ctx, named, insecure, options.GOptions.HostsDir, nil, func(resolver remotes.Resolver) error {
+			entry, err = fetchEntry(ctx, resolver, named)
+			return err
+		}); err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+	for _, entry := range entries {
+		if err := store.Save(listNamed.String(), entry); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(options.Stdout, "Created manifest list %s\n", listNamed.String())
+	return nil
+}
+
+// fetchEntry fetches the manifest of `named`, and fills the platform of the entry from the image config.
+func fetchEntry(ctx context.Context, resolver remotes.Resolver, named distributionref.Named) (*manifeststore.Entry, error) {
+	desc, raw, fetcher, err := resolveManifest(ctx, resolver, named)
+	if err != nil {
+		return nil, err
+	}
+	if images.IsIndexType(desc.MediaType) {
+		return nil, fmt.Errorf("%s is a manifest list", named.String())
+	}
+	if !images.IsManifestType(desc.MediaType) {
+		return nil, fmt.Errorf("%s has unsupported media type %q", named.String(), desc.MediaType)
+	}
+	manifest, err := parseManifest(raw)
+	if err != nil {
+		return nil, err
+	}
+	configBlob, err := fetchBlob(ctx, fetcher, manifest.Config)
+	if err != nil {
+		return nil, err
+	}
+	var config ocispec.Image
+	if err := json.Unmarshal(configBlob, &config); err != nil {
+		return nil, err
+	}
+	platform := config.Platform
+	return &manifeststore.Entry{
+		Ref: named.String(),
+		Descriptor: ocispec.Descriptor{
+			MediaType: desc.MediaType,
+			Digest:    desc.Digest,
+			Size:      desc.Size,
+			Platform:  &platform,
+		},
+		Raw: raw,
+	}, nil
+}
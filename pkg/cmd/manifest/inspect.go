@@ -0,0 +1,187 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+
+	distributionref "github.com/distribution/reference"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/manifeststore"
+)
+
+// verboseEntry is the verbose representation of a manifest, compatible with `docker manifest inspect -v`.
+type verboseEntry struct {
+	Ref              string
+	Descriptor       ocispec.Descriptor
+	Raw              []byte
+	SchemaV2Manifest *ocispec.Manifest `json:",omitempty"`
+	OCIManifest      *ocispec.Manifest `json:",omitempty"`
+}
+
+func newVerboseEntry(entry *manifeststore.Entry) (*verboseEntry, error) {
+	manifest, err := parseManifest(entry.Raw)
+	if err != nil {
+		return nil, err
+	}
+	v := &verboseEntry{
+		Ref:        entry.Ref,
+		Descriptor: entry.Descriptor,
+		Raw:        entry.Raw,
+	}
+	if entry.Descriptor.MediaType == images.MediaTypeDockerSchema2Manifest {
+		v.SchemaV2Manifest = manifest
+	} else {
+		v.OCIManifest = manifest
+	}
+	return v, nil
+}
+
+// Inspect prints a manifest or a manifest list.
+//
+// With a single reference, Inspect prints the local manifest list, or the manifest (list) in the registry.
+// With two references, Inspect prints the entry `refs[1]` of the local manifest list `refs[0]`.
+func Inspect(ctx context.Context, refs []string, options types.ManifestInspectOptions) error {
+	store, err := newStore(options.GOptions)
+	if err != nil {
+		return err
+	}
+	named, err := distributionref.ParseDockerRef(refs[0])
+	if err != nil {
+		return err
+	}
+
+	if len(refs) == 2 {
+		imageNamed, err := distributionref.ParseDockerRef(refs[1])
+		if err != nil {
+			return err
+		}
+		entry, err := store.Get(named.String(), imageNamed.String())
+		if err != nil {
+			return err
+		}
+		if options.Verbose {
+			v, err := newVerboseEntry(entry)
+			if err != nil {
+				return err
+			}
+			return printJSON(options, v)
+		}
+		return printRawJSON(options, entry.Raw)
+	}
+
+	exists, err := store.Exists(named.String())
+	if err != nil {
+		return err
+	}
+	if exists {
+		entries, err := store.List(named.String())
+		if err != nil {
+			return err
+		}
+		return printEntries(options, entries, buildIndex(entries))
+	}
+
+	insecure := options.Insecure || options.GOptions.InsecureRegistry
+	return withResolver(ctx, named, insecure, options.GOptions.HostsDir, nil, func(resolver remotes.Resolver) error {
+		return inspectRemote(ctx, resolver, named, options)
+	})
+}
+
+func inspectRemote(ctx context.Context, resolver remotes.Resolver, named distributionref.Named, options types.ManifestInspectOptions) error {
+	desc, raw, fetcher, err := resolveManifest(ctx, resolver, named)
+	if err != nil {
+		return err
+	}
+	if !options.Verbose {
+		return printRawJSON(options, raw)
+	}
+	if !images.IsIndexType(desc.MediaType) {
+		v, err := newVerboseEntry(&manifeststore.Entry{
+			Ref:        named.String(),
+			Descriptor: desc,
+			Raw:        raw,
+		})
+		if err != nil {
+			return err
+		}
+		return printJSON(options, v)
+	}
+
+	var index ocispec.Index
+	if err := json.Unmarshal(raw, &index); err != nil {
+		return err
+	}
+	entries := make([]*manifeststore.Entry, 0, len(index.Manifests))
+	for _, manifestDesc := range index.Manifests {
+		manifestRaw, err := fetchBlob(ctx, fetcher, manifestDesc)
+		if err != nil {
+			return err
+		}
+		canonical, err := distributionref.WithDigest(distributionref.TrimNamed(named), manifestDesc.Digest)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, &manifeststore.Entry{
+			Ref:        canonical.String(),
+			Descriptor: manifestDesc,
+			Raw:        manifestRaw,
+		})
+	}
+	return printEntries(options, entries, index)
+}
+
+// printEntries prints the index, or the verbose entries of the index.
+func printEntries(options types.ManifestInspectOptions, entries []*manifeststore.Entry, index ocispec.Index) error {
+	if !options.Verbose {
+		return printJSON(options, index)
+	}
+	verboseEntries := make([]*verboseEntry, len(entries))
+	for i, entry := range entries {
+		v, err := newVerboseEntry(entry)
+		if err != nil {
+			return err
+		}
+		verboseEntries[i] = v
+	}
+	return printJSON(options, verboseEntries)
+}
+
+func printJSON(options types.ManifestInspectOptions, v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "    ")
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(options.Stdout, string(b))
+	return nil
+}
+
+func printRawJSON(options types.ManifestInspectOptions, raw []byte) error {
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, raw, "", "    "); err != nil {
+		return err
+	}
+	fmt.Fprintln(options.Stdout, buf.String())
+	return nil
+}
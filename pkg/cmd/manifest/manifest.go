@@ -0,0 +1,164 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	distributionref "github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+	dockerconfig "github.com/containerd/containerd/v2/core/remotes/docker/config"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+	"github.com/containerd/nerdctl/v2/pkg/manifeststore"
+)
+
+// maxManifestSize is the maximum size of a manifest fetched from a registry.
+const maxManifestSize = 4 << 20
+
+func newStore(globalOptions types.GlobalCommandOptions) (manifeststore.Store, error) {
+	dataStore, err := clientutil.DataStore(globalOptions.DataRoot, globalOptions.Address)
+	if err != nil {
+		return nil, err
+	}
+	return manifeststore.New(dataStore, globalOptions.Namespace)
+}
+
+// withResolver calls `fn` with a resolver for the registry of `named`.
+//
+// When insecure is set, skips verifying certs, and also falls back to HTTP when the registry does not speak HTTPS.
+func withResolver(ctx context.Context, named distributionref.Named, insecure bool, hostsDirs []string, tracker docker.StatusTracker,
+	fn func(remotes.Resolver) error) error {
+	refDomain := distributionref.Domain(named)
+
+	var dOpts []dockerconfigresolver.Opt
+	if insecure {
+		log.G(ctx).Warnf("skipping verifying HTTPS certs for %q", refDomain)
+		dOpts = append(dOpts, dockerconfigresolver.WithSkipVerifyCerts(true))
+	}
+	dOpts = append(dOpts, dockerconfigresolver.WithHostsDirs(hostsDirs))
+	newResolver := func() (remotes.Resolver, error) {
+		ho, err := dockerconfigresolver.NewHostOptions(ctx, refDomain, dOpts...)
+		if err != nil {
+			return nil, err
+		}
+		return docker.NewResolver(docker.ResolverOptions{
+			Tracker: tracker,
+			Hosts:   dockerconfig.ConfigureHosts(ctx, *ho),
+		}), nil
+	}
+
+	resolver, err := newResolver()
+	if err != nil {
+		return err
+	}
+	if err = fn(resolver); err != nil {
+		// In some circumstance (e.g. people just use 80 port to support pure http), the error will contain message like "dial tcp <port>: connection refused"
+		if !errutil.IsErrHTTPResponseToHTTPSClient(err) && !errutil.IsErrConnectionRefused(err) {
+			return err
+		}
+		if insecure {
+			log.G(ctx).WithError(err).Warnf("server %q does not seem to support HTTPS, falling back to plain HTTP", refDomain)
+			dOpts = append(dOpts, dockerconfigresolver.WithPlainHTTP(true))
+			resolver, err = newResolver()
+			if err != nil {
+				return err
+			}
+			return fn(resolver)
+		}
+		log.G(ctx).WithError(err).Errorf("server %q does not seem to support HTTPS", refDomain)
+		log.G(ctx).Info("Hint: you may want to try --insecure to allow plain HTTP (if you are in a trusted network)")
+		return err
+	}
+	return nil
+}
+
+// fetchBlob fetches the blob of `desc` and verifies its digest.
+func fetchBlob(ctx context.Context, fetcher remotes.Fetcher, desc ocispec.Descriptor) ([]byte, error) {
+	if desc.Size > maxManifestSize {
+		return nil, fmt.Errorf("blob %s is too large (%d bytes)", desc.Digest, desc.Size)
+	}
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(io.LimitReader(rc, desc.Size))
+	if err != nil {
+		return nil, err
+	}
+	if dgst := digest.FromBytes(b); dgst != desc.Digest {
+		return nil, fmt.Errorf("digest mismatch for %s: got %s", desc.Digest, dgst)
+	}
+	return b, nil
+}
+
+// resolveManifest resolves `named` and fetches its manifest or index.
+func resolveManifest(ctx context.Context, resolver remotes.Resolver, named distributionref.Named) (ocispec.Descriptor, []byte, remotes.Fetcher, error) {
+	name, desc, err := resolver.Resolve(ctx, named.String())
+	if err != nil {
+		return ocispec.Descriptor{}, nil, nil, err
+	}
+	fetcher, err := resolver.Fetcher(ctx, name)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, nil, err
+	}
+	b, err := fetchBlob(ctx, fetcher, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, nil, nil, err
+	}
+	return desc, b, fetcher, nil
+}
+
+// buildIndex builds the manifest list of the entries.
+// A Docker manifest list is built when all the entries are Docker manifests, otherwise an OCI index is built.
+func buildIndex(entries []*manifeststore.Entry) ocispec.Index {
+	mediaType := images.MediaTypeDockerSchema2ManifestList
+	manifests := make([]ocispec.Descriptor, len(entries))
+	for i, entry := range entries {
+		if entry.Descriptor.MediaType != images.MediaTypeDockerSchema2Manifest {
+			mediaType = ocispec.MediaTypeImageIndex
+		}
+		manifests[i] = entry.Descriptor
+	}
+	index := ocispec.Index{
+		MediaType: mediaType,
+		Manifests: manifests,
+	}
+	index.SchemaVersion = 2
+	return index
+}
+
+// parseManifest parses the manifest blob of an entry.
+func parseManifest(raw []byte) (*ocispec.Manifest, error) {
+	var manifest ocispec.Manifest
+	if err := json.Unmarshal(raw, &manifest); err != nil {
+		return nil, err
+	}
+	return &manifest, nil
+}
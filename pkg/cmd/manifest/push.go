@@ -0,0 +1,132 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	distributionref "github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/push"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+	"github.com/containerd/platforms"
+)
+
+// distributionSourceLabelPrefix is the prefix of the content labels that tell the pusher
+// where the blobs can be mounted from.
+const distributionSourceLabelPrefix = "containerd.io/distribution.source."
+
+// Push pushes the local manifest list `listRef` to the registry.
+//
+// The manifests of the entries are written to the content store, labeled with their source repositories,
+// so that the layers and configs are mounted from the source repositories instead of being uploaded.
+func Push(ctx context.Context, client *containerd.Client, listRef string, options types.ManifestPushOptions) error {
+	listNamed, err := distributionref.ParseDockerRef(listRef)
+	if err != nil {
+		return err
+	}
+	ref := listNamed.String()
+	store, err := newStore(options.GOptions)
+	if err != nil {
+		return err
+	}
+	entries, err := store.List(ref)
+	if err != nil {
+		return err
+	}
+
+	ctx, done, err := client.WithLease(ctx)
+	if err != nil {
+		return err
+	}
+	defer done(ctx)
+
+	cs := client.ContentStore()
+	index := buildIndex(entries)
+	indexLabels := make(map[string]string)
+	for i, entry := range entries {
+		named, err := distributionref.ParseNormalizedNamed(entry.Ref)
+		if err != nil {
+			return err
+		}
+		sourceLabel := distributionSourceLabelPrefix + distributionref.Domain(named)
+		if err := content.WriteBlob(ctx, cs, entry.Descriptor.Digest.String(), bytes.NewReader(entry.Raw), entry.Descriptor); err != nil {
+			return fmt.Errorf("failed to write the manifest of %s: %w", entry.Ref, err)
+		}
+		// The manifest may already exist in the content store, so the label is set with Update rather than WriteBlob
+		info, err := cs.Info(ctx, entry.Descriptor.Digest)
+		if err != nil {
+			return err
+		}
+		repos := strutil.DedupeStrSlice(append(strings.Split(info.Labels[sourceLabel], ","), distributionref.Path(named)))
+		if _, err := cs.Update(ctx, content.Info{
+			Digest: entry.Descriptor.Digest,
+			Labels: map[string]string{sourceLabel: strings.Trim(strings.Join(repos, ","), ",")},
+		}, "labels."+sourceLabel); err != nil {
+			return err
+		}
+		indexLabels[fmt.Sprintf("containerd.io/gc.ref.content.m.%d", i)] = entry.Descriptor.Digest.String()
+	}
+	indexJSON, err := json.MarshalIndent(index, "", "    ")
+	if err != nil {
+		return err
+	}
+	indexDesc := ocispec.Descriptor{
+		MediaType: index.MediaType,
+		Digest:    digest.FromBytes(indexJSON),
+		Size:      int64(len(indexJSON)),
+	}
+	if err := content.WriteBlob(ctx, cs, indexDesc.Digest.String(), bytes.NewReader(indexJSON), indexDesc,
+		content.WithLabels(indexLabels)); err != nil {
+		return fmt.Errorf("failed to write the manifest list: %w", err)
+	}
+
+	// push.Push pushes an image of the image store, so a temporary image is created for the manifest list
+	pushRef := ref + "-tmp-manifest-list"
+	if _, err := client.ImageService().Create(ctx, images.Image{Name: pushRef, Target: indexDesc}); err != nil {
+		return err
+	}
+	defer client.ImageService().Delete(ctx, pushRef, images.SynchronousDelete())
+	log.G(ctx).Infof("pushing manifest list %s (%s, %d entries)", ref, index.MediaType, len(entries))
+
+	pushTracker := docker.NewInMemoryTracker()
+	insecure := options.Insecure || options.GOptions.InsecureRegistry
+	if err := withResolver(ctx, listNamed, insecure, options.GOptions.HostsDir, pushTracker, func(resolver remotes.Resolver) error {
+		return push.Push(ctx, client, resolver, pushTracker, options.Stdout, pushRef, ref, platforms.All, false, true)
+	}); err != nil {
+		return err
+	}
+	fmt.Fprintln(options.Stdout, indexDesc.Digest.String())
+
+	if options.Purge {
+		return store.Remove(ref)
+	}
+	return nil
+}
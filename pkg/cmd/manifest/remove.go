@@ -0,0 +1,50 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifest
+
+import (
+	"context"
+	"errors"
+
+	distributionref "github.com/distribution/reference"
+
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+// Remove removes the local manifest lists.
+func Remove(ctx context.Context, listRefs []string, options types.ManifestRemoveOptions) error {
+	store, err := newStore(options.GOptions)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, listRef := range listRefs {
+		listNamed, err := distributionref.ParseDockerRef(listRef)
+		if err == nil {
+			err = store.Remove(listNamed.String())
+		}
+		if err != nil {
+			log.G(ctx).WithError(err).Errorf("failed to remove manifest list %q", listRef)
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New("failed to remove one or more manifest lists")
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"runtime"
+
+	"github.com/containernetworking/cni/libcni"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/oci"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/netwalker"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+// Connect connects a container to a CNI network.
+//
+// If the container is running, the network is attached to its network namespace immediately.
+// Otherwise, the network is attached by the OCI hook when the container is started.
+func Connect(ctx context.Context, client *containerd.Client, options types.NetworkConnectOptions) error {
+	if runtime.GOOS != "linux" {
+		return errors.New("network connect is only supported on linux")
+	}
+	e, err := netutil.NewCNIEnv(options.GOptions.CNIPath, options.GOptions.CNINetConfPath, netutil.WithNamespace(options.GOptions.Namespace))
+	if err != nil {
+		return err
+	}
+	net, err := findNetwork(ctx, e, options.Network)
+	if err != nil {
+		return err
+	}
+	dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	hs, err := hostsstore.NewStore(dataStore)
+	if err != nil {
+		return err
+	}
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			return connectContainer(ctx, e, hs, net, found.Container, options.GOptions.Namespace)
+		},
+	}
+	if n, err := walker.Walk(ctx, options.Container); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no such container %s", options.Container)
+	}
+	return nil
+}
+
+func connectContainer(ctx context.Context, e *netutil.CNIEnv, hs hostsstore.Store, net *netutil.NetworkConfig,
+	container containerd.Container, ns string) error {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	networks, err := containerNetworks(lab)
+	if err != nil {
+		return err
+	}
+	if netType, err := nettype.Detect(networks); err != nil {
+		return err
+	} else if netType != nettype.CNI {
+		return fmt.Errorf("container %s uses network %q and cannot be connected to a CNI network", container.ID(), networks[0])
+	}
+	if strutil.InStringSlice(networks, net.Name) {
+		return fmt.Errorf("container %s is already connected to network %s", container.ID(), net.Name)
+	}
+	// The OCI hook passes the static addresses to all the networks of the container,
+	// so a static IP cannot be valid for another network, and a static MAC would be duplicated.
+	if lab[labels.IPAddress] != "" || lab[labels.IP6Address] != "" {
+		return fmt.Errorf("container %s has a static IP address and cannot be connected to another network", container.ID())
+	}
+	if lab[labels.MACAddress] != "" {
+		return fmt.Errorf("container %s has a static MAC address and cannot be connected to another network", container.ID())
+	}
+
+	running, err := isTaskRunning(ctx, container)
+	if err != nil {
+		return err
+	}
+	if running {
+		if err := attachNetwork(ctx, e, hs, net, container, lab, ns); err != nil {
+			return err
+		}
+	}
+	if err := updateContainerNetworks(ctx, container, append(networks, net.Name)); err != nil {
+		if running {
+			if detachErr := detachNetwork(ctx, e, hs, net, container, ns); detachErr != nil {
+				log.G(ctx).WithError(detachErr).Warnf("failed to detach network %q from container %s", net.Name, container.ID())
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+// attachNetwork runs CNI ADD of net against the netns of the running container,
+// and registers the result to the hosts store.
+// The CNI args are the same as the ones passed by the OCI hook.
+func attachNetwork(ctx context.Context, e *netutil.CNIEnv, hs hostsstore.Store, net *netutil.NetworkConfig,
+	container containerd.Container, lab map[string]string, ns string) error {
+	nsPath, err := containerutil.ContainerNetNSPath(ctx, container)
+	if err != nil {
+		return err
+	}
+	meta, err := hs.Load(ns, container.ID())
+	if err != nil {
+		return err
+	}
+	rt := &libcni.RuntimeConf{
+		ContainerID: ns + "-" + container.ID(),
+		NetNS:       nsPath,
+		IfName:      netutil.NextInterfaceName(meta.Networks),
+		Args: [][2]string{
+			{"IgnoreUnknown", "1"},
+			{"NERDCTL_CNI_DHCP_HOSTNAME", lab[labels.Hostname]},
+		},
+	}
+	result, err := e.AttachNetwork(ctx, net, rt)
+	if err != nil {
+		return fmt.Errorf("failed to attach network %q to container %s: %w", net.Name, container.ID(), err)
+	}
+	if err := hs.AddNetwork(ns, container.ID(), net.Name, result); err != nil {
+		if detachErr := e.DetachNetwork(ctx, net, rt); detachErr != nil {
+			log.G(ctx).WithError(detachErr).Warnf("failed to detach network %q from container %s", net.Name, container.ID())
+		}
+		return err
+	}
+	return nil
+}
+
+// findNetwork returns the CNI network specified by name, short ID, or long ID.
+func findNetwork(ctx context.Context, e *netutil.CNIEnv, req string) (*netutil.NetworkConfig, error) {
+	var net *netutil.NetworkConfig
+	walker := netwalker.NetworkWalker{
+		Client: e,
+		OnFound: func(ctx context.Context, found netwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			net = found.Network
+			return nil
+		},
+	}
+	if n, err := walker.Walk(ctx, req); err != nil {
+		return nil, err
+	} else if n == 0 {
+		return nil, fmt.Errorf("no such network: %s", req)
+	}
+	return net, nil
+}
+
+func containerNetworks(lab map[string]string) ([]string, error) {
+	var networks []string
+	if networksJSON, ok := lab[labels.Networks]; ok {
+		if err := json.Unmarshal([]byte(networksJSON), &networks); err != nil {
+			return nil, err
+		}
+	}
+	return networks, nil
+}
+
+// updateContainerNetworks updates the "nerdctl/networks" label and the corresponding OCI annotation,
+// so that the OCI hook sets up the same networks when the container is restarted.
+func updateContainerNetworks(ctx context.Context, container containerd.Container, networks []string) error {
+	networksJSON, err := json.Marshal(networks)
+	if err != nil {
+		return err
+	}
+	m := map[string]string{
+		labels.Networks: string(networksJSON),
+	}
+	spec, err := container.Spec(ctx)
+	if err != nil {
+		return err
+	}
+	return container.Update(ctx,
+		containerd.UpdateContainerOpts(containerd.WithAdditionalContainerLabels(m)),
+		containerd.UpdateContainerOpts(containerd.WithSpec(spec, oci.WithAnnotations(m))),
+	)
+}
+
+// isTaskRunning returns true if the container has a task that is not stopped.
+func isTaskRunning(ctx context.Context, container containerd.Container) (bool, error) {
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return false, nil
+		}
+		return false, err
+	}
+	status, err := task.Status(ctx)
+	if err != nil {
+		return false, err
+	}
+	return status.Status != containerd.Stopped, nil
+}
@@ -0,0 +1,153 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package network
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"runtime"
+	"slices"
+
+	"github.com/containernetworking/cni/libcni"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/containerwalker"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+)
+
+// Disconnect disconnects a container from a CNI network.
+//
+// If the container is running, the network is detached from its network namespace immediately.
+func Disconnect(ctx context.Context, client *containerd.Client, options types.NetworkDisconnectOptions) error {
+	if runtime.GOOS != "linux" {
+		return errors.New("network disconnect is only supported on linux")
+	}
+	e, err := netutil.NewCNIEnv(options.GOptions.CNIPath, options.GOptions.CNINetConfPath, netutil.WithNamespace(options.GOptions.Namespace))
+	if err != nil {
+		return err
+	}
+	dataStore, err := clientutil.DataStore(options.GOptions.DataRoot, options.GOptions.Address)
+	if err != nil {
+		return err
+	}
+	hs, err := hostsstore.NewStore(dataStore)
+	if err != nil {
+		return err
+	}
+	walker := &containerwalker.ContainerWalker{
+		Client: client,
+		OnFound: func(ctx context.Context, found containerwalker.Found) error {
+			if found.MatchCount > 1 {
+				return fmt.Errorf("multiple IDs found with provided prefix: %s", found.Req)
+			}
+			return disconnectContainer(ctx, e, hs, options.Network, found.Container, options.GOptions.Namespace, options.Force)
+		},
+	}
+	if n, err := walker.Walk(ctx, options.Container); err != nil {
+		return err
+	} else if n == 0 {
+		return fmt.Errorf("no such container %s", options.Container)
+	}
+	return nil
+}
+
+func disconnectContainer(ctx context.Context, e *netutil.CNIEnv, hs hostsstore.Store, req string,
+	container containerd.Container, ns string, force bool) error {
+	lab, err := container.Labels(ctx)
+	if err != nil {
+		return err
+	}
+	networks, err := containerNetworks(lab)
+	if err != nil {
+		return err
+	}
+	// With --force, a container can be disconnected from a network that no longer exists.
+	name := req
+	net, err := findNetwork(ctx, e, req)
+	if err != nil {
+		if !force {
+			return err
+		}
+		log.G(ctx).WithError(err).Warnf("disconnecting container %s from network %q", container.ID(), req)
+	} else {
+		name = net.Name
+	}
+	idx := slices.Index(networks, name)
+	if idx < 0 {
+		return fmt.Errorf("container %s is not connected to network %s", container.ID(), name)
+	}
+	if len(networks) == 1 {
+		return fmt.Errorf("container %s cannot be disconnected from its last network %s", container.ID(), name)
+	}
+
+	running, err := isTaskRunning(ctx, container)
+	if err != nil {
+		return err
+	}
+	if running {
+		if net == nil {
+			err = fmt.Errorf("no such network: %s", name)
+		} else {
+			err = detachNetwork(ctx, e, hs, net, container, ns)
+		}
+		if err != nil {
+			if !force {
+				return err
+			}
+			log.G(ctx).WithError(err).Warnf("failed to detach network %q from container %s", name, container.ID())
+			if err := hs.RemoveNetwork(ns, container.ID(), name); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	return updateContainerNetworks(ctx, container, slices.Delete(networks, idx, idx+1))
+}
+
+// detachNetwork runs CNI DEL of net against the netns of the running container,
+// and unregisters the network from the hosts store.
+func detachNetwork(ctx context.Context, e *netutil.CNIEnv, hs hostsstore.Store, net *netutil.NetworkConfig,
+	container containerd.Container, ns string) error {
+	meta, err := hs.Load(ns, container.ID())
+	if err != nil {
+		return err
+	}
+	ifName := netutil.ContainerInterfaceName(meta.Networks[net.Name])
+	if ifName == "" {
+		return fmt.Errorf("unknown interface name of network %q in container %s", net.Name, container.ID())
+	}
+	nsPath, err := containerutil.ContainerNetNSPath(ctx, container)
+	if err != nil {
+		return err
+	}
+	rt := &libcni.RuntimeConf{
+		ContainerID: ns + "-" + container.ID(),
+		NetNS:       nsPath,
+		IfName:      ifName,
+		Args:        [][2]string{{"IgnoreUnknown", "1"}},
+	}
+	if err := e.DetachNetwork(ctx, net, rt); err != nil {
+		return fmt.Errorf("failed to detach network %q from container %s: %w", net.Name, container.ID(), err)
+	}
+	return hs.RemoveNetwork(ns, container.ID(), net.Name)
+}
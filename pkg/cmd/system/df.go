@@ -0,0 +1,565 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"text/template"
+
+	"github.com/docker/go-units"
+	"github.com/opencontainers/go-digest"
+	"github.com/opencontainers/image-spec/identity"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/snapshots"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/buildkitutil"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/builder"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
+	"github.com/containerd/nerdctl/v2/pkg/containerdutil"
+	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil"
+	"github.com/containerd/nerdctl/v2/pkg/inspecttypes/dockercompat"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
+)
+
+// diskUsageSummary is the printable summary of a category, compatible with `docker system df --format`.
+type diskUsageSummary struct {
+	Type        string
+	TotalCount  string
+	Active      string
+	Size        string
+	Reclaimable string
+}
+
+// imageDiskUsage is the printable disk usage of an image, compatible with `docker system df -v --format`.
+type imageDiskUsage struct {
+	Repository   string
+	Tag          string
+	ID           string
+	CreatedSince string
+	Size         string
+	SharedSize   string
+	UniqueSize   string
+	Containers   string
+}
+
+// containerDiskUsage is the printable disk usage of a container, compatible with `docker system df -v --format`.
+type containerDiskUsage struct {
+	ID           string
+	Image        string
+	Command      string
+	LocalVolumes string
+	Size         string
+	CreatedSince string
+	Status       string
+	Names        string
+}
+
+// volumeDiskUsage is the printable disk usage of a volume, compatible with `docker system df -v --format`.
+type volumeDiskUsage struct {
+	Name  string
+	Links string
+	Size  string
+}
+
+// buildCacheDiskUsage is the printable disk usage of a build cache record, compatible with `docker system df -v --format`.
+type buildCacheDiskUsage struct {
+	ID           string
+	CacheType    string
+	Size         string
+	CreatedSince string
+	LastUsedAt   string
+	UsageCount   string
+	Shared       string
+}
+
+// diskUsage is the verbose disk usage, printed with `nerdctl system df -v --format`.
+type diskUsage struct {
+	Images     []imageDiskUsage
+	Containers []containerDiskUsage
+	Volumes    []volumeDiskUsage
+	BuildCache []buildCacheDiskUsage
+}
+
+// DiskUsage prints the disk space used by images, containers, volumes and the build cache.
+//
+// Images are accounted with the blobs in the content store and the unpacked snapshots of the
+// snapshotter in use and of the other snapshotters that unpacked them. The blobs and snapshots
+// that are shared by several images are accounted once.
+// Containers are accounted with the usage of their writable snapshots.
+func DiskUsage(ctx context.Context, client *containerd.Client, options types.SystemDiskUsageOptions) error {
+	containers, err := client.Containers(ctx)
+	if err != nil {
+		return err
+	}
+
+	var (
+		summaries []diskUsageSummary
+		verbose   diskUsage
+	)
+
+	imageSummary, imageUsages, err := imagesDiskUsage(ctx, client, containers, options)
+	if err != nil {
+		return err
+	}
+	summaries = append(summaries, imageSummary)
+	verbose.Images = imageUsages
+
+	containerSummary, containerUsages, err := containersDiskUsage(ctx, client, containers)
+	if err != nil {
+		return err
+	}
+	summaries = append(summaries, containerSummary)
+	verbose.Containers = containerUsages
+
+	volumeSummary, volumeUsages, err := volumesDiskUsage(ctx, containers, options)
+	if err != nil {
+		return err
+	}
+	summaries = append(summaries, volumeSummary)
+	verbose.Volumes = volumeUsages
+
+	buildCacheSummary, buildCacheUsages, buildCacheSize := buildCachesDiskUsage(ctx, options)
+	summaries = append(summaries, buildCacheSummary)
+	verbose.BuildCache = buildCacheUsages
+
+	if options.Format != "" {
+		tmpl, err := formatter.ParseTemplate(options.Format)
+		if err != nil {
+			return err
+		}
+		if options.Verbose {
+			return executeTemplate(options.Stdout, tmpl, verbose)
+		}
+		for _, s := range summaries {
+			if err := executeTemplate(options.Stdout, tmpl, s); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if !options.Verbose {
+		w := tabwriter.NewWriter(options.Stdout, 4, 8, 4, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tTOTAL\tACTIVE\tSIZE\tRECLAIMABLE")
+		for _, s := range summaries {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", s.Type, s.TotalCount, s.Active, s.Size, s.Reclaimable)
+		}
+		return w.Flush()
+	}
+
+	fmt.Fprintln(options.Stdout, "Images space usage:")
+	fmt.Fprintln(options.Stdout)
+	w := tabwriter.NewWriter(options.Stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "REPOSITORY\tTAG\tIMAGE ID\tCREATED\tSIZE\tSHARED SIZE\tUNIQUE SIZE\tCONTAINERS")
+	for _, u := range verbose.Images {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", u.Repository, u.Tag, u.ID, u.CreatedSince, u.Size, u.SharedSize, u.UniqueSize, u.Containers)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(options.Stdout)
+	fmt.Fprintln(options.Stdout, "Containers space usage:")
+	fmt.Fprintln(options.Stdout)
+	w = tabwriter.NewWriter(options.Stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "CONTAINER ID\tIMAGE\tCOMMAND\tLOCAL VOLUMES\tSIZE\tCREATED\tSTATUS\tNAMES")
+	for _, u := range verbose.Containers {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", u.ID, u.Image, u.Command, u.LocalVolumes, u.Size, u.CreatedSince, u.Status, u.Names)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(options.Stdout)
+	fmt.Fprintln(options.Stdout, "Local Volumes space usage:")
+	fmt.Fprintln(options.Stdout)
+	w = tabwriter.NewWriter(options.Stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "VOLUME NAME\tLINKS\tSIZE")
+	for _, u := range verbose.Volumes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", u.Name, u.Links, u.Size)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Fprintln(options.Stdout)
+	fmt.Fprintf(options.Stdout, "Build cache usage: %s\n", units.HumanSize(float64(buildCacheSize)))
+	fmt.Fprintln(options.Stdout)
+	w = tabwriter.NewWriter(options.Stdout, 4, 8, 4, ' ', 0)
+	fmt.Fprintln(w, "CACHE ID\tCACHE TYPE\tSIZE\tCREATED\tLAST USED\tUSAGE\tSHARED")
+	for _, u := range verbose.BuildCache {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n", u.ID, u.CacheType, u.Size, u.CreatedSince, u.LastUsedAt, u.UsageCount, u.Shared)
+	}
+	return w.Flush()
+}
+
+func executeTemplate(w io.Writer, tmpl *template.Template, x interface{}) error {
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, x); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w, b.String())
+	return err
+}
+
+func newDiskUsageSummary(typ string, total, active int, size, reclaimable int64) diskUsageSummary {
+	reclaimableStr := units.HumanSize(float64(reclaimable))
+	if size > 0 {
+		reclaimableStr = fmt.Sprintf("%s (%d%%)", reclaimableStr, reclaimable*100/size)
+	}
+	return diskUsageSummary{
+		Type:        typ,
+		TotalCount:  fmt.Sprintf("%d", total),
+		Active:      fmt.Sprintf("%d", active),
+		Size:        units.HumanSize(float64(size)),
+		Reclaimable: reclaimableStr,
+	}
+}
+
+// labelSnapshotRefPrefix is set on the config blob of the image by the unpacker, for each snapshotter.
+const labelSnapshotRefPrefix = "containerd.io/gc.ref.snapshot."
+
+// snapshotSizeCache caches the sizes of the snapshots of all the snapshotters,
+// as the snapshots are shared by several images.
+type snapshotSizeCache struct {
+	client       *containerd.Client
+	snapshotters map[string]snapshots.Snapshotter
+	sizes        map[string]int64
+}
+
+func (x *snapshotSizeCache) get(ctx context.Context, snapshotter, chainID string) int64 {
+	key := snapshotter + "/" + chainID
+	if size, ok := x.sizes[key]; ok {
+		return size
+	}
+	sn, ok := x.snapshotters[snapshotter]
+	if !ok {
+		sn = containerdutil.SnapshotService(x.client, snapshotter)
+		x.snapshotters[snapshotter] = sn
+	}
+	usage, err := sn.Usage(ctx, chainID)
+	if err != nil && !errdefs.IsNotFound(err) {
+		log.G(ctx).WithError(err).Debugf("failed to get the usage of snapshot %q in snapshotter %q", chainID, snapshotter)
+	}
+	x.sizes[key] = usage.Size
+	return usage.Size
+}
+
+// imageResources returns the sizes of the blobs and the unpacked snapshots of the image, keyed by
+// digests and snapshotters/chain IDs. The blobs that are not present in the content store are skipped.
+// The snapshots are accounted for the snapshotter in use, and for the snapshotters that unpacked the image.
+func imageResources(ctx context.Context, cs content.Store, snapshotter string, img images.Image,
+	snapshotSizes *snapshotSizeCache) (map[string]int64, error) {
+	resources := make(map[string]int64)
+	handler := images.HandlerFunc(func(ctx context.Context, desc ocispec.Descriptor) ([]ocispec.Descriptor, error) {
+		info, err := cs.Info(ctx, desc.Digest)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				return nil, images.ErrSkipDesc
+			}
+			return nil, err
+		}
+		resources[desc.Digest.String()] = info.Size
+
+		switch desc.MediaType {
+		case images.MediaTypeDockerSchema2Config, ocispec.MediaTypeImageConfig:
+			b, err := content.ReadBlob(ctx, cs, desc)
+			if err != nil {
+				return nil, err
+			}
+			var config ocispec.Image
+			if err := json.Unmarshal(b, &config); err != nil {
+				return nil, err
+			}
+			snapshotters := []string{snapshotter}
+			for k := range info.Labels {
+				if sn, ok := strings.CutPrefix(k, labelSnapshotRefPrefix); ok && sn != snapshotter && !strings.Contains(sn, "/") {
+					snapshotters = append(snapshotters, sn)
+				}
+			}
+			for _, chainID := range identity.ChainIDs(append([]digest.Digest{}, config.RootFS.DiffIDs...)) {
+				for _, sn := range snapshotters {
+					if size := snapshotSizes.get(ctx, sn, chainID.String()); size > 0 {
+						resources["snapshot:"+sn+"/"+chainID.String()] = size
+					}
+				}
+			}
+			return nil, nil
+		}
+		return images.Children(ctx, cs, desc)
+	})
+	if err := images.Walk(ctx, handler, img.Target); err != nil {
+		return nil, err
+	}
+	return resources, nil
+}
+
+func imagesDiskUsage(ctx context.Context, client *containerd.Client, containers []containerd.Container,
+	options types.SystemDiskUsageOptions) (diskUsageSummary, []imageDiskUsage, error) {
+	imageList, err := client.ImageService().List(ctx)
+	if err != nil {
+		return diskUsageSummary{}, nil, err
+	}
+	containerCounts := make(map[string]int)
+	for _, c := range containers {
+		info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return diskUsageSummary{}, nil, err
+		}
+		containerCounts[info.Image]++
+	}
+
+	snapshotSizes := &snapshotSizeCache{
+		client:       client,
+		snapshotters: make(map[string]snapshots.Snapshotter),
+		sizes:        make(map[string]int64),
+	}
+	var (
+		cs           = client.ContentStore()
+		allResources = make([]map[string]int64, len(imageList))
+		refCounts    = make(map[string]int)
+		activeKeys   = make(map[string]struct{})
+		sizes        = make(map[string]int64)
+		active       int
+	)
+	for i, img := range imageList {
+		resources, err := imageResources(ctx, cs, options.GOptions.Snapshotter, img, snapshotSizes)
+		if err != nil {
+			log.G(ctx).WithError(err).Warnf("failed to get the disk usage of image %q", img.Name)
+		}
+		allResources[i] = resources
+		isActive := containerCounts[img.Name] > 0
+		if isActive {
+			active++
+		}
+		for key, size := range resources {
+			refCounts[key]++
+			sizes[key] = size
+			if isActive {
+				activeKeys[key] = struct{}{}
+			}
+		}
+	}
+
+	var totalSize, reclaimable int64
+	for key, size := range sizes {
+		totalSize += size
+		if _, ok := activeKeys[key]; !ok {
+			reclaimable += size
+		}
+	}
+
+	usages := make([]imageDiskUsage, len(imageList))
+	for i, img := range imageList {
+		var size, sharedSize int64
+		for key, s := range allResources[i] {
+			size += s
+			if refCounts[key] > 1 {
+				sharedSize += s
+			}
+		}
+		repository, tag := imgutil.ParseRepoTag(img.Name)
+		if repository == "" {
+			repository = "<none>"
+		}
+		if tag == "" {
+			tag = "<none>"
+		}
+		usages[i] = imageDiskUsage{
+			Repository:   repository,
+			Tag:          tag,
+			ID:           strings.Split(img.Target.Digest.String(), ":")[1][:12],
+			CreatedSince: formatter.TimeSinceInHuman(img.CreatedAt),
+			Size:         units.HumanSize(float64(size)),
+			SharedSize:   units.HumanSize(float64(sharedSize)),
+			UniqueSize:   units.HumanSize(float64(size - sharedSize)),
+			Containers:   fmt.Sprintf("%d", containerCounts[img.Name]),
+		}
+	}
+	return newDiskUsageSummary("Images", len(imageList), active, totalSize, reclaimable), usages, nil
+}
+
+func containersDiskUsage(ctx context.Context, client *containerd.Client, containers []containerd.Container) (diskUsageSummary, []containerDiskUsage, error) {
+	var (
+		usages            []containerDiskUsage
+		active            int
+		size, reclaimable int64
+	)
+	for _, c := range containers {
+		info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return diskUsageSummary{}, nil, err
+		}
+		var rwSize int64
+		if info.Snapshotter != "" && info.SnapshotKey != "" {
+			usage, err := client.SnapshotService(info.Snapshotter).Usage(ctx, info.SnapshotKey)
+			if err != nil {
+				log.G(ctx).WithError(err).Debugf("failed to get the usage of the snapshot of container %q", c.ID())
+			}
+			rwSize = usage.Size
+		}
+		size += rwSize
+
+		running := false
+		if task, err := c.Task(ctx, nil); err == nil {
+			if status, err := task.Status(ctx); err == nil {
+				running = status.Status == containerd.Running
+			}
+		}
+		if running {
+			active++
+		} else {
+			reclaimable += rwSize
+		}
+
+		command := ""
+		if spec, err := c.Spec(ctx); err == nil {
+			command = formatter.InspectContainerCommandTrunc(spec)
+		}
+		id := c.ID()
+		if len(id) > 12 {
+			id = id[:12]
+		}
+		usages = append(usages, containerDiskUsage{
+			ID:           id,
+			Image:        info.Image,
+			Command:      command,
+			LocalVolumes: fmt.Sprintf("%d", countVolumes(info.Labels)),
+			Size:         units.HumanSize(float64(rwSize)),
+			CreatedSince: formatter.TimeSinceInHuman(info.CreatedAt),
+			Status:       formatter.ContainerStatus(ctx, c),
+			Names:        info.Labels[labels.Name],
+		})
+	}
+	return newDiskUsageSummary("Containers", len(usages), active, size, reclaimable), usages, nil
+}
+
+// countVolumes returns the number of volumes mounted in the container.
+func countVolumes(containerLabels map[string]string) int {
+	mountsJSON, ok := containerLabels[labels.Mounts]
+	if !ok {
+		return 0
+	}
+	var mounts []dockercompat.MountPoint
+	if err := json.Unmarshal([]byte(mountsJSON), &mounts); err != nil {
+		return 0
+	}
+	n := 0
+	for _, m := range mounts {
+		if m.Type == mountutil.Volume {
+			n++
+		}
+	}
+	return n
+}
+
+func volumesDiskUsage(ctx context.Context, containers []containerd.Container, options types.SystemDiskUsageOptions) (diskUsageSummary, []volumeDiskUsage, error) {
+	vols, err := volume.Volumes(options.GOptions.Namespace, options.GOptions.DataRoot, options.GOptions.Address, true, nil)
+	if err != nil {
+		return diskUsageSummary{}, nil, err
+	}
+	links, err := volume.UsedVolumes(ctx, containers)
+	if err != nil {
+		return diskUsageSummary{}, nil, err
+	}
+	var (
+		usages            []volumeDiskUsage
+		active            int
+		size, reclaimable int64
+	)
+	for _, vol := range vols {
+		size += vol.Size
+		if links[vol.Name] > 0 {
+			active++
+		} else {
+			reclaimable += vol.Size
+		}
+		usages = append(usages, volumeDiskUsage{
+			Name:  vol.Name,
+			Links: fmt.Sprintf("%d", links[vol.Name]),
+			Size:  units.HumanSize(float64(vol.Size)),
+		})
+	}
+	return newDiskUsageSummary("Local Volumes", len(usages), active, size, reclaimable), usages, nil
+}
+
+// buildCachesDiskUsage returns the usage of the build cache, and the size of the build cache.
+// The build cache is accounted as empty when BuildKit is not available.
+func buildCachesDiskUsage(ctx context.Context, options types.SystemDiskUsageOptions) (diskUsageSummary, []buildCacheDiskUsage, int64) {
+	var records []buildkitutil.UsageInfo
+	if options.BuildKitHost != "" {
+		var err error
+		records, err = builder.DiskUsage(ctx, types.BuilderDiskUsageOptions{
+			Stderr:       options.Stderr,
+			GOptions:     options.GOptions,
+			BuildKitHost: options.BuildKitHost,
+		})
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("failed to get the disk usage of the build cache")
+		}
+	}
+	var (
+		usages            []buildCacheDiskUsage
+		active            int
+		size, reclaimable int64
+	)
+	for _, r := range records {
+		if r.InUse {
+			active++
+		}
+		if !r.Shared {
+			size += r.Size
+			if !r.InUse {
+				reclaimable += r.Size
+			}
+		}
+		lastUsedAt := ""
+		if r.LastUsedAt != nil {
+			lastUsedAt = formatter.TimeSinceInHuman(*r.LastUsedAt)
+		}
+		usages = append(usages, buildCacheDiskUsage{
+			ID:           r.ID,
+			CacheType:    string(r.RecordType),
+			Size:         units.HumanSize(float64(r.Size)),
+			CreatedSince: formatter.TimeSinceInHuman(r.CreatedAt),
+			LastUsedAt:   lastUsedAt,
+			UsageCount:   fmt.Sprintf("%d", r.UsageCount),
+			Shared:       fmt.Sprintf("%t", r.Shared),
+		})
+	}
+	return newDiskUsageSummary("Build Cache", len(records), active, size, reclaimable), usages, size
+}
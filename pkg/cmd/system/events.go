@@ -22,48 +22,233 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
+	"sort"
 	"strings"
 	"text/template"
 	"time"
 
-	_ "github.com/containerd/containerd/api/events" // Register grpc event types
+	apievents "github.com/containerd/containerd/api/events" // Register grpc event types
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/core/events"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
 	"github.com/containerd/log"
 	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/formatter"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/typeurl/v2"
 )
 
+// Event types, compatible with Docker.
+const (
+	ContainerEventType = "container"
+	ImageEventType     = "image"
+)
+
+// EventActor describes the object that emitted an event, compatible with Docker.
+type EventActor struct {
+	ID         string
+	Attributes map[string]string
+}
+
 // EventOut contains information about an event.
+//
+// The JSON representation is compatible with `docker events --format '{{json .}}'`,
+// plus the containerd namespace of the event.
 type EventOut struct {
-	Timestamp time.Time
-	ID        string
-	Namespace string
-	Topic     string
-	Status    Status
-	Event     string
+	Status   string `json:"status,omitempty"`
+	ID       string `json:"id,omitempty"`
+	From     string `json:"from,omitempty"`
+	Type     string
+	Action   string
+	Actor    EventActor
+	Scope    string `json:"scope,omitempty"`
+	Time     int64  `json:"time,omitempty"`
+	TimeNano int64  `json:"timeNano,omitempty"`
+
+	// Namespace is the containerd namespace of the event (nerdctl extension)
+	Namespace string `json:"namespace,omitempty"`
+	// Timestamp is the time of the event (nerdctl extension)
+	Timestamp time.Time `json:"-"`
+	// Topic is the containerd topic of the event, e.g. "/tasks/start" (nerdctl extension)
+	Topic string `json:"-"`
+	// Event is the containerd event in JSON (nerdctl extension)
+	Event string `json:"-"`
 }
 
-type Status string
+// topicActions maps the containerd topics to the Docker-compatible types and actions.
+// The events of the other topics are not printed.
+var topicActions = map[string]struct{ Type, Action string }{
+	"/containers/create":  {ContainerEventType, "create"},
+	"/containers/update":  {ContainerEventType, "update"},
+	"/containers/delete":  {ContainerEventType, "destroy"},
+	"/tasks/start":        {ContainerEventType, "start"},
+	"/tasks/exit":         {ContainerEventType, "die"}, // "exec_die" for exec processes
+	"/tasks/oom":          {ContainerEventType, "oom"},
+	"/tasks/paused":       {ContainerEventType, "pause"},
+	"/tasks/resumed":      {ContainerEventType, "unpause"},
+	"/tasks/exec-added":   {ContainerEventType, "exec_create"},
+	"/tasks/exec-started": {ContainerEventType, "exec_start"},
+	"/tasks/checkpointed": {ContainerEventType, "checkpoint"},
+	"/images/create":      {ImageEventType, "tag"},
+	"/images/update":      {ImageEventType, "tag"},
+	"/images/delete":      {ImageEventType, "delete"},
+}
 
-const (
-	START   Status = "START"
-	UNKNOWN Status = "UNKNOWN"
-)
+func newEventOut(timestamp time.Time, namespace, topic, typ, action, id string, attributes map[string]string) *EventOut {
+	out := &EventOut{
+		Status: action,
+		ID:     id,
+		Type:   typ,
+		Action: action,
+		Actor: EventActor{
+			ID:         id,
+			Attributes: attributes,
+		},
+		Scope:     "local",
+		Time:      timestamp.Unix(),
+		TimeNano:  timestamp.UnixNano(),
+		Namespace: namespace,
+		Timestamp: timestamp,
+		Topic:     topic,
+	}
+	if typ == ContainerEventType {
+		out.From = attributes["image"]
+	}
+	return out
+}
+
+// containerAttributes returns the attributes of the container events: the image, the name, and the labels of the container.
+// The attributes are cached, so that they are still available after the container is deleted.
+type containerAttributes struct {
+	client *containerd.Client
+	cache  map[string]map[string]string
+}
+
+func (x *containerAttributes) get(ctx context.Context, namespace, id string) map[string]string {
+	key := namespace + "/" + id
+	if attrs, ok := x.cache[key]; ok {
+		return copyAttributes(attrs)
+	}
+	attrs := make(map[string]string)
+	c, err := x.client.LoadContainer(namespaces.WithNamespace(ctx, namespace), id)
+	if err != nil {
+		return attrs
+	}
+	info, err := c.Info(namespaces.WithNamespace(ctx, namespace), containerd.WithoutRefreshedMetadata)
+	if err != nil {
+		return attrs
+	}
+	for k, v := range info.Labels {
+		if !strings.HasPrefix(k, labels.Prefix) {
+			attrs[k] = v
+		}
+	}
+	attrs["image"] = info.Image
+	if name := info.Labels[labels.Name]; name != "" {
+		attrs["name"] = name
+	}
+	x.cache[key] = attrs
+	return copyAttributes(attrs)
+}
+
+// forget removes the attributes of the deleted container from the cache.
+func (x *containerAttributes) forget(namespace, id string) {
+	delete(x.cache, namespace+"/"+id)
+}
+
+func copyAttributes(attrs map[string]string) map[string]string {
+	res := make(map[string]string, len(attrs))
+	for k, v := range attrs {
+		res[k] = v
+	}
+	return res
+}
 
-func TopicToStatus(topic string) Status {
-	if strings.Contains(strings.ToUpper(topic), string(START)) {
-		return START
+// convertEnvelope converts the containerd event to the Docker-compatible event.
+// convertEnvelope returns nil for the events that do not have a Docker counterpart.
+func convertEnvelope(ctx context.Context, e *events.Envelope, containerAttrs *containerAttributes) (*EventOut, error) {
+	ta, ok := topicActions[e.Topic]
+	if !ok || e.Event == nil {
+		return nil, nil
+	}
+	v, err := typeurl.UnmarshalAny(e.Event)
+	if err != nil {
+		return nil, fmt.Errorf("cannot unmarshal an event from Any: %w", err)
+	}
+	eventJSON, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("cannot marshal Any into JSON: %w", err)
 	}
 
-	return UNKNOWN
+	action := ta.Action
+	var id string
+	attributes := map[string]string{}
+	switch ev := v.(type) {
+	case *apievents.ContainerCreate:
+		id = ev.ID
+	case *apievents.ContainerUpdate:
+		id = ev.ID
+	case *apievents.ContainerDelete:
+		id = ev.ID
+	case *apievents.TaskStart:
+		id = ev.ContainerID
+	case *apievents.TaskExit:
+		id = ev.ContainerID
+		if ev.ID != "" && ev.ID != ev.ContainerID {
+			action = "exec_die"
+			attributes["execID"] = ev.ID
+		}
+		attributes["exitCode"] = fmt.Sprintf("%d", ev.ExitStatus)
+	case *apievents.TaskOOM:
+		id = ev.ContainerID
+	case *apievents.TaskPaused:
+		id = ev.ContainerID
+	case *apievents.TaskResumed:
+		id = ev.ContainerID
+	case *apievents.TaskExecAdded:
+		id = ev.ContainerID
+		attributes["execID"] = ev.ExecID
+	case *apievents.TaskExecStarted:
+		id = ev.ContainerID
+		attributes["execID"] = ev.ExecID
+	case *apievents.TaskCheckpointed:
+		id = ev.ContainerID
+	case *apievents.ImageCreate:
+		id = ev.Name
+		for k, v := range ev.Labels {
+			attributes[k] = v
+		}
+	case *apievents.ImageUpdate:
+		id = ev.Name
+		for k, v := range ev.Labels {
+			attributes[k] = v
+		}
+	case *apievents.ImageDelete:
+		id = ev.Name
+	default:
+		return nil, nil
+	}
+
+	if ta.Type == ContainerEventType {
+		for k, v := range containerAttrs.get(ctx, e.Namespace, id) {
+			if _, ok := attributes[k]; !ok {
+				attributes[k] = v
+			}
+		}
+		if e.Topic == "/containers/delete" {
+			containerAttrs.forget(e.Namespace, id)
+		}
+	} else {
+		attributes["name"] = id
+	}
+	out := newEventOut(e.Timestamp, e.Namespace, e.Topic, ta.Type, action, id, attributes)
+	out.Event = string(eventJSON)
+	return out, nil
 }
 
 // Events is from https://github.com/containerd/containerd/blob/v1.4.3/cmd/ctr/commands/events/events.go
 func Events(ctx context.Context, client *containerd.Client, options types.SystemEventsOptions) error {
-	eventsClient := client.EventService()
-	eventsCh, errCh := eventsClient.Subscribe(ctx)
 	var tmpl *template.Template
 	switch options.Format {
 	case "":
@@ -77,59 +262,104 @@ func Events(ctx context.Context, client *containerd.Client, options types.System
 			return err
 		}
 	}
+	filters, err := parseEventFilters(options.Filters)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	var since, until time.Time
+	if options.Since != "" {
+		if since, err = parseTimestamp(options.Since, now); err != nil {
+			return fmt.Errorf("invalid value for --since: %w", err)
+		}
+	}
+	if options.Until != "" {
+		if until, err = parseTimestamp(options.Until, now); err != nil {
+			return fmt.Errorf("invalid value for --until: %w", err)
+		}
+		if !since.IsZero() && until.Before(since) {
+			return errors.New("--until must be after --since")
+		}
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	// Subscribe before replaying the past events, so that no event is lost in between
+	eventsCh, errCh := client.EventService().Subscribe(ctx, filters.containerdFilters()...)
+
+	containerAttrs := &containerAttributes{client: client, cache: make(map[string]map[string]string)}
+	if !since.IsZero() {
+		replayed, err := replayEvents(ctx, client, filters, containerAttrs)
+		if err != nil {
+			return err
+		}
+		for _, out := range replayed {
+			if out.Timestamp.Before(since) || (!until.IsZero() && out.Timestamp.After(until)) || !filters.match(out) {
+				continue
+			}
+			if err := printEvent(options.Stdout, tmpl, out); err != nil {
+				return err
+			}
+		}
+	}
+
+	var untilCh <-chan time.Time
+	if !until.IsZero() {
+		d := time.Until(until)
+		if d <= 0 {
+			return nil
+		}
+		untilCh = time.After(d)
+	}
 	for {
 		var e *events.Envelope
 		select {
 		case e = <-eventsCh:
 		case err := <-errCh:
 			return err
+		case <-untilCh:
+			return nil
 		}
-		if e != nil {
-			var out []byte
-			var id string
-			if e.Event != nil {
-				v, err := typeurl.UnmarshalAny(e.Event)
-				if err != nil {
-					log.G(ctx).WithError(err).Warn("cannot unmarshal an event from Any")
-					continue
-				}
-				out, err = json.Marshal(v)
-				if err != nil {
-					log.G(ctx).WithError(err).Warn("cannot marshal Any into JSON")
-					continue
-				}
-			}
-			if tmpl != nil {
-				var data map[string]interface{}
-				err := json.Unmarshal(out, &data)
-				if err != nil {
-					log.G(ctx).WithError(err).Warn("cannot marshal Any into JSON")
-				} else {
-					_, ok := data["container_id"]
-					if ok {
-						id = data["container_id"].(string)
-					}
-				}
-
-				out := EventOut{e.Timestamp, id, e.Namespace, e.Topic, TopicToStatus(e.Topic), string(out)}
-				var b bytes.Buffer
-				if err := tmpl.Execute(&b, out); err != nil {
-					return err
-				}
-				if _, err := fmt.Fprintln(options.Stdout, b.String()+"\n"); err != nil {
-					return err
-				}
-			} else {
-				if _, err := fmt.Fprintln(
-					options.Stdout,
-					e.Timestamp,
-					e.Namespace,
-					e.Topic,
-					string(out),
-				); err != nil {
-					return err
-				}
-			}
+		if e == nil {
+			continue
+		}
+		out, err := convertEnvelope(ctx, e, containerAttrs)
+		if err != nil {
+			log.G(ctx).WithError(err).Warn("cannot convert an event")
+			continue
+		}
+		if out == nil || !filters.match(out) {
+			continue
+		}
+		if err := printEvent(options.Stdout, tmpl, out); err != nil {
+			return err
+		}
+	}
+}
+
+// printEvent prints the event with the template, or in the same format as `docker events`.
+func printEvent(w io.Writer, tmpl *template.Template, out *EventOut) error {
+	if tmpl != nil {
+		var b bytes.Buffer
+		if err := tmpl.Execute(&b, out); err != nil {
+			return err
+		}
+		_, err := fmt.Fprintln(w, b.String())
+		return err
+	}
+	s := fmt.Sprintf("%s %s %s %s", out.Timestamp.UTC().Format("2006-01-02T15:04:05.000000000Z07:00"), out.Type, out.Action, out.Actor.ID)
+	if len(out.Actor.Attributes) > 0 {
+		keys := make([]string, 0, len(out.Actor.Attributes))
+		for k := range out.Actor.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		attrs := make([]string, len(keys))
+		for i, k := range keys {
+			attrs[i] = fmt.Sprintf("%s=%s", k, out.Actor.Attributes[k])
 		}
+		s += fmt.Sprintf(" (%s)", strings.Join(attrs, ", "))
 	}
+	_, err := fmt.Fprintln(w, s)
+	return err
 }
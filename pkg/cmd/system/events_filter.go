@@ -0,0 +1,196 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	distributionref "github.com/distribution/reference"
+)
+
+// eventFilters are the `--filter` flags of `nerdctl events`, keyed by the filter name.
+// Values of the same filter are OR-ed, different filters are AND-ed, as in Docker.
+type eventFilters map[string][]string
+
+var eventFilterKeys = []string{"type", "event", "container", "image", "label", "namespace"}
+
+func parseEventFilters(filters []string) (eventFilters, error) {
+	res := make(eventFilters)
+	for _, f := range filters {
+		k, v, ok := strings.Cut(f, "=")
+		if !ok || v == "" {
+			return nil, fmt.Errorf("bad format of filter (expected name=value): %q", f)
+		}
+		known := false
+		for _, key := range eventFilterKeys {
+			if k == key {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return nil, fmt.Errorf("invalid filter %q", k)
+		}
+		res[k] = append(res[k], v)
+	}
+	return res, nil
+}
+
+// containerdFilters translates the "type", "event" and "namespace" filters to containerd event filters.
+// The other filters need the container and image metadata, and are applied by match.
+func (f eventFilters) containerdFilters() []string {
+	var topics []string
+	if len(f["type"]) > 0 || len(f["event"]) > 0 {
+		for topic, ta := range topicActions {
+			if len(f["type"]) > 0 && !contains(f["type"], ta.Type) {
+				continue
+			}
+			if len(f["event"]) > 0 && !contains(f["event"], ta.Action) &&
+				!(topic == "/tasks/exit" && contains(f["event"], "exec_die")) {
+				continue
+			}
+			topics = append(topics, topic)
+		}
+		sort.Strings(topics)
+		if len(topics) == 0 {
+			// No topic can match; leave it to match
+			return nil
+		}
+	}
+	nss := f["namespace"]
+	if len(nss) == 0 {
+		nss = []string{""}
+	}
+	if len(topics) == 0 {
+		topics = []string{""}
+	}
+	var res []string
+	for _, ns := range nss {
+		for _, topic := range topics {
+			var conds []string
+			if topic != "" {
+				conds = append(conds, fmt.Sprintf("topic==%q", topic))
+			}
+			if ns != "" {
+				conds = append(conds, fmt.Sprintf("namespace==%q", ns))
+			}
+			if len(conds) > 0 {
+				res = append(res, strings.Join(conds, ","))
+			}
+		}
+	}
+	return res
+}
+
+// match returns true if the event matches all the filters.
+func (f eventFilters) match(out *EventOut) bool {
+	if v := f["type"]; len(v) > 0 && !contains(v, out.Type) {
+		return false
+	}
+	if v := f["event"]; len(v) > 0 && !contains(v, out.Action) {
+		return false
+	}
+	if v := f["namespace"]; len(v) > 0 && !contains(v, out.Namespace) {
+		return false
+	}
+	if v := f["container"]; len(v) > 0 {
+		if out.Type != ContainerEventType {
+			return false
+		}
+		matched := false
+		for _, c := range v {
+			if strings.HasPrefix(out.Actor.ID, c) || out.Actor.Attributes["name"] == c {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if v := f["image"]; len(v) > 0 {
+		image := out.Actor.Attributes["image"]
+		if out.Type == ImageEventType {
+			image = out.Actor.ID
+		}
+		matched := false
+		for _, img := range v {
+			if image == img || normalizeImageName(image) == normalizeImageName(img) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	for _, l := range f["label"] {
+		k, v, hasValue := strings.Cut(l, "=")
+		attr, ok := out.Actor.Attributes[k]
+		if !ok || (hasValue && attr != v) {
+			return false
+		}
+	}
+	return true
+}
+
+func normalizeImageName(s string) string {
+	named, err := distributionref.ParseDockerRef(s)
+	if err != nil {
+		return s
+	}
+	return named.String()
+}
+
+func contains(haystack []string, needle string) bool {
+	for _, s := range haystack {
+		if s == needle {
+			return true
+		}
+	}
+	return false
+}
+
+// parseTimestamp parses the `--since` and `--until` values: a duration relative to `now` (e.g. "10m"),
+// a Unix timestamp (e.g. "1700000000" or "1700000000.123456789"), or an RFC 3339 date or timestamp
+// (e.g. "2006-01-02", "2006-01-02T15:04:05", "2006-01-02T15:04:05Z07:00").
+func parseTimestamp(value string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(value); err == nil {
+		return now.Add(-d), nil
+	}
+	if secs, err := strconv.ParseFloat(value, 64); err == nil && !strings.ContainsAny(value, "eE") {
+		whole, frac := math.Modf(secs)
+		return time.Unix(int64(whole), int64(frac*1e9)), nil
+	}
+	for _, layout := range []string{
+		time.RFC3339Nano,
+		"2006-01-02T15:04:05.999999999",
+		"2006-01-02T15:04:05",
+		"2006-01-02T15:04",
+		"2006-01-02",
+	} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("failed to parse timestamp %q", value)
+}
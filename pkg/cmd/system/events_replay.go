@@ -0,0 +1,116 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/pkg/namespaces"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/ocihook/state"
+)
+
+// replayEvents reconstructs the past events from the current state of the containers and images,
+// as containerd does not keep the history of the events.
+//
+// The "create" and "tag" events are reconstructed from the creation times, the "start" events from
+// the lifecycle states of the containers, and the "die" events from the exit statuses of the tasks.
+// The events of the removed objects cannot be reconstructed.
+func replayEvents(ctx context.Context, client *containerd.Client, filters eventFilters, containerAttrs *containerAttributes) ([]*EventOut, error) {
+	nsList := filters["namespace"]
+	if len(nsList) == 0 {
+		var err error
+		nsList, err = client.NamespaceService().List(ctx)
+		if err != nil {
+			return nil, err
+		}
+	}
+	var res []*EventOut
+	for _, ns := range nsList {
+		nsCtx := namespaces.WithNamespace(ctx, ns)
+		containers, err := client.Containers(nsCtx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range containers {
+			outs, err := replayContainerEvents(nsCtx, ns, c, containerAttrs)
+			if err != nil {
+				if errdefs.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			res = append(res, outs...)
+		}
+
+		imgs, err := client.ImageService().List(nsCtx)
+		if err != nil {
+			return nil, err
+		}
+		for _, img := range imgs {
+			res = append(res, newEventOut(img.CreatedAt, ns, "/images/create", ImageEventType, "tag", img.Name,
+				map[string]string{"name": img.Name}))
+		}
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Timestamp.Before(res[j].Timestamp)
+	})
+	return res, nil
+}
+
+func replayContainerEvents(ctx context.Context, ns string, c containerd.Container, containerAttrs *containerAttributes) ([]*EventOut, error) {
+	info, err := c.Info(ctx, containerd.WithoutRefreshedMetadata)
+	if err != nil {
+		return nil, err
+	}
+	attrs := containerAttrs.get(ctx, ns, c.ID())
+	res := []*EventOut{
+		newEventOut(info.CreatedAt, ns, "/containers/create", ContainerEventType, "create", c.ID(), copyAttributes(attrs)),
+	}
+
+	stateDir := info.Labels[labels.StateDir]
+	if stateDir == "" {
+		return res, nil
+	}
+	lf := state.NewLifecycleState(stateDir)
+	if err := lf.WithLock(lf.Load); err != nil {
+		log.G(ctx).WithError(err).Debugf("failed to load the lifecycle state of container %q", c.ID())
+		return res, nil
+	}
+	if lf.StartedAt.IsZero() {
+		return res, nil
+	}
+	res = append(res, newEventOut(lf.StartedAt, ns, "/tasks/start", ContainerEventType, "start", c.ID(), copyAttributes(attrs)))
+
+	task, err := c.Task(ctx, nil)
+	if err != nil {
+		return res, nil
+	}
+	status, err := task.Status(ctx)
+	if err != nil || status.Status != containerd.Stopped || status.ExitTime.Before(lf.StartedAt) {
+		return res, nil
+	}
+	dieAttrs := copyAttributes(attrs)
+	dieAttrs["exitCode"] = fmt.Sprintf("%d", status.ExitStatus)
+	res = append(res, newEventOut(status.ExitTime, ns, "/tasks/exit", ContainerEventType, "die", c.ID(), dieAttrs))
+	return res, nil
+}
@@ -0,0 +1,98 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package system
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	apievents "github.com/containerd/containerd/api/events"
+	"github.com/containerd/containerd/v2/core/events"
+	"github.com/containerd/typeurl/v2"
+	"gotest.tools/v3/assert"
+)
+
+func TestEventFilters(t *testing.T) {
+	_, err := parseEventFilters([]string{"foo=bar"})
+	assert.ErrorContains(t, err, "invalid filter")
+	_, err = parseEventFilters([]string{"type"})
+	assert.ErrorContains(t, err, "bad format")
+
+	filters, err := parseEventFilters([]string{"type=container", "event=start", "event=die", "namespace=default"})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, filters.containerdFilters(), []string{
+		`topic=="/tasks/exit",namespace=="default"`,
+		`topic=="/tasks/start",namespace=="default"`,
+	})
+
+	filters, err = parseEventFilters([]string{"container=foo", "image=alpine", "label=com.example=bar"})
+	assert.NilError(t, err)
+	assert.Equal(t, len(filters.containerdFilters()), 0)
+	out := newEventOut(time.Now(), "default", "/tasks/start", ContainerEventType, "start", "0123456789ab", map[string]string{
+		"name":        "foo",
+		"image":       "docker.io/library/alpine:latest",
+		"com.example": "bar",
+	})
+	assert.Assert(t, filters.match(out))
+	out.Actor.Attributes["com.example"] = "baz"
+	assert.Assert(t, !filters.match(out))
+
+	filters, err = parseEventFilters([]string{"container=0123", "type=image"})
+	assert.NilError(t, err)
+	out = newEventOut(time.Now(), "default", "/tasks/start", ContainerEventType, "start", "0123456789ab", nil)
+	assert.Assert(t, !filters.match(out))
+	delete(filters, "type")
+	assert.Assert(t, filters.match(out))
+}
+
+func TestContainerAttributesForgetDeleted(t *testing.T) {
+	// the cached attributes are used without the client
+	containerAttrs := &containerAttributes{cache: map[string]map[string]string{
+		"default/foo": {"name": "foo", "image": "docker.io/library/alpine:latest"},
+	}}
+	ev, err := typeurl.MarshalAny(&apievents.ContainerDelete{ID: "foo"})
+	assert.NilError(t, err)
+	out, err := convertEnvelope(context.Background(), &events.Envelope{
+		Timestamp: time.Now(),
+		Namespace: "default",
+		Topic:     "/containers/delete",
+		Event:     ev,
+	}, containerAttrs)
+	assert.NilError(t, err)
+	assert.Equal(t, out.Action, "destroy")
+	assert.Equal(t, out.Actor.Attributes["name"], "foo")
+	assert.Equal(t, len(containerAttrs.cache), 0)
+}
+
+func TestParseTimestamp(t *testing.T) {
+	now := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	testCases := map[string]time.Time{
+		"10m":                  now.Add(-10 * time.Minute),
+		"1704164645":           time.Unix(1704164645, 0),
+		"1704164645.5":         time.Unix(1704164645, 500000000),
+		"2024-01-02T03:04:05Z": now,
+		"2024-01-02":           time.Date(2024, 1, 2, 0, 0, 0, 0, time.Local),
+	}
+	for value, expected := range testCases {
+		got, err := parseTimestamp(value, now)
+		assert.NilError(t, err, value)
+		assert.Assert(t, got.Equal(expected), "%s: expected %v, got %v", value, expected, got)
+	}
+	_, err := parseTimestamp("yesterday", now)
+	assert.ErrorContains(t, err, "failed to parse timestamp")
+}
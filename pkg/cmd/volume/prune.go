@@ -44,7 +44,7 @@ func Prune(ctx context.Context, client *containerd.Client, options types.VolumeP
 		return err
 	}
 
-	usedVolumesList, err := usedVolumes(ctx, containers)
+	usedVolumesList, err := UsedVolumes(ctx, containers)
 	if err != nil {
 		return err
 	}
@@ -50,7 +50,7 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 		return err
 	}
 
-	usedVolumesList, err := usedVolumes(ctx, containers)
+	usedVolumesList, err := UsedVolumes(ctx, containers)
 	if err != nil {
 		return err
 	}
@@ -85,8 +85,9 @@ func Remove(ctx context.Context, client *containerd.Client, volumes []string, op
 	return nil
 }
 
-func usedVolumes(ctx context.Context, containers []containerd.Container) (map[string]struct{}, error) {
-	usedVolumesList := make(map[string]struct{})
+// UsedVolumes returns the number of containers that use each volume.
+func UsedVolumes(ctx context.Context, containers []containerd.Container) (map[string]int, error) {
+	usedVolumesList := make(map[string]int)
 	for _, c := range containers {
 		l, err := c.Labels(ctx)
 		if err != nil {
@@ -110,7 +111,7 @@ func usedVolumes(ctx context.Context, containers []containerd.Container) (map[st
 		}
 		for _, m := range mounts {
 			if m.Type == mountutil.Volume {
-				usedVolumesList[m.Name] = struct{}{}
+				usedVolumesList[m.Name]++
 			}
 		}
 	}
@@ -28,12 +28,14 @@ import (
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/containerd/v2/pkg/identifiers"
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
 	"github.com/containerd/nerdctl/v2/pkg/reflectutil"
 )
 
 // Options groups the command line options recommended for a Compose implementation (ProjectOptions) and extra options for nerdctl
 type Options struct {
+	GOptions         types.GlobalCommandOptions
 	Project          string // empty for default
 	ProjectDirectory string
 	ConfigPaths      []string
@@ -140,21 +142,13 @@ type Composer struct {
 	client  *containerd.Client
 }
 
+// createNerdctlCmd creates the `nerdctl` command with the global flags.
+// The other commands are executed in-process; only `nerdctl build` is still executed as a command,
+// as it drives buildctl.
 func (c *Composer) createNerdctlCmd(ctx context.Context, args ...string) *exec.Cmd {
 	return exec.CommandContext(ctx, c.NerdctlCmd, append(c.NerdctlArgs, args...)...)
 }
 
-func (c *Composer) runNerdctlCmd(ctx context.Context, args ...string) error {
-	cmd := c.createNerdctlCmd(ctx, args...)
-	if c.DebugPrintFull {
-		log.G(ctx).Debugf("Running %v", cmd.Args)
-	}
-	if out, err := cmd.CombinedOutput(); err != nil {
-		return fmt.Errorf("error while executing %v: %q: %w", cmd.Args, string(out), err)
-	}
-	return nil
-}
-
 // Services returns the parsed Service objects in dependency order.
 func (c *Composer) Services(ctx context.Context, svcs ...string) ([]*serviceparser.Service, error) {
 	var services []*serviceparser.Service
@@ -19,9 +19,17 @@ package composer
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/idutil/imagewalker"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
@@ -42,23 +50,121 @@ func (c *Composer) Containers(ctx context.Context, services ...string) ([]contai
 	return containers, nil
 }
 
-func (c *Composer) containerExists(ctx context.Context, name, service string) (bool, error) {
+// findContainer returns the container of the service with the name, or nil if the container does not exist.
+func (c *Composer) findContainer(ctx context.Context, name, service string) (containerd.Container, error) {
 	// get list of containers for service
 	containers, err := c.Containers(ctx, service)
 	if err != nil {
-		return false, err
+		return nil, err
 	}
 
 	for _, container := range containers {
 		containerLabels, err := container.Labels(ctx)
 		if err != nil {
-			return false, err
+			return nil, err
 		}
 		if name == containerLabels[labels.Name] {
-			// container exists
-			return true, nil
+			return container, nil
 		}
 	}
 	// container doesn't exist
-	return false, nil
+	return nil, nil
+}
+
+// serviceConfigHash returns the hash of the service config and the image digest, for detecting the diverged containers.
+// ensureServiceImage must be called before serviceConfigHash.
+func (c *Composer) serviceConfigHash(ctx context.Context, ps *serviceparser.Service) (string, error) {
+	var imageDigest string
+	walker := &imagewalker.ImageWalker{
+		Client: c.client,
+		OnFound: func(ctx context.Context, found imagewalker.Found) error {
+			if found.MatchIndex == 0 {
+				imageDigest = found.Image.Target.Digest.String()
+			}
+			return nil
+		},
+	}
+	if _, err := walker.Walk(ctx, ps.Image); err != nil {
+		return "", err
+	}
+	return serviceparser.ConfigHash(*ps.Unparsed, imageDigest)
+}
+
+// needsRecreate returns whether the existing container needs to be recreated with the recreate strategy.
+func needsRecreate(ctx context.Context, ctr containerd.Container, recreate, configHash string) (bool, error) {
+	switch recreate {
+	case RecreateForce:
+		return true, nil
+	case RecreateNever:
+		return false, nil
+	}
+	containerLabels, err := ctr.Labels(ctx)
+	if err != nil {
+		return false, err
+	}
+	return containerLabels[labels.ComposeConfigHash] != configHash, nil
+}
+
+// createContainer creates the service container, in the same way as `nerdctl create`.
+// The image must have been ensured with ensureServiceImage.
+func (c *Composer) createContainer(ctx context.Context, service *serviceparser.Service, sc serviceparser.Container, configHash string, inRun bool) (containerd.Container, error) {
+	options := sc.CreateOptions
+	options.Stdout = io.Discard
+	// Always propagate stderr to print detailed error messages (https://github.com/containerd/nerdctl/issues/1942)
+	options.Stderr = os.Stderr
+	options.GOptions = c.GOptions
+	options.ImagePullOpt.GOptions = c.GOptions
+	options.NerdctlCmd = c.NerdctlCmd
+	options.NerdctlArgs = c.NerdctlArgs
+	options.InRun = inRun
+	options.Detach = !options.Interactive
+	// add metadata labels to container https://github.com/compose-spec/compose-spec/blob/master/spec.md#labels
+	options.Label = append([]string{
+		fmt.Sprintf("%s=%s", labels.ComposeProject, c.project.Name),
+		fmt.Sprintf("%s=%s", labels.ComposeService, service.Unparsed.Name),
+		fmt.Sprintf("%s=%s", labels.ComposeConfigHash, configHash),
+		fmt.Sprintf("%s=%s", labels.ComposeConfigFiles, strings.Join(c.project.ComposeFiles, ",")),
+		fmt.Sprintf("%s=%s", labels.ComposeWorkingDir, c.project.WorkingDir),
+	}, options.Label...)
+
+	fileObjectVolumes, err := c.materializeFileObjects(sc)
+	if err != nil {
+		return nil, err
+	}
+	options.Volume = append(options.Volume, fileObjectVolumes...)
+
+	if c.DebugPrintFull {
+		log.G(ctx).Debugf("Creating container %s: %+v, %+v", sc.Name, options, sc.NetworkOptions)
+	}
+
+	netManager, err := containerutil.NewNetworkingOptionsManager(c.GOptions, sc.NetworkOptions, c.client)
+	if err != nil {
+		return nil, err
+	}
+	ctr, gc, err := container.Create(ctx, c.client, sc.Args, netManager, options)
+	if err != nil {
+		if gc != nil {
+			gc()
+		}
+		return nil, err
+	}
+	return ctr, nil
+}
+
+// startContainers starts the containers, in the same way as `nerdctl start`.
+func (c *Composer) startContainers(ctx context.Context, ids ...string) error {
+	return container.Start(ctx, c.client, ids, types.ContainerStartOptions{
+		Stdout:   io.Discard,
+		GOptions: c.GOptions,
+	})
+}
+
+// removeContainerForcibly removes the container, in the same way as `nerdctl rm -f`.
+func (c *Composer) removeContainerForcibly(ctx context.Context, req string, volumes bool) error {
+	return container.Remove(ctx, c.client, []string{req}, types.ContainerRemoveOptions{
+		Stdout:   io.Discard,
+		GOptions: c.GOptions,
+		Force:    true,
+		Volumes:  volumes,
+	})
 }
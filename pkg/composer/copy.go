@@ -24,6 +24,7 @@ import (
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/docker/docker/pkg/system"
 )
@@ -71,22 +72,20 @@ func (c *Composer) Copy(ctx context.Context, co CopyOptions) error {
 	}
 
 	for _, container := range containers {
-		args := []string{"cp"}
-		if co.FollowLink {
-			args = append(args, "--follow-link")
-		}
-		if direction == fromService {
-			args = append(args, fmt.Sprintf("%s:%s", container.ID(), srcPath), dstPath)
-		}
-		if direction == toService {
-			args = append(args, srcPath, fmt.Sprintf("%s:%s", container.ID(), dstPath))
+		options := types.ContainerCpOptions{
+			GOptions:       c.GOptions,
+			ContainerReq:   container.ID(),
+			Container2Host: direction == fromService,
+			SrcPath:        srcPath,
+			DestPath:       dstPath,
+			FollowSymLink:  co.FollowLink,
 		}
 		err := c.logCopyMsg(ctx, container, direction, srcService, srcPath, destService, dstPath, co.DryRun)
 		if err != nil {
 			return err
 		}
 		if !co.DryRun {
-			if err := c.runNerdctlCmd(ctx, args...); err != nil {
+			if err := c.copyFiles(ctx, options); err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,32 @@
+//go:build !linux
+
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+)
+
+// copyFiles is not supported, as `nerdctl cp` is only available on Linux.
+func (c *Composer) copyFiles(ctx context.Context, options types.ContainerCpOptions) error {
+	return fmt.Errorf("copying files between the host and containers is not supported on this platform: %w", errdefs.ErrNotImplemented)
+}
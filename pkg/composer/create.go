@@ -19,16 +19,12 @@ package composer
 import (
 	"context"
 	"fmt"
-	"os"
-	"path/filepath"
-	"strings"
 
 	"golang.org/x/sync/errgroup"
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/containerd/log"
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
-	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
 // FYI: https://github.com/docker/compose/blob/v2.14.1/pkg/api/api.go#L423
@@ -38,8 +34,7 @@ const (
 	// RecreateForce specifies always force-recreating service containers
 	RecreateForce = "force"
 	// RecreateDiverged specifies only recreating service containers which diverges from compose model.
-	// (Unimplemented, currently equal to `RecreateNever`) In docker-compose,
-	// service config is hashed and stored in a label.
+	// The service config and the image digest are hashed and stored in the labels.ComposeConfigHash label.
 	// FYI: https://github.com/docker/compose/blob/v2.14.1/pkg/compose/convergence.go#L244
 	RecreateDiverged = "diverged"
 )
@@ -123,22 +118,22 @@ func (c *Composer) Create(ctx context.Context, opt CreateOptions, services []str
 		}
 	}
 
-	for _, ps := range parsedServices {
-		if err := c.createService(ctx, ps, opt); err != nil {
-			return err
-		}
-	}
-
-	return nil
+	return forEachServiceInParallel(ctx, parsedServices, func(ctx context.Context, ps *serviceparser.Service) error {
+		return c.createService(ctx, ps, opt)
+	})
 }
 
 func (c *Composer) createService(ctx context.Context, ps *serviceparser.Service, opt CreateOptions) error {
 	recreate := opt.recreateStrategy()
+	configHash, err := c.serviceConfigHash(ctx, ps)
+	if err != nil {
+		return err
+	}
 	var runEG errgroup.Group
 	for _, container := range ps.Containers {
 		container := container
 		runEG.Go(func() error {
-			_, err := c.createServiceContainer(ctx, ps, container, recreate)
+			_, err := c.createServiceContainer(ctx, ps, container, recreate, configHash)
 			if err != nil {
 				return err
 			}
@@ -154,23 +149,26 @@ func (c *Composer) createService(ctx context.Context, ps *serviceparser.Service,
 // 1. the logic is similar to `upServiceContainer`, need to decouple some of the logic.
 // 2. ideally, `compose up` should equal to `compose create` + `compose start`, we should decouple and reuse the logic in `compose up`.
 // 3. it'll be easier to refactor after related `compose` logic are moved to `pkg` from `cmd`.
-func (c *Composer) createServiceContainer(ctx context.Context, service *serviceparser.Service, container serviceparser.Container, recreate string) (string, error) {
+func (c *Composer) createServiceContainer(ctx context.Context, service *serviceparser.Service, container serviceparser.Container, recreate, configHash string) (string, error) {
 	// check if container already exists
-	exists, err := c.containerExists(ctx, container.Name, service.Unparsed.Name)
+	existing, err := c.findContainer(ctx, container.Name, service.Unparsed.Name)
 	if err != nil {
 		return "", fmt.Errorf("error while checking for containers with name %q: %s", container.Name, err)
 	}
 
-	// delete container if it already exists and force-recreate is enabled
-	if exists {
-		if recreate != RecreateForce {
+	// delete container if it already exists and needs to be recreated
+	if existing != nil {
+		recreateNeeded, err := needsRecreate(ctx, existing, recreate, configHash)
+		if err != nil {
+			return "", err
+		}
+		if !recreateNeeded {
 			log.G(ctx).Infof("Container %s exists, skipping", container.Name)
-			return "", nil
+			return existing.ID(), nil
 		}
 
-		log.G(ctx).Debugf("Container %q already exists and force-created is enabled, deleting", container.Name)
-		delCmd := c.createNerdctlCmd(ctx, "rm", "-f", container.Name)
-		if err = delCmd.Run(); err != nil {
+		log.G(ctx).Debugf("Container %q already exists and needs to be recreated, deleting", container.Name)
+		if err = c.removeContainerForcibly(ctx, container.Name, false); err != nil {
 			return "", fmt.Errorf("could not delete container %q: %s", container.Name, err)
 		}
 		log.G(ctx).Infof("Re-creating container %s", container.Name)
@@ -178,38 +176,14 @@ func (c *Composer) createServiceContainer(ctx context.Context, service *servicep
 		log.G(ctx).Infof("Creating container %s", container.Name)
 	}
 
-	tempDir, err := os.MkdirTemp(os.TempDir(), "compose-")
-	if err != nil {
-		return "", fmt.Errorf("error while creating/re-creating container %s: %w", container.Name, err)
-	}
-	defer os.RemoveAll(tempDir)
-	cidFilename := filepath.Join(tempDir, "cid")
-
-	//add metadata labels to container https://github.com/compose-spec/compose-spec/blob/master/spec.md#labels
-	container.RunArgs = append([]string{
-		"--cidfile=" + cidFilename,
-		fmt.Sprintf("-l=%s=%s", labels.ComposeProject, c.project.Name),
-		fmt.Sprintf("-l=%s=%s", labels.ComposeService, service.Unparsed.Name),
-	}, container.RunArgs...)
-
-	cmd := c.createNerdctlCmd(ctx, append([]string{"create"}, container.RunArgs...)...)
-	if c.DebugPrintFull {
-		log.G(ctx).Debugf("Running %v", cmd.Args)
-	}
-
 	// FIXME
 	if service.Unparsed.StdinOpen != service.Unparsed.Tty {
 		return "", fmt.Errorf("currently StdinOpen(-i) and Tty(-t) should be same")
 	}
 
-	err = cmd.Run()
-	if err != nil {
-		return "", fmt.Errorf("error while creating container %s: %w", container.Name, err)
-	}
-
-	cid, err := os.ReadFile(cidFilename)
+	ctr, err := c.createContainer(ctx, service, container, configHash, false)
 	if err != nil {
 		return "", fmt.Errorf("error while creating container %s: %w", container.Name, err)
 	}
-	return strings.TrimSpace(string(cid)), nil
+	return ctr.ID(), nil
 }
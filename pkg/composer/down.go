@@ -19,8 +19,12 @@ package composer
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/network"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
@@ -68,6 +72,11 @@ func (c *Composer) Down(ctx context.Context, downOptions DownOptions) error {
 		}
 	}
 
+	// remove the materialized secrets and configs of the project
+	if err := c.removeFileObjects(""); err != nil {
+		log.G(ctx).Warn(err)
+	}
+
 	for shortName := range c.project.Networks {
 		if err := c.downNetwork(ctx, shortName); err != nil {
 			return err
@@ -109,7 +118,11 @@ func (c *Composer) downNetwork(ctx context.Context, shortName string) error {
 		}
 
 		log.G(ctx).Infof("Removing network %s", fullName)
-		if err := c.runNerdctlCmd(ctx, "network", "rm", fullName); err != nil {
+		if err := network.Remove(ctx, c.client, types.NetworkRemoveOptions{
+			Stdout:   io.Discard,
+			GOptions: c.GOptions,
+			Networks: []string{fullName},
+		}); err != nil {
 			log.G(ctx).Warn(err)
 		}
 	}
@@ -132,7 +145,11 @@ func (c *Composer) downVolume(ctx context.Context, shortName string) error {
 		return err
 	} else if volExists {
 		log.G(ctx).Infof("Removing volume %s", fullName)
-		if err := c.runNerdctlCmd(ctx, "volume", "rm", "-f", fullName); err != nil {
+		if err := volume.Remove(ctx, c.client, []string{fullName}, types.VolumeRemoveOptions{
+			Stdout:   io.Discard,
+			GOptions: c.GOptions,
+			Force:    true,
+		}); err != nil {
 			log.G(ctx).Warn(err)
 		}
 	}
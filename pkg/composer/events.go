@@ -0,0 +1,127 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/system"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// EventsOptions has the options of `nerdctl compose events`.
+type EventsOptions struct {
+	// JSON prints the events as JSON objects, one per line
+	JSON bool
+}
+
+// composeEvent is the event printed by `nerdctl compose events --json`,
+// compatible with `docker compose events --json`.
+type composeEvent struct {
+	Time       time.Time         `json:"time"`
+	Type       string            `json:"type"`
+	Action     string            `json:"action"`
+	ID         string            `json:"id"`
+	Service    string            `json:"service"`
+	Attributes map[string]string `json:"attributes"`
+}
+
+// Events streams the events of the containers of the services, until ctx is done.
+// The events are the same as `nerdctl events`, filtered by the project and the services.
+func (c *Composer) Events(ctx context.Context, writer io.Writer, services []string, eo EventsOptions) error {
+	serviceNames, err := c.ServiceNames(services...)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	pr, pw := io.Pipe()
+	defer pr.Close()
+	go func() {
+		pw.CloseWithError(system.Events(ctx, c.client, types.SystemEventsOptions{
+			Stdout:   pw,
+			GOptions: c.GOptions,
+			Format:   "{{json .}}",
+			Filters: []string{
+				"type=" + system.ContainerEventType,
+				"namespace=" + c.GOptions.Namespace,
+				fmt.Sprintf("label=%s=%s", labels.ComposeProject, c.project.Name),
+			},
+		}))
+	}()
+
+	scanner := bufio.NewScanner(pr)
+	for scanner.Scan() {
+		var out system.EventOut
+		if err := json.Unmarshal(scanner.Bytes(), &out); err != nil {
+			return err
+		}
+		service := out.Actor.Attributes[labels.ComposeService]
+		if !slices.Contains(serviceNames, service) {
+			continue
+		}
+		ev := composeEvent{
+			Time:       time.Unix(0, out.TimeNano),
+			Type:       out.Type,
+			Action:     out.Action,
+			ID:         out.Actor.ID,
+			Service:    service,
+			Attributes: out.Actor.Attributes,
+		}
+		if err := printComposeEvent(writer, ev, eo.JSON); err != nil {
+			return err
+		}
+	}
+	return scanner.Err()
+}
+
+// printComposeEvent prints the event as JSON, or in the same format as `docker compose events`.
+func printComposeEvent(w io.Writer, ev composeEvent, asJSON bool) error {
+	if asJSON {
+		b, err := json.Marshal(ev)
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintln(w, string(b))
+		return err
+	}
+	s := fmt.Sprintf("%s %s %s %s", ev.Time.Format("2006-01-02 15:04:05.000000"), ev.Type, ev.Action, ev.ID)
+	if len(ev.Attributes) > 0 {
+		keys := make([]string, 0, len(ev.Attributes))
+		for k := range ev.Attributes {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		attrs := make([]string, len(keys))
+		for i, k := range keys {
+			attrs[i] = fmt.Sprintf("%s=%s", k, ev.Attributes[k])
+		}
+		s += fmt.Sprintf(" (%s)", strings.Join(attrs, ", "))
+	}
+	_, err := fmt.Fprintln(w, s)
+	return err
+}
@@ -19,13 +19,14 @@ package composer
 import (
 	"context"
 	"fmt"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
@@ -34,7 +35,7 @@ import (
 type ExecOptions struct {
 	ServiceName string
 	Index       int
-	// params to be passed to `nerdctl exec`
+	// params of `nerdctl exec`
 	Detach      bool
 	Interactive bool
 	Tty         bool
@@ -76,38 +77,20 @@ func (c *Composer) Exec(ctx context.Context, eo ExecOptions) error {
 	return c.exec(ctx, containers[eo.Index-1], eo)
 }
 
-// exec constructs/executes the `nerdctl exec` command to be executed on the given container.
-func (c *Composer) exec(ctx context.Context, container containerd.Container, eo ExecOptions) error {
-	args := []string{
-		"exec",
-		fmt.Sprintf("--detach=%t", eo.Detach),
-		fmt.Sprintf("--interactive=%t", eo.Interactive),
-		fmt.Sprintf("--tty=%t", eo.Tty),
-		fmt.Sprintf("--privileged=%t", eo.Privileged),
+// exec executes the command on the given container, in the same way as `nerdctl exec`.
+func (c *Composer) exec(ctx context.Context, ctr containerd.Container, eo ExecOptions) error {
+	options := types.ContainerExecOptions{
+		GOptions:    c.GOptions,
+		TTY:         eo.Tty,
+		Interactive: eo.Interactive,
+		Detach:      eo.Detach,
+		Workdir:     eo.WorkDir,
+		Env:         eo.Env,
+		Privileged:  eo.Privileged,
+		User:        eo.User,
 	}
-	if eo.User != "" {
-		args = append(args, "--user", eo.User)
-	}
-	if eo.WorkDir != "" {
-		args = append(args, "--workdir", eo.WorkDir)
-	}
-	for _, e := range eo.Env {
-		args = append(args, "--env", e)
-	}
-	args = append(args, container.ID())
-	args = append(args, eo.Args...)
-	cmd := c.createNerdctlCmd(ctx, args...)
-
-	if eo.Interactive {
-		cmd.Stdin = os.Stdin
-	}
-	if !eo.Detach {
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
-	}
-
 	if c.DebugPrintFull {
-		log.G(ctx).Debugf("Executing %v", cmd.Args)
+		log.G(ctx).Debugf("Executing %v on container %s", eo.Args, ctr.ID())
 	}
-	return cmd.Run()
+	return container.Exec(ctx, c.client, append([]string{ctr.ID()}, eo.Args...), options)
 }
@@ -0,0 +1,125 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/nerdctl/v2/pkg/clientutil"
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+)
+
+// fileObjectsDir returns the directory of the materialized secrets and configs of the project,
+// or of the container when containerName is specified.
+//
+// The directory is like "<DATASTORE>/compose/<NAMESPACE>/<PROJECT>/<CONTAINER>".
+func (c *Composer) fileObjectsDir(containerName string) (string, error) {
+	dataStore, err := clientutil.DataStore(c.GOptions.DataRoot, c.GOptions.Address)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dataStore, "compose", c.GOptions.Namespace, c.project.Name, containerName), nil
+}
+
+// materializeFileObjects writes the secrets and the configs of the container to the data store,
+// and returns the `nerdctl run -v` strings for bind-mounting them read-only.
+func (c *Composer) materializeFileObjects(sc serviceparser.Container) ([]string, error) {
+	if len(sc.FileObjects) == 0 {
+		return nil, nil
+	}
+	dir, err := c.fileObjectsDir(sc.Name)
+	if err != nil {
+		return nil, err
+	}
+	// remove the stale files of the previous container with the same name
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	var volumes []string
+	for _, obj := range sc.FileObjects {
+		src, err := materializeFileObject(dir, obj)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", obj.Type, obj.Source, err)
+		}
+		volumes = append(volumes, fmt.Sprintf("%s:%s:ro", src, obj.Target))
+	}
+	return volumes, nil
+}
+
+// materializeFileObject writes the file object under dir, and returns the path of the written file.
+func materializeFileObject(dir string, obj serviceparser.FileObject) (string, error) {
+	content := obj.Content
+	mode := os.FileMode(0444)
+	if obj.File != "" {
+		st, err := os.Stat(obj.File)
+		if err != nil {
+			return "", err
+		}
+		if st.IsDir() {
+			// directories are bind-mounted as they are
+			if obj.UID != nil || obj.GID != nil || obj.Mode != nil {
+				return "", fmt.Errorf("uid, gid, and mode cannot be specified for directory %q", obj.File)
+			}
+			return obj.File, nil
+		}
+		content, err = os.ReadFile(obj.File)
+		if err != nil {
+			return "", err
+		}
+		mode = st.Mode().Perm()
+	}
+	if obj.Mode != nil {
+		mode = *obj.Mode
+	}
+
+	// obj.Target is an absolute path
+	p := filepath.Join(dir, obj.Type+"s", obj.Target)
+	if err := os.MkdirAll(filepath.Dir(p), 0700); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(p, content, 0600); err != nil {
+		return "", err
+	}
+	if obj.UID != nil || obj.GID != nil {
+		uid, gid := -1, -1
+		if obj.UID != nil {
+			uid = *obj.UID
+		}
+		if obj.GID != nil {
+			gid = *obj.GID
+		}
+		if err := os.Lchown(p, uid, gid); err != nil {
+			return "", err
+		}
+	}
+	// chmod explicitly, as the mode of os.WriteFile is affected by umask
+	if err := os.Chmod(p, mode); err != nil {
+		return "", err
+	}
+	return p, nil
+}
+
+// removeFileObjects removes the materialized secrets and configs of the container.
+func (c *Composer) removeFileObjects(containerName string) error {
+	dir, err := c.fileObjectsDir(containerName)
+	if err != nil {
+		return err
+	}
+	return os.RemoveAll(dir)
+}
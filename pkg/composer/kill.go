@@ -18,10 +18,13 @@ package composer
 
 import (
 	"context"
+	"io"
 
 	"golang.org/x/sync/errgroup"
 
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 )
 
 type KillOptions struct {
@@ -38,11 +41,15 @@ func (c *Composer) Kill(ctx context.Context, opts KillOptions, services []string
 		return err
 	}
 	eg, ctx := errgroup.WithContext(ctx)
-	for _, container := range containers {
-		container := container
+	for _, ctr := range containers {
+		ctr := ctr
 		eg.Go(func() error {
-			args := []string{"kill", "-s", opts.Signal, container.ID()}
-			if err := c.runNerdctlCmd(ctx, args...); err != nil {
+			if err := container.Kill(ctx, c.client, []string{ctr.ID()}, types.ContainerKillOptions{
+				Stdout:     io.Discard,
+				Stderr:     io.Discard,
+				GOptions:   c.GOptions,
+				KillSignal: opts.Signal,
+			}); err != nil {
 				log.G(ctx).Warn(err)
 				return err
 			}
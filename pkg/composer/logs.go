@@ -19,14 +19,17 @@ package composer
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"os/signal"
+	"strconv"
 	"strings"
 
-	"github.com/compose-spec/compose-go/v2/types"
+	compose "github.com/compose-spec/compose-go/v2/types"
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/composer/pipetagger"
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
@@ -43,10 +46,10 @@ type LogsOptions struct {
 
 func (c *Composer) Logs(ctx context.Context, lo LogsOptions, services []string) error {
 	var serviceNames []string
-	err := c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
+	err := c.project.ForEachService(services, func(name string, svc *compose.ServiceConfig) error {
 		serviceNames = append(serviceNames, svc.Name)
 		return nil
-	}, types.IgnoreDependencies)
+	}, compose.IgnoreDependencies)
 	if err != nil {
 		return err
 	}
@@ -62,12 +65,20 @@ func (c *Composer) logs(ctx context.Context, containers []containerd.Container,
 	type containerState struct {
 		name   string
 		logTag string
-		logCmd *exec.Cmd
+	}
+
+	var tail uint
+	if lo.Tail != "" && lo.Tail != "all" {
+		n, err := strconv.ParseUint(lo.Tail, 10, 32)
+		if err != nil {
+			return fmt.Errorf("failed to parse tail %q: %w", lo.Tail, err)
+		}
+		tail = uint(n)
 	}
 
 	containerStates := make(map[string]containerState, len(containers)) // key: containerID
-	for _, container := range containers {
-		info, err := container.Info(ctx, containerd.WithoutRefreshedMetadata)
+	for _, ctr := range containers {
+		info, err := ctr.Info(ctx, containerd.WithoutRefreshedMetadata)
 		if err != nil {
 			return err
 		}
@@ -76,53 +87,40 @@ func (c *Composer) logs(ctx context.Context, containers []containerd.Container,
 		if l := len(logTag); l > logTagMaxLen {
 			logTagMaxLen = l
 		}
-		containerStates[container.ID()] = containerState{
+		containerStates[ctr.ID()] = containerState{
 			name:   name,
 			logTag: logTag,
 		}
 	}
 
-	logsEOFChan := make(chan string) // value: container name
+	// The logs are no longer followed when logsCtx is cancelled
+	logsCtx, cancelLogs := context.WithCancel(ctx)
+	defer cancelLogs()
+	logsEOFChan := make(chan string, len(containerStates)) // value: container name
 	for id, state := range containerStates {
-		// TODO: show logs without executing `nerdctl logs`
-		args := []string{"logs"}
-		if lo.Follow {
-			args = append(args, "-f")
-		}
-		if lo.Timestamps {
-			args = append(args, "-t")
-		}
-		if lo.Tail != "" {
-			args = append(args, "-n")
-			if lo.Tail == "all" {
-				args = append(args, "+0")
-			} else {
-				args = append(args, lo.Tail)
-			}
-		}
-
-		args = append(args, id)
-		state.logCmd = c.createNerdctlCmd(ctx, args...)
-		stdout, err := state.logCmd.StdoutPipe()
-		if err != nil {
-			return err
-		}
 		logWidth := logTagMaxLen + 1
 		if lo.NoLogPrefix {
 			logWidth = -1
 		}
-		stdoutTagger := pipetagger.New(os.Stdout, stdout, state.logTag, logWidth, lo.NoColor)
-		stderr, err := state.logCmd.StderrPipe()
-		if err != nil {
-			return err
-		}
-		stderrTagger := pipetagger.New(os.Stderr, stderr, state.logTag, logWidth, lo.NoColor)
-		if c.DebugPrintFull {
-			log.G(ctx).Debugf("Running %v", state.logCmd.Args)
-		}
-		if err := state.logCmd.Start(); err != nil {
-			return err
+		stdoutR, stdoutW := io.Pipe()
+		stderrR, stderrW := io.Pipe()
+		stdoutTagger := pipetagger.New(os.Stdout, stdoutR, state.logTag, logWidth, lo.NoColor)
+		stderrTagger := pipetagger.New(os.Stderr, stderrR, state.logTag, logWidth, lo.NoColor)
+		options := types.ContainerLogsOptions{
+			Stdout:     stdoutW,
+			Stderr:     stderrW,
+			GOptions:   c.GOptions,
+			Follow:     lo.Follow,
+			Timestamps: lo.Timestamps,
+			Tail:       tail,
 		}
+		go func() {
+			if err := container.Logs(logsCtx, c.client, id, options); err != nil && logsCtx.Err() == nil {
+				log.G(ctx).WithError(err).Warnf("failed to show the logs of container %q", state.name)
+			}
+			stdoutW.Close()
+			stderrW.Close()
+		}()
 		containerName := state.name
 		go func() {
 			stdoutTagger.Run()
@@ -145,7 +143,7 @@ selectLoop:
 			break selectLoop
 		case containerName := <-logsEOFChan:
 			if lo.Follow {
-				// When `nerdctl logs -f` has exited, we can assume that the container has exited
+				// When following the logs has finished, we can assume that the container has exited
 				log.G(ctx).Infof("Container %q exited", containerName)
 				// In case a container has exited and the parameter --abort-on-container-exit,
 				// we break the loop and set an error, so we can exit the program with 1
@@ -168,13 +166,5 @@ selectLoop:
 		}
 	}
 
-	for _, state := range containerStates {
-		if state.logCmd != nil && state.logCmd.Process != nil {
-			if err := state.logCmd.Process.Kill(); err != nil {
-				log.G(ctx).Warn(err)
-			}
-		}
-	}
-
 	return containerError
 }
@@ -0,0 +1,97 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// ProjectSummary is a project listed by `nerdctl compose ls`.
+// The JSON representation is compatible with `docker compose ls --format=json`.
+type ProjectSummary struct {
+	Name        string
+	Status      string
+	ConfigFiles string
+}
+
+// ListProjects returns the projects of the containers in the namespace of ctx, sorted by the name.
+// The projects without running containers are omitted unless all is set.
+//
+// ListProjects does not need a compose file, as the projects are discovered from the labels of the containers.
+func ListProjects(ctx context.Context, client *containerd.Client, all bool) ([]ProjectSummary, error) {
+	containers, err := client.Containers(ctx, fmt.Sprintf("labels.%q", labels.ComposeProject))
+	if err != nil {
+		return nil, err
+	}
+	type project struct {
+		states      map[string]int
+		configFiles string
+	}
+	projects := make(map[string]*project)
+	for _, container := range containers {
+		info, err := container.Info(ctx, containerd.WithoutRefreshedMetadata)
+		if err != nil {
+			// the container may have been removed in the meantime
+			continue
+		}
+		name := info.Labels[labels.ComposeProject]
+		p, ok := projects[name]
+		if !ok {
+			p = &project{states: make(map[string]int)}
+			projects[name] = p
+		}
+		if configFiles := info.Labels[labels.ComposeConfigFiles]; configFiles != "" {
+			p.configFiles = configFiles
+		}
+		state := string(containerd.Unknown)
+		if status, err := containerutil.ContainerStatus(ctx, container); err == nil {
+			state = string(status.Status)
+			if status.Status == containerd.Stopped {
+				state = "exited"
+			}
+		}
+		p.states[state]++
+	}
+
+	var res []ProjectSummary
+	for name, p := range projects {
+		if p.states[string(containerd.Running)] == 0 && !all {
+			continue
+		}
+		states := make([]string, 0, len(p.states))
+		for state, n := range p.states {
+			states = append(states, fmt.Sprintf("%s(%d)", state, n))
+		}
+		sort.Strings(states)
+		res = append(res, ProjectSummary{
+			Name:        name,
+			Status:      strings.Join(states, ", "),
+			ConfigFiles: p.configFiles,
+		})
+	}
+	sort.Slice(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+	return res, nil
+}
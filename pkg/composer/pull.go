@@ -21,9 +21,12 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/compose-spec/compose-go/v2/types"
+	compose "github.com/compose-spec/compose-go/v2/types"
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/platformutil"
 )
 
 type PullOptions struct {
@@ -31,7 +34,7 @@ type PullOptions struct {
 }
 
 func (c *Composer) Pull(ctx context.Context, po PullOptions, services []string) error {
-	return c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
+	return c.project.ForEachService(services, func(name string, svc *compose.ServiceConfig) error {
 		ps, err := serviceparser.Parse(c.project, *svc)
 		if err != nil {
 			return err
@@ -40,50 +43,60 @@ func (c *Composer) Pull(ctx context.Context, po PullOptions, services []string)
 	})
 }
 
-func (c *Composer) pullServiceImage(ctx context.Context, image string, platform string, ps *serviceparser.Service, po PullOptions) error {
-	log.G(ctx).Infof("Pulling image %s", image)
+func (c *Composer) pullServiceImage(ctx context.Context, imageName string, platform string, ps *serviceparser.Service, po PullOptions) error {
+	log.G(ctx).Infof("Pulling image %s", imageName)
 
-	var args []string // nolint: prealloc
+	var platforms []string
 	if platform != "" {
-		args = append(args, "--platform="+platform)
+		platforms = append(platforms, platform)
 	}
-	if po.Quiet {
-		args = append(args, "--quiet")
+	ocispecPlatforms, err := platformutil.NewOCISpecPlatformSlice(false, platforms)
+	if err != nil {
+		return err
 	}
-	if verifier, ok := ps.Unparsed.Extensions[serviceparser.ComposeVerify]; ok {
-		args = append(args, "--verify="+verifier.(string))
-	}
-	if publicKey, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignPublicKey]; ok {
-		args = append(args, "--cosign-key="+publicKey.(string))
+	gOptions := c.GOptions
+	gOptions.Experimental = gOptions.Experimental || c.Options.Experimental
+	options := types.ImagePullOptions{
+		Stdout:          os.Stdout,
+		Stderr:          os.Stderr,
+		GOptions:        gOptions,
+		VerifyOptions:   ImageVerifyOptions(ps),
+		OCISpecPlatform: ocispecPlatforms,
+		Mode:            "always",
+		Quiet:           po.Quiet,
+		IPFSAddress:     c.IPFSAddress,
 	}
-	if certificateIdentity, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateIdentity]; ok {
-		args = append(args, "--cosign-certificate-identity="+certificateIdentity.(string))
+	if _, err := image.EnsureImage(ctx, c.client, imageName, options); err != nil {
+		return fmt.Errorf("error while pulling image %s: %w", imageName, err)
 	}
-	if certificateIdentityRegexp, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateIdentityRegexp]; ok {
-		args = append(args, "--cosign-certificate-identity-regexp="+certificateIdentityRegexp.(string))
+	return nil
+}
+
+// ImageVerifyOptions returns the options for verifying the image of the service,
+// from the x-nerdctl-verify and x-nerdctl-cosign-* extensions.
+func ImageVerifyOptions(ps *serviceparser.Service) types.ImageVerifyOptions {
+	var opt types.ImageVerifyOptions
+	if verifier, ok := ps.Unparsed.Extensions[serviceparser.ComposeVerify]; ok {
+		opt.Provider = verifier.(string)
+	} else {
+		opt.Provider = "none"
 	}
-	if certificateOidcIssuer, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateOidcIssuer]; ok {
-		args = append(args, "--cosign-certificate-oidc-issuer="+certificateOidcIssuer.(string))
+
+	// for cosign, if key is given, use key mode, otherwise use keyless mode.
+	if keyVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignPublicKey]; ok {
+		opt.CosignKey = keyVal.(string)
 	}
-	if certificateOidcIssuerRegexp, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateOidcIssuerRegexp]; ok {
-		args = append(args, "--cosign-certificate-oidc-issuer-regexp="+certificateOidcIssuerRegexp.(string))
+	if ciVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateIdentity]; ok {
+		opt.CosignCertificateIdentity = ciVal.(string)
 	}
-
-	if c.Options.Experimental {
-		args = append(args, "--experimental")
+	if cirVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateIdentityRegexp]; ok {
+		opt.CosignCertificateIdentityRegexp = cirVal.(string)
 	}
-
-	args = append(args, image)
-
-	cmd := c.createNerdctlCmd(ctx, append([]string{"pull"}, args...)...)
-	if c.DebugPrintFull {
-		log.G(ctx).Debugf("Running %v", cmd.Args)
+	if coiVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateOidcIssuer]; ok {
+		opt.CosignCertificateOidcIssuer = coiVal.(string)
 	}
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("error while pulling image %s: %w", image, err)
+	if coirVal, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignCertificateOidcIssuerRegexp]; ok {
+		opt.CosignCertificateOidcIssuerRegexp = coirVal.(string)
 	}
-	return nil
+	return opt
 }
@@ -21,8 +21,10 @@ import (
 	"fmt"
 	"os"
 
-	"github.com/compose-spec/compose-go/v2/types"
+	compose "github.com/compose-spec/compose-go/v2/types"
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/image"
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
 )
 
@@ -30,7 +32,7 @@ type PushOptions struct {
 }
 
 func (c *Composer) Push(ctx context.Context, po PushOptions, services []string) error {
-	return c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
+	return c.project.ForEachService(services, func(name string, svc *compose.ServiceConfig) error {
 		ps, err := serviceparser.Parse(c.project, *svc)
 		if err != nil {
 			return err
@@ -39,34 +41,28 @@ func (c *Composer) Push(ctx context.Context, po PushOptions, services []string)
 	})
 }
 
-func (c *Composer) pushServiceImage(ctx context.Context, image string, platform string, ps *serviceparser.Service, po PushOptions) error {
-	log.G(ctx).Infof("Pushing image %s", image)
+func (c *Composer) pushServiceImage(ctx context.Context, imageName string, platform string, ps *serviceparser.Service, po PushOptions) error {
+	log.G(ctx).Infof("Pushing image %s", imageName)
 
-	var args []string // nolint: prealloc
+	gOptions := c.GOptions
+	gOptions.Experimental = gOptions.Experimental || c.Options.Experimental
+	options := types.ImagePushOptions{
+		Stdout:      os.Stdout,
+		GOptions:    gOptions,
+		SignOptions: types.ImageSignOptions{Provider: "none"},
+		SociOptions: types.SociOptions{SpanSize: -1, MinLayerSize: -1},
+	}
 	if platform != "" {
-		args = append(args, "--platform="+platform)
+		options.Platforms = []string{platform}
 	}
 	if signer, ok := ps.Unparsed.Extensions[serviceparser.ComposeSign]; ok {
-		args = append(args, "--sign="+signer.(string))
+		options.SignOptions.Provider = signer.(string)
 	}
 	if privateKey, ok := ps.Unparsed.Extensions[serviceparser.ComposeCosignPrivateKey]; ok {
-		args = append(args, "--cosign-key="+privateKey.(string))
-	}
-	if c.Options.Experimental {
-		args = append(args, "--experimental")
-	}
-
-	args = append(args, image)
-
-	cmd := c.createNerdctlCmd(ctx, append([]string{"push"}, args...)...)
-	if c.DebugPrintFull {
-		log.G(ctx).Debugf("Running %v", cmd.Args)
+		options.SignOptions.CosignKey = privateKey.(string)
 	}
-	cmd.Stdin = os.Stdin
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("error while pushing image %s: %w", image, err)
+	if err := image.Push(ctx, c.client, imageName, options); err != nil {
+		return fmt.Errorf("error while pushing image %s: %w", imageName, err)
 	}
 	return nil
 }
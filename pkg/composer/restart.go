@@ -18,12 +18,14 @@ package composer
 
 import (
 	"context"
-	"fmt"
+	"io"
 	"sync"
+	"time"
 
-	"github.com/compose-spec/compose-go/v2/types"
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 )
 
@@ -32,41 +34,82 @@ type RestartOptions struct {
 	Timeout *uint
 }
 
-// Restart restarts running/stopped containers in `services`. It calls
-// `nerdctl restart CONTAINER_ID` to do the actual job.
+// Restart restarts running/stopped containers in `services`, in the same way as
+// `nerdctl restart CONTAINER_ID`.
+// The services that depend on the restarted services with `restart: true` are restarted too.
 func (c *Composer) Restart(ctx context.Context, opt RestartOptions, services []string) error {
 	// in dependency order
-	return c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
-		containers, err := c.Containers(ctx, svc.Name)
+	restarted, err := c.ServiceNames(services...)
+	if err != nil {
+		return err
+	}
+	dependents, err := c.dependentsToRestart(restarted)
+	if err != nil {
+		return err
+	}
+	for _, svc := range append(restarted, dependents...) {
+		containers, err := c.Containers(ctx, svc)
 		if err != nil {
 			return err
 		}
+		if err := c.restartContainers(ctx, containers, opt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
 
-		return c.restartContainers(ctx, containers, opt)
-	})
+// dependentsToRestart returns the services that depend on the restarted services with `restart: true`,
+// in dependency order.
+func (c *Composer) dependentsToRestart(restarted []string) ([]string, error) {
+	all, err := c.ServiceNames()
+	if err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool)
+	for _, name := range restarted {
+		seen[name] = true
+	}
+	var dependents []string
+	for _, name := range all {
+		if seen[name] {
+			continue
+		}
+		svc, err := c.project.GetService(name)
+		if err != nil {
+			return nil, err
+		}
+		for depName, dep := range svc.DependsOn {
+			if dep.Restart && seen[depName] {
+				seen[name] = true
+				dependents = append(dependents, name)
+				break
+			}
+		}
+	}
+	return dependents, nil
 }
 
 func (c *Composer) restartContainers(ctx context.Context, containers []containerd.Container, opt RestartOptions) error {
-	var timeoutArg string
+	var timeout *time.Duration
 	if opt.Timeout != nil {
-		// `nerdctl restart` uses `--time` instead of `--timeout`
-		timeoutArg = fmt.Sprintf("--time=%d", *opt.Timeout)
+		t := time.Duration(*opt.Timeout) * time.Second
+		timeout = &t
 	}
 
 	var rsWG sync.WaitGroup
-	for _, container := range containers {
-		container := container
+	for _, ctr := range containers {
+		ctr := ctr
 		rsWG.Add(1)
 		go func() {
 			defer rsWG.Done()
-			info, _ := container.Info(ctx, containerd.WithoutRefreshedMetadata)
+			info, _ := ctr.Info(ctx, containerd.WithoutRefreshedMetadata)
 			log.G(ctx).Infof("Restarting container %s", info.Labels[labels.Name])
-			args := []string{"restart"}
-			if opt.Timeout != nil {
-				args = append(args, timeoutArg)
-			}
-			args = append(args, container.ID())
-			if err := c.runNerdctlCmd(ctx, args...); err != nil {
+			if err := container.Restart(ctx, c.client, []string{ctr.ID()}, types.ContainerRestartOptions{
+				Stdout:  io.Discard,
+				GOption: c.GOptions,
+				Timeout: timeout,
+			}); err != nil {
 				log.G(ctx).Warn(err)
 			}
 		}()
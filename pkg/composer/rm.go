@@ -63,11 +63,6 @@ func (c *Composer) Remove(ctx context.Context, opt RemoveOptions, services []str
 }
 
 func (c *Composer) removeContainers(ctx context.Context, containers []containerd.Container, opt RemoveOptions) error {
-	args := []string{"rm", "-f"}
-	if opt.Volumes {
-		args = append(args, "-v")
-	}
-
 	var rmWG sync.WaitGroup
 	for _, container := range containers {
 		container := container
@@ -85,7 +80,11 @@ func (c *Composer) removeContainers(ctx context.Context, containers []containerd
 			}
 
 			log.G(ctx).Infof("Removing container %s", info.Labels[labels.Name])
-			if err := c.runNerdctlCmd(ctx, append(args, container.ID())...); err != nil {
+			if err := c.removeContainerForcibly(ctx, container.ID(), opt.Volumes); err != nil {
+				log.G(ctx).Warn(err)
+				return
+			}
+			if err := c.removeFileObjects(info.Labels[labels.Name]); err != nil {
 				log.G(ctx).Warn(err)
 			}
 		}()
@@ -104,7 +103,11 @@ func (c *Composer) removeContainersFromParsedServices(ctx context.Context, conta
 		go func() {
 			defer rmWG.Done()
 			log.G(ctx).Infof("Removing container %s", container.Name)
-			if err := c.runNerdctlCmd(ctx, "rm", "-f", id); err != nil {
+			if err := c.removeContainerForcibly(ctx, id, false); err != nil {
+				log.G(ctx).Warn(err)
+				return
+			}
+			if err := c.removeFileObjects(container.Name); err != nil {
 				log.G(ctx).Warn(err)
 			}
 		}()
@@ -22,8 +22,6 @@ import (
 	"fmt"
 	"sync"
 
-	"golang.org/x/sync/errgroup"
-
 	"github.com/compose-spec/compose-go/v2/format"
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/containerd/log"
@@ -223,12 +221,10 @@ func (c *Composer) runServices(ctx context.Context, parsedServices []*servicepar
 		containers   = make(map[string]serviceparser.Container) // key: container ID
 		services     = []string{}
 		containersMu sync.Mutex
-		runEG        errgroup.Group
 		cid          string // For printing cid when -d exists
 	)
 
 	for _, ps := range parsedServices {
-		ps := ps
 		services = append(services, ps.Unparsed.Name)
 
 		if len(ps.Containers) != 1 {
@@ -238,23 +234,26 @@ func (c *Composer) runServices(ctx context.Context, parsedServices []*servicepar
 		if len(ps.Containers) == 0 {
 			return fmt.Errorf("error, a service should have at least one container but %s does not have any container", ps.Unparsed.Name)
 		}
-		container := ps.Containers[0]
-
-		runEG.Go(func() error {
-			id, err := c.upServiceContainer(ctx, ps, container)
-			if err != nil {
-				return err
-			}
-			containersMu.Lock()
-			containers[id] = container
-			containersMu.Unlock()
-			if ps.Unparsed.Name == ro.ServiceName {
-				cid = id
-			}
-			return nil
-		})
 	}
-	if err := runEG.Wait(); err != nil {
+
+	if err := forEachServiceInParallel(ctx, parsedServices, func(ctx context.Context, ps *serviceparser.Service) error {
+		container := ps.Containers[0]
+		configHash, err := c.serviceConfigHash(ctx, ps)
+		if err != nil {
+			return err
+		}
+		id, _, err := c.upServiceContainer(ctx, ps, container, RecreateDiverged, configHash, false)
+		if err != nil {
+			return err
+		}
+		containersMu.Lock()
+		defer containersMu.Unlock()
+		containers[id] = container
+		if ps.Unparsed.Name == ro.ServiceName {
+			cid = id
+		}
+		return nil
+	}); err != nil {
 		return err
 	}
 
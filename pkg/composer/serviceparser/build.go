@@ -92,7 +92,11 @@ func parseBuildConfig(c *types.BuildConfig, project *types.Project, imageName st
 		}
 		var src string
 		if filepath.IsAbs(projectSecret.File) {
-			log.L.Warnf("build.secrets should be relative path, got %q", projectSecret.File)
+			// the loader resolves the relative paths to absolute paths, so only the paths
+			// outside of the project directory are warned
+			if rel, err := filepath.Rel(project.WorkingDir, projectSecret.File); err != nil || !filepath.IsLocal(rel) {
+				log.L.Warnf("build.secrets should be relative path, got %q", projectSecret.File)
+			}
 			src = projectSecret.File
 		} else {
 			var err error
@@ -19,6 +19,7 @@ package serviceparser
 import (
 	"bytes"
 	"encoding/csv"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
@@ -31,8 +32,14 @@ import (
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/containerd/containerd/v2/contrib/nvidia"
 	"github.com/containerd/containerd/v2/pkg/identifiers"
+	gocni "github.com/containerd/go-cni"
 	"github.com/containerd/log"
+	apitypes "github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
+	"github.com/containerd/nerdctl/v2/pkg/portutil"
 	"github.com/containerd/nerdctl/v2/pkg/reflectutil"
+	"github.com/opencontainers/go-digest"
 )
 
 // ComposeExtensionKey defines fields used to implement extension features.
@@ -67,6 +74,7 @@ func warnUnknownFields(svc types.ServiceConfig) {
 		"ContainerName",
 		"DependsOn",
 		"Deploy",
+		"Develop",
 		"Devices",
 		"Dockerfile", // handled by the loader (normalizer)
 		"DNS",
@@ -77,6 +85,7 @@ func warnUnknownFields(svc types.ServiceConfig) {
 		"Extends", // handled by the loader
 		"Extensions",
 		"ExtraHosts",
+		"HealthCheck",
 		"Hostname",
 		"Image",
 		"Init",
@@ -123,11 +132,13 @@ func warnUnknownFields(svc types.ServiceConfig) {
 	for depName, dep := range svc.DependsOn {
 		if unknown := reflectutil.UnknownNonEmptyFields(&dep,
 			"Condition",
+			"Required",
+			"Restart",
 		); len(unknown) > 0 {
 			log.L.Warnf("Ignoring: service %s: depends_on: %s: %+v", svc.Name, depName, unknown)
 		}
 		switch dep.Condition {
-		case "", types.ServiceConditionStarted:
+		case "", types.ServiceConditionStarted, types.ServiceConditionHealthy, types.ServiceConditionCompletedSuccessfully:
 			// NOP
 		default:
 			log.L.Warnf("Ignoring: service %s: depends_on: %s: condition %s", svc.Name, depName, dep.Condition)
@@ -187,9 +198,26 @@ func warnUnknownFields(svc types.ServiceConfig) {
 }
 
 type Container struct {
-	Name    string   // e.g., "compose-wordpress_wordpress_1"
-	RunArgs []string // {"--pull=never", ...}
-	Mkdir   []string // For Bind.CreateHostPath
+	Name           string                          // e.g., "compose-wordpress_wordpress_1"
+	CreateOptions  apitypes.ContainerCreateOptions // options of `nerdctl run`, e.g., Pull: "never"
+	NetworkOptions apitypes.NetworkOptions         // networking options of `nerdctl run`, e.g., NetworkSlice: {"compose-wordpress_default"}
+	Args           []string                        // {image, command...}
+	Mkdir          []string                        // For Bind.CreateHostPath
+	FileObjects    []FileObject                    // secrets and configs, to be materialized by the composer
+}
+
+// FileObject is a secret or a config of a container.
+// The composer materializes the file object in the data store with the ownership and the permissions,
+// and bind-mounts it to the target read-only.
+type FileObject struct {
+	Type    string       // "secret" or "config"
+	Source  string       // the name of the secret or the config
+	File    string       // absolute path of the source file on the host, or empty for Content
+	Content []byte       // inline content, or the value of the environment variable
+	Target  string       // absolute path in the container
+	UID     *int         // nil for the owner of the composer process
+	GID     *int         // nil for the group of the composer process
+	Mode    *os.FileMode // nil for the mode of File, or 0444 for Content
 }
 
 type Build struct {
@@ -203,6 +231,7 @@ type Service struct {
 	PullMode   string
 	Containers []Container // length = replicas
 	Build      *Build
+	Watch      []WatchTrigger // `develop.watch`
 	Unparsed   *types.ServiceConfig
 }
 
@@ -436,6 +465,13 @@ func Parse(project *types.Project, svc types.ServiceConfig) (*Service, error) {
 		}
 	}
 
+	if svc.Develop != nil {
+		parsed.Watch, err = parseWatchTriggers(svc.Develop, project)
+		if err != nil {
+			return nil, fmt.Errorf("service %s: failed to parse develop: %w", svc.Name, err)
+		}
+	}
+
 	switch svc.PullPolicy {
 	case "", types.PullPolicyMissing, types.PullPolicyIfNotPresent:
 		// NOP
@@ -462,6 +498,54 @@ func Parse(project *types.Project, svc types.ServiceConfig) (*Service, error) {
 	return parsed, nil
 }
 
+// setHealthcheckOptions sets the healthcheck options of svc.HealthCheck.
+// The test is passed as a JSON array (e.g., `["CMD","curl","-f","http://localhost"]`)
+// so that the CMD form is preserved.
+func setHealthcheckOptions(svc types.ServiceConfig, opts *apitypes.ContainerCreateOptions) error {
+	hc := svc.HealthCheck
+	if hc == nil {
+		return nil
+	}
+	if unknown := reflectutil.UnknownNonEmptyFields(hc,
+		"Test",
+		"Timeout",
+		"Interval",
+		"Retries",
+		"StartPeriod",
+		"StartInterval",
+		"Disable",
+	); len(unknown) > 0 {
+		log.L.Warnf("Ignoring: service %s: healthcheck: %+v", svc.Name, unknown)
+	}
+	if hc.Disable || (len(hc.Test) > 0 && hc.Test[0] == "NONE") {
+		opts.NoHealthcheck = true
+		return nil
+	}
+	if len(hc.Test) > 0 {
+		testJSON, err := json.Marshal(hc.Test)
+		if err != nil {
+			return err
+		}
+		opts.HealthCmd = string(testJSON)
+	}
+	if hc.Interval != nil {
+		opts.HealthInterval = time.Duration(*hc.Interval)
+	}
+	if hc.Timeout != nil {
+		opts.HealthTimeout = time.Duration(*hc.Timeout)
+	}
+	if hc.StartPeriod != nil {
+		opts.HealthStartPeriod = time.Duration(*hc.StartPeriod)
+	}
+	if hc.StartInterval != nil {
+		opts.HealthStartInterval = time.Duration(*hc.StartInterval)
+	}
+	if hc.Retries != nil {
+		opts.HealthRetries = int(*hc.Retries)
+	}
+	return nil
+}
+
 func newContainer(project *types.Project, parsed *Service, i int) (*Container, error) {
 	svc := *parsed.Unparsed
 	var c Container
@@ -473,263 +557,245 @@ func newContainer(project *types.Project, parsed *Service, i int) (*Container, e
 		c.Name = svc.ContainerName
 	}
 
-	c.RunArgs = []string{
-		"--name=" + c.Name,
-		"--pull=never", // because image will be ensured before running replicas with `nerdctl run`.
-	}
+	// The global options, the stdio, and the compose labels are filled by the composer.
+	opts := container.NewCreateOptions(apitypes.GlobalCommandOptions{})
+	opts.NameChanged = true
+	opts.Name = c.Name
+	opts.Pull = "never" // because image will be ensured before running replicas
 
 	for k, v := range svc.Annotations {
 		if v == "" {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--annotation=%s", k))
+			opts.Annotations = append(opts.Annotations, k)
 		} else {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--annotation=%s=%s", k, v))
+			opts.Annotations = append(opts.Annotations, fmt.Sprintf("%s=%s", k, v))
 		}
 	}
 
 	if svc.BlkioConfig != nil && svc.BlkioConfig.Weight != 0 {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--blkio-weight=%d", svc.BlkioConfig.Weight))
+		opts.BlkioWeight = svc.BlkioConfig.Weight
 	}
 
-	for _, v := range svc.CapAdd {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--cap-add=%s", v))
-	}
-
-	for _, v := range svc.CapDrop {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--cap-drop=%s", v))
-	}
+	opts.CapAdd = append(opts.CapAdd, svc.CapAdd...)
+	opts.CapDrop = append(opts.CapDrop, svc.CapDrop...)
 
 	if cpuLimit, err := getCPULimit(svc); err != nil {
 		return nil, err
 	} else if cpuLimit != "" {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--cpus=%s", cpuLimit))
-	}
-
-	if svc.CPUSet != "" {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--cpuset-cpus=%s", svc.CPUSet))
+		opts.CPUs, err = strconv.ParseFloat(cpuLimit, 64)
+		if err != nil {
+			return nil, err
+		}
 	}
 
+	opts.CPUSetCPUs = svc.CPUSet
 	if svc.CPUShares != 0 {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--cpu-shares=%d", svc.CPUShares))
-	}
-
-	for _, v := range svc.Devices {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--device=%s", v))
+		opts.CPUShares = uint64(svc.CPUShares)
 	}
 
-	for _, v := range svc.DNS {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--dns=%s", v))
-	}
-	for _, v := range svc.DNSSearch {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--dns-search=%s", v))
-	}
-	for _, v := range svc.DNSOpts {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--dns-option=%s", v))
-	}
+	opts.Device = append(opts.Device, svc.Devices...)
 
-	for _, v := range svc.Entrypoint {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--entrypoint=%s", v))
+	if len(svc.Entrypoint) > 0 {
+		opts.EntrypointChanged = true
+		opts.Entrypoint = append(opts.Entrypoint, svc.Entrypoint...)
 	}
 
 	for k, v := range svc.Environment {
 		if v == nil {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("-e=%s", k))
+			opts.Env = append(opts.Env, k)
 		} else {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("-e=%s=%s", k, *v))
-		}
-	}
-	for k, v := range svc.ExtraHosts {
-		for _, h := range v {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--add-host=%s:%s", k, h))
+			opts.Env = append(opts.Env, fmt.Sprintf("%s=%s", k, *v))
 		}
 	}
 
 	if svc.Init != nil && *svc.Init {
-		c.RunArgs = append(c.RunArgs, "--init")
+		opts.InitProcessFlag = true
+	}
+
+	if err := setHealthcheckOptions(svc, &opts); err != nil {
+		return nil, err
 	}
 
 	if memLimit, err := getMemLimit(svc); err != nil {
 		return nil, err
 	} else if memLimit > 0 {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("-m=%d", memLimit))
+		opts.Memory = strconv.FormatInt(int64(memLimit), 10)
 	}
 
 	if gpuReqs, err := getGPUs(svc); err != nil {
 		return nil, err
 	} else if len(gpuReqs) > 0 {
-		for _, gpus := range gpuReqs {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--gpus=%s", gpus))
-		}
+		opts.GPUs = append(opts.GPUs, gpuReqs...)
 	}
 
 	for k, v := range svc.Labels {
 		if v == "" {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("-l=%s", k))
+			opts.Label = append(opts.Label, k)
 		} else {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("-l=%s=%s", k, v))
+			opts.Label = append(opts.Label, fmt.Sprintf("%s=%s", k, v))
 		}
 	}
 
 	if svc.Logging != nil {
 		if svc.Logging.Driver != "" {
-			c.RunArgs = append(c.RunArgs, fmt.Sprintf("--log-driver=%s", svc.Logging.Driver))
+			opts.LogDriver = svc.Logging.Driver
 		}
-		if svc.Logging.Options != nil {
-			for k, v := range svc.Logging.Options {
-				c.RunArgs = append(c.RunArgs, fmt.Sprintf("--log-opt=%s=%s", k, v))
-			}
+		for k, v := range svc.Logging.Options {
+			opts.LogOpt = append(opts.LogOpt, fmt.Sprintf("%s=%s", k, v))
 		}
 	}
 
-	networks, err := getNetworks(project, svc)
+	netOpts, err := getNetworkOptions(project, svc)
 	if err != nil {
 		return nil, err
 	}
-	netTypeContainer := false
-	for _, net := range networks {
-		if strings.HasPrefix(net.fullName, "container:") {
-			netTypeContainer = true
-		}
-		c.RunArgs = append(c.RunArgs, "--net="+net.fullName)
-		if value, ok := svc.Networks[net.shortNetworkName]; ok {
-			if value != nil && value.Ipv4Address != "" {
-				c.RunArgs = append(c.RunArgs, "--ip="+value.Ipv4Address)
-			}
-		}
-	}
+	c.NetworkOptions = *netOpts
 
-	if netTypeContainer && svc.Hostname != "" {
-		return nil, fmt.Errorf("conflicting options: hostname and container network mode")
-	}
-	if !netTypeContainer {
-		hostname := svc.Hostname
-		if hostname == "" {
-			hostname = svc.Name
-		}
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--hostname=%s", hostname))
-	}
-
-	if svc.Pid != "" {
-		c.RunArgs = append(c.RunArgs, "--pid="+svc.Pid)
-	}
+	opts.Pid = svc.Pid
 
 	if svc.PidsLimit > 0 {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--pids-limit=%d", svc.PidsLimit))
-	}
-
-	if svc.Ulimits != nil {
-		for utype, ulimit := range svc.Ulimits {
-			if ulimit.Single != 0 {
-				c.RunArgs = append(c.RunArgs, fmt.Sprintf("--ulimit=%s=%d", utype, ulimit.Single))
-			} else {
-				c.RunArgs = append(c.RunArgs, fmt.Sprintf("--ulimit=%s=%d:%d", utype, ulimit.Soft, ulimit.Hard))
-			}
-		}
-	}
-
-	if svc.Platform != "" {
-		c.RunArgs = append(c.RunArgs, "--platform="+svc.Platform)
+		opts.PidsLimit = svc.PidsLimit
 	}
 
-	for _, p := range svc.Ports {
-		pStr, err := servicePortConfigToFlagP(p)
-		if err != nil {
-			return nil, err
+	for utype, ulimit := range svc.Ulimits {
+		if ulimit.Single != 0 {
+			opts.Ulimit = append(opts.Ulimit, fmt.Sprintf("%s=%d", utype, ulimit.Single))
+		} else {
+			opts.Ulimit = append(opts.Ulimit, fmt.Sprintf("%s=%d:%d", utype, ulimit.Soft, ulimit.Hard))
 		}
-		c.RunArgs = append(c.RunArgs, "-p="+pStr)
 	}
 
-	if svc.Privileged {
-		c.RunArgs = append(c.RunArgs, "--privileged")
-	}
-
-	if svc.ReadOnly {
-		c.RunArgs = append(c.RunArgs, "--read-only")
-	}
+	opts.Platform = svc.Platform
+	opts.Privileged = svc.Privileged
+	opts.ReadOnly = svc.ReadOnly
 
 	if svc.StopGracePeriod != nil {
 		timeout := time.Duration(*svc.StopGracePeriod)
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--stop-timeout=%d", int(timeout.Seconds())))
+		opts.StopTimeout = int(timeout.Seconds())
 	}
 	if svc.StopSignal != "" {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--stop-signal=%s", svc.StopSignal))
+		opts.StopSignal = svc.StopSignal
 	}
 
 	if restart, err := getRestart(svc); err != nil {
 		return nil, err
 	} else if restart != "" {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--restart=%s", restart))
+		opts.Restart = restart
 	}
 
 	if svc.Runtime != "" {
-		c.RunArgs = append(c.RunArgs, "--runtime="+svc.Runtime)
+		opts.Runtime = svc.Runtime
 	}
 
 	if svc.ShmSize > 0 {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--shm-size=%d", svc.ShmSize))
+		opts.ShmSize = strconv.FormatInt(int64(svc.ShmSize), 10)
 	}
 
-	for _, v := range svc.SecurityOpt {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--security-opt=%s", v))
-	}
+	opts.SecurityOpt = append(opts.SecurityOpt, svc.SecurityOpt...)
 
 	for k, v := range svc.Sysctls {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--sysctl=%s=%s", k, v))
-	}
-
-	if svc.StdinOpen {
-		c.RunArgs = append(c.RunArgs, "--interactive")
+		opts.Sysctl = append(opts.Sysctl, fmt.Sprintf("%s=%s", k, v))
 	}
 
-	if svc.User != "" {
-		c.RunArgs = append(c.RunArgs, "--user="+svc.User)
-	}
-
-	for _, v := range svc.GroupAdd {
-		c.RunArgs = append(c.RunArgs, fmt.Sprintf("--group-add=%s", v))
-	}
+	opts.Interactive = svc.StdinOpen
+	opts.User = svc.User
+	opts.GroupAdd = append(opts.GroupAdd, svc.GroupAdd...)
 
 	for _, v := range svc.Volumes {
-		vStr, mkdir, err := serviceVolumeConfigToFlagV(v, project)
+		vStr, mountStr, mkdir, err := serviceVolumeConfigToFlags(v, project)
 		if err != nil {
 			return nil, err
 		}
-		c.RunArgs = append(c.RunArgs, "-v="+vStr)
-		c.Mkdir = mkdir
+		if mountStr != "" {
+			opts.Mount = append(opts.Mount, mountStr)
+		} else {
+			opts.Volume = append(opts.Volume, vStr)
+		}
+		c.Mkdir = append(c.Mkdir, mkdir...)
 	}
 
 	for _, config := range svc.Configs {
 		fileRef := types.FileReferenceConfig(config)
-		vStr, err := fileReferenceConfigToFlagV(fileRef, project, false)
+		obj, err := parseFileReferenceConfig(fileRef, project, false)
 		if err != nil {
 			return nil, err
 		}
-		c.RunArgs = append(c.RunArgs, "-v="+vStr)
+		c.FileObjects = append(c.FileObjects, *obj)
 	}
 
 	for _, secret := range svc.Secrets {
 		fileRef := types.FileReferenceConfig(secret)
-		vStr, err := fileReferenceConfigToFlagV(fileRef, project, true)
+		obj, err := parseFileReferenceConfig(fileRef, project, true)
 		if err != nil {
 			return nil, err
 		}
-		c.RunArgs = append(c.RunArgs, "-v="+vStr)
+		c.FileObjects = append(c.FileObjects, *obj)
 	}
 
-	for _, tmpfs := range svc.Tmpfs {
-		c.RunArgs = append(c.RunArgs, "--tmpfs="+tmpfs)
+	opts.Tmpfs = append(opts.Tmpfs, svc.Tmpfs...)
+	opts.TTY = svc.Tty
+	opts.Workdir = svc.WorkingDir
+
+	c.CreateOptions = opts
+	c.Args = append([]string{parsed.Image}, svc.Command...) // NOT svc.Image
+	return &c, nil
+}
+
+// getNetworkOptions returns the networking options of the service containers.
+func getNetworkOptions(project *types.Project, svc types.ServiceConfig) (*apitypes.NetworkOptions, error) {
+	networks, err := getNetworks(project, svc)
+	if err != nil {
+		return nil, err
+	}
+	netOpts := &apitypes.NetworkOptions{
+		DNSServers:           svc.DNS,
+		DNSSearchDomains:     svc.DNSSearch,
+		DNSResolvConfOptions: svc.DNSOpts,
+		PortMappings:         []gocni.PortMapping{},
+	}
+	netTypeContainer := false
+	for _, net := range networks {
+		if strings.HasPrefix(net.fullName, "container:") {
+			netTypeContainer = true
+		}
+		netOpts.NetworkSlice = append(netOpts.NetworkSlice, net.fullName)
+		if value, ok := svc.Networks[net.shortNetworkName]; ok {
+			if value != nil && value.Ipv4Address != "" {
+				netOpts.IPAddress = value.Ipv4Address
+			}
+		}
+	}
+	if len(netOpts.NetworkSlice) == 0 {
+		netOpts.NetworkSlice = []string{netutil.DefaultNetworkName}
 	}
 
-	if svc.Tty {
-		c.RunArgs = append(c.RunArgs, "--tty")
+	if netTypeContainer && svc.Hostname != "" {
+		return nil, fmt.Errorf("conflicting options: hostname and container network mode")
+	}
+	if !netTypeContainer {
+		netOpts.Hostname = svc.Hostname
+		if netOpts.Hostname == "" {
+			netOpts.Hostname = svc.Name
+		}
 	}
 
-	if svc.WorkingDir != "" {
-		c.RunArgs = append(c.RunArgs, "-w="+svc.WorkingDir)
+	for k, v := range svc.ExtraHosts {
+		for _, h := range v {
+			netOpts.AddHost = append(netOpts.AddHost, fmt.Sprintf("%s:%s", k, h))
+		}
 	}
 
-	c.RunArgs = append(c.RunArgs, parsed.Image) // NOT svc.Image
-	c.RunArgs = append(c.RunArgs, svc.Command...)
-	return &c, nil
+	for _, p := range svc.Ports {
+		pStr, err := servicePortConfigToFlagP(p)
+		if err != nil {
+			return nil, err
+		}
+		pm, err := portutil.ParseFlagP(pStr)
+		if err != nil {
+			return nil, err
+		}
+		netOpts.PortMappings = append(netOpts.PortMappings, pm...)
+	}
+	return netOpts, nil
 }
 
 func servicePortConfigToFlagP(c types.ServicePortConfig) (string, error) {
@@ -764,52 +830,81 @@ func servicePortConfigToFlagP(c types.ServicePortConfig) (string, error) {
 	return s, nil
 }
 
-func serviceVolumeConfigToFlagV(c types.ServiceVolumeConfig, project *types.Project) (flagV string, mkdir []string, err error) {
+// serviceVolumeConfigToFlags converts the volume to the `nerdctl run -v` string (flagV),
+// or to the `nerdctl run --mount` string (flagMount) when the volume has options that cannot be expressed with `-v`.
+func serviceVolumeConfigToFlags(c types.ServiceVolumeConfig, project *types.Project) (flagV, flagMount string, mkdir []string, err error) {
 	if unknown := reflectutil.UnknownNonEmptyFields(&c,
 		"Type",
 		"Source",
 		"Target",
 		"ReadOnly",
+		"Consistency",
 		"Bind",
 		"Volume",
+		"Tmpfs",
 	); len(unknown) > 0 {
 		log.L.Warnf("Ignoring: volume: %+v", unknown)
 	}
 	if c.Bind != nil {
 		// c.Bind is expected to be a non-nil reference to an empty Bind struct
-		if unknown := reflectutil.UnknownNonEmptyFields(c.Bind, "CreateHostPath"); len(unknown) > 0 {
+		if unknown := reflectutil.UnknownNonEmptyFields(c.Bind, "CreateHostPath", "Propagation"); len(unknown) > 0 {
 			log.L.Warnf("Ignoring: volume: Bind: %+v", unknown)
 		}
 	}
 	if c.Volume != nil {
 		// c.Volume is expected to be a non-nil reference to an empty Volume struct
-		if unknown := reflectutil.UnknownNonEmptyFields(c.Volume); len(unknown) > 0 {
+		if unknown := reflectutil.UnknownNonEmptyFields(c.Volume, "NoCopy", "Subpath"); len(unknown) > 0 {
 			log.L.Warnf("Ignoring: volume: Volume: %+v", unknown)
 		}
 	}
+	if c.Tmpfs != nil {
+		if unknown := reflectutil.UnknownNonEmptyFields(c.Tmpfs, "Size", "Mode"); len(unknown) > 0 {
+			log.L.Warnf("Ignoring: volume: Tmpfs: %+v", unknown)
+		}
+	}
 
 	if c.Target == "" {
-		return "", nil, errors.New("volume target is missing")
+		return "", "", nil, errors.New("volume target is missing")
 	}
 	if !filepath.IsAbs(c.Target) {
-		return "", nil, fmt.Errorf("volume target must be an absolute path, got %q", c.Target)
+		return "", "", nil, fmt.Errorf("volume target must be an absolute path, got %q", c.Target)
 	}
 
-	if c.Source == "" {
-		// anonymous volume
-		s := c.Target
-		if c.ReadOnly {
-			s += ":ro"
-		}
-		return s, mkdir, nil
+	// mountOpts are the options of `--mount`, except type, src, and dst.
+	// `--mount` is used only when needMount is set, for compatibility with the platforms that lack `--mount`.
+	var (
+		mountOpts []string
+		needMount bool
+		src       string
+	)
+	if c.ReadOnly {
+		mountOpts = append(mountOpts, "readonly")
+	}
+	if c.Consistency != "" {
+		// consistency is accepted but ignored, so it does not need `--mount`
+		mountOpts = append(mountOpts, "consistency="+c.Consistency)
 	}
 
-	var src string
 	switch c.Type {
 	case "volume":
+		if c.Volume != nil && c.Volume.NoCopy {
+			mountOpts = append(mountOpts, "volume-nocopy")
+			needMount = true
+		}
+		if c.Volume != nil && c.Volume.Subpath != "" {
+			mountOpts = append(mountOpts, "volume-subpath="+c.Volume.Subpath)
+			needMount = true
+		}
+		if c.Source == "" {
+			// anonymous volume; `-v` does not take the options of anonymous volumes
+			if c.ReadOnly {
+				needMount = true
+			}
+			break
+		}
 		vol, ok := project.Volumes[c.Source]
 		if !ok {
-			return "", nil, fmt.Errorf("invalid volume %q", c.Source)
+			return "", "", nil, fmt.Errorf("invalid volume %q", c.Source)
 		}
 		// c.Source is like "db_data", vol.Name is like "compose-wordpress_db_data"
 		src = vol.Name
@@ -818,24 +913,51 @@ func serviceVolumeConfigToFlagV(c types.ServiceVolumeConfig, project *types.Proj
 		var err error
 		src, err = filepath.Abs(src)
 		if err != nil {
-			return "", nil, fmt.Errorf("invalid relative path %q: %w", c.Source, err)
+			return "", "", nil, fmt.Errorf("invalid relative path %q: %w", c.Source, err)
 		}
 		if c.Bind != nil && c.Bind.CreateHostPath {
 			if _, stErr := os.Stat(src); errors.Is(stErr, os.ErrNotExist) {
 				mkdir = append(mkdir, src)
 			}
 		}
+		if c.Bind != nil && c.Bind.Propagation != "" {
+			mountOpts = append(mountOpts, "bind-propagation="+c.Bind.Propagation)
+			needMount = true
+		}
+	case "tmpfs":
+		if c.Source != "" {
+			return "", "", nil, fmt.Errorf("tmpfs volume %q must not have source, got %q", c.Target, c.Source)
+		}
+		if c.Tmpfs != nil && c.Tmpfs.Size > 0 {
+			mountOpts = append(mountOpts, fmt.Sprintf("tmpfs-size=%d", c.Tmpfs.Size))
+		}
+		if c.Tmpfs != nil && c.Tmpfs.Mode != 0 {
+			mountOpts = append(mountOpts, fmt.Sprintf("tmpfs-mode=%o", c.Tmpfs.Mode))
+		}
+		needMount = true
 	default:
-		return "", nil, fmt.Errorf("unsupported volume type: %q", c.Type)
+		return "", "", nil, fmt.Errorf("unsupported volume type: %q", c.Type)
+	}
+
+	if needMount {
+		fields := []string{"type=" + c.Type}
+		if src != "" {
+			fields = append(fields, "src="+src)
+		}
+		fields = append(fields, "dst="+c.Target)
+		return "", strings.Join(append(fields, mountOpts...), ","), mkdir, nil
+	}
+	s := c.Target
+	if src != "" {
+		s = fmt.Sprintf("%s:%s", src, c.Target)
 	}
-	s := fmt.Sprintf("%s:%s", src, c.Target)
 	if c.ReadOnly {
 		s += ":ro"
 	}
-	return s, mkdir, nil
+	return s, "", mkdir, nil
 }
 
-func fileReferenceConfigToFlagV(c types.FileReferenceConfig, project *types.Project, secret bool) (string, error) {
+func parseFileReferenceConfig(c types.FileReferenceConfig, project *types.Project, secret bool) (*FileObject, error) {
 	objType := "config"
 	if secret {
 		objType = "secret"
@@ -847,28 +969,43 @@ func fileReferenceConfigToFlagV(c types.FileReferenceConfig, project *types.Proj
 	}
 
 	if err := identifiers.Validate(c.Source); err != nil {
-		return "", fmt.Errorf("%s source %q is invalid: %w", objType, c.Source, err)
+		return nil, fmt.Errorf("%s source %q is invalid: %w", objType, c.Source, err)
 	}
 
 	var obj types.FileObjectConfig
 	if secret {
 		secret, ok := project.Secrets[c.Source]
 		if !ok {
-			return "", fmt.Errorf("secret %s is undefined", c.Source)
+			return nil, fmt.Errorf("secret %s is undefined", c.Source)
 		}
 		obj = types.FileObjectConfig(secret)
 	} else {
 		config, ok := project.Configs[c.Source]
 		if !ok {
-			return "", fmt.Errorf("config %s is undefined", c.Source)
+			return nil, fmt.Errorf("config %s is undefined", c.Source)
 		}
 		obj = types.FileObjectConfig(config)
 	}
-	src := project.RelativePath(obj.File)
-	var err error
-	src, err = filepath.Abs(src)
-	if err != nil {
-		return "", fmt.Errorf("%s %s: invalid relative path %q: %w", objType, c.Source, src, err)
+	parsed := &FileObject{
+		Type:   objType,
+		Source: c.Source,
+	}
+	switch {
+	case obj.File != "":
+		src, err := filepath.Abs(project.RelativePath(obj.File))
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: invalid relative path %q: %w", objType, c.Source, obj.File, err)
+		}
+		parsed.File = src
+	case obj.Environment != "":
+		v, ok := project.Environment[obj.Environment]
+		if !ok {
+			return nil, fmt.Errorf("%s %s: environment variable %q is not set", objType, c.Source, obj.Environment)
+		}
+		parsed.Content = []byte(v)
+	default:
+		// the content may be empty
+		parsed.Content = []byte(obj.Content)
 	}
 
 	target := c.Target
@@ -884,24 +1021,34 @@ func fileReferenceConfigToFlagV(c types.FileReferenceConfig, project *types.Proj
 			if secret {
 				target = filepath.Join("/run/secrets", target)
 			} else {
-				return "", fmt.Errorf("config %s: target %q must be an absolute path", c.Source, c.Target)
+				return nil, fmt.Errorf("config %s: target %q must be an absolute path", c.Source, c.Target)
 			}
 		}
 	}
+	parsed.Target = target
 
 	if c.UID != "" {
-		// Raise an error rather than ignoring the value, for avoiding any security issue
-		return "", fmt.Errorf("%s %s: unsupported field: UID", objType, c.Source)
+		uid, err := strconv.Atoi(c.UID)
+		if err != nil || uid < 0 {
+			return nil, fmt.Errorf("%s %s: invalid uid %q", objType, c.Source, c.UID)
+		}
+		parsed.UID = &uid
 	}
 	if c.GID != "" {
-		return "", fmt.Errorf("%s %s: unsupported field: GID", objType, c.Source)
+		gid, err := strconv.Atoi(c.GID)
+		if err != nil || gid < 0 {
+			return nil, fmt.Errorf("%s %s: invalid gid %q", objType, c.Source, c.GID)
+		}
+		parsed.GID = &gid
 	}
 	if c.Mode != nil {
-		return "", fmt.Errorf("%s %s: unsupported field: Mode", objType, c.Source)
+		if *c.Mode&^uint32(os.ModePerm) != 0 {
+			return nil, fmt.Errorf("%s %s: invalid mode %o", objType, c.Source, *c.Mode)
+		}
+		mode := os.FileMode(*c.Mode)
+		parsed.Mode = &mode
 	}
-
-	s := fmt.Sprintf("%s:%s:ro", src, target)
-	return s, nil
+	return parsed, nil
 }
 
 // DefaultImageName returns the image name following compose naming logic.
@@ -913,3 +1060,26 @@ func DefaultImageName(projectName string, serviceName string) string {
 func DefaultContainerName(projectName, serviceName, suffix string) string {
 	return DefaultImageName(projectName, serviceName) + Separator + suffix
 }
+
+// ConfigHash returns the hash of the service config and the image digest.
+// The fields that do not affect the containers, such as the build config, the number of the replicas,
+// the dependencies, the profiles and the develop config, are not hashed.
+// FYI: https://github.com/docker/compose/blob/v2.29.1/pkg/compose/hash.go
+func ConfigHash(svc types.ServiceConfig, imageDigest string) (string, error) {
+	svc.Build = nil
+	svc.PullPolicy = ""
+	svc.Scale = nil
+	svc.DependsOn = nil
+	svc.Profiles = nil
+	svc.Develop = nil
+	if svc.Deploy != nil {
+		deploy := *svc.Deploy
+		deploy.Replicas = nil
+		svc.Deploy = &deploy
+	}
+	b, err := json.Marshal(svc)
+	if err != nil {
+		return "", err
+	}
+	return digest.SHA256.FromBytes(append(b, imageDigest...)).Encoded(), nil
+}
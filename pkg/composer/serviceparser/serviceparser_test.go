@@ -22,8 +22,10 @@ import (
 	"path/filepath"
 	"strconv"
 	"testing"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
+	gocni "github.com/containerd/go-cni"
 	"github.com/containerd/nerdctl/v2/pkg/composer/projectloader"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 	"github.com/containerd/nerdctl/v2/pkg/testutil"
@@ -156,29 +158,29 @@ volumes:
 	assert.Assert(t, len(wp.Containers) == 1)
 	wp1 := wp.Containers[0]
 	assert.Assert(t, wp1.Name == DefaultContainerName(project.Name, "wordpress", "1"))
-	assert.Assert(t, in(wp1.RunArgs, "--name="+wp1.Name))
-	assert.Assert(t, in(wp1.RunArgs, "--hostname=wordpress"))
-	assert.Assert(t, in(wp1.RunArgs, fmt.Sprintf("--net=%s_default", project.Name)))
-	assert.Assert(t, in(wp1.RunArgs, "--restart=always"))
-	assert.Assert(t, in(wp1.RunArgs, "-e=WORDPRESS_DB_HOST=db"))
-	assert.Assert(t, in(wp1.RunArgs, "-e=WORDPRESS_DB_USER=exampleuser"))
-	assert.Assert(t, in(wp1.RunArgs, "-p=8080:80/tcp"))
-	assert.Assert(t, in(wp1.RunArgs, fmt.Sprintf("-v=%s_wordpress:/var/www/html", project.Name)))
-	assert.Assert(t, in(wp1.RunArgs, "--pids-limit=100"))
-	assert.Assert(t, in(wp1.RunArgs, "--ulimit=nproc=500"))
-	assert.Assert(t, in(wp1.RunArgs, "--ulimit=nofile=20000:20000"))
-	assert.Assert(t, in(wp1.RunArgs, "--dns=8.8.8.8"))
-	assert.Assert(t, in(wp1.RunArgs, "--dns=8.8.4.4"))
-	assert.Assert(t, in(wp1.RunArgs, "--dns-search=example.com"))
-	assert.Assert(t, in(wp1.RunArgs, "--dns-option=no-tld-query"))
-	assert.Assert(t, in(wp1.RunArgs, "--log-driver=json-file"))
-	assert.Assert(t, in(wp1.RunArgs, "--log-opt=max-size=5K"))
-	assert.Assert(t, in(wp1.RunArgs, "--log-opt=max-file=2"))
-	assert.Assert(t, in(wp1.RunArgs, "--add-host=test.com:172.19.1.1"))
-	assert.Assert(t, in(wp1.RunArgs, "--add-host=test2.com:172.19.1.2"))
-	assert.Assert(t, in(wp1.RunArgs, "--shm-size=1073741824"))
-	assert.Assert(t, in(wp1.RunArgs, "--user=1001:1001"))
-	assert.Assert(t, in(wp1.RunArgs, "--group-add=1001"))
+	assert.Equal(t, wp1.CreateOptions.Name, wp1.Name)
+	assert.Equal(t, wp1.NetworkOptions.Hostname, "wordpress")
+	assert.Assert(t, in(wp1.NetworkOptions.NetworkSlice, fmt.Sprintf("%s_default", project.Name)))
+	assert.Equal(t, wp1.CreateOptions.Restart, "always")
+	assert.Assert(t, in(wp1.CreateOptions.Env, "WORDPRESS_DB_HOST=db"))
+	assert.Assert(t, in(wp1.CreateOptions.Env, "WORDPRESS_DB_USER=exampleuser"))
+	assert.DeepEqual(t, wp1.NetworkOptions.PortMappings, []gocni.PortMapping{{HostPort: 8080, ContainerPort: 80, Protocol: "tcp", HostIP: "0.0.0.0"}})
+	assert.Assert(t, in(wp1.CreateOptions.Volume, fmt.Sprintf("%s_wordpress:/var/www/html", project.Name)))
+	assert.Equal(t, wp1.CreateOptions.PidsLimit, int64(100))
+	assert.Assert(t, in(wp1.CreateOptions.Ulimit, "nproc=500"))
+	assert.Assert(t, in(wp1.CreateOptions.Ulimit, "nofile=20000:20000"))
+	assert.Assert(t, in(wp1.NetworkOptions.DNSServers, "8.8.8.8"))
+	assert.Assert(t, in(wp1.NetworkOptions.DNSServers, "8.8.4.4"))
+	assert.Assert(t, in(wp1.NetworkOptions.DNSSearchDomains, "example.com"))
+	assert.Assert(t, in(wp1.NetworkOptions.DNSResolvConfOptions, "no-tld-query"))
+	assert.Equal(t, wp1.CreateOptions.LogDriver, "json-file")
+	assert.Assert(t, in(wp1.CreateOptions.LogOpt, "max-size=5K"))
+	assert.Assert(t, in(wp1.CreateOptions.LogOpt, "max-file=2"))
+	assert.Assert(t, in(wp1.NetworkOptions.AddHost, "test.com:172.19.1.1"))
+	assert.Assert(t, in(wp1.NetworkOptions.AddHost, "test2.com:172.19.1.2"))
+	assert.Equal(t, wp1.CreateOptions.ShmSize, "1073741824")
+	assert.Equal(t, wp1.CreateOptions.User, "1001:1001")
+	assert.Assert(t, in(wp1.CreateOptions.GroupAdd, "1001"))
 
 	dbSvc, err := project.GetService("db")
 	assert.NilError(t, err)
@@ -190,10 +192,10 @@ volumes:
 	assert.Assert(t, len(db.Containers) == 1)
 	db1 := db.Containers[0]
 	assert.Assert(t, db1.Name == DefaultContainerName(project.Name, "db", "1"))
-	assert.Assert(t, in(db1.RunArgs, "--hostname=db"))
-	assert.Assert(t, in(db1.RunArgs, fmt.Sprintf("-v=%s_db:/var/lib/mysql", project.Name)))
-	assert.Assert(t, in(db1.RunArgs, "--stop-signal=SIGUSR1"))
-	assert.Assert(t, in(db1.RunArgs, "--stop-timeout=90"))
+	assert.Equal(t, db1.NetworkOptions.Hostname, "db")
+	assert.Assert(t, in(db1.CreateOptions.Volume, fmt.Sprintf("%s_db:/var/lib/mysql", project.Name)))
+	assert.Equal(t, db1.CreateOptions.StopSignal, "SIGUSR1")
+	assert.Equal(t, db1.CreateOptions.StopTimeout, 90)
 }
 
 func TestParseDeprecated(t *testing.T) {
@@ -225,9 +227,9 @@ services:
 	assert.Assert(t, len(foo.Containers) == 1)
 	for i, c := range foo.Containers {
 		assert.Assert(t, c.Name == DefaultContainerName(project.Name, "foo", strconv.Itoa(i+1)))
-		assert.Assert(t, in(c.RunArgs, "--name="+c.Name))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("--cpus=%f", 0.42)))
-		assert.Assert(t, in(c.RunArgs, "-m=44040192"))
+		assert.Equal(t, c.CreateOptions.Name, c.Name)
+		assert.Equal(t, c.CreateOptions.CPUs, 0.42)
+		assert.Equal(t, c.CreateOptions.Memory, "44040192")
 	}
 }
 
@@ -286,11 +288,11 @@ services:
 	assert.Assert(t, len(foo.Containers) == 3)
 	for i, c := range foo.Containers {
 		assert.Assert(t, c.Name == DefaultContainerName(project.Name, "foo", strconv.Itoa(i+1)))
-		assert.Assert(t, in(c.RunArgs, "--name="+c.Name))
+		assert.Equal(t, c.CreateOptions.Name, c.Name)
 
-		assert.Assert(t, in(c.RunArgs, "--restart=no"))
-		assert.Assert(t, in(c.RunArgs, "--cpus=0.42"))
-		assert.Assert(t, in(c.RunArgs, "-m=44040192"))
+		assert.Equal(t, c.CreateOptions.Restart, "no")
+		assert.Equal(t, c.CreateOptions.CPUs, 0.42)
+		assert.Equal(t, c.CreateOptions.Memory, "44040192")
 	}
 
 	barSvc, err := project.GetService("bar")
@@ -302,9 +304,9 @@ services:
 	t.Logf("bar: %+v", bar)
 	assert.Assert(t, len(bar.Containers) == 1)
 	for _, c := range bar.Containers {
-		assert.Assert(t, in(c.RunArgs, "--restart=always"))
-		assert.Assert(t, in(c.RunArgs, `--gpus="capabilities=gpu,utility,compute",driver=nvidia,count=2`))
-		assert.Assert(t, in(c.RunArgs, `--gpus=capabilities=nvidia,"device=dummy,dummy2"`))
+		assert.Equal(t, c.CreateOptions.Restart, "always")
+		assert.Assert(t, in(c.CreateOptions.GPUs, `"capabilities=gpu,utility,compute",driver=nvidia,count=2`))
+		assert.Assert(t, in(c.CreateOptions.GPUs, `capabilities=nvidia,"device=dummy,dummy2"`))
 	}
 
 	bazSvc, err := project.GetService("baz")
@@ -316,8 +318,8 @@ services:
 	t.Logf("baz: %+v", baz)
 	assert.Assert(t, len(baz.Containers) == 1)
 	for _, c := range baz.Containers {
-		assert.Assert(t, in(c.RunArgs, "--restart=no"))
-		assert.Assert(t, in(c.RunArgs, `--gpus=capabilities=utility,count=-1`))
+		assert.Equal(t, c.CreateOptions.Restart, "no")
+		assert.Assert(t, in(c.CreateOptions.GPUs, `capabilities=utility,count=-1`))
 	}
 
 	quxSvc, err := project.GetService("qux")
@@ -357,9 +359,79 @@ services:
 
 	t.Logf("foo: %+v", foo)
 	for _, c := range foo.Containers {
-		assert.Assert(t, in(c.RunArgs, "-v=/file1:/file1"))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/file2", filepath.Join(project.WorkingDir, "file2"))))
-		assert.Assert(t, in(c.RunArgs, "-v=/file3:/file3"))
+		assert.Assert(t, in(c.CreateOptions.Volume, "/file1:/file1"))
+		assert.Assert(t, in(c.CreateOptions.Volume, fmt.Sprintf("%s:/file2", filepath.Join(project.WorkingDir, "file2"))))
+		assert.Assert(t, in(c.CreateOptions.Volume, "/file3:/file3"))
+	}
+}
+
+func TestParseVolumeLongSyntax(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: nginx:alpine
+    volumes:
+    - type: volume
+      source: vol1
+      target: /vol1
+      read_only: true
+    - type: volume
+      source: vol1
+      target: /vol1-nocopy
+      volume:
+        nocopy: true
+        subpath: sub
+    - type: volume
+      target: /anonymous
+      read_only: true
+    - type: bind
+      source: ./bind1
+      target: /bind1
+      consistency: cached
+      bind:
+        propagation: rshared
+        create_host_path: true
+    - type: bind
+      source: ./bind2
+      target: /bind2
+      bind:
+        create_host_path: true
+    - type: tmpfs
+      target: /tmpfs1
+      tmpfs:
+        size: 64m
+        mode: 01770
+volumes:
+  vol1: {}
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := projectloader.Load(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+
+	foo, err := Parse(project, fooSvc)
+	assert.NilError(t, err)
+
+	t.Logf("foo: %+v", foo)
+	vol1 := project.Name + "_vol1"
+	bind1, bind2 := filepath.Join(project.WorkingDir, "bind1"), filepath.Join(project.WorkingDir, "bind2")
+	for _, c := range foo.Containers {
+		assert.DeepEqual(t, c.CreateOptions.Volume, []string{
+			vol1 + ":/vol1:ro",
+			bind2 + ":/bind2",
+		})
+		assert.DeepEqual(t, c.CreateOptions.Mount, []string{
+			"type=volume,src=" + vol1 + ",dst=/vol1-nocopy,volume-nocopy,volume-subpath=sub",
+			"type=volume,dst=/anonymous,readonly",
+			"type=bind,src=" + bind1 + ",dst=/bind1,consistency=cached,bind-propagation=rshared",
+			"type=tmpfs,dst=/tmpfs1,tmpfs-size=67108864,tmpfs-mode=1770",
+		})
+		assert.DeepEqual(t, c.Mkdir, []string{bind1, bind2})
 	}
 }
 
@@ -389,7 +461,7 @@ services:
 
 	t.Logf("foo: %+v", foo)
 	for _, c := range foo.Containers {
-		assert.Assert(t, in(c.RunArgs, "--net=host"))
+		assert.DeepEqual(t, c.NetworkOptions.NetworkSlice, []string{"host"})
 	}
 
 	barSvc, err := project.GetService("bar")
@@ -400,8 +472,8 @@ services:
 
 	t.Logf("bar: %+v", bar)
 	for _, c := range bar.Containers {
-		assert.Assert(t, in(c.RunArgs, "--net=container:nginx"))
-		assert.Assert(t, !in(c.RunArgs, "--hostname=bar"))
+		assert.DeepEqual(t, c.NetworkOptions.NetworkSlice, []string{"container:nginx"})
+		assert.Equal(t, c.NetworkOptions.Hostname, "")
 	}
 
 }
@@ -418,10 +490,15 @@ services:
       target: secret2-foo
     - source: secret3
       target: /mnt/secret3-foo
+      uid: "1000"
+      gid: "1001"
+      mode: 0400
+    - secret4
     configs:
     - config1
     - source: config2
       target: /mnt/config2-foo
+      mode: 0440
 secrets:
   secret1:
     file: ./secret1
@@ -429,19 +506,21 @@ secrets:
     file: ./secret2
   secret3:
     file: ./secret3
+  secret4:
+    environment: SECRET4
 configs:
   config1:
     file: ./config1
   config2:
-    file: ./config2
+    content: content-config2
 `
 	comp := testutil.NewComposeDir(t, dockerComposeYAML)
 	defer comp.CleanUp()
 
-	project, err := projectloader.Load(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	project, err := projectloader.Load(comp.YAMLFullPath(), comp.ProjectName(), map[string]string{"SECRET4": "content-secret4"})
 	assert.NilError(t, err)
 
-	for _, f := range []string{"secret1", "secret2", "secret3", "config1", "config2"} {
+	for _, f := range []string{"secret1", "secret2", "secret3", "config1"} {
 		err = os.WriteFile(filepath.Join(project.WorkingDir, f), []byte("content-"+f), 0444)
 		assert.NilError(t, err)
 	}
@@ -453,12 +532,133 @@ configs:
 	assert.NilError(t, err)
 
 	t.Logf("foo: %+v", foo)
+	uid, gid := 1000, 1001
+	secret3Mode, config2Mode := os.FileMode(0400), os.FileMode(0440)
 	for _, c := range foo.Containers {
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/run/secrets/secret1:ro", filepath.Join(project.WorkingDir, "secret1"))))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/run/secrets/secret2-foo:ro", filepath.Join(project.WorkingDir, "secret2"))))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/mnt/secret3-foo:ro", filepath.Join(project.WorkingDir, "secret3"))))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/config1:ro", filepath.Join(project.WorkingDir, "config1"))))
-		assert.Assert(t, in(c.RunArgs, fmt.Sprintf("-v=%s:/mnt/config2-foo:ro", filepath.Join(project.WorkingDir, "config2"))))
+		assert.DeepEqual(t, c.FileObjects, []FileObject{
+			{Type: "config", Source: "config1", File: filepath.Join(project.WorkingDir, "config1"), Target: "/config1"},
+			{Type: "config", Source: "config2", Content: []byte("content-config2"), Target: "/mnt/config2-foo", Mode: &config2Mode},
+			{Type: "secret", Source: "secret1", File: filepath.Join(project.WorkingDir, "secret1"), Target: "/run/secrets/secret1"},
+			{Type: "secret", Source: "secret2", File: filepath.Join(project.WorkingDir, "secret2"), Target: "/run/secrets/secret2-foo"},
+			{Type: "secret", Source: "secret3", File: filepath.Join(project.WorkingDir, "secret3"), Target: "/mnt/secret3-foo", UID: &uid, GID: &gid, Mode: &secret3Mode},
+			{Type: "secret", Source: "secret4", Content: []byte("content-secret4"), Target: "/run/secrets/secret4"},
+		})
+	}
+}
+
+func TestParseConfigsInvalid(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: nginx:alpine
+    secrets:
+    - source: secret1
+      uid: foo
+  bar:
+    image: nginx:alpine
+    secrets:
+    - secret2
+secrets:
+  secret1:
+    file: ./secret1
+  secret2:
+    environment: SECRET2
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := projectloader.Load(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+	_, err = Parse(project, fooSvc)
+	assert.ErrorContains(t, err, "invalid uid")
+
+	barSvc, err := project.GetService("bar")
+	assert.NilError(t, err)
+	_, err = Parse(project, barSvc)
+	assert.ErrorContains(t, err, "is not set")
+}
+
+func TestParseInclude(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+include:
+  - path: sub/compose.yaml
+    env_file: sub/custom.env
+services:
+  web:
+    image: nginx:alpine
+    extends:
+      file: base.yaml
+      service: base
+    depends_on:
+      - db
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	comp.WriteFile("base.yaml", `
+services:
+  base:
+    image: alpine:3.14
+    volumes:
+      - ./data:/data
+    environment:
+      FOO: bar
+`)
+	assert.NilError(t, os.Mkdir(filepath.Join(comp.Dir(), "sub"), 0755))
+	comp.WriteFile("sub/compose.yaml", `
+services:
+  db:
+    image: ${DB_IMAGE}
+    volumes:
+      - ./dbdata:/var/lib/db
+      - dbvol:/var/lib/dbvol
+    secrets:
+      - s1
+    networks:
+      - backend
+volumes:
+  dbvol: {}
+networks:
+  backend: {}
+secrets:
+  s1:
+    file: ./s1.txt
+`)
+	comp.WriteFile("sub/custom.env", "DB_IMAGE=mariadb:10.5\n")
+	comp.WriteFile("sub/s1.txt", "content-s1")
+
+	project, err := projectloader.Load(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	webSvc, err := project.GetService("web")
+	assert.NilError(t, err)
+	web, err := Parse(project, webSvc)
+	assert.NilError(t, err)
+	t.Logf("web: %+v", web)
+	assert.Equal(t, web.Image, "nginx:alpine")
+	for _, c := range web.Containers {
+		assert.Assert(t, in(c.CreateOptions.Env, "FOO=bar"))
+		assert.Assert(t, in(c.CreateOptions.Volume, filepath.Join(comp.Dir(), "data")+":/data"))
+	}
+
+	dbSvc, err := project.GetService("db")
+	assert.NilError(t, err)
+	db, err := Parse(project, dbSvc)
+	assert.NilError(t, err)
+	t.Logf("db: %+v", db)
+	assert.Equal(t, db.Image, "mariadb:10.5")
+	for _, c := range db.Containers {
+		assert.Assert(t, in(c.CreateOptions.Volume, filepath.Join(comp.Dir(), "sub", "dbdata")+":/var/lib/db"))
+		assert.Assert(t, in(c.CreateOptions.Volume, project.Name+"_dbvol:/var/lib/dbvol"))
+		assert.DeepEqual(t, c.NetworkOptions.NetworkSlice, []string{project.Name + "_backend"})
+		assert.DeepEqual(t, c.FileObjects, []FileObject{
+			{Type: "secret", Source: "s1", File: filepath.Join(comp.Dir(), "sub", "s1.txt"), Target: "/run/secrets/s1"},
+		})
 	}
 }
 
@@ -496,14 +696,152 @@ services:
 
 	var c Container
 	c = getContainersFromService("onfailure_no_count")[0]
-	assert.Assert(t, in(c.RunArgs, "--restart=on-failure"))
+	assert.Equal(t, c.CreateOptions.Restart, "on-failure")
 
 	c = getContainersFromService("onfailure_with_count")[0]
-	assert.Assert(t, in(c.RunArgs, "--restart=on-failure:10"))
+	assert.Equal(t, c.CreateOptions.Restart, "on-failure:10")
 
 	c = getContainersFromService("onfailure_ignore")[0]
-	assert.Assert(t, !in(c.RunArgs, "--restart=on-failure:3.14"))
+	assert.Assert(t, c.CreateOptions.Restart != "on-failure:3.14")
 
 	c = getContainersFromService("unless_stopped")[0]
-	assert.Assert(t, in(c.RunArgs, "--restart=unless-stopped"))
+	assert.Equal(t, c.CreateOptions.Restart, "unless-stopped")
+}
+
+func TestParseHealthcheck(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: nginx:alpine
+    healthcheck:
+      test: ["CMD", "curl", "-f", "http://localhost"]
+      interval: 1m30s
+      timeout: 10s
+      retries: 3
+      start_period: 40s
+      start_interval: 5s
+  bar:
+    image: nginx:alpine
+    healthcheck:
+      test: curl -f http://localhost
+  baz:
+    image: nginx:alpine
+    healthcheck:
+      disable: true
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := projectloader.Load(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+
+	foo, err := Parse(project, fooSvc)
+	assert.NilError(t, err)
+
+	t.Logf("foo: %+v", foo)
+	for _, c := range foo.Containers {
+		assert.Equal(t, c.CreateOptions.HealthCmd, `["CMD","curl","-f","http://localhost"]`)
+		assert.Equal(t, c.CreateOptions.HealthInterval, 90*time.Second)
+		assert.Equal(t, c.CreateOptions.HealthTimeout, 10*time.Second)
+		assert.Equal(t, c.CreateOptions.HealthRetries, 3)
+		assert.Equal(t, c.CreateOptions.HealthStartPeriod, 40*time.Second)
+		assert.Equal(t, c.CreateOptions.HealthStartInterval, 5*time.Second)
+	}
+
+	barSvc, err := project.GetService("bar")
+	assert.NilError(t, err)
+
+	bar, err := Parse(project, barSvc)
+	assert.NilError(t, err)
+
+	t.Logf("bar: %+v", bar)
+	for _, c := range bar.Containers {
+		assert.Equal(t, c.CreateOptions.HealthCmd, `["CMD-SHELL","curl -f http://localhost"]`)
+	}
+
+	bazSvc, err := project.GetService("baz")
+	assert.NilError(t, err)
+
+	baz, err := Parse(project, bazSvc)
+	assert.NilError(t, err)
+
+	t.Logf("baz: %+v", baz)
+	for _, c := range baz.Containers {
+		assert.Assert(t, c.CreateOptions.NoHealthcheck)
+	}
+}
+
+func TestConfigHash(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: nginx:alpine
+    build: .
+    deploy:
+      replicas: 2
+  bar:
+    image: nginx:alpine
+    environment:
+      - FOO=1
+  baz:
+    image: nginx:alpine
+    depends_on:
+      bar:
+        condition: service_started
+    develop:
+      watch:
+        - action: sync
+          path: ./src
+          target: /src
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := projectloader.Load(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+	fooHash, err := ConfigHash(fooSvc, "sha256:aaa")
+	assert.NilError(t, err)
+
+	// the build config and the number of the replicas are not hashed
+	scaled := fooSvc
+	scaled.Build = nil
+	scaled.Deploy = &types.DeployConfig{Replicas: new(int)}
+	scaledHash, err := ConfigHash(scaled, "sha256:aaa")
+	assert.NilError(t, err)
+	assert.Equal(t, scaledHash, fooHash)
+	assert.Equal(t, *fooSvc.Deploy.Replicas, 2)
+
+	// the image digest is hashed
+	updatedHash, err := ConfigHash(fooSvc, "sha256:bbb")
+	assert.NilError(t, err)
+	assert.Assert(t, updatedHash != fooHash)
+
+	barSvc, err := project.GetService("bar")
+	assert.NilError(t, err)
+	barHash, err := ConfigHash(barSvc, "sha256:aaa")
+	assert.NilError(t, err)
+	barSvc.Environment["FOO"] = nil
+	changedHash, err := ConfigHash(barSvc, "sha256:aaa")
+	assert.NilError(t, err)
+	assert.Assert(t, changedHash != barHash)
+
+	// the dependencies, the profiles and the develop config are not hashed
+	bazSvc, err := project.GetService("baz")
+	assert.NilError(t, err)
+	bazHash, err := ConfigHash(bazSvc, "sha256:aaa")
+	assert.NilError(t, err)
+	bazSvc.DependsOn = types.DependsOnConfig{"bar": {Condition: types.ServiceConditionHealthy, Required: true}}
+	bazSvc.Profiles = []string{"debug"}
+	bazSvc.Develop = &types.DevelopConfig{Watch: []types.Trigger{{Path: "./src", Action: types.WatchActionRebuild}}}
+	changedHash, err = ConfigHash(bazSvc, "sha256:aaa")
+	assert.NilError(t, err)
+	assert.Equal(t, changedHash, bazHash)
 }
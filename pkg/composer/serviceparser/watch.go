@@ -0,0 +1,121 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package serviceparser
+
+import (
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/reflectutil"
+	"github.com/moby/patternmatcher"
+)
+
+// WatchTrigger is a rule of `develop.watch`.
+type WatchTrigger struct {
+	Path   string // absolute path on the host
+	Action types.WatchAction
+	Target string   // absolute path in the container, empty for the rebuild action
+	Ignore []string // patterns relative to Path, in the syntax of .dockerignore
+}
+
+func parseWatchTriggers(c *types.DevelopConfig, project *types.Project) ([]WatchTrigger, error) {
+	if unknown := reflectutil.UnknownNonEmptyFields(c, "Watch"); len(unknown) > 0 {
+		log.L.Warnf("Ignoring: develop: %+v", unknown)
+	}
+	var triggers []WatchTrigger
+	for i, w := range c.Watch {
+		if unknown := reflectutil.UnknownNonEmptyFields(&w, "Path", "Action", "Target", "Ignore"); len(unknown) > 0 {
+			log.L.Warnf("Ignoring: develop.watch[%d]: %+v", i, unknown)
+		}
+		if w.Path == "" {
+			return nil, fmt.Errorf("develop.watch[%d]: path must be specified", i)
+		}
+		t := WatchTrigger{
+			Path:   project.RelativePath(w.Path),
+			Action: w.Action,
+		}
+		switch w.Action {
+		case types.WatchActionSync, types.WatchActionSyncRestart:
+			if w.Target == "" {
+				return nil, fmt.Errorf("develop.watch[%d]: target must be specified for action %q", i, w.Action)
+			}
+			if !path.IsAbs(w.Target) {
+				return nil, fmt.Errorf("develop.watch[%d]: target %q must be an absolute path", i, w.Target)
+			}
+			t.Target = path.Clean(w.Target)
+		case types.WatchActionRebuild:
+			// NOP
+		case "":
+			return nil, fmt.Errorf("develop.watch[%d]: action must be specified", i)
+		default:
+			return nil, fmt.Errorf("develop.watch[%d]: unknown action %q", i, w.Action)
+		}
+		for _, pattern := range w.Ignore {
+			pattern = strings.TrimSuffix(filepath.Clean(pattern), string(filepath.Separator))
+			if _, err := patternmatcher.New([]string{pattern}); err != nil {
+				return nil, fmt.Errorf("develop.watch[%d]: invalid ignore pattern %q: %w", i, pattern, err)
+			}
+			t.Ignore = append(t.Ignore, pattern)
+		}
+		triggers = append(triggers, t)
+	}
+	return triggers, nil
+}
+
+// Match returns the path relative to t.Path, if the host path is watched by the trigger.
+// A path is not watched if it matches the ignore patterns, with the semantics of .dockerignore:
+// a pattern matching a directory matches its contents, `**` matches any number of directories,
+// and a pattern prefixed with `!` re-includes the paths matched by the previous patterns.
+func (t *WatchTrigger) Match(hostPath string) (string, bool) {
+	rel, err := filepath.Rel(t.Path, hostPath)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	if rel == "." || len(t.Ignore) == 0 {
+		return rel, true
+	}
+	// The matcher is created for each call, as it is not safe for concurrent use.
+	// The error is negligible, as the patterns have been validated.
+	pm, err := patternmatcher.New(t.Ignore)
+	if err != nil {
+		return rel, true
+	}
+	var (
+		ignored bool
+		info    patternmatcher.MatchInfo
+	)
+	elems := strings.Split(rel, string(filepath.Separator))
+	for i := range elems {
+		ignored, info, err = pm.MatchesUsingParentResults(filepath.Join(elems[:i+1]...), info)
+		if err != nil {
+			return rel, true
+		}
+	}
+	if ignored {
+		return "", false
+	}
+	return rel, true
+}
+
+// ContainerPath returns the path in the container to sync the path relative to t.Path.
+func (t *WatchTrigger) ContainerPath(rel string) string {
+	return path.Join(t.Target, filepath.ToSlash(rel))
+}
@@ -0,0 +1,120 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package serviceparser
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/composer/projectloader"
+	"github.com/containerd/nerdctl/v2/pkg/testutil"
+)
+
+func TestParseWatch(t *testing.T) {
+	t.Parallel()
+	const dockerComposeYAML = `
+services:
+  foo:
+    image: nginx:alpine
+    build: .
+    develop:
+      watch:
+        - path: ./src
+          action: sync
+          target: /app/src
+          ignore:
+            - node_modules/
+            - "*.tmp"
+        - path: package.json
+          action: rebuild
+        - path: ./conf
+          action: sync+restart
+          target: /etc/nginx/conf.d
+`
+	comp := testutil.NewComposeDir(t, dockerComposeYAML)
+	defer comp.CleanUp()
+
+	project, err := projectloader.Load(comp.YAMLFullPath(), comp.ProjectName(), nil)
+	assert.NilError(t, err)
+
+	fooSvc, err := project.GetService("foo")
+	assert.NilError(t, err)
+
+	foo, err := Parse(project, fooSvc)
+	assert.NilError(t, err)
+
+	t.Logf("foo: %+v", foo)
+	assert.Equal(t, len(foo.Watch), 3)
+	sync := foo.Watch[0]
+	assert.Equal(t, sync.Path, filepath.Join(comp.Dir(), "src"))
+	assert.Equal(t, sync.Action, types.WatchActionSync)
+	assert.Equal(t, sync.Target, "/app/src")
+	assert.DeepEqual(t, sync.Ignore, []string{"node_modules", "*.tmp"})
+	assert.Equal(t, foo.Watch[1].Path, filepath.Join(comp.Dir(), "package.json"))
+	assert.Equal(t, foo.Watch[1].Action, types.WatchActionRebuild)
+	assert.Equal(t, foo.Watch[2].Action, types.WatchActionSyncRestart)
+
+	_, err = parseWatchTriggers(&types.DevelopConfig{
+		Watch: []types.Trigger{{Path: "./src", Action: types.WatchActionSync, Target: "app"}},
+	}, project)
+	assert.ErrorContains(t, err, "must be an absolute path")
+
+	_, err = parseWatchTriggers(&types.DevelopConfig{
+		Watch: []types.Trigger{{Path: "./src", Action: "unknown"}},
+	}, project)
+	assert.ErrorContains(t, err, "unknown action")
+}
+
+func TestWatchTriggerMatch(t *testing.T) {
+	t.Parallel()
+	trigger := WatchTrigger{
+		Path:   "/src",
+		Action: types.WatchActionSync,
+		Target: "/app",
+		Ignore: []string{"node_modules", "*.tmp", "**/*.log", "docs", "!docs/README.md"},
+	}
+
+	testCases := []struct {
+		hostPath string
+		rel      string
+		matched  bool
+	}{
+		{"/src", ".", true},
+		{"/src/main.go", "main.go", true},
+		{"/src/pkg/util.go", "pkg/util.go", true},
+		{"/src/foo.tmp", "", false},
+		{"/src/pkg/foo.tmp", "pkg/foo.tmp", true},
+		{"/src/node_modules/foo/index.js", "", false},
+		{"/src/app.log", "", false},
+		{"/src/pkg/sub/app.log", "", false},
+		{"/src/docs/index.md", "", false},
+		{"/src/docs/README.md", "docs/README.md", true},
+		{"/srcfoo/main.go", "", false},
+		{"/other/main.go", "", false},
+	}
+	for _, tc := range testCases {
+		rel, matched := trigger.Match(filepath.FromSlash(tc.hostPath))
+		assert.Equal(t, matched, tc.matched, tc.hostPath)
+		assert.Equal(t, rel, filepath.FromSlash(tc.rel), tc.hostPath)
+	}
+
+	assert.Equal(t, trigger.ContainerPath(filepath.FromSlash("pkg/util.go")), "/app/pkg/util.go")
+	assert.Equal(t, trigger.ContainerPath("."), "/app")
+}
@@ -18,11 +18,14 @@ package composer
 
 import (
 	"context"
-	"fmt"
+	"io"
 	"sync"
+	"time"
 
 	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
@@ -33,8 +36,8 @@ type StopOptions struct {
 	Timeout *uint
 }
 
-// Stop stops containers in `services` without removing them. It calls
-// `nerdctl stop CONTAINER_ID` to do the actual job.
+// Stop stops containers in `services` without removing them, in the same way as
+// `nerdctl stop CONTAINER_ID`.
 func (c *Composer) Stop(ctx context.Context, opt StopOptions, services []string) error {
 	serviceNames, err := c.ServiceNames(services...)
 	if err != nil {
@@ -54,10 +57,10 @@ func (c *Composer) Stop(ctx context.Context, opt StopOptions, services []string)
 }
 
 func (c *Composer) stopContainers(ctx context.Context, containers []containerd.Container, opt StopOptions) error {
-	var timeoutArg string
+	var timeout *time.Duration
 	if opt.Timeout != nil {
-		// `nerdctl stop` uses `--time` instead of `--timeout`
-		timeoutArg = fmt.Sprintf("--time=%d", *opt.Timeout)
+		t := time.Duration(*opt.Timeout) * time.Second
+		timeout = &t
 	}
 
 	var rmWG sync.WaitGroup
@@ -68,12 +71,7 @@ func (c *Composer) stopContainers(ctx context.Context, containers []containerd.C
 			defer rmWG.Done()
 			info, _ := container.Info(ctx, containerd.WithoutRefreshedMetadata)
 			log.G(ctx).Infof("Stopping container %s", info.Labels[labels.Name])
-			args := []string{"stop"}
-			if opt.Timeout != nil {
-				args = append(args, timeoutArg)
-			}
-			args = append(args, container.ID())
-			if err := c.runNerdctlCmd(ctx, args...); err != nil {
+			if err := c.stopContainer(ctx, container.ID(), timeout); err != nil {
 				log.G(ctx).Warn(err)
 			}
 		}()
@@ -92,10 +90,20 @@ func (c *Composer) stopContainersFromParsedServices(ctx context.Context, contain
 		go func() {
 			defer rmWG.Done()
 			log.G(ctx).Infof("Stopping container %s", container.Name)
-			if err := c.runNerdctlCmd(ctx, "stop", id); err != nil {
+			if err := c.stopContainer(ctx, id, nil); err != nil {
 				log.G(ctx).Warn(err)
 			}
 		}()
 	}
 	rmWG.Wait()
 }
+
+// stopContainer stops the container, in the same way as `nerdctl stop`.
+func (c *Composer) stopContainer(ctx context.Context, id string, timeout *time.Duration) error {
+	return container.Stop(ctx, c.client, []string{id}, types.ContainerStopOptions{
+		Stdout:   io.Discard,
+		Stderr:   io.Discard,
+		GOptions: c.GOptions,
+		Timeout:  timeout,
+	})
+}
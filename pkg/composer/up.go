@@ -20,6 +20,8 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"slices"
+	"time"
 
 	"github.com/compose-spec/compose-go/v2/types"
 	"github.com/containerd/log"
@@ -38,6 +40,32 @@ type UpOptions struct {
 	QuietPull            bool
 	RemoveOrphans        bool
 	Scale                map[string]int // map of service name to replicas
+	ForceRecreate        bool
+	NoRecreate           bool
+	NoDeps               bool
+	AlwaysRecreateDeps   bool
+	Wait                 bool
+	WaitTimeout          time.Duration // zero for no timeout
+}
+
+// recreateStrategy returns the recreate strategy for the service.
+// The dependencies that are not specified in `services` are not affected by ForceRecreate and NoRecreate.
+// FYI: https://github.com/docker/compose/blob/v2.29.1/pkg/compose/convergence.go#L103-L107
+func (uo UpOptions) recreateStrategy(service string, services []string) string {
+	if len(services) > 0 && !slices.Contains(services, service) {
+		if uo.AlwaysRecreateDeps {
+			return RecreateForce
+		}
+		return RecreateDiverged
+	}
+	switch {
+	case uo.ForceRecreate:
+		return RecreateForce
+	case uo.NoRecreate:
+		return RecreateNever
+	default:
+		return RecreateDiverged
+	}
 }
 
 func (c *Composer) Up(ctx context.Context, uo UpOptions, services []string) error {
@@ -67,6 +95,11 @@ func (c *Composer) Up(ctx context.Context, uo UpOptions, services []string) erro
 		}
 	}
 
+	var dependencyOpts []types.DependencyOption
+	if uo.NoDeps {
+		dependencyOpts = append(dependencyOpts, types.IgnoreDependencies)
+	}
+
 	var parsedServices []*serviceparser.Service
 	// use WithServices to sort the services in dependency order
 	if err := c.project.ForEachService(services, func(name string, svc *types.ServiceConfig) error {
@@ -82,7 +115,7 @@ func (c *Composer) Up(ctx context.Context, uo UpOptions, services []string) erro
 		}
 		parsedServices = append(parsedServices, ps)
 		return nil
-	}); err != nil {
+	}, dependencyOpts...); err != nil {
 		return err
 	}
 
@@ -102,20 +135,32 @@ func (c *Composer) Up(ctx context.Context, uo UpOptions, services []string) erro
 		}
 	}
 
-	return c.upServices(ctx, parsedServices, uo)
+	return c.upServices(ctx, parsedServices, uo, services)
 }
 
 func validateFileObjectConfig(obj types.FileObjectConfig, shortName, objType string, project *types.Project) error {
-	if unknown := reflectutil.UnknownNonEmptyFields(&obj, "Name", "External", "File"); len(unknown) > 0 {
+	if unknown := reflectutil.UnknownNonEmptyFields(&obj, "Name", "External", "File", "Environment", "Content"); len(unknown) > 0 {
 		log.L.Warnf("Ignoring: %s %s: %+v", objType, shortName, unknown)
 	}
 
-	if obj.File == "" {
-		return fmt.Errorf("%s %q: lacks file path", objType, shortName)
-	}
-	fullPath := project.RelativePath(obj.File)
-	if _, err := os.Stat(fullPath); err != nil {
-		return fmt.Errorf("%s %q: failed to open file %q: %w", objType, shortName, fullPath, err)
+	switch {
+	case obj.File != "":
+		if obj.Environment != "" || obj.Content != "" {
+			return fmt.Errorf("%s %q: file, environment, and content are mutually exclusive", objType, shortName)
+		}
+		fullPath := project.RelativePath(obj.File)
+		if _, err := os.Stat(fullPath); err != nil {
+			return fmt.Errorf("%s %q: failed to open file %q: %w", objType, shortName, fullPath, err)
+		}
+	case obj.Environment != "":
+		if obj.Content != "" {
+			return fmt.Errorf("%s %q: file, environment, and content are mutually exclusive", objType, shortName)
+		}
+		if _, ok := project.Environment[obj.Environment]; !ok {
+			return fmt.Errorf("%s %q: environment variable %q is not set", objType, shortName, obj.Environment)
+		}
+	case obj.Content == "":
+		return fmt.Errorf("%s %q: lacks file path, environment, or content", objType, shortName)
 	}
 	return nil
 }
@@ -19,9 +19,13 @@ package composer
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/network"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/netutil"
 	"github.com/containerd/nerdctl/v2/pkg/reflectutil"
 )
 
@@ -47,19 +51,21 @@ func (c *Composer) upNetwork(ctx context.Context, shortName string) error {
 	} else if !netExists {
 		log.G(ctx).Infof("Creating network %s", fullName)
 		//add metadata labels to network https://github.com/compose-spec/compose-spec/blob/master/spec.md#labels-1
-		createArgs := []string{
-			fmt.Sprintf("--label=%s=%s", labels.ComposeProject, c.project.Name),
-			fmt.Sprintf("--label=%s=%s", labels.ComposeNetwork, shortName),
+		createOpts := types.NetworkCreateOptions{
+			GOptions: c.GOptions,
+			Name:     fullName,
+			// The default network driver has the same name as the default network ("bridge", or "nat" on Windows)
+			Driver:     netutil.DefaultNetworkName,
+			Options:    net.DriverOpts,
+			IPAMDriver: "default",
+			Labels: []string{
+				fmt.Sprintf("%s=%s", labels.ComposeProject, c.project.Name),
+				fmt.Sprintf("%s=%s", labels.ComposeNetwork, shortName),
+			},
 		}
 
 		if net.Driver != "" {
-			createArgs = append(createArgs, fmt.Sprintf("--driver=%s", net.Driver))
-		}
-
-		if net.DriverOpts != nil {
-			for k, v := range net.DriverOpts {
-				createArgs = append(createArgs, fmt.Sprintf("--opt=%s=%s", k, v))
-			}
+			createOpts.Driver = net.Driver
 		}
 
 		if net.Ipam.Config != nil {
@@ -72,23 +78,17 @@ func (c *Composer) upNetwork(ctx context.Context, shortName string) error {
 				log.G(ctx).Warnf("Ignoring: network %s: ipam.config[0]: %+v", shortName, unknown)
 			}
 			if ipamConfig.Subnet != "" {
-				createArgs = append(createArgs, fmt.Sprintf("--subnet=%s", ipamConfig.Subnet))
-			}
-			if ipamConfig.Gateway != "" {
-				createArgs = append(createArgs, fmt.Sprintf("--gateway=%s", ipamConfig.Gateway))
-			}
-			if ipamConfig.IPRange != "" {
-				createArgs = append(createArgs, fmt.Sprintf("--ip-range=%s", ipamConfig.IPRange))
+				createOpts.Subnets = []string{ipamConfig.Subnet}
 			}
+			createOpts.Gateway = ipamConfig.Gateway
+			createOpts.IPRange = ipamConfig.IPRange
 		}
 
-		createArgs = append(createArgs, fullName)
-
 		if c.DebugPrintFull {
-			log.G(ctx).Debugf("Creating network args: %s", createArgs)
+			log.G(ctx).Debugf("Creating network options: %+v", createOpts)
 		}
 
-		if err := c.runNerdctlCmd(ctx, append([]string{"network", "create"}, createArgs...)...); err != nil {
+		if err := network.Create(createOpts, io.Discard); err != nil {
 			return err
 		}
 	}
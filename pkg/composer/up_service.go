@@ -21,18 +21,28 @@ import (
 	"errors"
 	"fmt"
 	"os"
-	"path/filepath"
-	"strings"
 	"sync"
+	"time"
 
+	"github.com/compose-spec/compose-go/v2/types"
 	"golang.org/x/sync/errgroup"
 
+	"github.com/containerd/console"
+	containerd "github.com/containerd/containerd/v2/client"
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/container"
 	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+	"github.com/containerd/nerdctl/v2/pkg/consoleutil"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+	"github.com/containerd/nerdctl/v2/pkg/errutil"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/taskutil"
 )
 
-func (c *Composer) upServices(ctx context.Context, parsedServices []*serviceparser.Service, uo UpOptions) error {
+// upServices creates and starts the containers of parsedServices.
+// targets are the services specified in the command line, or empty for all the services.
+func (c *Composer) upServices(ctx context.Context, parsedServices []*serviceparser.Service, uo UpOptions, targets []string) error {
 	if len(parsedServices) == 0 {
 		return errors.New("no service was provided")
 	}
@@ -45,30 +55,56 @@ func (c *Composer) upServices(ctx context.Context, parsedServices []*servicepars
 	}
 
 	var (
-		containers   = make(map[string]serviceparser.Container) // key: container ID
-		services     = []string{}
-		containersMu sync.Mutex
+		containers      = make(map[string]serviceparser.Container) // key: container ID
+		services        = []string{}
+		selected        = make(map[string]bool)
+		createdServices = make(map[string]bool) // services whose containers were (re)created
+		containersMu    sync.Mutex
 	)
 	for _, ps := range parsedServices {
-		ps := ps
-		var runEG errgroup.Group
 		services = append(services, ps.Unparsed.Name)
+		selected[ps.Unparsed.Name] = true
+	}
+	if err := forEachServiceInParallel(ctx, parsedServices, func(ctx context.Context, ps *serviceparser.Service) error {
+		if err := c.waitServiceDependencies(ctx, ps, selected); err != nil {
+			return err
+		}
+		configHash, err := c.serviceConfigHash(ctx, ps)
+		if err != nil {
+			return err
+		}
+		recreate := uo.recreateStrategy(ps.Unparsed.Name, targets)
+		// `restart: true` restarts the service when the dependency has been updated
+		var restart bool
+		containersMu.Lock()
+		for depName, dep := range ps.Unparsed.DependsOn {
+			restart = restart || (dep.Restart && createdServices[depName])
+		}
+		containersMu.Unlock()
+		var runEG errgroup.Group
 		for _, container := range ps.Containers {
 			container := container
 			runEG.Go(func() error {
-				id, err := c.upServiceContainer(ctx, ps, container)
+				id, created, err := c.upServiceContainer(ctx, ps, container, recreate, configHash, restart)
 				if err != nil {
 					return err
 				}
 				containersMu.Lock()
 				containers[id] = container
+				if created {
+					createdServices[ps.Unparsed.Name] = true
+				}
 				containersMu.Unlock()
 				return nil
 			})
 		}
-		if err := runEG.Wait(); err != nil {
-			return err
-		}
+		return runEG.Wait()
+	}); err != nil {
+		return err
+	}
+
+	if uo.Wait {
+		return c.waitServices(ctx, parsedServices, uo.WaitTimeout)
 	}
 
 	if uo.Detach {
@@ -96,6 +132,245 @@ func (c *Composer) upServices(ctx context.Context, parsedServices []*servicepars
 	return nil
 }
 
+// waitServices waits for the containers of the services to be running, or healthy if they have healthchecks.
+// The containers of the services that other services depend on with `service_completed_successfully` may exit successfully.
+// The services that other services only depend on with `required: false` may fail, as Docker Compose skips them.
+// timeout is not applied if it is zero.
+func (c *Composer) waitServices(ctx context.Context, parsedServices []*serviceparser.Service, timeout time.Duration) error {
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	completable := make(map[string]bool)
+	required := make(map[string]bool)
+	optional := make(map[string]bool)
+	for _, ps := range parsedServices {
+		for depName, dep := range ps.Unparsed.DependsOn {
+			if dep.Condition == types.ServiceConditionCompletedSuccessfully {
+				completable[depName] = true
+			}
+			if dep.Required {
+				required[depName] = true
+			} else {
+				optional[depName] = true
+			}
+		}
+	}
+	eg, egCtx := errgroup.WithContext(ctx)
+	for _, ps := range parsedServices {
+		containers, err := c.Containers(ctx, ps.Unparsed.Name)
+		if err != nil {
+			return err
+		}
+		for _, container := range containers {
+			container := container
+			mayComplete := completable[ps.Unparsed.Name]
+			mayFail := optional[ps.Unparsed.Name] && !required[ps.Unparsed.Name]
+			eg.Go(func() error {
+				err := waitContainerReady(egCtx, container, mayComplete)
+				if err != nil && mayFail && egCtx.Err() == nil {
+					log.G(ctx).WithError(err).Warnf("skipping the optional dependency %q", ps.Unparsed.Name)
+					return nil
+				}
+				return err
+			})
+		}
+	}
+	if err := eg.Wait(); err != nil {
+		if errors.Is(err, context.DeadlineExceeded) {
+			return fmt.Errorf("timeout waiting for the services to be running or healthy: %w", err)
+		}
+		return err
+	}
+	log.G(ctx).Info("All the services are running or healthy")
+	return nil
+}
+
+// waitContainerReady waits for the container to be running, or healthy if it has a healthcheck.
+// The container may exit with zero if mayComplete is true.
+func waitContainerReady(ctx context.Context, container containerd.Container, mayComplete bool) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		status, err := containerutil.ContainerStatus(ctx, container)
+		if err != nil {
+			return err
+		}
+		if status.Status == containerd.Stopped {
+			if mayComplete && status.ExitStatus == 0 {
+				return nil
+			}
+			return fmt.Errorf("container %s exited (%d)", container.ID(), status.ExitStatus)
+		}
+		lab, err := container.Labels(ctx)
+		if err != nil {
+			return err
+		}
+		health, err := healthcheck.Status(lab)
+		if err != nil {
+			return err
+		}
+		switch health {
+		case healthcheck.Healthy:
+			return nil
+		case healthcheck.Unhealthy:
+			return fmt.Errorf("container %s is unhealthy", container.ID())
+		case healthcheck.NoHealthcheck:
+			if status.Status == containerd.Running {
+				return nil
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// forEachServiceInParallel calls fn for the services in parallel.
+// fn is called for a service after it has returned for all the dependencies of the service,
+// so that the services are started in the dependency order.
+// The dependencies that are not in parsedServices are not waited for.
+func forEachServiceInParallel(ctx context.Context, parsedServices []*serviceparser.Service, fn func(ctx context.Context, ps *serviceparser.Service) error) error {
+	done := make(map[string]chan struct{}, len(parsedServices)) // key: service name
+	for _, ps := range parsedServices {
+		done[ps.Unparsed.Name] = make(chan struct{})
+	}
+	eg, ctx := errgroup.WithContext(ctx)
+	for _, ps := range parsedServices {
+		ps := ps
+		eg.Go(func() error {
+			for depName := range ps.Unparsed.DependsOn {
+				depDone, ok := done[depName]
+				if !ok {
+					continue
+				}
+				select {
+				case <-depDone:
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			if err := fn(ctx, ps); err != nil {
+				return err
+			}
+			close(done[ps.Unparsed.Name])
+			return nil
+		})
+	}
+	return eg.Wait()
+}
+
+// waitServiceDependencies waits for the dependencies of the service to satisfy their `depends_on` conditions.
+// The dependencies that are not in selected (e.g., with `--no-deps`) are skipped, as in forEachServiceInParallel.
+// The failures of the dependencies with `required: false` are only warned.
+func (c *Composer) waitServiceDependencies(ctx context.Context, ps *serviceparser.Service, selected map[string]bool) error {
+	for depName, dep := range ps.Unparsed.DependsOn {
+		if !selected[depName] {
+			continue
+		}
+		if err := c.waitServiceCondition(ctx, depName, dep.Condition); err != nil {
+			if !dep.Required {
+				log.G(ctx).WithError(err).Warnf("Ignoring: service %s: optional dependency %q failed", ps.Unparsed.Name, depName)
+				continue
+			}
+			return fmt.Errorf("dependency %q of service %q failed: %w", depName, ps.Unparsed.Name, err)
+		}
+	}
+	return nil
+}
+
+// waitServiceCondition waits for the containers of the service to satisfy the `depends_on` condition.
+func (c *Composer) waitServiceCondition(ctx context.Context, service, condition string) error {
+	var (
+		wait    func(context.Context, containerd.Container) error
+		waitFor string
+	)
+	switch condition {
+	case types.ServiceConditionHealthy:
+		wait, waitFor = waitContainerHealthy, "healthy"
+	case types.ServiceConditionCompletedSuccessfully:
+		wait, waitFor = waitContainerCompleted, "complete"
+	default:
+		return nil
+	}
+	containers, err := c.Containers(ctx, service)
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 {
+		return fmt.Errorf("service %q has no container", service)
+	}
+	for _, container := range containers {
+		log.G(ctx).Infof("Waiting for container %s to be %s", container.ID(), waitFor)
+		if err := wait(ctx, container); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitContainerHealthy waits for the container to become healthy.
+func waitContainerHealthy(ctx context.Context, container containerd.Container) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		lab, err := container.Labels(ctx)
+		if err != nil {
+			return err
+		}
+		health, err := healthcheck.Status(lab)
+		if err != nil {
+			return err
+		}
+		switch health {
+		case healthcheck.Healthy:
+			return nil
+		case healthcheck.Unhealthy:
+			return fmt.Errorf("container %s is unhealthy", container.ID())
+		case healthcheck.NoHealthcheck:
+			return fmt.Errorf("container %s has no healthcheck configured", container.ID())
+		}
+		status, err := containerutil.ContainerStatus(ctx, container)
+		if err != nil {
+			return err
+		}
+		if status.Status == containerd.Stopped {
+			return fmt.Errorf("container %s exited (%d)", container.ID(), status.ExitStatus)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// waitContainerCompleted waits for the container to exit successfully.
+func waitContainerCompleted(ctx context.Context, container containerd.Container) error {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		status, err := containerutil.ContainerStatus(ctx, container)
+		if err != nil {
+			return err
+		}
+		if status.Status == containerd.Stopped {
+			if status.ExitStatus != 0 {
+				return fmt.Errorf("container %s exited (%d)", container.ID(), status.ExitStatus)
+			}
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
 func (c *Composer) ensureServiceImage(ctx context.Context, ps *serviceparser.Service, allowBuild, forceBuild bool, bo BuildOptions, quiet bool) error {
 	if ps.Build != nil && allowBuild {
 		if ps.Build.Force || forceBuild {
@@ -116,20 +391,28 @@ func (c *Composer) ensureServiceImage(ctx context.Context, ps *serviceparser.Ser
 }
 
 // upServiceContainer must be called after ensureServiceImage
-// upServiceContainer returns container ID
-func (c *Composer) upServiceContainer(ctx context.Context, service *serviceparser.Service, container serviceparser.Container) (string, error) {
+// upServiceContainer returns container ID, and whether the container was (re)created.
+// The existing container is recreated if needed with the recreate strategy, or just started.
+// The existing container is restarted if restart is true, even if it is running.
+func (c *Composer) upServiceContainer(ctx context.Context, service *serviceparser.Service, container serviceparser.Container, recreate, configHash string, restart bool) (string, bool, error) {
 	// check if container already exists
-	exists, err := c.containerExists(ctx, container.Name, service.Unparsed.Name)
+	existing, err := c.findContainer(ctx, container.Name, service.Unparsed.Name)
 	if err != nil {
-		return "", fmt.Errorf("error while checking for containers with name %q: %s", container.Name, err)
+		return "", false, fmt.Errorf("error while checking for containers with name %q: %s", container.Name, err)
 	}
 
-	// delete container if it already exists
-	if exists {
+	// delete container if it already exists and needs to be recreated
+	if existing != nil {
+		recreateNeeded, err := needsRecreate(ctx, existing, recreate, configHash)
+		if err != nil {
+			return "", false, err
+		}
+		if !recreateNeeded {
+			return existing.ID(), false, c.startExistingContainer(ctx, existing, container.Name, restart)
+		}
 		log.G(ctx).Debugf("Container %q already exists, deleting", container.Name)
-		delCmd := c.createNerdctlCmd(ctx, "rm", "-f", container.Name)
-		if err = delCmd.Run(); err != nil {
-			return "", fmt.Errorf("could not delete container %q: %s", container.Name, err)
+		if err = c.removeContainerForcibly(ctx, container.Name, false); err != nil {
+			return "", false, fmt.Errorf("could not delete container %q: %s", container.Name, err)
 		}
 		log.G(ctx).Infof("Re-creating container %s", container.Name)
 	} else {
@@ -139,57 +422,93 @@ func (c *Composer) upServiceContainer(ctx context.Context, service *serviceparse
 	for _, f := range container.Mkdir {
 		log.G(ctx).Debugf("Creating a directory %q", f)
 		if err = os.MkdirAll(f, 0o755); err != nil {
-			return "", fmt.Errorf("failed to create a directory %q: %w", f, err)
+			return "", false, fmt.Errorf("failed to create a directory %q: %w", f, err)
 		}
 	}
 
-	tempDir, err := os.MkdirTemp(os.TempDir(), "compose-")
-	if err != nil {
-		return "", fmt.Errorf("error while creating/re-creating container %s: %w", container.Name, err)
+	// FIXME
+	if service.Unparsed.StdinOpen != service.Unparsed.Tty {
+		return "", false, fmt.Errorf("currently StdinOpen(-i) and Tty(-t) should be same")
 	}
-	defer os.RemoveAll(tempDir)
-	cidFilename := filepath.Join(tempDir, "cid")
 
-	var runFlagD bool
-	if !service.Unparsed.StdinOpen && !service.Unparsed.Tty {
-		container.RunArgs = append([]string{"-d"}, container.RunArgs...)
-		runFlagD = true
+	ctr, err := c.createContainer(ctx, service, container, configHash, true)
+	if err != nil {
+		return "", false, fmt.Errorf("error while creating container %s: %w", container.Name, err)
 	}
 
-	//add metadata labels to container https://github.com/compose-spec/compose-spec/blob/master/spec.md#labels
-	container.RunArgs = append([]string{
-		"--cidfile=" + cidFilename,
-		fmt.Sprintf("-l=%s=%s", labels.ComposeProject, c.project.Name),
-		fmt.Sprintf("-l=%s=%s", labels.ComposeService, service.Unparsed.Name),
-	}, container.RunArgs...)
-
-	cmd := c.createNerdctlCmd(ctx, append([]string{"run"}, container.RunArgs...)...)
-	if c.DebugPrintFull {
-		log.G(ctx).Debugf("Running %v", cmd.Args)
+	if service.Unparsed.StdinOpen {
+		// like `nerdctl run -it`, blocks until the container exits
+		if err := c.attachContainer(ctx, ctr); err != nil {
+			return "", false, fmt.Errorf("error while running container %s: %w", container.Name, err)
+		}
+		return ctr.ID(), true, nil
 	}
-
-	// FIXME
-	if service.Unparsed.StdinOpen != service.Unparsed.Tty {
-		return "", fmt.Errorf("currently StdinOpen(-i) and Tty(-t) should be same")
+	if err := c.startContainers(ctx, ctr.ID()); err != nil {
+		return "", false, fmt.Errorf("error while starting container %s: %w", container.Name, err)
 	}
+	return ctr.ID(), true, nil
+}
 
-	if service.Unparsed.StdinOpen {
-		cmd.Stdin = os.Stdin
+// startExistingContainer starts the existing container, unless it is already running.
+// The running container is restarted if restart is true.
+func (c *Composer) startExistingContainer(ctx context.Context, ctr containerd.Container, name string, restart bool) error {
+	status, err := containerutil.ContainerStatus(ctx, ctr)
+	if err == nil && status.Status == containerd.Running {
+		if restart {
+			return c.restartContainers(ctx, []containerd.Container{ctr}, RestartOptions{})
+		}
+		log.G(ctx).Infof("Container %s is up-to-date", name)
+		return nil
 	}
-	if !runFlagD {
-		cmd.Stdout = os.Stdout
+	log.G(ctx).Infof("Starting container %s", name)
+	if err := c.startContainers(ctx, ctr.ID()); err != nil {
+		return fmt.Errorf("error while starting container %s: %w", name, err)
 	}
-	// Always propagate stderr to print detailed error messages (https://github.com/containerd/nerdctl/issues/1942)
-	cmd.Stderr = os.Stderr
+	return nil
+}
 
-	err = cmd.Run()
+// attachContainer starts the container with the stdin and the terminal attached, and waits for the container to exit.
+func (c *Composer) attachContainer(ctx context.Context, ctr containerd.Container) error {
+	lab, err := ctr.Labels(ctx)
 	if err != nil {
-		return "", fmt.Errorf("error while creating container %s: %w", container.Name, err)
+		return err
 	}
-
-	cid, err := os.ReadFile(cidFilename)
+	con := console.Current()
+	defer con.Reset()
+	if err := con.SetRaw(); err != nil {
+		return err
+	}
+	detachC := make(chan struct{})
+	task, err := taskutil.NewTask(ctx, c.client, ctr, nil, true, true, false, con, lab[labels.LogURI], "", c.GOptions.Namespace, detachC)
+	if err != nil {
+		return err
+	}
+	if err := task.Start(ctx); err != nil {
+		return err
+	}
+	if err := container.StartHealthMonitor(ctx, ctr, c.GOptions); err != nil {
+		log.G(ctx).WithError(err).Warnf("failed to start the healthcheck monitor of container %q", ctr.ID())
+	}
+	if err := consoleutil.HandleConsoleResize(ctx, task, con); err != nil {
+		log.G(ctx).WithError(err).Error("console resize")
+	}
+	statusC, err := task.Wait(ctx)
 	if err != nil {
-		return "", fmt.Errorf("error while creating container %s: %w", container.Name, err)
+		return err
+	}
+	select {
+	case <-detachC:
+		if io := task.IO(); io != nil {
+			io.Wait()
+		}
+	case status := <-statusC:
+		code, _, err := status.Result()
+		if err != nil {
+			return err
+		}
+		if code != 0 {
+			return errutil.NewExitCoderErr(int(code))
+		}
 	}
-	return strings.TrimSpace(string(cid)), nil
+	return nil
 }
@@ -19,8 +19,11 @@ package composer
 import (
 	"context"
 	"fmt"
+	"io"
 
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
+	"github.com/containerd/nerdctl/v2/pkg/cmd/volume"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/nerdctl/v2/pkg/reflectutil"
 )
@@ -50,12 +53,14 @@ func (c *Composer) upVolume(ctx context.Context, shortName string) error {
 	} else if !volExists {
 		log.G(ctx).Infof("Creating volume %s", fullName)
 		//add metadata labels to volume https://github.com/compose-spec/compose-spec/blob/master/spec.md#labels-2
-		createArgs := []string{
-			fmt.Sprintf("--label=%s=%s", labels.ComposeProject, c.project.Name),
-			fmt.Sprintf("--label=%s=%s", labels.ComposeVolume, shortName),
-			fullName,
-		}
-		if err := c.runNerdctlCmd(ctx, append([]string{"volume", "create"}, createArgs...)...); err != nil {
+		if _, err := volume.Create(fullName, types.VolumeCreateOptions{
+			Stdout:   io.Discard,
+			GOptions: c.GOptions,
+			Labels: []string{
+				fmt.Sprintf("%s=%s", labels.ComposeProject, c.project.Name),
+				fmt.Sprintf("%s=%s", labels.ComposeVolume, shortName),
+			},
+		}); err != nil {
 			return err
 		}
 	}
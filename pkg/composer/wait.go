@@ -0,0 +1,115 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// WaitOptions has the options of `nerdctl compose wait`.
+type WaitOptions struct {
+	// DownProject runs `compose down` when the first container exits
+	DownProject bool
+}
+
+type waitResult struct {
+	name string
+	code uint32
+	err  error
+}
+
+// Wait blocks until the containers of the services exit, and prints their exit codes in the order of exit.
+// With DownProject, Wait returns after the first container exits and the project is down.
+func (c *Composer) Wait(ctx context.Context, writer io.Writer, services []string, wo WaitOptions) error {
+	serviceNames, err := c.ServiceNames(services...)
+	if err != nil {
+		return err
+	}
+	containers, err := c.Containers(ctx, serviceNames...)
+	if err != nil {
+		return err
+	}
+	if len(containers) == 0 {
+		return fmt.Errorf("no containers for services %v", serviceNames)
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	resultCh := make(chan waitResult, len(containers))
+	for _, container := range containers {
+		go func(container containerd.Container) {
+			resultCh <- waitContainerExit(ctx, container)
+		}(container)
+	}
+
+	var errs []error
+	for range containers {
+		res := <-resultCh
+		if res.err != nil {
+			errs = append(errs, fmt.Errorf("failed to wait for container %s: %w", res.name, res.err))
+			continue
+		}
+		if _, err := fmt.Fprintf(writer, "%s %d\n", res.name, res.code); err != nil {
+			return err
+		}
+		if wo.DownProject {
+			cancel()
+			log.G(ctx).Infof("Container %s exited, removing the project %s", res.name, c.project.Name)
+			return c.Down(context.WithoutCancel(ctx), DownOptions{})
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func waitContainerExit(ctx context.Context, container containerd.Container) waitResult {
+	res := waitResult{name: container.ID()}
+	info, err := container.Info(ctx, containerd.WithoutRefreshedMetadata)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	res.name = info.Labels[labels.Name]
+	task, err := container.Task(ctx, nil)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			// the container has been created but never started, Docker reports 0 as well
+			return res
+		}
+		res.err = err
+		return res
+	}
+	statusC, err := task.Wait(ctx)
+	if err != nil {
+		res.err = err
+		return res
+	}
+	select {
+	case status := <-statusC:
+		res.code, _, res.err = status.Result()
+	case <-ctx.Done():
+		res.err = ctx.Err()
+	}
+	return res
+}
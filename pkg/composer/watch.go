@@ -0,0 +1,267 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/compose-spec/compose-go/v2/types"
+	"github.com/fsnotify/fsnotify"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/composer/serviceparser"
+)
+
+// watchDebounceDuration is the duration to batch the file events, as editors often write a file several times.
+const watchDebounceDuration = 500 * time.Millisecond
+
+// WatchOptions stores all option input from `nerdctl compose watch`
+type WatchOptions struct {
+	NoUp  bool
+	Quiet bool
+}
+
+// Watch watches the paths of the `develop.watch` sections of the services, and updates the services on changes.
+// The changed files are copied into the running containers (`sync`), optionally followed by restarting the containers
+// (`sync+restart`), or the service image is rebuilt and the containers are recreated (`rebuild`).
+func (c *Composer) Watch(ctx context.Context, wo WatchOptions, services []string) error {
+	parsedServices, err := c.Services(ctx, services...)
+	if err != nil {
+		return err
+	}
+	var watched []*serviceparser.Service
+	for _, ps := range parsedServices {
+		if len(ps.Watch) > 0 {
+			watched = append(watched, ps)
+		}
+	}
+	if len(watched) == 0 {
+		return errors.New("none of the selected services is configured for watch, consider setting a 'develop' section")
+	}
+
+	if !wo.NoUp {
+		if err := c.Up(ctx, UpOptions{Detach: true, QuietPull: wo.Quiet}, services); err != nil {
+			return err
+		}
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	defer watcher.Close()
+	for _, ps := range watched {
+		for _, t := range ps.Watch {
+			if err := addWatchTrigger(watcher, t, t.Path); err != nil {
+				return err
+			}
+			log.G(ctx).Infof("Watching %s for service %s (%s)", t.Path, ps.Unparsed.Name, t.Action)
+		}
+	}
+
+	var (
+		pending  = make(map[string]struct{})
+		debounce <-chan time.Time
+	)
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			log.G(ctx).Debugf("Received a file event: %s", event)
+			if event.Has(fsnotify.Create) {
+				// fsnotify does not watch the directories recursively
+				if st, err := os.Stat(event.Name); err == nil && st.IsDir() {
+					for _, ps := range watched {
+						for _, t := range ps.Watch {
+							if err := addWatchTrigger(watcher, t, event.Name); err != nil {
+								log.G(ctx).WithError(err).Warnf("failed to watch %s", event.Name)
+							}
+						}
+					}
+				}
+			}
+			pending[event.Name] = struct{}{}
+			debounce = time.After(watchDebounceDuration)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.G(ctx).WithError(err).Warn("error while watching files")
+		case <-debounce:
+			paths := make([]string, 0, len(pending))
+			for p := range pending {
+				paths = append(paths, p)
+			}
+			sort.Strings(paths)
+			pending = make(map[string]struct{})
+			for _, ps := range watched {
+				if err := c.handleWatchEvents(ctx, ps, paths); err != nil {
+					log.G(ctx).WithError(err).Errorf("failed to update service %s", ps.Unparsed.Name)
+				}
+			}
+		}
+	}
+}
+
+// addWatchTrigger adds the directories under root that are watched by the trigger to the watcher.
+// The parent directory is added for a file, as editors often replace a file instead of writing it.
+func addWatchTrigger(watcher *fsnotify.Watcher, t serviceparser.WatchTrigger, root string) error {
+	if _, ok := t.Match(root); !ok {
+		return nil
+	}
+	st, err := os.Stat(root)
+	if err != nil {
+		return fmt.Errorf("failed to watch %s: %w", root, err)
+	}
+	if !st.IsDir() {
+		return watcher.Add(filepath.Dir(root))
+	}
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			return nil
+		}
+		if _, ok := t.Match(path); !ok {
+			return filepath.SkipDir
+		}
+		return watcher.Add(path)
+	})
+}
+
+// watchSync is a pair of the changed path on the host and the path in the container.
+type watchSync struct {
+	hostPath      string
+	containerPath string
+}
+
+// handleWatchEvents updates the service for the changed paths, which must be sorted.
+func (c *Composer) handleWatchEvents(ctx context.Context, ps *serviceparser.Service, paths []string) error {
+	var (
+		rebuild bool
+		restart bool
+		syncs   []watchSync
+	)
+	for _, t := range ps.Watch {
+		var lastDir string
+		for _, p := range paths {
+			rel, ok := t.Match(p)
+			if !ok {
+				continue
+			}
+			switch t.Action {
+			case types.WatchActionRebuild:
+				rebuild = true
+			case types.WatchActionSync, types.WatchActionSyncRestart:
+				if t.Action == types.WatchActionSyncRestart {
+					restart = true
+				}
+				// the contents of a directory are synced with the directory
+				if lastDir != "" && strings.HasPrefix(p, lastDir+string(filepath.Separator)) {
+					continue
+				}
+				if st, err := os.Stat(p); err == nil && st.IsDir() {
+					lastDir = p
+				}
+				syncs = append(syncs, watchSync{hostPath: p, containerPath: t.ContainerPath(rel)})
+			}
+		}
+	}
+
+	if rebuild {
+		return c.rebuildService(ctx, ps)
+	}
+	if len(syncs) == 0 {
+		return nil
+	}
+	containers, err := c.Containers(ctx, ps.Unparsed.Name)
+	if err != nil {
+		return err
+	}
+	for _, container := range containers {
+		for _, s := range syncs {
+			if err := c.syncWatchedPath(ctx, container, s); err != nil {
+				return err
+			}
+		}
+	}
+	if restart {
+		return c.restartContainers(ctx, containers, RestartOptions{})
+	}
+	return nil
+}
+
+// syncWatchedPath copies the changed path into the container, or removes the path from the container
+// if the path has been removed from the host.
+func (c *Composer) syncWatchedPath(ctx context.Context, container containerd.Container, s watchSync) error {
+	st, err := os.Stat(s.hostPath)
+	if errors.Is(err, os.ErrNotExist) {
+		log.G(ctx).Infof("Removing %s from container %s", s.containerPath, container.ID())
+		return c.exec(ctx, container, ExecOptions{Args: []string{"rm", "-rf", s.containerPath}})
+	} else if err != nil {
+		return err
+	}
+	log.G(ctx).Infof("Syncing %s to %s in container %s", s.hostPath, s.containerPath, container.ID())
+	src := s.hostPath
+	if st.IsDir() {
+		// copy the contents of the directory, not the directory itself
+		src += string(filepath.Separator) + "."
+		return c.copyToContainer(ctx, container, src, s.containerPath)
+	}
+	if err := c.copyToContainer(ctx, container, src, s.containerPath); err != nil {
+		// the parent directory may not exist in the container yet
+		log.G(ctx).WithError(err).Debugf("Creating the parent directory of %s in container %s", s.containerPath, container.ID())
+		if mkdirErr := c.exec(ctx, container, ExecOptions{Args: []string{"mkdir", "-p", path.Dir(s.containerPath)}}); mkdirErr != nil {
+			return err
+		}
+		return c.copyToContainer(ctx, container, src, s.containerPath)
+	}
+	return nil
+}
+
+// rebuildService rebuilds the service image, and recreates the service containers.
+func (c *Composer) rebuildService(ctx context.Context, ps *serviceparser.Service) error {
+	if ps.Build == nil {
+		return fmt.Errorf("service %s: rebuild requires build config", ps.Unparsed.Name)
+	}
+	log.G(ctx).Infof("Rebuilding service %s", ps.Unparsed.Name)
+	if err := c.buildServiceImage(ctx, ps.Image, ps.Build, ps.Unparsed.Platform, BuildOptions{}); err != nil {
+		return err
+	}
+	// the containers are recreated, as the image digest in the config hash has changed
+	uo := UpOptions{
+		Detach:  true,
+		NoBuild: true,
+		NoDeps:  true,
+	}
+	return c.upServices(ctx, []*serviceparser.Service{ps}, uo, []string{ps.Unparsed.Name})
+}
@@ -0,0 +1,29 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package composer
+
+import (
+	"context"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/nerdctl/v2/pkg/containerutil"
+)
+
+// copyToContainer copies src on the host to dst in the container, in the same way as `nerdctl cp`.
+func (c *Composer) copyToContainer(ctx context.Context, container containerd.Container, src, dst string) error {
+	return containerutil.CopyFiles(ctx, c.client, container, false, dst, src, c.GOptions.Snapshotter, false)
+}
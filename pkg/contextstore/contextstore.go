@@ -0,0 +1,251 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package contextstore stores the contexts managed by `nerdctl context`.
+//
+// Each context is a TOML file "<name>.toml" in the contexts directory, which is
+// located next to nerdctl.toml. The properties of a context are a subset of nerdctl.toml.
+// The name of the context selected by `nerdctl context use` is stored in the "current" file.
+package contextstore
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/nerdctl/v2/pkg/config"
+	"github.com/containerd/nerdctl/v2/pkg/lockutil"
+)
+
+// DefaultContextName is the name of the built-in context that corresponds to nerdctl.toml.
+// The default context cannot be created nor removed.
+const DefaultContextName = "default"
+
+const (
+	contextExt  = ".toml"
+	currentFile = "current"
+)
+
+// nameRegexp is from https://github.com/docker/cli/blob/v26.1.2/cli/command/context.go
+var nameRegexp = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9_.+-]+$`)
+
+// Context is a named set of nerdctl.toml properties.
+// Empty properties are inherited from nerdctl.toml.
+type Context struct {
+	Name           string   `toml:"-"`
+	Description    string   `toml:"description,omitempty"`
+	Address        string   `toml:"address,omitempty"`
+	Namespace      string   `toml:"namespace,omitempty"`
+	Snapshotter    string   `toml:"snapshotter,omitempty"`
+	DataRoot       string   `toml:"data_root,omitempty"`
+	CNIPath        string   `toml:"cni_path,omitempty"`
+	CNINetConfPath string   `toml:"cni_netconfpath,omitempty"`
+	HostsDir       []string `toml:"hosts_dir,omitempty"`
+}
+
+// FromConfig returns the context that carries the properties of cfg.
+func FromConfig(name string, cfg *config.Config) *Context {
+	return &Context{
+		Name:           name,
+		Address:        cfg.Address,
+		Namespace:      cfg.Namespace,
+		Snapshotter:    cfg.Snapshotter,
+		DataRoot:       cfg.DataRoot,
+		CNIPath:        cfg.CNIPath,
+		CNINetConfPath: cfg.CNINetConfPath,
+		HostsDir:       cfg.HostsDir,
+	}
+}
+
+// ValidateName validates the name of a context to be created.
+func ValidateName(name string) error {
+	if name == DefaultContextName {
+		return fmt.Errorf("%q is a reserved context name", name)
+	}
+	if !nameRegexp.MatchString(name) {
+		return fmt.Errorf("context name %q is invalid, names are validated against regexp %q", name, nameRegexp.String())
+	}
+	return nil
+}
+
+// New returns the store of the contexts in dir.
+// The directory is created on the first write, so that read-only commands
+// do not need the permission to create it.
+func New(dir string) Store {
+	return &contextStore{
+		dir: dir,
+	}
+}
+
+// Store stores the contexts.
+type Store interface {
+	// Get returns the context, or an errdefs.ErrNotFound error.
+	Get(name string) (*Context, error)
+	// List returns the contexts sorted by name, excluding the default context.
+	List() ([]*Context, error)
+	// Create creates the context, or returns an errdefs.ErrAlreadyExists error.
+	Create(c *Context) error
+	// Remove removes the context, or returns an errdefs.ErrNotFound error.
+	Remove(name string) error
+	// Current returns the name of the context selected by `nerdctl context use`.
+	Current() (string, error)
+	// SetCurrent selects the context. Selecting the default context clears the selection.
+	SetCurrent(name string) error
+}
+
+type contextStore struct {
+	dir string
+}
+
+func (x *contextStore) path(name string) string {
+	return filepath.Join(x.dir, name+contextExt)
+}
+
+func (x *contextStore) Get(name string) (*Context, error) {
+	if !nameRegexp.MatchString(name) {
+		return nil, fmt.Errorf("context %q does not exist: %w", name, errdefs.ErrNotFound)
+	}
+	b, err := os.ReadFile(x.path(name))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("context %q does not exist: %w", name, errdefs.ErrNotFound)
+		}
+		return nil, err
+	}
+	var c Context
+	if err := toml.NewDecoder(bytes.NewReader(b)).DisallowUnknownFields().Decode(&c); err != nil {
+		return nil, fmt.Errorf("failed to parse context %q: %w", name, err)
+	}
+	c.Name = name
+	return &c, nil
+}
+
+func (x *contextStore) List() ([]*Context, error) {
+	dirEntries, err := os.ReadDir(x.dir)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var contexts []*Context
+	for _, dirEntry := range dirEntries {
+		name, ok := strings.CutSuffix(dirEntry.Name(), contextExt)
+		if !ok || dirEntry.IsDir() {
+			continue
+		}
+		c, err := x.Get(name)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				// removed concurrently
+				continue
+			}
+			return nil, err
+		}
+		contexts = append(contexts, c)
+	}
+	sort.Slice(contexts, func(i, j int) bool {
+		return contexts[i].Name < contexts[j].Name
+	})
+	return contexts, nil
+}
+
+func (x *contextStore) Create(c *Context) error {
+	if err := ValidateName(c.Name); err != nil {
+		return err
+	}
+	b, err := toml.Marshal(c)
+	if err != nil {
+		return err
+	}
+	fn := func() error {
+		if _, err := os.Stat(x.path(c.Name)); err == nil {
+			return fmt.Errorf("context %q already exists: %w", c.Name, errdefs.ErrAlreadyExists)
+		} else if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return os.WriteFile(x.path(c.Name), b, 0600)
+	}
+	return x.withLock(fn)
+}
+
+func (x *contextStore) Remove(name string) error {
+	if !nameRegexp.MatchString(name) {
+		return fmt.Errorf("context %q does not exist: %w", name, errdefs.ErrNotFound)
+	}
+	fn := func() error {
+		if err := os.Remove(x.path(name)); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("context %q does not exist: %w", name, errdefs.ErrNotFound)
+			}
+			return err
+		}
+		return nil
+	}
+	return x.withLock(fn)
+}
+
+func (x *contextStore) Current() (string, error) {
+	b, err := os.ReadFile(filepath.Join(x.dir, currentFile))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return DefaultContextName, nil
+		}
+		return "", err
+	}
+	if name := strings.TrimSpace(string(b)); name != "" {
+		return name, nil
+	}
+	return DefaultContextName, nil
+}
+
+func (x *contextStore) SetCurrent(name string) error {
+	if name != DefaultContextName && !nameRegexp.MatchString(name) {
+		return fmt.Errorf("context %q does not exist: %w", name, errdefs.ErrNotFound)
+	}
+	fn := func() error {
+		p := filepath.Join(x.dir, currentFile)
+		if name == DefaultContextName {
+			if err := os.Remove(p); err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+			return nil
+		}
+		if _, err := os.Stat(x.path(name)); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("context %q does not exist: %w", name, errdefs.ErrNotFound)
+			}
+			return err
+		}
+		return os.WriteFile(p, []byte(name+"\n"), 0600)
+	}
+	return x.withLock(fn)
+}
+
+func (x *contextStore) withLock(fn func() error) error {
+	if err := os.MkdirAll(x.dir, 0700); err != nil {
+		return err
+	}
+	return lockutil.WithDirLock(x.dir, fn)
+}
@@ -0,0 +1,78 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package contextstore
+
+import (
+	"path/filepath"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
+)
+
+func TestStore(t *testing.T) {
+	// the directory does not exist until the first write
+	store := New(filepath.Join(t.TempDir(), "contexts"))
+
+	contexts, err := store.List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(contexts), 0)
+	current, err := store.Current()
+	assert.NilError(t, err)
+	assert.Equal(t, current, DefaultContextName)
+	_, err = store.Get("foo")
+	assert.Assert(t, errdefs.IsNotFound(err))
+
+	foo := &Context{
+		Name:      "foo",
+		Address:   "unix:///run/k3s/containerd/containerd.sock",
+		Namespace: "k8s.io",
+		HostsDir:  []string{"/etc/containerd/certs.d"},
+	}
+	assert.NilError(t, store.Create(foo))
+	assert.NilError(t, store.Create(&Context{Name: "bar", Description: "bar"}))
+	err = store.Create(&Context{Name: "foo"})
+	assert.Assert(t, errdefs.IsAlreadyExists(err))
+	assert.ErrorContains(t, store.Create(&Context{Name: DefaultContextName}), "reserved")
+	assert.ErrorContains(t, store.Create(&Context{Name: "../foo"}), "invalid")
+
+	got, err := store.Get("foo")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, got, foo)
+
+	contexts, err = store.List()
+	assert.NilError(t, err)
+	assert.Equal(t, len(contexts), 2)
+	assert.Equal(t, contexts[0].Name, "bar")
+	assert.Equal(t, contexts[1].Name, "foo")
+
+	assert.NilError(t, store.SetCurrent("foo"))
+	current, err = store.Current()
+	assert.NilError(t, err)
+	assert.Equal(t, current, "foo")
+	assert.Assert(t, errdefs.IsNotFound(store.SetCurrent("baz")))
+	assert.NilError(t, store.SetCurrent(DefaultContextName))
+	current, err = store.Current()
+	assert.NilError(t, err)
+	assert.Equal(t, current, DefaultContextName)
+
+	assert.NilError(t, store.Remove("foo"))
+	assert.Assert(t, errdefs.IsNotFound(store.Remove("foo")))
+	_, err = store.Get("foo")
+	assert.Assert(t, errdefs.IsNotFound(err))
+}
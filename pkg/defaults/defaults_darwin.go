@@ -36,6 +36,10 @@ func CgroupManager() string {
 	return ""
 }
 
+func APISocket() string {
+	return ""
+}
+
 func HostsDirs() []string {
 	return []string{}
 }
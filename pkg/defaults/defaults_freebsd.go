@@ -55,6 +55,10 @@ func NerdctlTOML() string {
 	return "/etc/nerdctl/nerdctl.toml"
 }
 
+func APISocket() string {
+	return "/var/run/nerdctl/docker.sock"
+}
+
 func HostsDirs() []string {
 	return []string{"/etc/containerd/certs.d", "/etc/docker/certs.d"}
 }
@@ -110,6 +110,19 @@ func NerdctlTOML() string {
 	return filepath.Join(xch, "nerdctl/nerdctl.toml")
 }
 
+// APISocket returns the default path of the socket of `nerdctl system serve`.
+func APISocket() string {
+	if !rootlessutil.IsRootless() {
+		return "/run/nerdctl/docker.sock"
+	}
+	xdr, err := rootlessutil.XDGRuntimeDir()
+	if err != nil {
+		log.L.Warn(err)
+		xdr = fmt.Sprintf("/run/user/%d", rootlessutil.ParentEUID())
+	}
+	return filepath.Join(xdr, "nerdctl/docker.sock")
+}
+
 func HostsDirs() []string {
 	if !rootlessutil.IsRootless() {
 		return []string{"/etc/containerd/certs.d", "/etc/docker/certs.d"}
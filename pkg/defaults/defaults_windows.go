@@ -63,6 +63,10 @@ func NerdctlTOML() string {
 	return filepath.Join(ucd, "nerdctl\\nerdctl.toml")
 }
 
+func APISocket() string {
+	return filepath.Join(DataRoot(), "docker.sock")
+}
+
 func HostsDirs() []string {
 	programData := os.Getenv("ProgramData")
 	if programData == "" {
@@ -106,6 +106,9 @@ type Store interface {
 	Acquire(Meta) error
 	Release(ns, id string) error
 	Update(ns, id, newName string) error
+	Load(ns, id string) (*Meta, error)
+	AddNetwork(ns, id, network string, result *types100.Result) error
+	RemoveNetwork(ns, id, network string) error
 }
 
 type store struct {
@@ -177,3 +180,65 @@ func (x *store) Update(ns, id, newName string) error {
 	}
 	return lockutil.WithDirLock(x.hostsD, fn)
 }
+
+// Load returns the meta of the container.
+// Load returns an error that satisfies errors.Is(err, os.ErrNotExist) if the container is not running.
+func (x *store) Load(ns, id string) (*Meta, error) {
+	var meta *Meta
+	fn := func() error {
+		var err error
+		meta, err = x.readMeta(ns, id)
+		return err
+	}
+	return meta, lockutil.WithDirLock(x.hostsD, fn)
+}
+
+// AddNetwork is triggered by `nerdctl network connect`.
+func (x *store) AddNetwork(ns, id, network string, result *types100.Result) error {
+	return x.updateNetworks(ns, id, func(networks map[string]*types100.Result) {
+		networks[network] = result
+	})
+}
+
+// RemoveNetwork is triggered by `nerdctl network disconnect`.
+func (x *store) RemoveNetwork(ns, id, network string) error {
+	return x.updateNetworks(ns, id, func(networks map[string]*types100.Result) {
+		delete(networks, network)
+	})
+}
+
+func (x *store) updateNetworks(ns, id string, update func(map[string]*types100.Result)) error {
+	fn := func() error {
+		meta, err := x.readMeta(ns, id)
+		if err != nil {
+			return err
+		}
+		if meta.Networks == nil {
+			meta.Networks = make(map[string]*types100.Result)
+		}
+		update(meta.Networks)
+		metaB, err := json.Marshal(meta)
+		if err != nil {
+			return err
+		}
+		metaPath := filepath.Join(x.hostsD, ns, id, metaJSON)
+		if err := os.WriteFile(metaPath, metaB, 0644); err != nil {
+			return err
+		}
+		return newUpdater(meta.ID, x.hostsD).update()
+	}
+	return lockutil.WithDirLock(x.hostsD, fn)
+}
+
+func (x *store) readMeta(ns, id string) (*Meta, error) {
+	metaPath := filepath.Join(x.hostsD, ns, id, metaJSON)
+	metaB, err := os.ReadFile(metaPath)
+	if err != nil {
+		return nil, err
+	}
+	meta := &Meta{}
+	if err := json.Unmarshal(metaB, meta); err != nil {
+		return nil, err
+	}
+	return meta, nil
+}
@@ -0,0 +1,316 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+/*
+   Portions from https://github.com/moby/moby/blob/v26.1.2/daemon/health.go
+   Copyright (C) Docker/Moby authors.
+   Licensed under the Apache License, Version 2.0
+   NOTICE: https://github.com/moby/moby/blob/v26.1.2/NOTICE
+*/
+
+// Package healthcheck implements Docker-compatible container healthchecks.
+//
+// The healthcheck configuration is stored in the "nerdctl/healthcheck" container label,
+// and the health state is stored as "health.json" in the container state dir.
+package healthcheck
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	containerd "github.com/containerd/containerd/v2/client"
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+// Health status strings, compatible with Docker.
+const (
+	// NoHealthcheck is the status of a container without healthcheck.
+	NoHealthcheck = "none"
+	// Starting is the status of a container whose healthcheck has not succeeded yet.
+	Starting = "starting"
+	// Healthy is the status of a container whose last probe succeeded.
+	Healthy = "healthy"
+	// Unhealthy is the status of a container whose probe failed `Retries` times in a row.
+	Unhealthy = "unhealthy"
+)
+
+// Test types, compatible with Docker.
+const (
+	TestNone     = "NONE"
+	TestCmd      = "CMD"
+	TestCmdShell = "CMD-SHELL"
+)
+
+// Defaults from https://github.com/moby/moby/blob/v26.1.2/daemon/health.go#L23-L47
+const (
+	DefaultProbeInterval      = 30 * time.Second
+	DefaultProbeTimeout       = 30 * time.Second
+	DefaultStartPeriod        = 0 * time.Second
+	DefaultStartInterval      = 5 * time.Second
+	DefaultProbeRetries       = 3
+	MinimumDuration           = 1 * time.Millisecond
+	MaxLogEntries             = 5
+	MaxOutputLen              = 4096
+	exitStatusHealthy         = 0
+	exitStatusTimeoutOrKilled = -1
+)
+
+// Healthcheck is the healthcheck configuration of a container.
+// The JSON representation is compatible with `HEALTHCHECK` in Docker image configs.
+type Healthcheck struct {
+	// Test is the test to perform to check that the container is healthy.
+	// An empty slice means to inherit the default.
+	// {"NONE"} : disable healthcheck
+	// {"CMD", args...} : exec arguments directly
+	// {"CMD-SHELL", command} : run command with system's default shell
+	Test []string `json:",omitempty"`
+
+	Interval      time.Duration `json:",omitempty"` // Interval is the time to wait between checks.
+	Timeout       time.Duration `json:",omitempty"` // Timeout is the time to wait before considering the check to have hung.
+	StartPeriod   time.Duration `json:",omitempty"` // The start period for the container to initialize before the retries starts to count down.
+	StartInterval time.Duration `json:",omitempty"` // The interval to attempt healthchecks at during the start period
+	Retries       int           `json:",omitempty"` // Retries is the number of consecutive failures needed to consider a container as unhealthy.
+}
+
+// Health stores the health state of a container.
+type Health struct {
+	Status        string               // Status is one of Starting, Healthy or Unhealthy
+	FailingStreak int                  // FailingStreak is the number of consecutive failures
+	Log           []*HealthcheckResult // Log contains the last few results (oldest first)
+}
+
+// HealthcheckResult stores information about a single run of a healthcheck probe.
+type HealthcheckResult struct {
+	Start    time.Time // Start is the time this check started
+	End      time.Time // End is the time this check ended
+	ExitCode int       // ExitCode meanings: 0=healthy, 1=unhealthy, -1=timeout or killed
+	Output   string    // Output from last check
+}
+
+// Disabled returns true if the healthcheck is disabled with {"NONE"}, or not configured at all.
+func (hc *Healthcheck) Disabled() bool {
+	return hc == nil || len(hc.Test) == 0 || hc.Test[0] == TestNone
+}
+
+// Validate validates the healthcheck configuration.
+func (hc *Healthcheck) Validate() error {
+	if hc == nil {
+		return nil
+	}
+	for name, d := range map[string]time.Duration{
+		"interval":       hc.Interval,
+		"timeout":        hc.Timeout,
+		"start-period":   hc.StartPeriod,
+		"start-interval": hc.StartInterval,
+	} {
+		if d != 0 && d < MinimumDuration {
+			return fmt.Errorf("--health-%s cannot be less than %s", name, MinimumDuration)
+		}
+	}
+	if hc.Retries < 0 {
+		return errors.New("--health-retries cannot be negative")
+	}
+	if len(hc.Test) > 0 {
+		switch hc.Test[0] {
+		case TestNone:
+		case TestCmd:
+			if len(hc.Test) < 2 {
+				return errors.New("healthcheck test \"CMD\" requires at least one argument")
+			}
+		case TestCmdShell:
+			if len(hc.Test) != 2 {
+				return errors.New("healthcheck test \"CMD-SHELL\" requires exactly one argument")
+			}
+		default:
+			return fmt.Errorf("unknown healthcheck test type %q", hc.Test[0])
+		}
+	}
+	return nil
+}
+
+// ProbeArgs returns the process args to execute the probe.
+func (hc *Healthcheck) ProbeArgs(shell []string) ([]string, error) {
+	if hc.Disabled() {
+		return nil, errors.New("healthcheck is disabled")
+	}
+	switch hc.Test[0] {
+	case TestCmd:
+		return hc.Test[1:], nil
+	case TestCmdShell:
+		return append(append([]string{}, shell...), hc.Test[1]), nil
+	default:
+		return nil, fmt.Errorf("unknown healthcheck test type %q", hc.Test[0])
+	}
+}
+
+// ProbeInterval returns the interval to wait before the next probe.
+// `inStartPeriod` should be true while the start period is in effect.
+func (hc *Healthcheck) ProbeInterval(inStartPeriod bool) time.Duration {
+	if inStartPeriod {
+		if hc.StartInterval != 0 {
+			return hc.StartInterval
+		}
+		return DefaultStartInterval
+	}
+	if hc.Interval != 0 {
+		return hc.Interval
+	}
+	return DefaultProbeInterval
+}
+
+// ProbeTimeout returns the probe timeout.
+func (hc *Healthcheck) ProbeTimeout() time.Duration {
+	if hc.Timeout != 0 {
+		return hc.Timeout
+	}
+	return DefaultProbeTimeout
+}
+
+// ProbeRetries returns the number of consecutive failures needed to consider the container as unhealthy.
+func (hc *Healthcheck) ProbeRetries() int {
+	if hc.Retries != 0 {
+		return hc.Retries
+	}
+	return DefaultProbeRetries
+}
+
+// Merge fills the unset fields of `hc` with the values from `base`, typically the HEALTHCHECK of the image.
+// Merge returns `base` if `hc` is nil.
+//
+// From https://github.com/moby/moby/blob/v26.1.2/daemon/commit.go#L111-L134
+func Merge(hc, base *Healthcheck) *Healthcheck {
+	if hc == nil {
+		return base
+	}
+	if base == nil {
+		return hc
+	}
+	merged := *hc
+	if len(merged.Test) == 0 {
+		merged.Test = base.Test
+	}
+	if merged.Interval == 0 {
+		merged.Interval = base.Interval
+	}
+	if merged.Timeout == 0 {
+		merged.Timeout = base.Timeout
+	}
+	if merged.StartPeriod == 0 {
+		merged.StartPeriod = base.StartPeriod
+	}
+	if merged.StartInterval == 0 {
+		merged.StartInterval = base.StartInterval
+	}
+	if merged.Retries == 0 {
+		merged.Retries = base.Retries
+	}
+	return &merged
+}
+
+// FromImage returns the HEALTHCHECK of the image config, or nil.
+// The OCI image spec does not define HEALTHCHECK, so the raw config blob is parsed
+// as a Docker image config.
+func FromImage(ctx context.Context, image containerd.Image) (*Healthcheck, error) {
+	desc, err := image.Config(ctx)
+	if err != nil {
+		return nil, err
+	}
+	switch desc.MediaType {
+	case ocispec.MediaTypeImageConfig, images.MediaTypeDockerSchema2Config:
+	default:
+		return nil, nil
+	}
+	b, err := content.ReadBlob(ctx, image.ContentStore(), desc)
+	if err != nil {
+		return nil, err
+	}
+	var dockerImage struct {
+		Config struct {
+			Healthcheck *Healthcheck `json:",omitempty"`
+		} `json:"config,omitempty"`
+	}
+	if err := json.Unmarshal(b, &dockerImage); err != nil {
+		return nil, err
+	}
+	return dockerImage.Config.Healthcheck, nil
+}
+
+// FromLabels returns the healthcheck stored in the container labels, or nil.
+func FromLabels(containerLabels map[string]string) (*Healthcheck, error) {
+	hcJSON, ok := containerLabels[labels.HealthCheck]
+	if !ok || hcJSON == "" {
+		return nil, nil
+	}
+	var hc Healthcheck
+	if err := json.Unmarshal([]byte(hcJSON), &hc); err != nil {
+		return nil, fmt.Errorf("failed to parse label %q: %w", labels.HealthCheck, err)
+	}
+	return &hc, nil
+}
+
+// Update updates the health state with the probe result, and returns the new status.
+// `inStartPeriod` should be true while the start period is in effect; failures during the
+// start period do not count towards the retries.
+//
+// From https://github.com/moby/moby/blob/v26.1.2/daemon/health.go#L169-L246
+func (h *Health) Update(hc *Healthcheck, result *HealthcheckResult, inStartPeriod bool) string {
+	h.Log = append(h.Log, result)
+	if len(h.Log) > MaxLogEntries {
+		h.Log = h.Log[len(h.Log)-MaxLogEntries:]
+	}
+	if h.Status == "" {
+		h.Status = Starting
+	}
+
+	if result.ExitCode == exitStatusHealthy {
+		h.FailingStreak = 0
+		h.Status = Healthy
+		return h.Status
+	}
+	if inStartPeriod {
+		// Failures during the start period don't count towards the maximum number of retries
+		return h.Status
+	}
+	h.FailingStreak++
+	if h.FailingStreak >= hc.ProbeRetries() {
+		h.Status = Unhealthy
+	}
+	return h.Status
+}
+
+// Reset resets the health state when the container (re)starts.
+// The probe log is preserved.
+func (h *Health) Reset() {
+	h.Status = Starting
+	h.FailingStreak = 0
+}
+
+// TimeoutResult returns the probe result for a probe that exceeded the timeout.
+func TimeoutResult(start time.Time, timeout time.Duration) *HealthcheckResult {
+	return &HealthcheckResult{
+		Start:    start,
+		End:      time.Now(),
+		ExitCode: exitStatusTimeoutOrKilled,
+		Output:   fmt.Sprintf("Health check exceeded timeout (%v)", timeout),
+	}
+}
@@ -0,0 +1,148 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+)
+
+func TestHealthUpdate(t *testing.T) {
+	t.Parallel()
+	hc := &Healthcheck{
+		Test:    []string{TestCmdShell, "exit 0"},
+		Retries: 2,
+	}
+	ok := &HealthcheckResult{ExitCode: 0}
+	ng := &HealthcheckResult{ExitCode: 1}
+
+	var h Health
+	// failures during the start period are not counted
+	assert.Equal(t, h.Update(hc, ng, true), Starting)
+	assert.Equal(t, h.FailingStreak, 0)
+	assert.Equal(t, h.Update(hc, ok, true), Healthy)
+	assert.Equal(t, h.Update(hc, ng, false), Healthy)
+	assert.Equal(t, h.FailingStreak, 1)
+	assert.Equal(t, h.Update(hc, ng, false), Unhealthy)
+	assert.Equal(t, h.FailingStreak, 2)
+	assert.Equal(t, h.Update(hc, ok, false), Healthy)
+	assert.Equal(t, h.FailingStreak, 0)
+
+	for i := 0; i < MaxLogEntries*2; i++ {
+		h.Update(hc, ok, false)
+	}
+	assert.Equal(t, len(h.Log), MaxLogEntries)
+
+	h.Reset()
+	assert.Equal(t, h.Status, Starting)
+	assert.Equal(t, len(h.Log), MaxLogEntries)
+}
+
+func TestMerge(t *testing.T) {
+	t.Parallel()
+	base := &Healthcheck{
+		Test:     []string{TestCmd, "true"},
+		Interval: 10 * time.Second,
+		Timeout:  5 * time.Second,
+		Retries:  5,
+	}
+	assert.DeepEqual(t, Merge(nil, base), base)
+	assert.DeepEqual(t, Merge(&Healthcheck{Interval: time.Second}, base), &Healthcheck{
+		Test:     []string{TestCmd, "true"},
+		Interval: time.Second,
+		Timeout:  5 * time.Second,
+		Retries:  5,
+	})
+	assert.Assert(t, Merge(&Healthcheck{Test: []string{TestNone}}, base).Disabled())
+	assert.Assert(t, Merge(&Healthcheck{}, nil).Disabled())
+}
+
+func TestValidate(t *testing.T) {
+	t.Parallel()
+	testCases := []struct {
+		hc          *Healthcheck
+		errorString string
+	}{
+		{hc: nil},
+		{hc: &Healthcheck{Test: []string{TestCmd, "true"}, Interval: time.Second}},
+		{hc: &Healthcheck{Test: []string{TestNone}}},
+		{hc: &Healthcheck{Test: []string{TestCmd}}, errorString: "requires at least one argument"},
+		{hc: &Healthcheck{Test: []string{TestCmdShell, "a", "b"}}, errorString: "requires exactly one argument"},
+		{hc: &Healthcheck{Test: []string{"FOO"}}, errorString: "unknown healthcheck test type"},
+		{hc: &Healthcheck{Interval: time.Microsecond}, errorString: "--health-interval cannot be less than"},
+		{hc: &Healthcheck{Retries: -1}, errorString: "--health-retries cannot be negative"},
+	}
+	for _, tc := range testCases {
+		err := tc.hc.Validate()
+		if tc.errorString == "" {
+			assert.NilError(t, err)
+		} else {
+			assert.ErrorContains(t, err, tc.errorString)
+		}
+	}
+}
+
+func TestProbeArgs(t *testing.T) {
+	t.Parallel()
+	shell := []string{"/bin/sh", "-c"}
+
+	args, err := (&Healthcheck{Test: []string{TestCmd, "curl", "-f", "http://localhost"}}).ProbeArgs(shell)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, args, []string{"curl", "-f", "http://localhost"})
+
+	args, err = (&Healthcheck{Test: []string{TestCmdShell, "curl -f http://localhost"}}).ProbeArgs(shell)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, args, []string{"/bin/sh", "-c", "curl -f http://localhost"})
+
+	_, err = (&Healthcheck{Test: []string{TestNone}}).ProbeArgs(shell)
+	assert.ErrorContains(t, err, "disabled")
+}
+
+func TestStatus(t *testing.T) {
+	t.Parallel()
+	stateDir := t.TempDir()
+
+	status, err := Status(map[string]string{labels.StateDir: stateDir})
+	assert.NilError(t, err)
+	assert.Equal(t, status, NoHealthcheck)
+
+	containerLabels := map[string]string{
+		labels.StateDir:    stateDir,
+		labels.HealthCheck: `{"Test":["CMD-SHELL","exit 0"]}`,
+	}
+	status, err = Status(containerLabels)
+	assert.NilError(t, err)
+	assert.Equal(t, status, Starting)
+
+	hs := NewHealthState(stateDir)
+	err = hs.WithLock(func() error {
+		if err := hs.Load(); err != nil {
+			return err
+		}
+		hs.Update(&Healthcheck{}, &HealthcheckResult{ExitCode: 0}, false)
+		return hs.Save()
+	})
+	assert.NilError(t, err)
+
+	status, err = Status(containerLabels)
+	assert.NilError(t, err)
+	assert.Equal(t, status, Healthy)
+}
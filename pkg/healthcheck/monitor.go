@@ -0,0 +1,50 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/containerd/log"
+)
+
+// SpawnMonitor spawns a detached `nerdctl internal healthcheck-monitor` process for the container `id`.
+// The monitor runs the probes until the task of the container exits.
+//
+// SpawnMonitor is called by the commands that start the task, and by the OCI hook, so that the monitor
+// is also spawned for the tasks started by the restart manager of containerd.
+func SpawnMonitor(ctx context.Context, address, namespace, id string) error {
+	selfExe, err := os.Executable()
+	if err != nil {
+		return err
+	}
+	args := []string{
+		"--address=" + address,
+		"--namespace=" + namespace,
+		"internal", "healthcheck-monitor", id,
+	}
+	cmd := exec.Command(selfExe, args...)
+	cmd.SysProcAttr = monitorSysProcAttr()
+	log.G(ctx).Debugf("Spawning healthcheck monitor %v", cmd.Args)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start healthcheck monitor: %w", err)
+	}
+	return cmd.Process.Release()
+}
@@ -0,0 +1,119 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package healthcheck
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/lockutil"
+)
+
+// The health state is stored inside the container statedir, next to lifecycle.json.
+// Like state.LifecycleState, you MUST use WithLock to perform any operation (like Load or Save).
+
+const (
+	healthFile = "health.json"
+)
+
+func NewHealthState(stateDir string) *HealthState {
+	return &HealthState{
+		stateDir: stateDir,
+	}
+}
+
+type HealthState struct {
+	stateDir string
+	Health
+}
+
+func (hs *HealthState) WithLock(fun func() error) error {
+	err := lockutil.WithDirLock(hs.stateDir, fun)
+	if err != nil {
+		return fmt.Errorf("failed to lock state dir: %w", err)
+	}
+
+	return nil
+}
+
+func (hs *HealthState) Load() error {
+	data, err := os.ReadFile(filepath.Join(hs.stateDir, healthFile))
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("unable to read health file: %w", err)
+		}
+	} else {
+		err = json.Unmarshal(data, &hs.Health)
+		if err != nil {
+			return fmt.Errorf("unable to unmarshall health data: %w", err)
+		}
+	}
+	return nil
+}
+
+func (hs *HealthState) Save() error {
+	data, err := json.Marshal(hs.Health)
+	if err != nil {
+		return fmt.Errorf("unable to marshall health data: %w", err)
+	}
+	err = os.WriteFile(filepath.Join(hs.stateDir, healthFile), data, 0600)
+	if err != nil {
+		return fmt.Errorf("unable to write health file: %w", err)
+	}
+	return nil
+}
+
+// Inspect returns the health state of the container with `containerLabels`.
+// Inspect returns nil if the container has no healthcheck.
+func Inspect(containerLabels map[string]string) (*Health, error) {
+	hc, err := FromLabels(containerLabels)
+	if err != nil {
+		return nil, err
+	}
+	if hc.Disabled() {
+		return nil, nil
+	}
+	stateDir := containerLabels[labels.StateDir]
+	if stateDir == "" {
+		return nil, fmt.Errorf("label %q is not set", labels.StateDir)
+	}
+	hs := NewHealthState(stateDir)
+	if err := hs.WithLock(hs.Load); err != nil {
+		return nil, err
+	}
+	if hs.Status == "" {
+		hs.Status = Starting
+	}
+	return &hs.Health, nil
+}
+
+// Status returns the health status string of the container with `containerLabels`.
+// Status returns NoHealthcheck if the container has no healthcheck.
+func Status(containerLabels map[string]string) (string, error) {
+	h, err := Inspect(containerLabels)
+	if err != nil {
+		return "", err
+	}
+	if h == nil {
+		return NoHealthcheck, nil
+	}
+	return h.Status, nil
+}
@@ -0,0 +1,331 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package commit
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/docker/go-connections/nat"
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+
+	"github.com/containerd/log"
+)
+
+// The Dockerfile directives supported by `--change`
+const (
+	cmdDirective        = "CMD"
+	entrypointDirective = "ENTRYPOINT"
+	envDirective        = "ENV"
+	labelDirective      = "LABEL"
+	exposeDirective     = "EXPOSE"
+	userDirective       = "USER"
+	workdirDirective    = "WORKDIR"
+	volumeDirective     = "VOLUME"
+	stopSignalDirective = "STOPSIGNAL"
+	onBuildDirective    = "ONBUILD"
+)
+
+// Changes are the image config changes specified with the Dockerfile-style `--change` instructions.
+type Changes struct {
+	CMD, Entrypoint []string
+	// Env is the list of "KEY=VALUE", in the order of the instructions
+	Env    []string
+	Labels map[string]string
+	// ExposedPorts is the list of "PORT/PROTO"
+	ExposedPorts []string
+	User         string
+	// WorkingDir may be relative to the working directory of the image
+	WorkingDir string
+	Volumes    []string
+	StopSignal string
+	OnBuild    []string
+}
+
+// ParseChanges parses the Dockerfile-style `--change` instructions.
+func ParseChanges(userChanges []string) (Changes, error) {
+	var changes Changes
+	for _, change := range userChanges {
+		if strings.TrimSpace(change) == "" {
+			return Changes{}, fmt.Errorf("received an empty value in change flag")
+		}
+		if err := changes.parse(change); err != nil {
+			return Changes{}, err
+		}
+	}
+	return changes, nil
+}
+
+func (changes *Changes) parse(change string) error {
+	directive, args, _ := strings.Cut(strings.TrimSpace(change), " ")
+	directive = strings.ToUpper(directive)
+	args = strings.TrimSpace(args)
+	if args == "" {
+		return fmt.Errorf("%s requires at least one argument", directive)
+	}
+
+	switch directive {
+	case cmdDirective:
+		cmd, err := parseCommand(change, args)
+		if err != nil {
+			return err
+		}
+		if changes.CMD != nil {
+			log.L.Warn("multiple change flags supplied for the CMD directive, overriding with last supplied")
+		}
+		changes.CMD = cmd
+	case entrypointDirective:
+		entrypoint, err := parseCommand(change, args)
+		if err != nil {
+			return err
+		}
+		if changes.Entrypoint != nil {
+			log.L.Warnf("multiple change flags supplied for the Entrypoint directive, overriding with last supplied")
+		}
+		changes.Entrypoint = entrypoint
+	case envDirective, labelDirective:
+		kvs, err := parseKeyValues(directive, args)
+		if err != nil {
+			return err
+		}
+		for _, kv := range kvs {
+			if directive == envDirective {
+				changes.Env = append(changes.Env, kv[0]+"="+kv[1])
+				continue
+			}
+			if changes.Labels == nil {
+				changes.Labels = make(map[string]string)
+			}
+			changes.Labels[kv[0]] = kv[1]
+		}
+	case exposeDirective:
+		words, err := splitWords(args)
+		if err != nil {
+			return err
+		}
+		ports, _, err := nat.ParsePortSpecs(words)
+		if err != nil {
+			return fmt.Errorf("invalid %s instruction %q: %w", directive, change, err)
+		}
+		for port := range ports {
+			changes.ExposedPorts = append(changes.ExposedPorts, string(port))
+		}
+		sort.Strings(changes.ExposedPorts)
+	case userDirective:
+		changes.User = args
+	case workdirDirective:
+		changes.WorkingDir = joinWorkingDir(changes.WorkingDir, args)
+	case volumeDirective:
+		volumes, err := parseJSONOrWords(args)
+		if err != nil {
+			return err
+		}
+		for _, v := range volumes {
+			if v == "" {
+				return fmt.Errorf("VOLUME specified can not be an empty string")
+			}
+		}
+		changes.Volumes = append(changes.Volumes, volumes...)
+	case stopSignalDirective:
+		changes.StopSignal = args
+	case onBuildDirective:
+		trigger, triggerArgs, _ := strings.Cut(args, " ")
+		switch trigger = strings.ToUpper(trigger); trigger {
+		case onBuildDirective, "FROM", "MAINTAINER":
+			return fmt.Errorf("%s isn't allowed as an ONBUILD trigger", trigger)
+		}
+		changes.OnBuild = append(changes.OnBuild, trigger+" "+strings.TrimSpace(triggerArgs))
+	default:
+		return fmt.Errorf("unknown change directive %q", directive)
+	}
+	return nil
+}
+
+// parseCommand parses the exec form (JSON array) or the shell form of CMD and ENTRYPOINT.
+func parseCommand(change, args string) ([]string, error) {
+	if !strings.HasPrefix(args, "[") {
+		return []string{"/bin/sh", "-c", args}, nil
+	}
+	var cmd []string
+	if err := json.Unmarshal([]byte(args), &cmd); err != nil {
+		return nil, fmt.Errorf("malformed json in change flag value %q", change)
+	}
+	return cmd, nil
+}
+
+// parseJSONOrWords parses the JSON array, or the whitespace-separated words.
+func parseJSONOrWords(args string) ([]string, error) {
+	if strings.HasPrefix(args, "[") {
+		var words []string
+		if err := json.Unmarshal([]byte(args), &words); err == nil {
+			return words, nil
+		}
+	}
+	return splitWords(args)
+}
+
+// parseKeyValues parses the arguments of ENV and LABEL.
+// Both "KEY=VALUE ..." and the legacy "KEY VALUE" forms are supported.
+func parseKeyValues(directive, args string) ([][2]string, error) {
+	words, err := splitWords(args)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.Contains(words[0], "=") {
+		// The legacy form; the value is the rest of the arguments
+		key, value, _ := strings.Cut(args, " ")
+		if value = strings.TrimSpace(value); value == "" {
+			return nil, fmt.Errorf("%s must have two arguments", directive)
+		}
+		return [][2]string{{key, value}}, nil
+	}
+	kvs := make([][2]string, 0, len(words))
+	for _, word := range words {
+		key, value, ok := strings.Cut(word, "=")
+		if !ok || key == "" {
+			return nil, fmt.Errorf("%s names can not be blank and must be in the form of KEY=VALUE: %q", directive, word)
+		}
+		kvs = append(kvs, [2]string{key, value})
+	}
+	return kvs, nil
+}
+
+// splitWords splits the string into the whitespace-separated words.
+// The quotes are removed, and the backslash escapes the next character outside single quotes.
+func splitWords(s string) ([]string, error) {
+	var (
+		words   []string
+		word    strings.Builder
+		inWord  bool
+		quote   rune
+		escaped bool
+	)
+	for _, c := range s {
+		switch {
+		case escaped:
+			word.WriteRune(c)
+			escaped = false
+		case c == '\\' && quote != '\'':
+			escaped = true
+			inWord = true
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			} else {
+				word.WriteRune(c)
+			}
+		case c == '"' || c == '\'':
+			quote = c
+			inWord = true
+		case c == ' ' || c == '\t':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteRune(c)
+			inWord = true
+		}
+	}
+	if quote != 0 {
+		return nil, fmt.Errorf("unmatched quote in %q", s)
+	}
+	if escaped {
+		return nil, errors.New("unexpected end of the instruction after the backslash")
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
+
+func joinWorkingDir(base, dir string) string {
+	if path.IsAbs(dir) || base == "" {
+		return dir
+	}
+	return path.Join(base, dir)
+}
+
+// ApplyChanges applies the changes to the image config.
+func ApplyChanges(config *dockerspec.DockerOCIImageConfig, changes Changes) {
+	if changes.CMD != nil {
+		config.Cmd = changes.CMD
+	}
+	if changes.Entrypoint != nil {
+		config.Entrypoint = changes.Entrypoint
+	}
+	for _, kv := range changes.Env {
+		config.Env = setEnv(config.Env, kv)
+	}
+	if len(changes.Labels) > 0 {
+		if config.Labels == nil {
+			config.Labels = make(map[string]string)
+		}
+		for k, v := range changes.Labels {
+			config.Labels[k] = v
+		}
+	}
+	if len(changes.ExposedPorts) > 0 {
+		if config.ExposedPorts == nil {
+			config.ExposedPorts = make(map[string]struct{})
+		}
+		for _, port := range changes.ExposedPorts {
+			config.ExposedPorts[port] = struct{}{}
+		}
+	}
+	if changes.User != "" {
+		config.User = changes.User
+	}
+	if changes.WorkingDir != "" {
+		config.WorkingDir = joinWorkingDir(config.WorkingDir, changes.WorkingDir)
+		if !path.IsAbs(config.WorkingDir) {
+			config.WorkingDir = "/" + config.WorkingDir
+		}
+	}
+	if len(changes.Volumes) > 0 {
+		if config.Volumes == nil {
+			config.Volumes = make(map[string]struct{})
+		}
+		for _, v := range changes.Volumes {
+			config.Volumes[v] = struct{}{}
+		}
+	}
+	if changes.StopSignal != "" {
+		config.StopSignal = changes.StopSignal
+	}
+	if len(changes.OnBuild) > 0 {
+		config.OnBuild = append(config.OnBuild, changes.OnBuild...)
+	}
+}
+
+// setEnv sets "KEY=VALUE" to env, replacing the existing value of the key.
+func setEnv(env []string, kv string) []string {
+	key, _, _ := strings.Cut(kv, "=")
+	for i, e := range env {
+		if k, _, _ := strings.Cut(e, "="); k == key {
+			res := append([]string{}, env...)
+			res[i] = kv
+			return res
+		}
+	}
+	return append(env, kv)
+}
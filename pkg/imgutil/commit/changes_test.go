@@ -0,0 +1,124 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package commit
+
+import (
+	"testing"
+
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestParseChanges(t *testing.T) {
+	changes, err := ParseChanges([]string{
+		`CMD ["/foo", "bar"]`,
+		`entrypoint echo "hello world"`,
+		`ENV A=1 B="two words" C=a\ b`,
+		`ENV D legacy value`,
+		`LABEL com.example.a=1 "com.example.b"='x y'`,
+		`EXPOSE 80 53/udp 8000-8001`,
+		`USER nobody:nogroup`,
+		`WORKDIR /app`,
+		`WORKDIR sub`,
+		`VOLUME ["/data", "/cache"]`,
+		`VOLUME /logs`,
+		`STOPSIGNAL SIGINT`,
+		`ONBUILD run make`,
+	})
+	assert.NilError(t, err)
+	assert.DeepEqual(t, changes, Changes{
+		CMD:          []string{"/foo", "bar"},
+		Entrypoint:   []string{"/bin/sh", "-c", `echo "hello world"`},
+		Env:          []string{"A=1", "B=two words", "C=a b", "D=legacy value"},
+		Labels:       map[string]string{"com.example.a": "1", "com.example.b": "x y"},
+		ExposedPorts: []string{"53/udp", "80/tcp", "8000/tcp", "8001/tcp"},
+		User:         "nobody:nogroup",
+		WorkingDir:   "/app/sub",
+		Volumes:      []string{"/data", "/cache", "/logs"},
+		StopSignal:   "SIGINT",
+		OnBuild:      []string{"RUN make"},
+	})
+}
+
+func TestParseChangesInvalid(t *testing.T) {
+	for _, change := range []string{
+		"",
+		"CMD",
+		`CMD ["/foo"`,
+		"FROM alpine",
+		"ENV =value",
+		"ENV KEY",
+		`LABEL a="unterminated`,
+		"EXPOSE 80/foo",
+		"EXPOSE 99999",
+		"ONBUILD FROM alpine",
+	} {
+		_, err := ParseChanges([]string{change})
+		assert.Check(t, err != nil, "%q", change)
+	}
+}
+
+func TestApplyChanges(t *testing.T) {
+	config := dockerspec.DockerOCIImageConfig{
+		ImageConfig: ocispec.ImageConfig{
+			Env:        []string{"PATH=/bin", "A=0"},
+			Labels:     map[string]string{"a": "0"},
+			WorkingDir: "/base",
+		},
+	}
+	changes, err := ParseChanges([]string{
+		"ENV A=1 B=2",
+		"LABEL b=1",
+		"WORKDIR rel",
+		"EXPOSE 80",
+		"ONBUILD RUN true",
+	})
+	assert.NilError(t, err)
+	ApplyChanges(&config, changes)
+	assert.DeepEqual(t, config.Env, []string{"PATH=/bin", "A=1", "B=2"})
+	assert.DeepEqual(t, config.Labels, map[string]string{"a": "0", "b": "1"})
+	assert.Equal(t, config.WorkingDir, "/base/rel")
+	assert.DeepEqual(t, config.ExposedPorts, map[string]struct{}{"80/tcp": {}})
+	assert.DeepEqual(t, config.OnBuild, []string{"RUN true"})
+}
+
+func TestValidateOpts(t *testing.T) {
+	opts := &Opts{}
+	assert.NilError(t, opts.validate())
+	assert.Equal(t, opts.Compression, CompressionGzip)
+	assert.Equal(t, opts.Format, FormatDocker)
+
+	assert.NilError(t, (&Opts{Compression: CompressionZstd, Format: FormatOCI}).validate())
+	assert.ErrorContains(t, (&Opts{Compression: CompressionZstd}).validate(), "requires the oci format")
+	assert.Check(t, (&Opts{Compression: "lz4"}).validate() != nil)
+	assert.Check(t, (&Opts{Format: "v1"}).validate() != nil)
+}
+
+func TestLayerMediaType(t *testing.T) {
+	mediaType, err := layerMediaType(ocispec.MediaTypeImageLayerGzip, FormatDocker)
+	assert.NilError(t, err)
+	assert.Equal(t, mediaType, "application/vnd.docker.image.rootfs.diff.tar.gzip")
+	mediaType, err = layerMediaType("application/vnd.docker.image.rootfs.diff.tar.gzip", FormatOCI)
+	assert.NilError(t, err)
+	assert.Equal(t, mediaType, ocispec.MediaTypeImageLayerGzip)
+	mediaType, err = layerMediaType(ocispec.MediaTypeImageLayerZstd, FormatOCI)
+	assert.NilError(t, err)
+	assert.Equal(t, mediaType, ocispec.MediaTypeImageLayerZstd)
+	_, err = layerMediaType(ocispec.MediaTypeImageLayerZstd, FormatDocker)
+	assert.ErrorContains(t, err, "require the oci format")
+}
@@ -22,11 +22,13 @@ import (
 	"crypto/rand"
 	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"runtime"
 	"strings"
 	"time"
 
+	dockerspec "github.com/moby/docker-image-spec/specs-go/v1"
 	"github.com/opencontainers/go-digest"
 	"github.com/opencontainers/image-spec/identity"
 	"github.com/opencontainers/image-spec/specs-go"
@@ -36,20 +38,32 @@ import (
 	"github.com/containerd/containerd/v2/core/content"
 	"github.com/containerd/containerd/v2/core/diff"
 	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/images/converter"
 	"github.com/containerd/containerd/v2/core/leases"
 	"github.com/containerd/containerd/v2/core/snapshots"
 	"github.com/containerd/containerd/v2/pkg/cio"
 	"github.com/containerd/containerd/v2/pkg/rootfs"
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/api/types"
 	imgutil "github.com/containerd/nerdctl/v2/pkg/imgutil"
+	converterutil "github.com/containerd/nerdctl/v2/pkg/imgutil/converter"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
 	"github.com/containerd/platforms"
 )
 
-type Changes struct {
-	CMD, Entrypoint []string
-}
+// The compression types of the committed layer
+const (
+	CompressionGzip         = "gzip"
+	CompressionZstd         = "zstd"
+	CompressionUncompressed = "uncompressed"
+)
+
+// The formats of the committed image
+const (
+	FormatDocker = "docker"
+	FormatOCI    = "oci"
+)
 
 type Opts struct {
 	Author  string
@@ -57,6 +71,31 @@ type Opts struct {
 	Ref     string
 	Pause   bool
 	Changes Changes
+	// Compression is the compression of the committed layer (default: gzip)
+	Compression string
+	// Format is the media type format of the image (default: docker)
+	Format string
+}
+
+func (opts *Opts) validate() error {
+	switch opts.Compression {
+	case "":
+		opts.Compression = CompressionGzip
+	case CompressionGzip, CompressionZstd, CompressionUncompressed:
+	default:
+		return fmt.Errorf("unknown compression %q, must be one of gzip, zstd, or uncompressed", opts.Compression)
+	}
+	switch opts.Format {
+	case "":
+		opts.Format = FormatDocker
+	case FormatDocker, FormatOCI:
+	default:
+		return fmt.Errorf("unknown format %q, must be either docker or oci", opts.Format)
+	}
+	if opts.Compression == CompressionZstd && opts.Format != FormatOCI {
+		return errors.New("zstd compression requires the oci format")
+	}
+	return nil
 }
 
 var (
@@ -65,6 +104,9 @@ var (
 )
 
 func Commit(ctx context.Context, client *containerd.Client, container containerd.Container, opts *Opts) (digest.Digest, error) {
+	if err := opts.validate(); err != nil {
+		return emptyDigest, err
+	}
 	id := container.ID()
 	info, err := container.Info(ctx)
 	if err != nil {
@@ -134,7 +176,7 @@ func Commit(ctx context.Context, client *containerd.Client, container containerd
 	}
 	defer done(ctx)
 
-	diffLayerDesc, diffID, err := createDiff(ctx, id, sn, client.ContentStore(), differ)
+	diffLayerDesc, diffID, err := createDiff(ctx, id, sn, client.ContentStore(), differ, opts)
 	if err != nil {
 		return emptyDigest, fmt.Errorf("failed to export layer: %w", err)
 	}
@@ -149,7 +191,7 @@ func Commit(ctx context.Context, client *containerd.Client, container containerd
 		return emptyDigest, fmt.Errorf("failed to apply diff: %w", err)
 	}
 
-	commitManifestDesc, configDigest, err := writeContentsForImage(ctx, snName, baseImg, imageConfig, diffLayerDesc)
+	commitManifestDesc, configDigest, err := writeContentsForImage(ctx, snName, baseImg, imageConfig, diffLayerDesc, opts.Format)
 	if err != nil {
 		return emptyDigest, err
 	}
@@ -181,24 +223,27 @@ func Commit(ctx context.Context, client *containerd.Client, container containerd
 }
 
 // generateCommitImageConfig returns commit oci image config based on the container's image.
-func generateCommitImageConfig(ctx context.Context, container containerd.Container, img containerd.Image, diffID digest.Digest, opts *Opts) (ocispec.Image, error) {
+func generateCommitImageConfig(ctx context.Context, container containerd.Container, img containerd.Image, diffID digest.Digest, opts *Opts) (dockerspec.DockerOCIImage, error) {
 	spec, err := container.Spec(ctx)
 	if err != nil {
-		return ocispec.Image{}, err
+		return dockerspec.DockerOCIImage{}, err
 	}
 
-	baseConfig, _, err := imgutil.ReadImageConfig(ctx, img) // aware of img.platform
+	// Read the config as the Docker image config, so that the Docker-specific fields (e.g., Healthcheck) are kept
+	var baseConfig dockerspec.DockerOCIImage
+	configDesc, err := img.Config(ctx) // aware of img.platform
 	if err != nil {
-		return ocispec.Image{}, err
+		return dockerspec.DockerOCIImage{}, err
 	}
-
-	// TODO(fuweid): support updating the USER/ENV/... fields?
-	if opts.Changes.CMD != nil {
-		baseConfig.Config.Cmd = opts.Changes.CMD
+	p, err := content.ReadBlob(ctx, img.ContentStore(), configDesc)
+	if err != nil {
+		return dockerspec.DockerOCIImage{}, err
 	}
-	if opts.Changes.Entrypoint != nil {
-		baseConfig.Config.Entrypoint = opts.Changes.Entrypoint
+	if err := json.Unmarshal(p, &baseConfig); err != nil {
+		return dockerspec.DockerOCIImage{}, err
 	}
+
+	ApplyChanges(&baseConfig.Config, opts.Changes)
 	if opts.Author == "" {
 		opts.Author = baseConfig.Author
 	}
@@ -220,38 +265,44 @@ func generateCommitImageConfig(ctx context.Context, container containerd.Contain
 		log.G(ctx).Warnf("assuming os=%q", os)
 	}
 	log.G(ctx).Debugf("generateCommitImageConfig(): arch=%q, os=%q", arch, os)
-	return ocispec.Image{
-		Platform: ocispec.Platform{
-			Architecture: arch,
-			OS:           os,
-		},
+	return dockerspec.DockerOCIImage{
+		Image: ocispec.Image{
+			Platform: ocispec.Platform{
+				Architecture: arch,
+				OS:           os,
+			},
 
-		Created: &createdTime,
-		Author:  opts.Author,
-		Config:  baseConfig.Config,
-		RootFS: ocispec.RootFS{
-			Type:    "layers",
-			DiffIDs: append(baseConfig.RootFS.DiffIDs, diffID),
+			Created: &createdTime,
+			Author:  opts.Author,
+			RootFS: ocispec.RootFS{
+				Type:    "layers",
+				DiffIDs: append(baseConfig.RootFS.DiffIDs, diffID),
+			},
+			History: append(baseConfig.History, ocispec.History{
+				Created:    &createdTime,
+				CreatedBy:  createdBy,
+				Author:     opts.Author,
+				Comment:    opts.Message,
+				EmptyLayer: (diffID == emptyGZLayer),
+			}),
 		},
-		History: append(baseConfig.History, ocispec.History{
-			Created:    &createdTime,
-			CreatedBy:  createdBy,
-			Author:     opts.Author,
-			Comment:    opts.Message,
-			EmptyLayer: (diffID == emptyGZLayer),
-		}),
+		Config: baseConfig.Config,
 	}, nil
 }
 
 // writeContentsForImage will commit oci image config and manifest into containerd's content store.
-func writeContentsForImage(ctx context.Context, snName string, baseImg containerd.Image, newConfig ocispec.Image, diffLayerDesc ocispec.Descriptor) (ocispec.Descriptor, digest.Digest, error) {
+func writeContentsForImage(ctx context.Context, snName string, baseImg containerd.Image, newConfig dockerspec.DockerOCIImage, diffLayerDesc ocispec.Descriptor, format string) (ocispec.Descriptor, digest.Digest, error) {
 	newConfigJSON, err := json.Marshal(newConfig)
 	if err != nil {
 		return ocispec.Descriptor{}, emptyDigest, err
 	}
 
+	manifestMediaType, configMediaType := images.MediaTypeDockerSchema2Manifest, images.MediaTypeDockerSchema2Config
+	if format == FormatOCI {
+		manifestMediaType, configMediaType = ocispec.MediaTypeImageManifest, ocispec.MediaTypeImageConfig
+	}
 	configDesc := ocispec.Descriptor{
-		MediaType: images.MediaTypeDockerSchema2Config,
+		MediaType: configMediaType,
 		Digest:    digest.FromBytes(newConfigJSON),
 		Size:      int64(len(newConfigJSON)),
 	}
@@ -261,13 +312,21 @@ func writeContentsForImage(ctx context.Context, snName string, baseImg container
 	if err != nil {
 		return ocispec.Descriptor{}, emptyDigest, err
 	}
-	layers := append(baseMfst.Layers, diffLayerDesc)
+	layers := make([]ocispec.Descriptor, 0, len(baseMfst.Layers)+1)
+	for _, l := range append(baseMfst.Layers, diffLayerDesc) {
+		// The layers of the base image may have the media types of the other format
+		l.MediaType, err = layerMediaType(l.MediaType, format)
+		if err != nil {
+			return ocispec.Descriptor{}, emptyDigest, err
+		}
+		layers = append(layers, l)
+	}
 
 	newMfst := struct {
 		MediaType string `json:"mediaType,omitempty"`
 		ocispec.Manifest
 	}{
-		MediaType: images.MediaTypeDockerSchema2Manifest,
+		MediaType: manifestMediaType,
 		Manifest: ocispec.Manifest{
 			Versioned: specs.Versioned{
 				SchemaVersion: 2,
@@ -283,7 +342,7 @@ func writeContentsForImage(ctx context.Context, snName string, baseImg container
 	}
 
 	newMfstDesc := ocispec.Descriptor{
-		MediaType: images.MediaTypeDockerSchema2Manifest,
+		MediaType: manifestMediaType,
 		Digest:    digest.FromBytes(newMfstJSON),
 		Size:      int64(len(newMfstJSON)),
 	}
@@ -313,9 +372,14 @@ func writeContentsForImage(ctx context.Context, snName string, baseImg container
 	return newMfstDesc, configDesc.Digest, nil
 }
 
-// createDiff creates a layer diff into containerd's content store.
-func createDiff(ctx context.Context, name string, sn snapshots.Snapshotter, cs content.Store, comparer diff.Comparer) (ocispec.Descriptor, digest.Digest, error) {
-	newDesc, err := rootfs.CreateDiff(ctx, name, sn, comparer)
+// createDiff creates a layer diff into containerd's content store, with the compression of the options.
+func createDiff(ctx context.Context, name string, sn snapshots.Snapshotter, cs content.Store, comparer diff.Comparer, opts *Opts) (ocispec.Descriptor, digest.Digest, error) {
+	// The differ only supports gzip, so the zstd layer is converted from the uncompressed layer
+	mediaType := ocispec.MediaTypeImageLayerGzip
+	if opts.Compression != CompressionGzip {
+		mediaType = ocispec.MediaTypeImageLayer
+	}
+	newDesc, err := rootfs.CreateDiff(ctx, name, sn, comparer, diff.WithMediaType(mediaType))
 	if err != nil {
 		return ocispec.Descriptor{}, digest.Digest(""), err
 	}
@@ -325,21 +389,64 @@ func createDiff(ctx context.Context, name string, sn snapshots.Snapshotter, cs c
 		return ocispec.Descriptor{}, digest.Digest(""), err
 	}
 
-	diffIDStr, ok := info.Labels["containerd.io/uncompressed"]
-	if !ok {
-		return ocispec.Descriptor{}, digest.Digest(""), fmt.Errorf("invalid differ response with no diffID")
+	var diffID digest.Digest
+	if mediaType == ocispec.MediaTypeImageLayer {
+		// The uncompressed layer is the diff itself
+		diffID = newDesc.Digest
+		info.Labels = map[string]string{"containerd.io/uncompressed": diffID.String()}
+		if _, err := cs.Update(ctx, info, "labels.containerd.io/uncompressed"); err != nil {
+			return ocispec.Descriptor{}, digest.Digest(""), err
+		}
+	} else {
+		diffIDStr, ok := info.Labels["containerd.io/uncompressed"]
+		if !ok {
+			return ocispec.Descriptor{}, digest.Digest(""), fmt.Errorf("invalid differ response with no diffID")
+		}
+		diffID, err = digest.Parse(diffIDStr)
+		if err != nil {
+			return ocispec.Descriptor{}, digest.Digest(""), err
+		}
 	}
 
-	diffID, err := digest.Parse(diffIDStr)
+	desc := ocispec.Descriptor{
+		MediaType: mediaType,
+		Digest:    newDesc.Digest,
+		Size:      info.Size,
+	}
+	if opts.Compression == CompressionZstd {
+		convertFunc, err := converterutil.ZstdLayerConvertFunc(types.ImageConvertOptions{ZstdCompressionLevel: 3})
+		if err != nil {
+			return ocispec.Descriptor{}, digest.Digest(""), err
+		}
+		zstdDesc, err := convertFunc(ctx, cs, desc)
+		if err != nil {
+			return ocispec.Descriptor{}, digest.Digest(""), fmt.Errorf("failed to compress the layer with zstd: %w", err)
+		}
+		desc = *zstdDesc
+	}
+	desc.MediaType, err = layerMediaType(desc.MediaType, opts.Format)
 	if err != nil {
 		return ocispec.Descriptor{}, digest.Digest(""), err
 	}
+	return desc, diffID, nil
+}
 
-	return ocispec.Descriptor{
-		MediaType: images.MediaTypeDockerSchema2LayerGzip,
-		Digest:    newDesc.Digest,
-		Size:      info.Size,
-	}, diffID, nil
+// layerMediaType converts the media type of the layer to the media type of the format.
+// The docker format has no media type for zstd, so the zstd layers require the oci format.
+func layerMediaType(mediaType, format string) (string, error) {
+	if format == FormatOCI {
+		return converter.ConvertDockerMediaTypeToOCI(mediaType), nil
+	}
+	switch mediaType {
+	case ocispec.MediaTypeImageLayer:
+		return images.MediaTypeDockerSchema2Layer, nil
+	case ocispec.MediaTypeImageLayerGzip:
+		return images.MediaTypeDockerSchema2LayerGzip, nil
+	case ocispec.MediaTypeImageLayerZstd:
+		return "", errors.New("the image has zstd layers, which require the oci format (--format=oci)")
+	default:
+		return mediaType, nil
+	}
 }
 
 // applyDiffLayer will apply diff layer content created by createDiff into the snapshotter.
@@ -0,0 +1,206 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/remotes/docker"
+	dockerconfig "github.com/containerd/containerd/v2/core/remotes/docker/config"
+	"github.com/containerd/log"
+	distributionref "github.com/distribution/reference"
+)
+
+// ErrReferrersAPIUnsupported is returned by ListReferrers when the registry does not support the referrers API.
+var ErrReferrersAPIUnsupported = errors.New("the registry does not support the referrers API")
+
+// linkNextRegexp matches the next page of the Link header, like `</v2/foo/tags/list?last=bar&n=100>; rel="next"`.
+var linkNextRegexp = regexp.MustCompile(`<([^>]+)>\s*;\s*rel="?next"?`)
+
+// ListTags lists the tags of the repository of `named`, using the "/v2/<name>/tags/list" API of the registry.
+//
+// The hosts and credentials are configured in the same way as New.
+func ListTags(ctx context.Context, named distributionref.Named, optFuncs ...Opt) ([]string, error) {
+	var tags []string
+	err := queryHosts(ctx, named, optFuncs, func(host docker.RegistryHost, repository string) error {
+		tags = []string{}
+		return getPages(ctx, host, apiURL(host, repository, "tags", "list"), nil, func(resp *http.Response) error {
+			var list struct {
+				Tags []string `json:"tags"`
+			}
+			if err := json.NewDecoder(resp.Body).Decode(&list); err != nil {
+				return fmt.Errorf("failed to decode the tags of %q: %w", repository, err)
+			}
+			tags = append(tags, list.Tags...)
+			return nil
+		})
+	})
+	return tags, err
+}
+
+// ListReferrers lists the referrers of the manifest `dgst` in the repository of `named`,
+// using the "/v2/<name>/referrers/<digest>" API of the registry (OCI distribution spec v1.1).
+// When artifactType is not empty, only the referrers of the artifact type are listed.
+//
+// ErrReferrersAPIUnsupported is returned when the registry does not support the API.
+func ListReferrers(ctx context.Context, named distributionref.Named, dgst digest.Digest, artifactType string, optFuncs ...Opt) ([]ocispec.Descriptor, error) {
+	var referrers []ocispec.Descriptor
+	err := queryHosts(ctx, named, optFuncs, func(host docker.RegistryHost, repository string) error {
+		referrers = []ocispec.Descriptor{}
+		u := apiURL(host, repository, "referrers", dgst.String())
+		if artifactType != "" {
+			u.RawQuery = url.Values{"artifactType": {artifactType}}.Encode()
+		}
+		return getPages(ctx, host, u, ErrReferrersAPIUnsupported, func(resp *http.Response) error {
+			var index ocispec.Index
+			if err := json.NewDecoder(resp.Body).Decode(&index); err != nil {
+				return fmt.Errorf("failed to decode the referrers of %q: %w", dgst, err)
+			}
+			for _, desc := range index.Manifests {
+				// The registry may not apply the filter
+				if artifactType == "" || desc.ArtifactType == artifactType {
+					referrers = append(referrers, desc)
+				}
+			}
+			return nil
+		})
+	})
+	return referrers, err
+}
+
+// queryHosts calls fn for the hosts of the repository of `named`, until fn succeeds.
+func queryHosts(ctx context.Context, named distributionref.Named, optFuncs []Opt, fn func(host docker.RegistryHost, repository string) error) error {
+	refDomain := distributionref.Domain(named)
+	ho, err := NewHostOptions(ctx, refDomain, optFuncs...)
+	if err != nil {
+		return err
+	}
+	hosts, err := dockerconfig.ConfigureHosts(ctx, *ho)(refDomain)
+	if err != nil {
+		return err
+	}
+
+	repository := distributionref.Path(named)
+	ctx = docker.WithScope(ctx, fmt.Sprintf("repository:%s:pull", repository))
+	var errs []error
+	for _, host := range hosts {
+		// The mirrors that cannot resolve tags are only used for fetching blobs
+		if !host.Capabilities.Has(docker.HostCapabilityResolve) {
+			continue
+		}
+		err := fn(host, repository)
+		if err == nil {
+			return nil
+		}
+		log.G(ctx).WithError(err).Debugf("failed to query host %q", host.Host)
+		errs = append(errs, err)
+	}
+	if len(errs) == 0 {
+		return fmt.Errorf("no host to query for %q", named.Name())
+	}
+	return errors.Join(errs...)
+}
+
+func apiURL(host docker.RegistryHost, repository string, elem ...string) *url.URL {
+	return &url.URL{
+		Scheme: host.Scheme,
+		Host:   host.Host,
+		Path:   path.Join(append([]string{host.Path, repository}, elem...)...),
+	}
+}
+
+// getPages calls fn for each page of the response, following the Link header of the registry.
+// When errNotFound is not nil, it is returned for the "404 Not Found" response.
+func getPages(ctx context.Context, host docker.RegistryHost, u *url.URL, errNotFound error, fn func(resp *http.Response) error) error {
+	for {
+		resp, err := doWithAuthorizer(ctx, host, u.String())
+		if err != nil {
+			return err
+		}
+		switch {
+		case resp.StatusCode == http.StatusNotFound && errNotFound != nil:
+			resp.Body.Close()
+			return errNotFound
+		case resp.StatusCode != http.StatusOK:
+			resp.Body.Close()
+			return fmt.Errorf("unexpected status from GET request to %s: %s", u, resp.Status)
+		}
+		err = fn(resp)
+		resp.Body.Close()
+		if err != nil {
+			return err
+		}
+
+		next := linkNextRegexp.FindStringSubmatch(resp.Header.Get("Link"))
+		if next == nil {
+			return nil
+		}
+		nextURL, err := url.Parse(next[1])
+		if err != nil {
+			return err
+		}
+		u = u.ResolveReference(nextURL)
+	}
+}
+
+// doWithAuthorizer sends a GET request to the host, and retries it once with the credentials
+// when the registry requires the authentication.
+// The caller must close the body of the response.
+func doWithAuthorizer(ctx context.Context, host docker.RegistryHost, u string) (*http.Response, error) {
+	client := host.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	for retried := false; ; retried = true {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if err != nil {
+			return nil, err
+		}
+		for k, v := range host.Header {
+			req.Header[k] = append(req.Header[k], v...)
+		}
+		req.Header.Set("Accept", "application/json, "+ocispec.MediaTypeImageIndex)
+		if host.Authorizer != nil {
+			if err := host.Authorizer.Authorize(ctx, req); err != nil {
+				return nil, err
+			}
+		}
+		resp, err := client.Do(req)
+		if err != nil {
+			return nil, err
+		}
+		if resp.StatusCode == http.StatusUnauthorized && !retried && host.Authorizer != nil {
+			err := host.Authorizer.AddResponses(ctx, []*http.Response{resp})
+			resp.Body.Close()
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		return resp, nil
+	}
+}
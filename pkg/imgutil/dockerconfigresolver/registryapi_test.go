@@ -0,0 +1,102 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package dockerconfigresolver
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	distributionref "github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+)
+
+func TestListTags(t *testing.T) {
+	pages := map[string][]string{
+		"":    {"1.0", "1.1"},
+		"1.1": {"2.0"},
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if user, pass, ok := r.BasicAuth(); !ok || user != "user" || pass != "pass" {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/v2/foo/bar/tags/list" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		last := r.URL.Query().Get("last")
+		if last == "" {
+			w.Header().Set("Link", `</v2/foo/bar/tags/list?last=1.1&n=2>; rel="next"`)
+		}
+		json.NewEncoder(w).Encode(map[string]any{"name": "foo/bar", "tags": pages[last]})
+	}))
+	defer srv.Close()
+
+	named, err := distributionref.ParseNormalizedNamed(strings.TrimPrefix(srv.URL, "http://") + "/foo/bar")
+	assert.NilError(t, err)
+	creds := func(string) (string, string, error) {
+		return "user", "pass", nil
+	}
+	tags, err := ListTags(context.Background(), named, WithAuthCreds(creds))
+	assert.NilError(t, err)
+	assert.DeepEqual(t, tags, []string{"1.0", "1.1", "2.0"})
+
+	_, err = ListTags(context.Background(), named, WithAuthCreds(func(string) (string, string, error) {
+		return "user", "wrong", nil
+	}))
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestListReferrers(t *testing.T) {
+	subject := digest.FromString("subject")
+	sbom := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, ArtifactType: "application/spdx+json", Digest: digest.FromString("sbom"), Size: 1}
+	sig := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, ArtifactType: "application/vnd.cncf.notary.signature", Digest: digest.FromString("sig"), Size: 1}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/foo/referrers/" + subject.String():
+			// The filter is not applied by the registry
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+			json.NewEncoder(w).Encode(ocispec.Index{MediaType: ocispec.MediaTypeImageIndex, Manifests: []ocispec.Descriptor{sbom, sig}})
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+
+	named, err := distributionref.ParseNormalizedNamed(host + "/foo")
+	assert.NilError(t, err)
+	referrers, err := ListReferrers(context.Background(), named, subject, "")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, referrers, []ocispec.Descriptor{sbom, sig})
+
+	referrers, err = ListReferrers(context.Background(), named, subject, sbom.ArtifactType)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, referrers, []ocispec.Descriptor{sbom})
+
+	named, err = distributionref.ParseNormalizedNamed(host + "/bar")
+	assert.NilError(t, err)
+	_, err = ListReferrers(context.Background(), named, subject, "")
+	assert.ErrorIs(t, err, ErrReferrersAPIUnsupported)
+}
@@ -154,6 +154,85 @@ outer:
 	}
 }
 
+// ShowRefsProgress continuously updates the output with the status of the references,
+// until ctx is done.
+func ShowRefsProgress(ctx context.Context, refs *Refs, out io.Writer) {
+	var (
+		ticker = time.NewTicker(100 * time.Millisecond)
+		fw     = progress.NewWriter(out)
+		start  = time.Now()
+		done   bool
+	)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			fw.Flush()
+
+			tw := tabwriter.NewWriter(fw, 1, 8, 1, ' ', 0)
+			Display(tw, refs.Statuses(), start)
+			tw.Flush()
+
+			if done {
+				fw.Flush()
+				return
+			}
+		case <-ctx.Done():
+			done = true // allow ui to update once more
+		}
+	}
+}
+
+// Refs tracks the status of the jobs for multiple references, such as the tags of a repository,
+// so that their progress can be displayed together.
+type Refs struct {
+	refs     []string
+	statuses map[string]StatusInfo
+	mu       sync.Mutex
+}
+
+// NewRefs creates a new instance of the reference status tracker.
+// All the references are waiting initially.
+func NewRefs(refs []string) *Refs {
+	r := &Refs{
+		statuses: map[string]StatusInfo{},
+	}
+	for _, ref := range refs {
+		r.refs = append(r.refs, ref)
+		r.statuses[ref] = StatusInfo{
+			Ref:    ref,
+			Status: StatusWaiting,
+		}
+	}
+	return r
+}
+
+// Update updates the status of a reference.
+func (r *Refs) Update(ref string, status StatusInfoStatus) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	info := r.statuses[ref]
+	now := time.Now()
+	if info.StartedAt.IsZero() {
+		info.StartedAt = now
+	}
+	info.Status = status
+	info.UpdatedAt = now
+	r.statuses[ref] = info
+}
+
+// Statuses returns the statuses of the references, in the order given to NewRefs.
+func (r *Refs) Statuses() []StatusInfo {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	statuses := make([]StatusInfo, 0, len(r.refs))
+	for _, ref := range r.refs {
+		statuses = append(statuses, r.statuses[ref])
+	}
+	return statuses
+}
+
 // Jobs provides a way of identifying the download keys for a particular task
 // encountering during the pull walk.
 //
@@ -223,6 +302,11 @@ const (
 	StatusDownloading StatusInfoStatus = "downloading"
 	StatusUploading   StatusInfoStatus = "uploading"
 	StatusExists      StatusInfoStatus = "exists"
+
+	// The statuses of the references tracked by Refs
+	StatusPulling StatusInfoStatus = "pulling"
+	StatusPushing StatusInfoStatus = "pushing"
+	StatusFailed  StatusInfoStatus = "failed"
 )
 
 // StatusInfo holds the status info for an upload or download.
@@ -253,7 +337,7 @@ func Display(w io.Writer, statuses []StatusInfo, start time.Time) {
 				status.Status,
 				bar,
 				progress.Bytes(status.Offset), progress.Bytes(status.Total))
-		case StatusResolving, StatusWaiting:
+		case StatusResolving, StatusWaiting, StatusPulling, StatusPushing, StatusFailed:
 			bar := progress.Bar(0.0)
 			fmt.Fprintf(w, "%s:\t%s\t%40r\t\n",
 				status.Ref,
@@ -0,0 +1,174 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package referrers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	distributionref "github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+)
+
+// labelGCRefReferrerPrefix is the prefix of the labels set on the content of the subjects,
+// so that the referrers fetched by Fetch are kept while their subjects are referenced.
+const labelGCRefReferrerPrefix = "containerd.io/gc.ref.content.referrer."
+
+// Fetch fetches the referrers of the subjects in the repository of `named`, and the referrers of the referrers,
+// into the content store. The subjects that are not in the content store are skipped.
+//
+// The referrers are labeled on the content of their subjects, so the caller has to hold a lease until the subjects are referenced.
+func Fetch(ctx context.Context, store content.Store, resolver remotes.Resolver, named distributionref.Named, subjects []digest.Digest, optFuncs ...dockerconfigresolver.Opt) ([]ocispec.Descriptor, error) {
+	fetcher, err := resolver.Fetcher(ctx, named.String())
+	if err != nil {
+		return nil, err
+	}
+	handler := images.Handlers(
+		remotes.FetchHandler(store, fetcher),
+		images.SetChildrenLabels(store, images.ChildrenHandler(store)),
+	)
+
+	var fetched []ocispec.Descriptor
+	seen := make(map[digest.Digest]bool)
+	for len(subjects) > 0 {
+		subject := subjects[0]
+		subjects = subjects[1:]
+		if seen[subject] {
+			continue
+		}
+		seen[subject] = true
+		info, err := store.Info(ctx, subject)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		descs, err := List(ctx, resolver, named, subject, "", optFuncs...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list the referrers of %s: %w", subject, err)
+		}
+		if len(descs) == 0 {
+			continue
+		}
+		info.Labels = map[string]string{}
+		var fieldpaths []string
+		for _, desc := range descs {
+			log.G(ctx).Debugf("fetching referrer %s (%s) of %s", desc.Digest, desc.ArtifactType, subject)
+			if err := images.Dispatch(ctx, handler, nil, desc); err != nil {
+				return nil, fmt.Errorf("failed to fetch the referrer %s: %w", desc.Digest, err)
+			}
+			key := labelGCRefReferrerPrefix + desc.Digest.String()
+			info.Labels[key] = desc.Digest.String()
+			fieldpaths = append(fieldpaths, "labels."+key)
+			fetched = append(fetched, desc)
+			subjects = append(subjects, desc.Digest)
+		}
+		if _, err := store.Update(ctx, info, fieldpaths...); err != nil {
+			return nil, err
+		}
+	}
+	return fetched, nil
+}
+
+// Local returns the referrers of the subjects that were fetched into the content store by Fetch,
+// and the referrers of the referrers.
+func Local(ctx context.Context, store content.Store, subjects []digest.Digest) ([]ocispec.Descriptor, error) {
+	var res []ocispec.Descriptor
+	seen := make(map[digest.Digest]bool)
+	for len(subjects) > 0 {
+		subject := subjects[0]
+		subjects = subjects[1:]
+		if seen[subject] {
+			continue
+		}
+		seen[subject] = true
+		info, err := store.Info(ctx, subject)
+		if err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+
+		var keys []string
+		for k := range info.Labels {
+			if strings.HasPrefix(k, labelGCRefReferrerPrefix) {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			dgst, err := digest.Parse(info.Labels[k])
+			if err != nil || seen[dgst] {
+				continue
+			}
+			desc, err := localDescriptor(ctx, store, dgst)
+			if err != nil {
+				if errdefs.IsNotFound(err) {
+					continue
+				}
+				return nil, err
+			}
+			res = append(res, desc)
+			subjects = append(subjects, dgst)
+		}
+	}
+	return res, nil
+}
+
+// localDescriptor returns the descriptor of the manifest in the content store.
+func localDescriptor(ctx context.Context, store content.Store, dgst digest.Digest) (ocispec.Descriptor, error) {
+	info, err := store.Info(ctx, dgst)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc := ocispec.Descriptor{Digest: dgst, Size: info.Size}
+	b, err := content.ReadBlob(ctx, store, desc)
+	if err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	var manifest struct {
+		MediaType    string `json:"mediaType"`
+		ArtifactType string `json:"artifactType"`
+		Config       struct {
+			MediaType string `json:"mediaType"`
+		} `json:"config"`
+	}
+	if err := json.Unmarshal(b, &manifest); err != nil {
+		return ocispec.Descriptor{}, err
+	}
+	desc.MediaType = manifest.MediaType
+	desc.ArtifactType = manifest.ArtifactType
+	if desc.ArtifactType == "" && manifest.Config.MediaType != ocispec.MediaTypeEmptyJSON {
+		// The artifact type of the manifest defaults to the media type of the config
+		desc.ArtifactType = manifest.Config.MediaType
+	}
+	return desc, nil
+}
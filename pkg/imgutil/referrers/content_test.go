@@ -0,0 +1,95 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package referrers
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/content"
+	"github.com/containerd/containerd/v2/plugins/content/local"
+)
+
+// labelStore is the in-memory label store of the local content store.
+type labelStore map[digest.Digest]map[string]string
+
+func (s labelStore) Get(dgst digest.Digest) (map[string]string, error) {
+	return s[dgst], nil
+}
+
+func (s labelStore) Set(dgst digest.Digest, labels map[string]string) error {
+	s[dgst] = labels
+	return nil
+}
+
+func (s labelStore) Update(dgst digest.Digest, update map[string]string) (map[string]string, error) {
+	labels := s[dgst]
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	for k, v := range update {
+		if v == "" {
+			delete(labels, k)
+		} else {
+			labels[k] = v
+		}
+	}
+	s[dgst] = labels
+	return labels, nil
+}
+
+func TestLocal(t *testing.T) {
+	ctx := context.Background()
+	store, err := local.NewLabeledStore(t.TempDir(), labelStore{})
+	assert.NilError(t, err)
+
+	writeManifest := func(m ocispec.Manifest, labels map[string]string) ocispec.Descriptor {
+		b, err := json.Marshal(m)
+		assert.NilError(t, err)
+		desc := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, Digest: digest.FromBytes(b), Size: int64(len(b))}
+		assert.NilError(t, content.WriteBlob(ctx, store, desc.Digest.String(), bytes.NewReader(b), desc, content.WithLabels(labels)))
+		return desc
+	}
+	// The signature of the SBOM, which refers to the image
+	sig := writeManifest(ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+		Config:    ocispec.Descriptor{MediaType: "application/vnd.dev.cosign.artifact.sig.v1+json"},
+	}, nil)
+	sbom := writeManifest(ocispec.Manifest{
+		MediaType:    ocispec.MediaTypeImageManifest,
+		ArtifactType: "application/spdx+json",
+		Config:       ocispec.DescriptorEmptyJSON,
+	}, map[string]string{labelGCRefReferrerPrefix + sig.Digest.String(): sig.Digest.String()})
+	subject := writeManifest(ocispec.Manifest{
+		MediaType: ocispec.MediaTypeImageManifest,
+	}, map[string]string{labelGCRefReferrerPrefix + sbom.Digest.String(): sbom.Digest.String()})
+
+	descs, err := Local(ctx, store, []digest.Digest{subject.Digest, digest.FromString("missing")})
+	assert.NilError(t, err)
+	assert.Equal(t, len(descs), 2)
+	assert.Equal(t, descs[0].Digest, sbom.Digest)
+	assert.Equal(t, descs[0].ArtifactType, "application/spdx+json")
+	assert.Equal(t, descs[1].Digest, sig.Digest)
+	// The artifact type defaults to the media type of the config
+	assert.Equal(t, descs[1].ArtifactType, "application/vnd.dev.cosign.artifact.sig.v1+json")
+}
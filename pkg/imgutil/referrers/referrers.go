@@ -0,0 +1,124 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package referrers discovers the OCI referrers of the manifests in the registries,
+// such as signatures, SBOMs and attestations.
+package referrers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	distributionref "github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/containerd/v2/core/images"
+	"github.com/containerd/containerd/v2/core/remotes"
+	"github.com/containerd/errdefs"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+)
+
+// maxIndexSize is the maximum size of the index of the fallback tag.
+const maxIndexSize = 4 << 20
+
+// cosignTagSuffixes are the suffixes of the tags that cosign uses for the signatures, attestations and SBOMs.
+var cosignTagSuffixes = []string{".sig", ".att", ".sbom"}
+
+// FallbackTag returns the tag of the referrers tag schema of OCI distribution spec v1.1, like "sha256-<hex>".
+func FallbackTag(dgst digest.Digest) string {
+	return dgst.Algorithm().String() + "-" + dgst.Encoded()
+}
+
+// CosignTags returns the tags that cosign uses for the signatures, attestations and SBOMs of the manifest.
+func CosignTags(dgst digest.Digest) []string {
+	tags := make([]string, len(cosignTagSuffixes))
+	for i, suffix := range cosignTagSuffixes {
+		tags[i] = FallbackTag(dgst) + suffix
+	}
+	return tags
+}
+
+// List lists the referrers of the manifest `dgst` in the repository of `named`.
+// When artifactType is not empty, only the referrers of the artifact type are listed.
+//
+// When the registry does not support the referrers API, the index of the fallback tag is read instead.
+func List(ctx context.Context, resolver remotes.Resolver, named distributionref.Named, dgst digest.Digest, artifactType string, optFuncs ...dockerconfigresolver.Opt) ([]ocispec.Descriptor, error) {
+	referrers, err := dockerconfigresolver.ListReferrers(ctx, named, dgst, artifactType, optFuncs...)
+	if !errors.Is(err, dockerconfigresolver.ErrReferrersAPIUnsupported) {
+		return referrers, err
+	}
+	log.G(ctx).Debugf("the registry of %q does not support the referrers API, falling back to the tag schema", named.Name())
+
+	referrers = []ocispec.Descriptor{}
+	ref := named.Name() + ":" + FallbackTag(dgst)
+	_, desc, err := resolver.Resolve(ctx, ref)
+	if err != nil {
+		if errdefs.IsNotFound(err) {
+			return referrers, nil
+		}
+		return nil, err
+	}
+	if !images.IsIndexType(desc.MediaType) {
+		return nil, fmt.Errorf("expected %q to be an index, got %q", ref, desc.MediaType)
+	}
+	if desc.Size > maxIndexSize {
+		return nil, fmt.Errorf("the index of %q is too large (%d bytes)", ref, desc.Size)
+	}
+	fetcher, err := resolver.Fetcher(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := fetcher.Fetch(ctx, desc)
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	b, err := io.ReadAll(io.LimitReader(rc, desc.Size))
+	if err != nil {
+		return nil, err
+	}
+	var index ocispec.Index
+	if err := json.Unmarshal(b, &index); err != nil {
+		return nil, err
+	}
+	for _, m := range index.Manifests {
+		if artifactType == "" || m.ArtifactType == artifactType {
+			referrers = append(referrers, m)
+		}
+	}
+	return referrers, nil
+}
+
+// ExistingTags returns the tags of the referrers tag schema and cosign that exist for the manifest `dgst`
+// in the repository of `named`.
+func ExistingTags(ctx context.Context, resolver remotes.Resolver, named distributionref.Named, dgst digest.Digest) ([]string, error) {
+	var tags []string
+	for _, tag := range append([]string{FallbackTag(dgst)}, CosignTags(dgst)...) {
+		if _, _, err := resolver.Resolve(ctx, named.Name()+":"+tag); err != nil {
+			if errdefs.IsNotFound(err) {
+				continue
+			}
+			return nil, err
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
@@ -0,0 +1,94 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package referrers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	distributionref "github.com/distribution/reference"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/nerdctl/v2/pkg/imgutil/dockerconfigresolver"
+)
+
+func TestTags(t *testing.T) {
+	dgst := digest.FromString("subject")
+	assert.Equal(t, FallbackTag(dgst), "sha256-"+dgst.Encoded())
+	assert.DeepEqual(t, CosignTags(dgst), []string{
+		"sha256-" + dgst.Encoded() + ".sig",
+		"sha256-" + dgst.Encoded() + ".att",
+		"sha256-" + dgst.Encoded() + ".sbom",
+	})
+}
+
+func TestListFallback(t *testing.T) {
+	subject := digest.FromString("subject")
+	sbom := ocispec.Descriptor{MediaType: ocispec.MediaTypeImageManifest, ArtifactType: "application/spdx+json", Digest: digest.FromString("sbom"), Size: 1}
+	index, err := json.Marshal(ocispec.Index{
+		MediaType: ocispec.MediaTypeImageIndex,
+		Manifests: []ocispec.Descriptor{sbom},
+	})
+	assert.NilError(t, err)
+	indexDigest := digest.FromBytes(index)
+
+	// The registry does not support the referrers API
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v2/foo/manifests/" + FallbackTag(subject), "/v2/foo/manifests/" + indexDigest.String():
+			w.Header().Set("Content-Type", ocispec.MediaTypeImageIndex)
+			w.Header().Set("Docker-Content-Digest", indexDigest.String())
+			w.Header().Set("Content-Length", strconv.Itoa(len(index)))
+			if r.Method == http.MethodGet {
+				w.Write(index)
+			}
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+	host := strings.TrimPrefix(srv.URL, "http://")
+	ctx := context.Background()
+	resolver, err := dockerconfigresolver.New(ctx, host)
+	assert.NilError(t, err)
+
+	named, err := distributionref.ParseNormalizedNamed(host + "/foo")
+	assert.NilError(t, err)
+	referrers, err := List(ctx, resolver, named, subject, "")
+	assert.NilError(t, err)
+	assert.DeepEqual(t, referrers, []ocispec.Descriptor{sbom})
+
+	referrers, err = List(ctx, resolver, named, subject, "application/vnd.cncf.notary.signature")
+	assert.NilError(t, err)
+	assert.Equal(t, len(referrers), 0)
+
+	tags, err := ExistingTags(ctx, resolver, named, subject)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, tags, []string{FallbackTag(subject)})
+
+	// No referrers
+	referrers, err = List(ctx, resolver, named, digest.FromString("other"), "")
+	assert.NilError(t, err)
+	assert.Equal(t, len(referrers), 0)
+}
@@ -41,6 +41,15 @@ const (
 	//Compose Volume Name
 	ComposeVolume = "com.docker.compose.volume"
 
+	// ComposeConfigFiles is the comma-separated list of the compose files of the project
+	ComposeConfigFiles = "com.docker.compose.project.config_files"
+
+	// ComposeWorkingDir is the working directory of the project
+	ComposeWorkingDir = "com.docker.compose.project.working_dir"
+
+	// ComposeConfigHash is the hash of the service config and the image digest of a compose container
+	ComposeConfigHash = "com.docker.compose.config-hash"
+
 	// Hostname
 	Hostname = Prefix + "hostname"
 
@@ -79,6 +88,10 @@ const (
 	// Mounts is the mount points for the container.
 	Mounts = Prefix + "mounts"
 
+	// VolumeSubpaths is a JSON-marshalled string of []mountutil.VolumeSubpath .
+	// The subpaths are resolved again whenever the container starts.
+	VolumeSubpaths = Prefix + "volume-subpaths"
+
 	// StopTimeout is seconds to wait for stop a container.
 	StopTimeout = Prefix + "stop-timeout"
 
@@ -95,6 +108,9 @@ const (
 	// that describes container error.
 	Error = Prefix + "error"
 
+	// HealthCheck is a JSON-marshalled string of healthcheck.Healthcheck .
+	HealthCheck = Prefix + "healthcheck"
+
 	// NerdctlDefaultNetwork indicates whether a network is the default network
 	// created and owned by Nerdctl.
 	// Boolean value which can be parsed with strconv.ParseBool() is required.
@@ -19,6 +19,7 @@
 package lockutil
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -77,3 +78,22 @@ func Unlock(locked *os.File) error {
 	}
 	return nil
 }
+
+// TryLock is similar to Lock but does not block.
+// TryLock returns ErrLocked if the lock is already held.
+func TryLock(dir string) (*os.File, error) {
+	dirFile, err := os.Open(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = flock(dirFile, unix.LOCK_EX|unix.LOCK_NB); err != nil {
+		dirFile.Close()
+		if errors.Is(err, unix.EWOULDBLOCK) {
+			return nil, ErrLocked
+		}
+		return nil, err
+	}
+
+	return dirFile, nil
+}
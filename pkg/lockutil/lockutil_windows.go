@@ -17,6 +17,7 @@
 package lockutil
 
 import (
+	"errors"
 	"fmt"
 	"os"
 
@@ -65,3 +66,21 @@ func Unlock(locked *os.File) error {
 
 	return windows.UnlockFileEx(windows.Handle(locked.Fd()), 0, 1, 0, &windows.Overlapped{})
 }
+
+// TryLock is similar to Lock but does not block.
+// TryLock returns ErrLocked if the lock is already held.
+func TryLock(dir string) (*os.File, error) {
+	dirFile, err := os.OpenFile(dir+".lock", os.O_CREATE, 0644)
+	if err != nil {
+		return nil, err
+	}
+	// 1 lock immediately, 2 exclusive lock
+	if err = windows.LockFileEx(windows.Handle(dirFile.Fd()), windows.LOCKFILE_FAIL_IMMEDIATELY|windows.LOCKFILE_EXCLUSIVE_LOCK, 0, 1, 0, &windows.Overlapped{}); err != nil {
+		dirFile.Close()
+		if errors.Is(err, windows.ERROR_LOCK_VIOLATION) {
+			return nil, ErrLocked
+		}
+		return nil, fmt.Errorf("failed to lock %q: %w", dir, err)
+	}
+	return dirFile, nil
+}
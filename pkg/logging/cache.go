@@ -0,0 +1,229 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+
+	"github.com/docker/go-units"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+)
+
+// Options of the local cache, compatible with Docker's dual logging.
+const (
+	CacheDisabled = "cache-disabled"
+	CacheMaxSize  = "cache-max-size"
+	CacheMaxFile  = "cache-max-file"
+)
+
+var CacheLogOpts = []string{
+	CacheDisabled,
+	CacheMaxSize,
+	CacheMaxFile,
+}
+
+const (
+	defaultCacheMaxSize = "20m"
+	defaultCacheMaxFile = "5"
+)
+
+// CachePath returns the path of the local cache of the logs.
+func CachePath(dataStore, ns, id string) string {
+	return filepath.Join(dataStore, "containers", ns, id, "container-cached.log")
+}
+
+// needsCache returns true if the logs written by the driver cannot be read back by `nerdctl logs`.
+func needsCache(driverName string) bool {
+	_, ok := logViewers[driverName]
+	return !ok
+}
+
+// cacheEnabled returns true unless the cache is disabled with the log options.
+func cacheEnabled(opts map[string]string) bool {
+	disabled, _ := strconv.ParseBool(opts[CacheDisabled])
+	return !disabled
+}
+
+// splitCacheLogOpts validates the cache options, and returns the other options.
+func splitCacheLogOpts(opts map[string]string) (map[string]string, error) {
+	driverOpts := make(map[string]string, len(opts))
+	for k, v := range opts {
+		switch k {
+		case CacheDisabled:
+			if _, err := strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %q", k, v)
+			}
+		case CacheMaxSize:
+			size, err := units.FromHumanSize(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %w", k, err)
+			}
+			if size <= 0 {
+				return nil, fmt.Errorf("%s must be a positive number", k)
+			}
+		case CacheMaxFile:
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %w", k, err)
+			}
+			if n < 1 {
+				return nil, fmt.Errorf("%s cannot be less than 1", k)
+			}
+		default:
+			driverOpts[k] = v
+		}
+	}
+	return driverOpts, nil
+}
+
+// newCacheLogger returns the json-file logger that writes the local cache.
+func newCacheLogger(opts map[string]string) *JSONLogger {
+	cacheOpts := map[string]string{
+		MaxSize: defaultCacheMaxSize,
+		MaxFile: defaultCacheMaxFile,
+	}
+	if v, ok := opts[CacheMaxSize]; ok {
+		cacheOpts[MaxSize] = v
+	}
+	if v, ok := opts[CacheMaxFile]; ok {
+		cacheOpts[MaxFile] = v
+	}
+	return &JSONLogger{Opts: cacheOpts}
+}
+
+// dualLogger writes the logs to the driver and to the local cache,
+// so that `nerdctl logs` can read the logs of any driver.
+type dualLogger struct {
+	Driver
+	cache *JSONLogger
+}
+
+func (d *dualLogger) Init(dataStore, ns, id string) error {
+	if err := d.Driver.Init(dataStore, ns, id); err != nil {
+		return err
+	}
+	d.cache.Opts[LogPath] = CachePath(dataStore, ns, id)
+	return d.cache.Init(dataStore, ns, id)
+}
+
+func (d *dualLogger) PreProcess(dataStore string, config *logging.Config) error {
+	if err := d.Driver.PreProcess(dataStore, config); err != nil {
+		return err
+	}
+	d.cache.Opts[LogPath] = CachePath(dataStore, config.Namespace, config.ID)
+	return d.cache.PreProcess(dataStore, config)
+}
+
+func (d *dualLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	driverStdout, cacheStdout := teeChannel(stdout, "log driver", "local cache")
+	driverStderr, cacheStderr := teeChannel(stderr, "log driver", "local cache")
+	var (
+		wg       sync.WaitGroup
+		cacheErr error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		cacheErr = d.cache.Process(cacheStdout, cacheStderr)
+		drainChannels(cacheStdout, cacheStderr)
+	}()
+	err := d.Driver.Process(driverStdout, driverStderr)
+	// the driver may have failed without reading all the lines
+	drainChannels(driverStdout, driverStderr)
+	wg.Wait()
+	return errors.Join(err, cacheErr)
+}
+
+func (d *dualLogger) PostProcess() error {
+	return errors.Join(d.Driver.PostProcess(), d.cache.PostProcess())
+}
+
+// teeBufferSize is the number of the lines buffered for each consumer of teeChannel.
+const teeBufferSize = 10000
+
+// teeChannel duplicates the lines received from the channel into two channels.
+// Each channel has its own buffer, and the lines are dropped for a channel whose buffer is full,
+// so that a slow consumer blocks neither the other consumer nor the output of the container.
+// name1 and name2 are the names of the consumers in the warnings.
+// Both channels are closed when the channel is closed.
+func teeChannel(in <-chan string, name1, name2 string) (<-chan string, <-chan string) {
+	out1 := make(chan string, teeBufferSize)
+	out2 := make(chan string, teeBufferSize)
+	go func() {
+		defer close(out1)
+		defer close(out2)
+		var dropped1, dropped2 int
+		for s := range in {
+			sendOrDrop(out1, s, name1, &dropped1)
+			sendOrDrop(out2, s, name2, &dropped2)
+		}
+		for name, dropped := range map[string]int{name1: dropped1, name2: dropped2} {
+			if dropped > 0 {
+				log.L.Warnf("dropped %d lines of the logs for the %s", dropped, name)
+			}
+		}
+	}()
+	return out1, out2
+}
+
+// sendOrDrop sends the line to the channel unless the buffer of the channel is full.
+// dropped is the number of the lines dropped since the last line sent to the channel.
+func sendOrDrop(out chan<- string, s, name string, dropped *int) {
+	select {
+	case out <- s:
+		if *dropped > 0 {
+			log.L.Warnf("dropped %d lines of the logs for the %s", *dropped, name)
+			*dropped = 0
+		}
+	default:
+		if *dropped == 0 {
+			log.L.Warnf("the %s is not reading the logs fast enough, dropping logs", name)
+		}
+		*dropped++
+	}
+}
+
+// drainChannels discards the lines of the channels until they are closed.
+func drainChannels(chans ...<-chan string) {
+	var wg sync.WaitGroup
+	for _, ch := range chans {
+		wg.Add(1)
+		go func(ch <-chan string) {
+			defer wg.Done()
+			for range ch {
+			}
+		}(ch)
+	}
+	wg.Wait()
+}
+
+// viewLogsCache loads the log entries from the local cache of the container.
+func viewLogsCache(lvopts LogViewOptions, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	cachePath := CachePath(lvopts.DatastoreRootPath, lvopts.Namespace, lvopts.ContainerID)
+	if _, err := os.Stat(cachePath); err != nil {
+		return fmt.Errorf("failed to stat the local log cache: %w", err)
+	}
+	return viewLogsJSONFileDirect(lvopts, cachePath, stdout, stderr, stopChannel)
+}
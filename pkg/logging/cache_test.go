@@ -0,0 +1,149 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+)
+
+// recordingLogger is a driver that records the lines, and cannot be read back.
+type recordingLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (r *recordingLogger) Init(dataStore, ns, id string) error {
+	return nil
+}
+
+func (r *recordingLogger) PreProcess(dataStore string, config *logging.Config) error {
+	return nil
+}
+
+func (r *recordingLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	fn := func(dataChan <-chan string) {
+		defer wg.Done()
+		for line := range dataChan {
+			r.mu.Lock()
+			r.lines = append(r.lines, line)
+			r.mu.Unlock()
+		}
+	}
+	go fn(stdout)
+	go fn(stderr)
+	wg.Wait()
+	return nil
+}
+
+func (r *recordingLogger) PostProcess() error {
+	return nil
+}
+
+func TestDualLogger(t *testing.T) {
+	recorder := &recordingLogger{}
+	RegisterDriver("test-recording", func(opts map[string]string) (Driver, error) {
+		return recorder, nil
+	}, nil)
+	t.Cleanup(func() {
+		delete(drivers, "test-recording")
+		delete(driversLogOptsValidateFunctions, "test-recording")
+	})
+
+	dataStore := t.TempDir()
+	const ns, id = "default", "0123456789abcdef"
+	driver, err := GetDriver("test-recording", map[string]string{CacheMaxSize: "1m"})
+	assert.NilError(t, err)
+	_, ok := driver.(*dualLogger)
+	assert.Assert(t, ok, "the driver should be wrapped with the local cache")
+	assert.NilError(t, driver.Init(dataStore, ns, id))
+	assert.NilError(t, driver.PreProcess(dataStore, &logging.Config{Namespace: ns, ID: id}))
+
+	stdout := make(chan string, 10)
+	stderr := make(chan string, 10)
+	stdout <- "line1"
+	stderr <- "line2"
+	stdout <- "line3"
+	close(stdout)
+	close(stderr)
+	assert.NilError(t, driver.Process(stdout, stderr))
+	assert.NilError(t, driver.PostProcess())
+	assert.Equal(t, len(recorder.lines), 3)
+
+	lvopts := LogViewOptions{
+		ContainerID:       id,
+		Namespace:         ns,
+		DatastoreRootPath: dataStore,
+	}
+	var stdoutBuf, stderrBuf bytes.Buffer
+	assert.NilError(t, viewLogsCache(lvopts, &stdoutBuf, &stderrBuf, make(chan os.Signal)))
+	assert.Equal(t, stdoutBuf.String(), "line1\nline3\n")
+	assert.Equal(t, stderrBuf.String(), "line2\n")
+
+	lvopts.Tail = 1
+	stdoutBuf.Reset()
+	stderrBuf.Reset()
+	assert.NilError(t, viewLogsCache(lvopts, &stdoutBuf, &stderrBuf, make(chan os.Signal)))
+	// The order of the streams is not deterministic, so only the number of the lines is checked
+	assert.Equal(t, strings.Count(stdoutBuf.String()+stderrBuf.String(), "\n"), 1)
+
+	driver, err = GetDriver("test-recording", map[string]string{CacheDisabled: "true"})
+	assert.NilError(t, err)
+	assert.Equal(t, driver, Driver(recorder))
+}
+
+func TestValidateCacheLogOpts(t *testing.T) {
+	assert.NilError(t, ValidateLogOpts("syslog", map[string]string{
+		CacheDisabled: "false",
+		CacheMaxSize:  "10m",
+		CacheMaxFile:  "3",
+	}))
+	for _, opts := range []map[string]string{
+		{CacheDisabled: "maybe"},
+		{CacheMaxSize: "-1"},
+		{CacheMaxSize: "big"},
+		{CacheMaxFile: "0"},
+	} {
+		assert.Check(t, ValidateLogOpts("syslog", opts) != nil, "%v", opts)
+	}
+}
+
+func TestTeeChannelSlowConsumer(t *testing.T) {
+	in := make(chan string)
+	// out1 is never read, so the lines are dropped for out1 once its buffer is full,
+	// and out2 still receives every line
+	out1, out2 := teeChannel(in, "slow", "fast")
+	for i := 0; i < teeBufferSize*2; i++ {
+		line := strconv.Itoa(i)
+		in <- line
+		assert.Equal(t, <-out2, line)
+	}
+	close(in)
+	_, ok := <-out2
+	assert.Assert(t, !ok)
+	assert.Equal(t, len(out1), teeBufferSize)
+}
@@ -0,0 +1,330 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+const (
+	gelfAddress           = "gelf-address"
+	gelfCompressionType   = "gelf-compression-type"
+	gelfCompressionLevel  = "gelf-compression-level"
+	gelfTCPMaxReconnect   = "gelf-tcp-max-reconnect"
+	gelfTCPReconnectDelay = "gelf-tcp-reconnect-delay"
+)
+
+const (
+	gelfCompressionTypeGzip = "gzip"
+	gelfCompressionTypeZlib = "zlib"
+	gelfCompressionTypeNone = "none"
+
+	gelfDefaultTCPMaxReconnect   = 3
+	gelfDefaultTCPReconnectDelay = 1 * time.Second
+
+	// gelfChunkSize is the maximum size of a UDP datagram, corresponding to Docker
+	gelfChunkSize = 1420
+	// gelfMaxChunks is the maximum number of chunks of a message in the GELF spec
+	gelfMaxChunks = 128
+)
+
+var GelfLogOpts = []string{
+	gelfAddress,
+	gelfCompressionType,
+	gelfCompressionLevel,
+	gelfTCPMaxReconnect,
+	gelfTCPReconnectDelay,
+	Tag,
+}
+
+// Syslog severity levels used for the "level" field
+const (
+	gelfLevelError = 3
+	gelfLevelInfo  = 6
+)
+
+func GelfLogOptsValidate(logOptMap map[string]string) error {
+	for key := range logOptMap {
+		if !strutil.InStringSlice(GelfLogOpts, key) {
+			log.L.Warnf("log-opt %s is ignored for gelf log driver", key)
+		}
+	}
+	_, err := parseGelfConfig(logOptMap)
+	return err
+}
+
+type gelfConfig struct {
+	// network is "udp" or "tcp"
+	network          string
+	address          string
+	compressionType  string
+	compressionLevel int
+	maxReconnect     int
+	reconnectDelay   time.Duration
+}
+
+func parseGelfConfig(opts map[string]string) (*gelfConfig, error) {
+	cfg := &gelfConfig{
+		compressionType:  gelfCompressionTypeGzip,
+		compressionLevel: flate.DefaultCompression,
+		maxReconnect:     gelfDefaultTCPMaxReconnect,
+		reconnectDelay:   gelfDefaultTCPReconnectDelay,
+	}
+	address, ok := opts[gelfAddress]
+	if !ok || address == "" {
+		return nil, fmt.Errorf("%s is required for gelf log driver", gelfAddress)
+	}
+	u, err := url.Parse(address)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s (%s): %w", gelfAddress, address, err)
+	}
+	if u.Scheme != "udp" && u.Scheme != "tcp" {
+		return nil, fmt.Errorf("%s should be in form proto://address, got %q", gelfAddress, address)
+	}
+	if _, _, err := net.SplitHostPort(u.Host); err != nil {
+		return nil, fmt.Errorf("%s should be in form proto://address, got %q: %w", gelfAddress, address, err)
+	}
+	cfg.network, cfg.address = u.Scheme, u.Host
+
+	if v, ok := opts[gelfCompressionType]; ok {
+		switch v {
+		case gelfCompressionTypeGzip, gelfCompressionTypeZlib, gelfCompressionTypeNone:
+			cfg.compressionType = v
+		default:
+			return nil, fmt.Errorf("unknown %s %q, must be one of gzip, zlib, or none", gelfCompressionType, v)
+		}
+	}
+	if v, ok := opts[gelfCompressionLevel]; ok {
+		level, err := strconv.Atoi(v)
+		if err != nil || level < flate.DefaultCompression || level > flate.BestCompression {
+			return nil, fmt.Errorf("invalid %s %q, must be an integer from -1 to 9", gelfCompressionLevel, v)
+		}
+		cfg.compressionLevel = level
+	}
+	for _, key := range []string{gelfCompressionType, gelfCompressionLevel} {
+		if _, ok := opts[key]; ok && cfg.network == "tcp" {
+			// The GELF TCP protocol does not support compression
+			return nil, fmt.Errorf("%s is not supported with TCP", key)
+		}
+	}
+	for _, key := range []string{gelfTCPMaxReconnect, gelfTCPReconnectDelay} {
+		if _, ok := opts[key]; ok && cfg.network != "tcp" {
+			return nil, fmt.Errorf("%s is only supported with TCP", key)
+		}
+	}
+	if v, ok := opts[gelfTCPMaxReconnect]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s %q, must be a non-negative integer", gelfTCPMaxReconnect, v)
+		}
+		cfg.maxReconnect = n
+	}
+	if v, ok := opts[gelfTCPReconnectDelay]; ok {
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 0 {
+			return nil, fmt.Errorf("invalid %s %q, must be a non-negative number of seconds", gelfTCPReconnectDelay, v)
+		}
+		cfg.reconnectDelay = time.Duration(n) * time.Second
+	}
+	return cfg, nil
+}
+
+// gelfMessage is a message of the GELF 1.1 format.
+// The fields prefixed with "_" are the additional fields.
+type gelfMessage struct {
+	Version      string  `json:"version"`
+	Host         string  `json:"host"`
+	ShortMessage string  `json:"short_message"`
+	Timestamp    float64 `json:"timestamp"`
+	Level        int     `json:"level"`
+	ContainerID  string  `json:"_container_id"`
+	Namespace    string  `json:"_namespace"`
+	Tag          string  `json:"_tag"`
+}
+
+type GelfLogger struct {
+	Opts   map[string]string
+	cfg    *gelfConfig
+	conn   net.Conn
+	mu     sync.Mutex
+	host   string
+	tag    string
+	config *logging.Config
+}
+
+func (g *GelfLogger) Init(dataStore, ns, id string) error {
+	return nil
+}
+
+func (g *GelfLogger) PreProcess(_ string, config *logging.Config) error {
+	cfg, err := parseGelfConfig(g.Opts)
+	if err != nil {
+		return err
+	}
+	tag, err := parseTag(g.Opts, config)
+	if err != nil {
+		return err
+	}
+	conn, err := net.Dial(cfg.network, cfg.address)
+	if err != nil {
+		return fmt.Errorf("failed to connect to the gelf address %s://%s: %w", cfg.network, cfg.address, err)
+	}
+	g.host, _ = os.Hostname()
+	g.cfg = cfg
+	g.conn = conn
+	g.tag = tag
+	g.config = config
+	return nil
+}
+
+func (g *GelfLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	fn := func(dataChan <-chan string, level int) {
+		defer wg.Done()
+		for line := range dataChan {
+			msg := &gelfMessage{
+				Version:      "1.1",
+				Host:         g.host,
+				ShortMessage: line,
+				Timestamp:    float64(time.Now().UnixMicro()) / 1e6,
+				Level:        level,
+				ContainerID:  g.config.ID,
+				Namespace:    g.config.Namespace,
+				Tag:          g.tag,
+			}
+			if err := g.send(msg); err != nil {
+				log.L.WithError(err).Error("failed to send the log to the gelf address")
+			}
+		}
+	}
+	go fn(stdout, gelfLevelInfo)
+	go fn(stderr, gelfLevelError)
+	wg.Wait()
+	return nil
+}
+
+func (g *GelfLogger) PostProcess() error {
+	return g.conn.Close()
+}
+
+func (g *GelfLogger) send(msg *gelfMessage) error {
+	b, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cfg.network == "tcp" {
+		return g.sendTCP(append(b, 0))
+	}
+	if b, err = gelfCompress(b, g.cfg.compressionType, g.cfg.compressionLevel); err != nil {
+		return err
+	}
+	return gelfSendUDP(g.conn, b)
+}
+
+// sendTCP writes the null-terminated message, reconnecting on failure.
+func (g *GelfLogger) sendTCP(b []byte) error {
+	_, err := g.conn.Write(b)
+	for i := 0; err != nil && i < g.cfg.maxReconnect; i++ {
+		time.Sleep(g.cfg.reconnectDelay)
+		g.conn.Close()
+		var conn net.Conn
+		if conn, err = net.Dial(g.cfg.network, g.cfg.address); err != nil {
+			continue
+		}
+		g.conn = conn
+		_, err = g.conn.Write(b)
+	}
+	return err
+}
+
+func gelfCompress(b []byte, compressionType string, level int) ([]byte, error) {
+	var (
+		buf bytes.Buffer
+		w   io.WriteCloser
+		err error
+	)
+	switch compressionType {
+	case gelfCompressionTypeNone:
+		return b, nil
+	case gelfCompressionTypeZlib:
+		w, err = zlib.NewWriterLevel(&buf, level)
+	default:
+		w, err = gzip.NewWriterLevel(&buf, level)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(b); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// gelfSendUDP sends the message, splitting it into the GELF chunks if it does not fit in a datagram.
+func gelfSendUDP(conn net.Conn, b []byte) error {
+	if len(b) <= gelfChunkSize {
+		_, err := conn.Write(b)
+		return err
+	}
+	// The chunk header is the magic bytes, the message ID, the sequence number, and the sequence count
+	const headerSize = 2 + 8 + 1 + 1
+	dataSize := gelfChunkSize - headerSize
+	count := (len(b) + dataSize - 1) / dataSize
+	if count > gelfMaxChunks {
+		return errors.New("the message is too large to be sent over UDP")
+	}
+	var id [8]byte
+	if _, err := rand.Read(id[:]); err != nil {
+		return err
+	}
+	chunk := make([]byte, 0, gelfChunkSize)
+	for i := 0; i < count; i++ {
+		end := min((i+1)*dataSize, len(b))
+		chunk = append(chunk[:0], 0x1e, 0x0f)
+		chunk = append(chunk, id[:]...)
+		chunk = append(chunk, byte(i), byte(count))
+		chunk = append(chunk, b[i*dataSize:end]...)
+		if _, err := conn.Write(chunk); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,156 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+)
+
+// processGelfLogs writes the lines to stdout with the gelf driver.
+func processGelfLogs(t *testing.T, opts map[string]string, lines ...string) {
+	t.Helper()
+	driver := &GelfLogger{Opts: opts}
+	assert.NilError(t, driver.PreProcess("", &logging.Config{Namespace: "default", ID: "0123456789abcdef"}))
+	stdout := make(chan string, len(lines))
+	stderr := make(chan string)
+	for _, line := range lines {
+		stdout <- line
+	}
+	close(stdout)
+	close(stderr)
+	assert.NilError(t, driver.Process(stdout, stderr))
+	assert.NilError(t, driver.PostProcess())
+}
+
+func TestGelfUDP(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	processGelfLogs(t, map[string]string{
+		gelfAddress: "udp://" + conn.LocalAddr().String(),
+		Tag:         "{{.Namespace}}/{{.ID}}",
+	}, "hello")
+
+	buf := make([]byte, 65536)
+	n, _, err := conn.ReadFrom(buf)
+	assert.NilError(t, err)
+	zr, err := gzip.NewReader(bytes.NewReader(buf[:n]))
+	assert.NilError(t, err)
+	var msg gelfMessage
+	assert.NilError(t, json.NewDecoder(zr).Decode(&msg))
+	assert.Equal(t, msg.Version, "1.1")
+	assert.Equal(t, msg.ShortMessage, "hello")
+	assert.Equal(t, msg.Level, gelfLevelInfo)
+	assert.Equal(t, msg.ContainerID, "0123456789abcdef")
+	assert.Equal(t, msg.Tag, "default/0123456789ab")
+}
+
+func TestGelfUDPChunked(t *testing.T) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer conn.Close()
+
+	line := strings.Repeat("x", 3*gelfChunkSize)
+	processGelfLogs(t, map[string]string{
+		gelfAddress:         "udp://" + conn.LocalAddr().String(),
+		gelfCompressionType: "none",
+	}, line)
+
+	var payload []byte
+	buf := make([]byte, 65536)
+	for i := 0; ; i++ {
+		n, _, err := conn.ReadFrom(buf)
+		assert.NilError(t, err)
+		assert.Assert(t, n <= gelfChunkSize)
+		assert.DeepEqual(t, buf[:2], []byte{0x1e, 0x0f})
+		assert.Equal(t, int(buf[10]), i)
+		payload = append(payload, buf[12:n]...)
+		if int(buf[11]) == i+1 {
+			break
+		}
+	}
+	var msg gelfMessage
+	assert.NilError(t, json.Unmarshal(payload, &msg))
+	assert.Equal(t, msg.ShortMessage, line)
+}
+
+func TestGelfTCP(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NilError(t, err)
+	defer l.Close()
+	received := make(chan []string)
+	go func() {
+		conn, err := l.Accept()
+		if err != nil {
+			close(received)
+			return
+		}
+		defer conn.Close()
+		var messages []string
+		r := bufio.NewReader(conn)
+		for {
+			b, err := r.ReadBytes(0)
+			if err == io.EOF {
+				break
+			}
+			messages = append(messages, string(bytes.TrimSuffix(b, []byte{0})))
+		}
+		received <- messages
+	}()
+
+	processGelfLogs(t, map[string]string{gelfAddress: "tcp://" + l.Addr().String()}, "line1", "line2")
+
+	messages := <-received
+	assert.Equal(t, len(messages), 2)
+	for i, m := range messages {
+		var msg gelfMessage
+		assert.NilError(t, json.Unmarshal([]byte(m), &msg))
+		assert.Equal(t, msg.ShortMessage, []string{"line1", "line2"}[i])
+	}
+}
+
+func TestParseGelfConfig(t *testing.T) {
+	for _, opts := range []map[string]string{
+		{},
+		{gelfAddress: "127.0.0.1:12201"},
+		{gelfAddress: "http://127.0.0.1:12201"},
+		{gelfAddress: "udp://127.0.0.1"},
+		{gelfAddress: "udp://127.0.0.1:12201", gelfCompressionType: "lz4"},
+		{gelfAddress: "udp://127.0.0.1:12201", gelfCompressionLevel: "10"},
+		{gelfAddress: "tcp://127.0.0.1:12201", gelfCompressionType: "gzip"},
+		{gelfAddress: "udp://127.0.0.1:12201", gelfTCPMaxReconnect: "1"},
+	} {
+		_, err := parseGelfConfig(opts)
+		assert.Check(t, err != nil, "%v", opts)
+	}
+	cfg, err := parseGelfConfig(map[string]string{gelfAddress: "tcp://127.0.0.1:12201", gelfTCPMaxReconnect: "5"})
+	assert.NilError(t, err)
+	assert.Equal(t, cfg.network, "tcp")
+	assert.Equal(t, cfg.maxReconnect, 5)
+}
@@ -17,7 +17,6 @@
 package logging
 
 import (
-	"bytes"
 	"errors"
 	"fmt"
 	"io"
@@ -25,14 +24,12 @@ import (
 	"os/exec"
 	"strconv"
 	"sync"
-	"text/template"
 	"time"
 
 	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
 	"github.com/containerd/log"
 	"github.com/containerd/nerdctl/v2/pkg/strutil"
 	"github.com/coreos/go-systemd/v22/journal"
-	"github.com/docker/cli/templates"
 	timetypes "github.com/docker/docker/api/types/time"
 )
 
@@ -54,12 +51,6 @@ type JournaldLogger struct {
 	vars map[string]string
 }
 
-type identifier struct {
-	ID        string
-	FullID    string
-	Namespace string
-}
-
 func (journaldLogger *JournaldLogger) Init(dataStore, ns, id string) error {
 	return nil
 }
@@ -68,30 +59,9 @@ func (journaldLogger *JournaldLogger) PreProcess(dataStore string, config *loggi
 	if !journal.Enabled() {
 		return errors.New("the local systemd journal is not available for logging")
 	}
-	shortID := config.ID[:12]
-	var syslogIdentifier string
-	if _, ok := journaldLogger.Opts[Tag]; !ok {
-		syslogIdentifier = shortID
-	} else {
-		var tmpl *template.Template
-		var err error
-		tmpl, err = templates.Parse(journaldLogger.Opts[Tag])
-		if err != nil {
-			return err
-		}
-
-		if tmpl != nil {
-			idn := identifier{
-				ID:        shortID,
-				FullID:    config.ID,
-				Namespace: config.Namespace,
-			}
-			var b bytes.Buffer
-			if err := tmpl.Execute(&b, idn); err != nil {
-				return err
-			}
-			syslogIdentifier = b.String()
-		}
+	syslogIdentifier, err := parseTag(journaldLogger.Opts, config)
+	if err != nil {
+		return err
 	}
 	// construct log metadata for the container
 	vars := map[string]string{
@@ -0,0 +1,343 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/logging/localfile"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+	"github.com/docker/docker/api/types/plugins/logdriver"
+	timetypes "github.com/docker/docker/api/types/time"
+	"github.com/docker/go-units"
+	"github.com/fsnotify/fsnotify"
+)
+
+const Compress = "compress"
+
+var LocalDriverLogOpts = []string{
+	MaxSize,
+	MaxFile,
+	Compress,
+}
+
+// The defaults correspond to Docker
+const (
+	defaultLocalMaxSize = "20m"
+	defaultLocalMaxFile = 5
+)
+
+type LocalLogger struct {
+	Opts   map[string]string
+	writer *localfile.Writer
+}
+
+func LocalLogOptsValidate(logOptMap map[string]string) error {
+	for key := range logOptMap {
+		if !strutil.InStringSlice(LocalDriverLogOpts, key) {
+			log.L.Warnf("log-opt %s is ignored for local log driver", key)
+		}
+	}
+	_, err := parseLocalLogOpts("", logOptMap)
+	return err
+}
+
+func parseLocalLogOpts(path string, opts map[string]string) (*localfile.Writer, error) {
+	w := &localfile.Writer{
+		Path:     path,
+		MaxFiles: defaultLocalMaxFile,
+		Compress: true,
+	}
+	maxSize := defaultLocalMaxSize
+	if v, ok := opts[MaxSize]; ok {
+		maxSize = v
+	}
+	var err error
+	w.MaxBytes, err = units.FromHumanSize(maxSize)
+	if err != nil {
+		return nil, err
+	}
+	if w.MaxBytes <= 0 {
+		return nil, fmt.Errorf("max-size must be a positive number")
+	}
+	if v, ok := opts[MaxFile]; ok {
+		w.MaxFiles, err = strconv.Atoi(v)
+		if err != nil {
+			return nil, err
+		}
+		if w.MaxFiles < 1 {
+			return nil, fmt.Errorf("max-file cannot be less than 1")
+		}
+	}
+	if v, ok := opts[Compress]; ok {
+		w.Compress, err = strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for compress: %q", v)
+		}
+	}
+	return w, nil
+}
+
+func (l *LocalLogger) Init(dataStore, ns, id string) error {
+	logFilePath := localfile.Path(dataStore, ns, id)
+	if err := os.MkdirAll(filepath.Dir(logFilePath), 0700); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(logFilePath, os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (l *LocalLogger) PreProcess(dataStore string, config *logging.Config) error {
+	w, err := parseLocalLogOpts(localfile.Path(dataStore, config.Namespace, config.ID), l.Opts)
+	if err != nil {
+		return err
+	}
+	l.writer = w
+	return nil
+}
+
+func (l *LocalLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	return localfile.Encode(stdout, stderr, l.writer)
+}
+
+func (l *LocalLogger) PostProcess() error {
+	return l.writer.Close()
+}
+
+// localEntryWriter writes the entries of the local log files after applying the filters.
+type localEntryWriter struct {
+	stdout, stderr io.Writer
+	timestamps     bool
+	since, until   time.Time
+}
+
+func (w *localEntryWriter) write(e *logdriver.LogEntry) error {
+	t := time.Unix(0, e.TimeNano)
+	if (!w.since.IsZero() && t.Before(w.since)) || (!w.until.IsZero() && t.After(w.until)) {
+		return nil
+	}
+	var output []byte
+	if w.timestamps {
+		output = append(output, t.UTC().Format(time.RFC3339Nano)...)
+		output = append(output, ' ')
+	}
+	output = append(output, e.Line...)
+	if !e.Partial {
+		output = append(output, '\n')
+	}
+	switch e.Source {
+	case "stdout":
+		_, err := w.stdout.Write(output)
+		return err
+	case "stderr":
+		_, err := w.stderr.Write(output)
+		return err
+	default:
+		log.L.Errorf("unknown stream name %q", e.Source)
+		return nil
+	}
+}
+
+// decodeLocalEntries writes the entries after skipping the first `skip` entries,
+// and returns the number of the entries that are still to be skipped.
+func decodeLocalEntries(dec *localfile.Decoder, w *localEntryWriter, skip int) (int, error) {
+	var e logdriver.LogEntry
+	for {
+		if err := dec.Decode(&e); err != nil {
+			if errors.Is(err, io.EOF) {
+				return skip, nil
+			}
+			return skip, err
+		}
+		if skip > 0 {
+			skip--
+			continue
+		}
+		if err := w.write(&e); err != nil {
+			log.L.Errorf("error while writing log entry to output stream: %s", err)
+		}
+	}
+}
+
+// countLocalEntries returns the number of the entries in the segment.
+func countLocalEntries(seg *localfile.Segment) (int, error) {
+	r, err := seg.Reader()
+	if err != nil {
+		return 0, err
+	}
+	dec := localfile.NewDecoder(r)
+	var (
+		e logdriver.LogEntry
+		n int
+	)
+	for {
+		if err := dec.Decode(&e); err != nil {
+			if errors.Is(err, io.EOF) {
+				return n, nil
+			}
+			return n, err
+		}
+		n++
+	}
+}
+
+func parseLogTime(value string, now time.Time) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	ts, err := timetypes.GetTimestamp(value, now)
+	if err != nil {
+		return time.Time{}, err
+	}
+	sec, nsec, err := timetypes.ParseTimestamps(ts, 0)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// Loads log entries from logfiles produced by the local driver and forwards
+// them to the provided io.Writers after applying the provided logging options.
+func viewLogsLocalFile(lvopts LogViewOptions, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	logFilePath := localfile.Path(lvopts.DatastoreRootPath, lvopts.Namespace, lvopts.ContainerID)
+	if _, err := os.Stat(logFilePath); err != nil {
+		return fmt.Errorf("failed to stat local log file %w", err)
+	}
+	return viewLogsLocalFileDirect(lvopts, logFilePath, stdout, stderr, stopChannel)
+}
+
+// Loads log entries from the rotated log files and the current log file.
+// If `LogViewOptions.Follow` is provided, it will keep reading the current log file until
+// it receives something through the stopChannel.
+func viewLogsLocalFileDirect(lvopts LogViewOptions, logFilePath string, stdout, stderr io.Writer, stopChannel chan os.Signal) error {
+	now := time.Now()
+	since, err := parseLogTime(lvopts.Since, now)
+	if err != nil {
+		return fmt.Errorf("invalid value for \"since\": %w", err)
+	}
+	until, err := parseLogTime(lvopts.Until, now)
+	if err != nil {
+		return fmt.Errorf("invalid value for \"until\": %w", err)
+	}
+	w := &localEntryWriter{
+		stdout:     stdout,
+		stderr:     stderr,
+		timestamps: lvopts.Timestamps,
+		since:      since,
+		until:      until,
+	}
+
+	// The segments are opened at once, so that they can be read even if the log is rotated while reading
+	segments, err := localfile.OpenSegments(logFilePath)
+	if err != nil {
+		return err
+	}
+	rotated, current := segments[:len(segments)-1], segments[len(segments)-1]
+	defer localfile.CloseSegments(rotated)
+	fin := current.File
+	defer func() { fin.Close() }()
+	skip := 0
+	if lvopts.Tail > 0 {
+		total := 0
+		for _, seg := range segments {
+			n, err := countLocalEntries(seg)
+			if err != nil {
+				return fmt.Errorf("failed to read local log file %q: %w", seg.Name, err)
+			}
+			total += n
+		}
+		skip = max(total-int(lvopts.Tail), 0)
+	}
+	for _, seg := range rotated {
+		r, err := seg.Reader()
+		if err == nil {
+			skip, err = decodeLocalEntries(localfile.NewDecoder(r), w, skip)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read local log file %q: %w", seg.Name, err)
+		}
+	}
+
+	var (
+		pos     int64
+		watcher *fsnotify.Watcher
+	)
+	// readCurrent reads the complete entries after pos
+	readCurrent := func() error {
+		if _, err := fin.Seek(pos, io.SeekStart); err != nil {
+			return err
+		}
+		dec := localfile.NewDecoder(fin)
+		skip, err = decodeLocalEntries(dec, w, skip)
+		pos += dec.Offset()
+		if err != nil {
+			return fmt.Errorf("failed to read local log file %q: %w", logFilePath, err)
+		}
+		return nil
+	}
+	for {
+		select {
+		case <-stopChannel:
+			log.L.Debug("received stop signal while re-reading local logfile, returning")
+			return nil
+		default:
+		}
+		if err := readCurrent(); err != nil {
+			return err
+		}
+		if !lvopts.Follow {
+			return nil
+		}
+		if watcher == nil {
+			if watcher, err = NewLogFileWatcher(filepath.Dir(logFilePath)); err != nil {
+				return err
+			}
+			defer watcher.Close()
+			// Read again as we might have missed the event.
+			continue
+		}
+		recreated, err := startTail(context.Background(), filepath.Base(logFilePath), watcher)
+		if err != nil {
+			return err
+		}
+		if recreated {
+			// Read the rest of the rotated file before switching to the new file
+			if err := readCurrent(); err != nil {
+				return err
+			}
+			newF, err := openFileShareDelete(logFilePath)
+			if err != nil {
+				return fmt.Errorf("failed to open local logfile %q: %w", logFilePath, err)
+			}
+			fin.Close()
+			fin = newF
+			pos = 0
+		}
+	}
+}
@@ -0,0 +1,159 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/nerdctl/v2/pkg/logging/localfile"
+)
+
+// writeLocalLogs writes the lines to stdout with the local driver.
+func writeLocalLogs(t *testing.T, dataStore string, config *logging.Config, opts map[string]string, lines []string) {
+	t.Helper()
+	driver, err := GetDriver("local", opts)
+	assert.NilError(t, err)
+	assert.NilError(t, driver.PreProcess(dataStore, config))
+	stdout := make(chan string, len(lines))
+	stderr := make(chan string)
+	for _, line := range lines {
+		stdout <- line
+	}
+	close(stdout)
+	close(stderr)
+	assert.NilError(t, driver.Process(stdout, stderr))
+	assert.NilError(t, driver.PostProcess())
+}
+
+func TestLocalLogsRotated(t *testing.T) {
+	dataStore := t.TempDir()
+	config := &logging.Config{Namespace: "default", ID: "0123456789abcdef"}
+	driver, err := GetDriver("local", nil)
+	assert.NilError(t, err)
+	assert.NilError(t, driver.Init(dataStore, config.Namespace, config.ID))
+
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("line%d", i))
+	}
+	// Each entry is about 40 bytes, so the log is rotated every 25 entries
+	opts := map[string]string{MaxSize: "1k", MaxFile: "3"}
+	writeLocalLogs(t, dataStore, config, opts, lines)
+
+	logPath := localfile.Path(dataStore, config.Namespace, config.ID)
+	segments, err := localfile.Segments(logPath)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, segments, []string{logPath + ".2.gz", logPath + ".1.gz", logPath})
+	// The temporary file of the compression is not a segment
+	assert.NilError(t, os.WriteFile(logPath+".1.gz.tmp", []byte("partial"), 0600))
+	segments, err = localfile.Segments(logPath)
+	assert.NilError(t, err)
+	assert.DeepEqual(t, segments, []string{logPath + ".2.gz", logPath + ".1.gz", logPath})
+
+	lvopts := LogViewOptions{
+		ContainerID:       config.ID,
+		Namespace:         config.Namespace,
+		DatastoreRootPath: dataStore,
+	}
+	var stdoutBuf, stderrBuf bytes.Buffer
+	assert.NilError(t, viewLogsLocalFile(lvopts, &stdoutBuf, &stderrBuf, make(chan os.Signal)))
+	got := strings.Split(strings.TrimSuffix(stdoutBuf.String(), "\n"), "\n")
+	assert.Assert(t, len(got) > 25 && len(got) < 100, "the oldest entries should have been removed, got %d entries", len(got))
+	assert.DeepEqual(t, got, lines[len(lines)-len(got):])
+	assert.Equal(t, stderrBuf.Len(), 0)
+
+	// The tail spans the rotated files
+	lvopts.Tail = 30
+	stdoutBuf.Reset()
+	assert.NilError(t, viewLogsLocalFile(lvopts, &stdoutBuf, &stderrBuf, make(chan os.Signal)))
+	assert.Equal(t, stdoutBuf.String(), strings.Join(lines[70:], "\n")+"\n")
+
+	lvopts.Tail = 0
+	lvopts.Until = time.Now().Add(-time.Hour).Format(time.RFC3339)
+	stdoutBuf.Reset()
+	assert.NilError(t, viewLogsLocalFile(lvopts, &stdoutBuf, &stderrBuf, make(chan os.Signal)))
+	assert.Equal(t, stdoutBuf.Len(), 0)
+}
+
+func TestLocalLogsRotationFailure(t *testing.T) {
+	dataStore := t.TempDir()
+	config := &logging.Config{Namespace: "default", ID: "0123456789abcdef"}
+	logPath := localfile.Path(dataStore, config.Namespace, config.ID)
+	// The rotation fails, as the oldest rotated file cannot be removed
+	assert.NilError(t, os.MkdirAll(filepath.Join(logPath+".1.gz", "dir"), 0700))
+
+	var lines []string
+	for i := 0; i < 100; i++ {
+		lines = append(lines, fmt.Sprintf("line%d", i))
+	}
+	opts := map[string]string{MaxSize: "1k", MaxFile: "2"}
+	writeLocalLogs(t, dataStore, config, opts, lines)
+	assert.NilError(t, os.RemoveAll(logPath+".1.gz"))
+
+	// The logs are still written to the current file
+	lvopts := LogViewOptions{
+		ContainerID:       config.ID,
+		Namespace:         config.Namespace,
+		DatastoreRootPath: dataStore,
+	}
+	var stdoutBuf, stderrBuf bytes.Buffer
+	assert.NilError(t, viewLogsLocalFile(lvopts, &stdoutBuf, &stderrBuf, make(chan os.Signal)))
+	assert.Equal(t, stdoutBuf.String(), strings.Join(lines, "\n")+"\n")
+}
+
+func TestLocalLogsFollow(t *testing.T) {
+	dataStore := t.TempDir()
+	config := &logging.Config{Namespace: "default", ID: "0123456789abcdef"}
+	logPath := localfile.Path(dataStore, config.Namespace, config.ID)
+	assert.NilError(t, os.MkdirAll(filepath.Dir(logPath), 0700))
+	opts := map[string]string{MaxSize: "100", Compress: "false"}
+	writeLocalLogs(t, dataStore, config, opts, []string{"line0"})
+
+	var stdoutBuf, stderrBuf bytes.Buffer
+	stopChannel := make(chan os.Signal)
+	done := make(chan error)
+	go func() {
+		lvopts := LogViewOptions{
+			ContainerID:       config.ID,
+			Namespace:         config.Namespace,
+			DatastoreRootPath: dataStore,
+			Follow:            true,
+		}
+		done <- viewLogsLocalFile(lvopts, &stdoutBuf, &stderrBuf, stopChannel)
+	}()
+
+	// Let the viewer start following
+	time.Sleep(100 * time.Millisecond)
+	// The log is rotated every 2 entries
+	for i := 1; i < 6; i++ {
+		writeLocalLogs(t, dataStore, config, opts, []string{fmt.Sprintf("line%d", i)})
+		time.Sleep(20 * time.Millisecond)
+	}
+	time.Sleep(2 * time.Second)
+	close(stopChannel)
+	assert.NilError(t, <-done)
+	assert.Equal(t, stdoutBuf.String(), "line0\nline1\nline2\nline3\nline4\nline5\n")
+}
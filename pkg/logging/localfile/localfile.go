@@ -0,0 +1,136 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package localfile implements the log files of the "local" logging driver.
+//
+// The format is compatible with Docker "local" logs: each entry is a protobuf-encoded
+// logdriver.LogEntry, prefixed and suffixed with its length as a big-endian uint32.
+package localfile
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/containerd/log"
+	"github.com/docker/docker/api/types/plugins/logdriver"
+)
+
+const encodeLen = 4
+
+func Path(dataStore, ns, id string) string {
+	// the file name corresponds to Docker
+	return filepath.Join(dataStore, "containers", ns, id, "container.log")
+}
+
+// Marshal returns the framed entry.
+func Marshal(e *logdriver.LogEntry) ([]byte, error) {
+	n := e.Size()
+	buf := make([]byte, n+2*encodeLen)
+	binary.BigEndian.PutUint32(buf, uint32(n))
+	if _, err := e.MarshalTo(buf[encodeLen : encodeLen+n]); err != nil {
+		return nil, err
+	}
+	binary.BigEndian.PutUint32(buf[encodeLen+n:], uint32(n))
+	return buf, nil
+}
+
+// Encode writes the lines received from the channels as framed entries.
+// Each entry is written with a single call of writer.Write.
+func Encode(stdout <-chan string, stderr <-chan string, writer io.Writer) error {
+	var encMu sync.Mutex
+	var wg sync.WaitGroup
+	wg.Add(2)
+	f := func(dataChan <-chan string, name string) {
+		defer wg.Done()
+		e := &logdriver.LogEntry{
+			Source: name,
+		}
+		for logEntry := range dataChan {
+			e.Line = []byte(logEntry)
+			e.TimeNano = time.Now().UnixNano()
+			buf, err := Marshal(e)
+			if err == nil {
+				encMu.Lock()
+				_, err = writer.Write(buf)
+				encMu.Unlock()
+			}
+			if err != nil {
+				// keep draining the channel, so that the container is not blocked
+				log.L.WithError(err).Errorf("failed to encode log entry")
+			}
+		}
+	}
+	go f(stdout, "stdout")
+	go f(stderr, "stderr")
+	wg.Wait()
+	return nil
+}
+
+// Decoder reads framed entries.
+type Decoder struct {
+	r      io.Reader
+	buf    []byte
+	offset int64
+}
+
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		r:   r,
+		buf: make([]byte, 1024),
+	}
+}
+
+// Decode reads the next entry.
+// io.EOF is returned at the end of the input, including when the last entry is not completely written yet.
+func (d *Decoder) Decode(e *logdriver.LogEntry) error {
+	var lenBuf [encodeLen]byte
+	if _, err := io.ReadFull(d.r, lenBuf[:]); err != nil {
+		return eof(err)
+	}
+	size := int(binary.BigEndian.Uint32(lenBuf[:]))
+	if len(d.buf) < size+encodeLen {
+		d.buf = make([]byte, size+encodeLen)
+	}
+	if _, err := io.ReadFull(d.r, d.buf[:size+encodeLen]); err != nil {
+		return eof(err)
+	}
+	if footer := int(binary.BigEndian.Uint32(d.buf[size:])); footer != size {
+		return fmt.Errorf("corrupted log entry: header size %d, footer size %d", size, footer)
+	}
+	e.Reset()
+	if err := e.Unmarshal(d.buf[:size]); err != nil {
+		return err
+	}
+	d.offset += int64(size + 2*encodeLen)
+	return nil
+}
+
+// Offset returns the number of bytes of the decoded entries.
+func (d *Decoder) Offset() int64 {
+	return d.offset
+}
+
+func eof(err error) error {
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return io.EOF
+	}
+	return err
+}
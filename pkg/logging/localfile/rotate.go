@@ -0,0 +1,321 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package localfile
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/containerd/log"
+)
+
+// Writer writes the log file, and rotates it when it exceeds MaxBytes.
+//
+// The rotated files are named "<Path>.1", "<Path>.2", ..., from the newest to the oldest,
+// with the ".gz" suffix if they are compressed.
+// The rotated file is compressed in the background, so that the compression does not block writing the logs.
+type Writer struct {
+	Path string
+	// MaxBytes is the maximum size of the log file; no rotation if <= 0.
+	MaxBytes int64
+	// MaxFiles is the maximum number of the log files, including the current one.
+	MaxFiles int
+	Compress bool
+
+	f    *os.File
+	size int64
+	// compressed is closed when the compression of the last rotated file is completed
+	compressed chan struct{}
+}
+
+// Write writes p to the log file, rotating the log file if needed.
+// The failure of the rotation is logged, and p is written to the current log file.
+func (w *Writer) Write(p []byte) (int, error) {
+	if w.f == nil {
+		if err := w.open(); err != nil {
+			return 0, err
+		}
+	}
+	if w.MaxBytes > 0 && w.size > 0 && w.size+int64(len(p)) > w.MaxBytes {
+		if err := w.rotate(); err != nil {
+			log.L.WithError(err).Errorf("failed to rotate the log file %q", w.Path)
+			if w.f == nil {
+				if err := w.open(); err != nil {
+					return 0, err
+				}
+			}
+		}
+	}
+	n, err := w.f.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// Close closes the log file, and waits for the compression of the rotated file.
+func (w *Writer) Close() error {
+	err := w.closeFile()
+	w.waitCompression()
+	return err
+}
+
+func (w *Writer) closeFile() error {
+	if w.f == nil {
+		return nil
+	}
+	err := w.f.Close()
+	w.f = nil
+	return err
+}
+
+func (w *Writer) waitCompression() {
+	if w.compressed != nil {
+		<-w.compressed
+		w.compressed = nil
+	}
+}
+
+func (w *Writer) open() error {
+	f, err := os.OpenFile(w.Path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return err
+	}
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	w.f = f
+	w.size = st.Size()
+	return nil
+}
+
+func (w *Writer) rotate() error {
+	if err := w.closeFile(); err != nil {
+		return err
+	}
+	if w.MaxFiles > 1 {
+		// the rotated files must not be renamed while the last rotated file is being compressed
+		w.waitCompression()
+		if err := w.shift(); err != nil {
+			return err
+		}
+	} else if err := os.Remove(w.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return w.open()
+}
+
+// shift renames "<Path>.<N>" to "<Path>.<N+1>", and the current file to "<Path>.1".
+func (w *Writer) shift() error {
+	for i := w.MaxFiles - 1; i >= 1; i-- {
+		for _, suffix := range []string{"", ".gz"} {
+			name := w.Path + "." + strconv.Itoa(i) + suffix
+			var err error
+			if i == w.MaxFiles-1 {
+				err = os.Remove(name)
+			} else {
+				err = os.Rename(name, w.Path+"."+strconv.Itoa(i+1)+suffix)
+			}
+			if err != nil && !errors.Is(err, os.ErrNotExist) {
+				return err
+			}
+		}
+	}
+	rotated := w.Path + ".1"
+	if err := os.Rename(w.Path, rotated); err != nil {
+		return err
+	}
+	if w.Compress {
+		compressed := make(chan struct{})
+		w.compressed = compressed
+		go func() {
+			defer close(compressed)
+			if err := compressFile(rotated); err != nil {
+				// the uncompressed file is kept
+				log.L.WithError(err).Errorf("failed to compress the log file %q", rotated)
+			}
+		}()
+	}
+	return nil
+}
+
+// compressFile replaces the file with "<name>.gz".
+// The compressed file is written to a temporary file, and renamed to "<name>.gz" atomically,
+// so that the readers see either the uncompressed file or the complete compressed file.
+func compressFile(name string) error {
+	src, err := os.Open(name)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	tmp := name + ".gz.tmp"
+	dst, err := os.OpenFile(tmp, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	zw := gzip.NewWriter(dst)
+	_, err = io.Copy(zw, src)
+	if closeErr := zw.Close(); err == nil {
+		err = closeErr
+	}
+	if closeErr := dst.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmp, name+".gz")
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return fmt.Errorf("failed to compress %q: %w", name, err)
+	}
+	return os.Remove(name)
+}
+
+// Segments returns the rotated log files and the current log file, from the oldest to the newest.
+func Segments(path string) ([]string, error) {
+	matches, err := filepath.Glob(path + ".*")
+	if err != nil {
+		return nil, err
+	}
+	// "<path>.<N>" and "<path>.<N>.gz" coexist while the file is being compressed
+	byIndex := make(map[int]string, len(matches))
+	for _, m := range matches {
+		s, compressed := strings.CutSuffix(strings.TrimPrefix(m, path+"."), ".gz")
+		i, err := strconv.Atoi(s)
+		if err != nil || i < 1 {
+			continue
+		}
+		if _, ok := byIndex[i]; ok && compressed {
+			continue
+		}
+		byIndex[i] = m
+	}
+	indices := make([]int, 0, len(byIndex))
+	for i := range byIndex {
+		indices = append(indices, i)
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(indices)))
+	rotated := make([]string, 0, len(indices)+1)
+	for _, i := range indices {
+		rotated = append(rotated, byIndex[i])
+	}
+	return append(rotated, path), nil
+}
+
+// Segment is an opened log file.
+// The segment can be read even after the file is renamed or removed by the rotation.
+type Segment struct {
+	Name string
+	File *os.File
+}
+
+// Reader returns the reader of the whole segment, decompressing it if needed.
+func (s *Segment) Reader() (io.Reader, error) {
+	if _, err := s.File.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	if !strings.HasSuffix(s.Name, ".gz") {
+		return s.File, nil
+	}
+	return gzip.NewReader(s.File)
+}
+
+// openSegmentsAttempts is the number of the attempts of OpenSegments, when the log is rotated while being opened.
+const openSegmentsAttempts = 10
+
+// OpenSegments opens the rotated log files and the current log file, from the oldest to the newest.
+//
+// The log files are listed again when the log is rotated or the rotated file is compressed while opening them,
+// so that the segments are neither skipped nor duplicated.
+func OpenSegments(path string) ([]*Segment, error) {
+	var lastErr error
+	for i := 0; i < openSegmentsAttempts; i++ {
+		names, err := Segments(path)
+		if err != nil {
+			return nil, err
+		}
+		segs, err := openSegments(names)
+		if err == nil {
+			if err = checkSegments(path, segs); err == nil {
+				return segs, nil
+			}
+			CloseSegments(segs)
+		}
+		if !errors.Is(err, os.ErrNotExist) && !errors.Is(err, errSegmentsChanged) {
+			return nil, err
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+var errSegmentsChanged = errors.New("the log files were rotated while being opened")
+
+func openSegments(names []string) ([]*Segment, error) {
+	segs := make([]*Segment, 0, len(names))
+	for _, name := range names {
+		f, err := os.Open(name)
+		if err != nil {
+			CloseSegments(segs)
+			return nil, err
+		}
+		segs = append(segs, &Segment{Name: name, File: f})
+	}
+	return segs, nil
+}
+
+// checkSegments checks that the log files are still the same as the opened segments.
+func checkSegments(path string, segs []*Segment) error {
+	names, err := Segments(path)
+	if err != nil {
+		return err
+	}
+	if len(names) != len(segs) {
+		return errSegmentsChanged
+	}
+	for i, seg := range segs {
+		if names[i] != seg.Name {
+			return errSegmentsChanged
+		}
+		opened, err := seg.File.Stat()
+		if err != nil {
+			return err
+		}
+		current, err := os.Stat(seg.Name)
+		if err != nil {
+			return err
+		}
+		if !os.SameFile(opened, current) {
+			return errSegmentsChanged
+		}
+	}
+	return nil
+}
+
+// CloseSegments closes the segments.
+func CloseSegments(segs []*Segment) {
+	for _, seg := range segs {
+		seg.File.Close()
+	}
+}
@@ -44,6 +44,7 @@ func RegisterLogViewer(driverName string, lvfn LogViewerFunc) {
 
 func init() {
 	RegisterLogViewer("json-file", viewLogsJSONFile)
+	RegisterLogViewer("local", viewLogsLocalFile)
 	RegisterLogViewer("journald", viewLogsJournald)
 	RegisterLogViewer("cri", viewLogsCRI)
 }
@@ -147,7 +148,11 @@ func InitContainerLogViewer(containerLabels map[string]string, lvopts LogViewOpt
 func (lv *ContainerLogViewer) PrintLogsTo(stdout, stderr io.Writer) error {
 	viewerFunc, err := getLogViewer(lv.loggingConfig.Driver)
 	if err != nil {
-		return err
+		// Fall back to the local cache written along with the logging driver
+		if _, ok := drivers[lv.loggingConfig.Driver]; !ok || !cacheEnabled(lv.loggingConfig.Opts) {
+			return err
+		}
+		viewerFunc = viewLogsCache
 	}
 
 	return viewerFunc(lv.logViewingOptions, stdout, stderr, lv.stopChannel)
@@ -18,6 +18,7 @@ package logging
 
 import (
 	"bufio"
+	"bytes"
 	"context"
 	"encoding/json"
 	"errors"
@@ -32,6 +33,7 @@ import (
 	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
 	"github.com/containerd/errdefs"
 	"github.com/containerd/log"
+	"github.com/docker/cli/templates"
 	"github.com/fsnotify/fsnotify"
 	"github.com/muesli/cancelreader"
 )
@@ -59,6 +61,13 @@ var drivers = make(map[string]DriverFactory)
 var driversLogOptsValidateFunctions = make(map[string]LogOptsValidateFunc)
 
 func ValidateLogOpts(logDriver string, logOpts map[string]string) error {
+	if _, ok := drivers[logDriver]; ok && needsCache(logDriver) {
+		// The cache options are common to the drivers that are not readable by `nerdctl logs`
+		var err error
+		if logOpts, err = splitCacheLogOpts(logOpts); err != nil {
+			return err
+		}
+	}
 	if value, ok := driversLogOptsValidateFunctions[logDriver]; ok && value != nil {
 		return value(logOpts)
 	}
@@ -84,13 +93,23 @@ func GetDriver(name string, opts map[string]string) (Driver, error) {
 	if !ok {
 		return nil, fmt.Errorf("unknown logging driver %q: %w", name, errdefs.ErrNotFound)
 	}
-	return driverFactory(opts)
+	driver, err := driverFactory(opts)
+	if err != nil {
+		return nil, err
+	}
+	if needsCache(name) && cacheEnabled(opts) {
+		driver = &dualLogger{Driver: driver, cache: newCacheLogger(opts)}
+	}
+	return driver, nil
 }
 
 func init() {
 	RegisterDriver("json-file", func(opts map[string]string) (Driver, error) {
 		return &JSONLogger{Opts: opts}, nil
 	}, JSONFileLogOptsValidate)
+	RegisterDriver("local", func(opts map[string]string) (Driver, error) {
+		return &LocalLogger{Opts: opts}, nil
+	}, LocalLogOptsValidate)
 	RegisterDriver("journald", func(opts map[string]string) (Driver, error) {
 		return &JournaldLogger{Opts: opts}, nil
 	}, JournalLogOptsValidate)
@@ -100,6 +119,42 @@ func init() {
 	RegisterDriver("syslog", func(opts map[string]string) (Driver, error) {
 		return &SyslogLogger{Opts: opts}, nil
 	}, SyslogOptsValidate)
+	RegisterDriver("gelf", func(opts map[string]string) (Driver, error) {
+		return &GelfLogger{Opts: opts}, nil
+	}, GelfLogOptsValidate)
+	RegisterDriver("splunk", func(opts map[string]string) (Driver, error) {
+		return &SplunkLogger{Opts: opts}, nil
+	}, SplunkLogOptsValidate)
+}
+
+type identifier struct {
+	ID        string
+	FullID    string
+	Namespace string
+}
+
+// parseTag returns the tag of the logs, executing the template of the "tag" option.
+// The default tag is the short ID of the container.
+func parseTag(opts map[string]string, config *logging.Config) (string, error) {
+	shortID := config.ID[:12]
+	tagTemplate, ok := opts[Tag]
+	if !ok {
+		return shortID, nil
+	}
+	tmpl, err := templates.Parse(tagTemplate)
+	if err != nil {
+		return "", err
+	}
+	idn := identifier{
+		ID:        shortID,
+		FullID:    config.ID,
+		Namespace: config.Namespace,
+	}
+	var b bytes.Buffer
+	if err := tmpl.Execute(&b, idn); err != nil {
+		return "", err
+	}
+	return b.String(), nil
 }
 
 // Main is the entrypoint for the containerd runtime v2 logging plugin mode.
@@ -0,0 +1,440 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+	"github.com/containerd/log"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
+)
+
+const (
+	splunkURL                = "splunk-url"
+	splunkToken              = "splunk-token"
+	splunkSource             = "splunk-source"
+	splunkSourceType         = "splunk-sourcetype"
+	splunkIndex              = "splunk-index"
+	splunkFormat             = "splunk-format"
+	splunkCAPath             = "splunk-capath"
+	splunkCAName             = "splunk-caname"
+	splunkInsecureSkipVerify = "splunk-insecureskipverify"
+	splunkVerifyConnection   = "splunk-verify-connection"
+	splunkGzip               = "splunk-gzip"
+	splunkGzipLevel          = "splunk-gzip-level"
+	splunkBatchSize          = "splunk-batch-size"
+	splunkFlushInterval      = "splunk-flush-interval"
+	splunkMaxRetries         = "splunk-max-retries"
+	splunkRetryWait          = "splunk-retry-wait"
+)
+
+var SplunkLogOpts = []string{
+	splunkURL,
+	splunkToken,
+	splunkSource,
+	splunkSourceType,
+	splunkIndex,
+	splunkFormat,
+	splunkCAPath,
+	splunkCAName,
+	splunkInsecureSkipVerify,
+	splunkVerifyConnection,
+	splunkGzip,
+	splunkGzipLevel,
+	splunkBatchSize,
+	splunkFlushInterval,
+	splunkMaxRetries,
+	splunkRetryWait,
+	Tag,
+}
+
+const (
+	splunkFormatInline = "inline"
+	splunkFormatJSON   = "json"
+	splunkFormatRaw    = "raw"
+
+	// The defaults of the batching correspond to Docker
+	splunkDefaultBatchSize     = 1000
+	splunkDefaultFlushInterval = 5 * time.Second
+	splunkDefaultMaxRetries    = 3
+	splunkDefaultRetryWait     = 1 * time.Second
+	// splunkBufferBatches is the number of the batches buffered while the requests are in flight.
+	// The logs are dropped when the buffer is full, so that a stalled endpoint does not block the container.
+	splunkBufferBatches = 10
+	// splunkTimeout is the timeout of a request, so that a stalled endpoint does not block the worker forever
+	splunkTimeout = 30 * time.Second
+
+	splunkEventPath = "/services/collector/event/1.0"
+)
+
+func SplunkLogOptsValidate(logOptMap map[string]string) error {
+	for key := range logOptMap {
+		if !strutil.InStringSlice(SplunkLogOpts, key) {
+			log.L.Warnf("log-opt %s is ignored for splunk log driver", key)
+		}
+	}
+	_, err := parseSplunkConfig(logOptMap)
+	return err
+}
+
+type splunkConfig struct {
+	url              string
+	token            string
+	source           string
+	sourceType       string
+	index            string
+	format           string
+	verifyConnection bool
+	gzip             bool
+	gzipLevel        int
+	batchSize        int
+	flushInterval    time.Duration
+	maxRetries       int
+	retryWait        time.Duration
+	tlsConfig        *tls.Config
+}
+
+func parseSplunkConfig(opts map[string]string) (*splunkConfig, error) {
+	cfg := &splunkConfig{
+		source:           opts[splunkSource],
+		sourceType:       opts[splunkSourceType],
+		index:            opts[splunkIndex],
+		format:           splunkFormatInline,
+		verifyConnection: true,
+		gzipLevel:        gzip.DefaultCompression,
+		batchSize:        splunkDefaultBatchSize,
+		flushInterval:    splunkDefaultFlushInterval,
+		maxRetries:       splunkDefaultMaxRetries,
+		retryWait:        splunkDefaultRetryWait,
+		tlsConfig:        &tls.Config{},
+	}
+	u, err := url.Parse(opts[splunkURL])
+	if err != nil || opts[splunkURL] == "" {
+		return nil, fmt.Errorf("%s is required for splunk log driver, in form scheme://host[:port]", splunkURL)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+		return nil, fmt.Errorf("%s should be in form scheme://host[:port], got %q", splunkURL, opts[splunkURL])
+	}
+	cfg.url = u.Scheme + "://" + u.Host
+	if cfg.token = opts[splunkToken]; cfg.token == "" {
+		return nil, fmt.Errorf("%s is required for splunk log driver", splunkToken)
+	}
+	if v, ok := opts[splunkFormat]; ok {
+		switch v {
+		case splunkFormatInline, splunkFormatJSON, splunkFormatRaw:
+			cfg.format = v
+		default:
+			return nil, fmt.Errorf("unknown %s %q, must be one of inline, json, or raw", splunkFormat, v)
+		}
+	}
+	for key, dst := range map[string]*bool{
+		splunkVerifyConnection:   &cfg.verifyConnection,
+		splunkGzip:               &cfg.gzip,
+		splunkInsecureSkipVerify: &cfg.tlsConfig.InsecureSkipVerify,
+	} {
+		if v, ok := opts[key]; ok {
+			if *dst, err = strconv.ParseBool(v); err != nil {
+				return nil, fmt.Errorf("invalid %s %q", key, v)
+			}
+		}
+	}
+	for key, dst := range map[string]*int{
+		splunkGzipLevel:  &cfg.gzipLevel,
+		splunkBatchSize:  &cfg.batchSize,
+		splunkMaxRetries: &cfg.maxRetries,
+	} {
+		if v, ok := opts[key]; ok {
+			if *dst, err = strconv.Atoi(v); err != nil {
+				return nil, fmt.Errorf("invalid %s %q", key, v)
+			}
+		}
+	}
+	if cfg.gzipLevel < gzip.DefaultCompression || cfg.gzipLevel > gzip.BestCompression {
+		return nil, fmt.Errorf("%s must be from -1 to 9", splunkGzipLevel)
+	}
+	if cfg.batchSize < 1 {
+		return nil, fmt.Errorf("%s must be a positive number", splunkBatchSize)
+	}
+	if cfg.maxRetries < 0 {
+		return nil, fmt.Errorf("%s cannot be negative", splunkMaxRetries)
+	}
+	for key, dst := range map[string]*time.Duration{
+		splunkFlushInterval: &cfg.flushInterval,
+		splunkRetryWait:     &cfg.retryWait,
+	} {
+		if v, ok := opts[key]; ok {
+			if *dst, err = time.ParseDuration(v); err != nil || *dst <= 0 {
+				return nil, fmt.Errorf("invalid %s %q, must be a positive duration", key, v)
+			}
+		}
+	}
+	if caPath, ok := opts[splunkCAPath]; ok {
+		pem, err := os.ReadFile(caPath)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s: %w", splunkCAPath, err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in %s %q", splunkCAPath, caPath)
+		}
+		cfg.tlsConfig.RootCAs = pool
+	}
+	cfg.tlsConfig.ServerName = opts[splunkCAName]
+	return cfg, nil
+}
+
+// splunkMessage is an event of the Splunk HTTP Event Collector.
+type splunkMessage struct {
+	Event      interface{} `json:"event"`
+	Time       string      `json:"time"`
+	Host       string      `json:"host"`
+	Source     string      `json:"source,omitempty"`
+	SourceType string      `json:"sourcetype,omitempty"`
+	Index      string      `json:"index,omitempty"`
+}
+
+// splunkEvent is the event of the "inline" and "json" formats.
+type splunkEvent struct {
+	Line   interface{} `json:"line"`
+	Source string      `json:"source"`
+	Tag    string      `json:"tag,omitempty"`
+}
+
+type SplunkLogger struct {
+	Opts     map[string]string
+	cfg      *splunkConfig
+	client   *http.Client
+	host     string
+	tag      string
+	messages chan *splunkMessage
+	done     chan struct{}
+	dropped  atomic.Int64
+}
+
+func (s *SplunkLogger) Init(dataStore, ns, id string) error {
+	return nil
+}
+
+func (s *SplunkLogger) PreProcess(_ string, config *logging.Config) error {
+	cfg, err := parseSplunkConfig(s.Opts)
+	if err != nil {
+		return err
+	}
+	tag, err := parseTag(s.Opts, config)
+	if err != nil {
+		return err
+	}
+	s.cfg = cfg
+	s.tag = tag
+	s.host, _ = os.Hostname()
+	s.client = &http.Client{
+		Transport: &http.Transport{
+			Proxy:           http.ProxyFromEnvironment,
+			TLSClientConfig: cfg.tlsConfig,
+		},
+		Timeout: splunkTimeout,
+	}
+	if cfg.verifyConnection {
+		if err := s.verifyConnection(); err != nil {
+			return err
+		}
+	}
+	s.messages = make(chan *splunkMessage, splunkBufferBatches*cfg.batchSize)
+	s.done = make(chan struct{})
+	go s.worker()
+	return nil
+}
+
+func (s *SplunkLogger) Process(stdout <-chan string, stderr <-chan string) error {
+	var wg sync.WaitGroup
+	wg.Add(2)
+	fn := func(dataChan <-chan string, source string) {
+		defer wg.Done()
+		for line := range dataChan {
+			select {
+			case s.messages <- s.newMessage(line, source):
+			default:
+				if s.dropped.Add(1) == 1 {
+					log.L.Warn("the buffer of the splunk logs is full, dropping logs")
+				}
+			}
+		}
+	}
+	go fn(stdout, "stdout")
+	go fn(stderr, "stderr")
+	wg.Wait()
+	return nil
+}
+
+func (s *SplunkLogger) PostProcess() error {
+	close(s.messages)
+	<-s.done
+	return nil
+}
+
+func (s *SplunkLogger) newMessage(line, source string) *splunkMessage {
+	now := time.Now()
+	msg := &splunkMessage{
+		Time:       fmt.Sprintf("%d.%06d", now.Unix(), now.Nanosecond()/int(time.Microsecond)),
+		Host:       s.host,
+		Source:     s.cfg.source,
+		SourceType: s.cfg.sourceType,
+		Index:      s.cfg.index,
+	}
+	switch s.cfg.format {
+	case splunkFormatRaw:
+		if s.tag != "" {
+			line = s.tag + " " + line
+		}
+		msg.Event = line
+	case splunkFormatJSON:
+		var v json.RawMessage
+		if err := json.Unmarshal([]byte(line), &v); err == nil {
+			msg.Event = &splunkEvent{Line: v, Source: source, Tag: s.tag}
+			break
+		}
+		msg.Event = &splunkEvent{Line: line, Source: source, Tag: s.tag}
+	default:
+		msg.Event = &splunkEvent{Line: line, Source: source, Tag: s.tag}
+	}
+	return msg
+}
+
+// worker posts the messages in batches, when the batch is full or on every flush interval.
+func (s *SplunkLogger) worker() {
+	defer close(s.done)
+	ticker := time.NewTicker(s.cfg.flushInterval)
+	defer ticker.Stop()
+	batch := make([]*splunkMessage, 0, s.cfg.batchSize)
+	flush := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := s.postMessages(batch); err != nil {
+			log.L.WithError(err).Errorf("failed to send %d logs to splunk", len(batch))
+		}
+		batch = batch[:0]
+		if n := s.dropped.Swap(0); n > 0 {
+			log.L.Warnf("dropped %d logs, as the buffer of the splunk logs was full", n)
+		}
+	}
+	for {
+		select {
+		case msg, ok := <-s.messages:
+			if !ok {
+				flush()
+				return
+			}
+			batch = append(batch, msg)
+			if len(batch) >= s.cfg.batchSize {
+				flush()
+			}
+		case <-ticker.C:
+			flush()
+		}
+	}
+}
+
+// postMessages posts the messages, retrying up to the max retries.
+func (s *SplunkLogger) postMessages(messages []*splunkMessage) error {
+	var buf bytes.Buffer
+	var w io.Writer = &buf
+	var gzw *gzip.Writer
+	if s.cfg.gzip {
+		var err error
+		if gzw, err = gzip.NewWriterLevel(&buf, s.cfg.gzipLevel); err != nil {
+			return err
+		}
+		w = gzw
+	}
+	enc := json.NewEncoder(w)
+	for _, msg := range messages {
+		if err := enc.Encode(msg); err != nil {
+			return err
+		}
+	}
+	if gzw != nil {
+		if err := gzw.Close(); err != nil {
+			return err
+		}
+	}
+	body := buf.Bytes()
+	var err error
+	for i := 0; i <= s.cfg.maxRetries; i++ {
+		if i > 0 {
+			time.Sleep(s.cfg.retryWait)
+		}
+		if err = s.post(body); err == nil {
+			return nil
+		}
+		log.L.WithError(err).Debugf("failed to send logs to splunk (attempt %d)", i+1)
+	}
+	return err
+}
+
+func (s *SplunkLogger) post(body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, s.cfg.url+splunkEventPath, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Splunk "+s.cfg.token)
+	if s.cfg.gzip {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	return s.do(req)
+}
+
+func (s *SplunkLogger) verifyConnection() error {
+	req, err := http.NewRequest(http.MethodOptions, s.cfg.url+splunkEventPath, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Splunk "+s.cfg.token)
+	if err := s.do(req); err != nil {
+		return fmt.Errorf("failed to verify the connection to splunk: %w", err)
+	}
+	return nil
+}
+
+func (s *SplunkLogger) do(req *http.Request) error {
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		b, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return errors.New(resp.Status + ": " + string(bytes.TrimSpace(b)))
+	}
+	_, err = io.Copy(io.Discard, resp.Body)
+	return err
+}
@@ -0,0 +1,198 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package logging
+
+import (
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/containerd/v2/core/runtime/v2/logging"
+)
+
+// splunkServer is a fake HTTP Event Collector that fails the first `failures` requests.
+type splunkServer struct {
+	mu       sync.Mutex
+	failures int
+	posts    int
+	batches  [][]splunkMessage
+}
+
+func (s *splunkServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Header.Get("Authorization") != "Splunk test-token" {
+		w.WriteHeader(http.StatusUnauthorized)
+		return
+	}
+	if r.URL.Path != splunkEventPath {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if r.Method == http.MethodOptions {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.posts++
+	if s.failures > 0 {
+		s.failures--
+		w.WriteHeader(http.StatusServiceUnavailable)
+		return
+	}
+	var body io.Reader = r.Body
+	if r.Header.Get("Content-Encoding") == "gzip" {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		body = zr
+	}
+	var batch []splunkMessage
+	dec := json.NewDecoder(body)
+	for dec.More() {
+		var msg splunkMessage
+		if err := dec.Decode(&msg); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		batch = append(batch, msg)
+	}
+	s.batches = append(s.batches, batch)
+}
+
+func processSplunkLogs(t *testing.T, opts map[string]string, lines ...string) {
+	t.Helper()
+	driver := &SplunkLogger{Opts: opts}
+	assert.NilError(t, driver.PreProcess("", &logging.Config{Namespace: "default", ID: "0123456789abcdef"}))
+	stdout := make(chan string, len(lines))
+	stderr := make(chan string)
+	for _, line := range lines {
+		stdout <- line
+	}
+	close(stdout)
+	close(stderr)
+	assert.NilError(t, driver.Process(stdout, stderr))
+	assert.NilError(t, driver.PostProcess())
+}
+
+func TestSplunkBatchAndRetry(t *testing.T) {
+	s := &splunkServer{failures: 1}
+	srv := httptest.NewServer(s)
+	defer srv.Close()
+
+	processSplunkLogs(t, map[string]string{
+		splunkURL:       srv.URL,
+		splunkToken:     "test-token",
+		splunkIndex:     "main",
+		splunkGzip:      "true",
+		splunkBatchSize: "2",
+		splunkRetryWait: "10ms",
+	}, "line1", "line2", "line3")
+
+	// The first batch is retried once
+	assert.Equal(t, s.posts, 3)
+	assert.Equal(t, len(s.batches), 2)
+	assert.Equal(t, len(s.batches[0]), 2)
+	assert.Equal(t, len(s.batches[1]), 1)
+	msg := s.batches[1][0]
+	assert.Equal(t, msg.Index, "main")
+	event := msg.Event.(map[string]interface{})
+	assert.Equal(t, event["line"], "line3")
+	assert.Equal(t, event["source"], "stdout")
+	assert.Equal(t, event["tag"], "0123456789ab")
+}
+
+func TestSplunkFormat(t *testing.T) {
+	testCases := []struct {
+		format   string
+		line     string
+		expected interface{}
+	}{
+		{format: "inline", line: `{"a":1}`, expected: map[string]interface{}{"line": `{"a":1}`, "source": "stdout", "tag": "tag"}},
+		{format: "json", line: `{"a":1}`, expected: map[string]interface{}{"line": map[string]interface{}{"a": float64(1)}, "source": "stdout", "tag": "tag"}},
+		{format: "json", line: "not json", expected: map[string]interface{}{"line": "not json", "source": "stdout", "tag": "tag"}},
+		{format: "raw", line: "hello", expected: "tag hello"},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.format, func(t *testing.T) {
+			s := &splunkServer{}
+			srv := httptest.NewServer(s)
+			defer srv.Close()
+			processSplunkLogs(t, map[string]string{
+				splunkURL:    srv.URL,
+				splunkToken:  "test-token",
+				splunkFormat: tc.format,
+				Tag:          "tag",
+			}, tc.line)
+			assert.Equal(t, len(s.batches), 1)
+			assert.DeepEqual(t, s.batches[0][0].Event, tc.expected)
+		})
+	}
+}
+
+func TestSplunkVerifyConnection(t *testing.T) {
+	srv := httptest.NewServer(&splunkServer{})
+	defer srv.Close()
+	driver := &SplunkLogger{Opts: map[string]string{
+		splunkURL:   srv.URL,
+		splunkToken: "wrong-token",
+	}}
+	err := driver.PreProcess("", &logging.Config{Namespace: "default", ID: "0123456789abcdef"})
+	assert.ErrorContains(t, err, "401")
+}
+
+func TestSplunkStalledEndpoint(t *testing.T) {
+	s := &splunkServer{}
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			<-release
+		}
+		s.ServeHTTP(w, r)
+	}))
+	defer srv.Close()
+
+	driver := &SplunkLogger{Opts: map[string]string{
+		splunkURL:       srv.URL,
+		splunkToken:     "test-token",
+		splunkBatchSize: "1",
+	}}
+	assert.NilError(t, driver.PreProcess("", &logging.Config{Namespace: "default", ID: "0123456789abcdef"}))
+	lines := 100
+	stdout := make(chan string, lines)
+	stderr := make(chan string)
+	for i := 0; i < lines; i++ {
+		stdout <- "line"
+	}
+	close(stdout)
+	close(stderr)
+	// Process does not block while the endpoint is stalled, and the overflowing logs are dropped
+	assert.NilError(t, driver.Process(stdout, stderr))
+	close(release)
+	assert.NilError(t, driver.PostProcess())
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	assert.Assert(t, len(s.batches) < lines)
+	assert.Assert(t, len(s.batches) >= splunkBufferBatches)
+}
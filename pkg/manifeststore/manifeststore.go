@@ -0,0 +1,180 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+// Package manifeststore stores the manifest lists created by `nerdctl manifest create`
+// until they are pushed.
+//
+// Each manifest list is a directory under "<dataStore>/manifests/<namespace>",
+// and each entry of the list is a JSON file in that directory.
+package manifeststore
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+
+	"github.com/containerd/errdefs"
+	"github.com/containerd/nerdctl/v2/pkg/lockutil"
+)
+
+// Entry is an image manifest in a manifest list.
+type Entry struct {
+	// Ref is the normalized reference of the image, e.g. "docker.io/library/alpine:3.19"
+	Ref string
+	// Descriptor is the descriptor of the manifest, including the platform
+	Descriptor ocispec.Descriptor
+	// Raw is the manifest blob
+	Raw []byte
+}
+
+func New(dataStore, ns string) (Store, error) {
+	dir := filepath.Join(dataStore, "manifests", ns)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, err
+	}
+	store := &manifestStore{
+		dir: dir,
+	}
+	return store, nil
+}
+
+// Store stores the manifest lists. The references must be normalized.
+type Store interface {
+	// Exists returns true if the manifest list exists.
+	Exists(listRef string) (bool, error)
+	// Get returns an entry of the manifest list, or an errdefs.ErrNotFound error.
+	Get(listRef, imageRef string) (*Entry, error)
+	// List returns the entries of the manifest list, or an errdefs.ErrNotFound error.
+	List(listRef string) ([]*Entry, error)
+	// Save adds or replaces an entry of the manifest list, creating the list if needed.
+	Save(listRef string, entry *Entry) error
+	// Remove removes the manifest list, or returns an errdefs.ErrNotFound error.
+	Remove(listRef string) error
+}
+
+type manifestStore struct {
+	dir string
+}
+
+var fileSafeReplacer = strings.NewReplacer(":", "-", "/", "_", "@", "_")
+
+// fileName converts the reference to a file name, like the `docker manifest` store does.
+func fileName(ref string) string {
+	return fileSafeReplacer.Replace(ref)
+}
+
+func (x *manifestStore) Exists(listRef string) (bool, error) {
+	_, err := os.Stat(filepath.Join(x.dir, fileName(listRef)))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+func (x *manifestStore) Get(listRef, imageRef string) (*Entry, error) {
+	var entry *Entry
+	fn := func() error {
+		var err error
+		entry, err = readEntry(filepath.Join(x.dir, fileName(listRef), fileName(imageRef)))
+		if errors.Is(err, os.ErrNotExist) {
+			return fmt.Errorf("manifest for image %s does not exist in %s: %w", imageRef, listRef, errdefs.ErrNotFound)
+		}
+		return err
+	}
+	if err := lockutil.WithDirLock(x.dir, fn); err != nil {
+		return nil, err
+	}
+	return entry, nil
+}
+
+func (x *manifestStore) List(listRef string) ([]*Entry, error) {
+	var entries []*Entry
+	fn := func() error {
+		listDir := filepath.Join(x.dir, fileName(listRef))
+		dirEntries, err := os.ReadDir(listDir)
+		if err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("no such manifest: %s: %w", listRef, errdefs.ErrNotFound)
+			}
+			return err
+		}
+		for _, dirEntry := range dirEntries {
+			entry, err := readEntry(filepath.Join(listDir, dirEntry.Name()))
+			if err != nil {
+				return err
+			}
+			entries = append(entries, entry)
+		}
+		return nil
+	}
+	if err := lockutil.WithDirLock(x.dir, fn); err != nil {
+		return nil, err
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Ref < entries[j].Ref
+	})
+	return entries, nil
+}
+
+func (x *manifestStore) Save(listRef string, entry *Entry) error {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	fn := func() error {
+		listDir := filepath.Join(x.dir, fileName(listRef))
+		if err := os.MkdirAll(listDir, 0700); err != nil {
+			return err
+		}
+		return os.WriteFile(filepath.Join(listDir, fileName(entry.Ref)), b, 0600)
+	}
+	return lockutil.WithDirLock(x.dir, fn)
+}
+
+func (x *manifestStore) Remove(listRef string) error {
+	fn := func() error {
+		listDir := filepath.Join(x.dir, fileName(listRef))
+		if _, err := os.Stat(listDir); err != nil {
+			if errors.Is(err, os.ErrNotExist) {
+				return fmt.Errorf("no such manifest: %s: %w", listRef, errdefs.ErrNotFound)
+			}
+			return err
+		}
+		return os.RemoveAll(listDir)
+	}
+	return lockutil.WithDirLock(x.dir, fn)
+}
+
+func readEntry(path string) (*Entry, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var entry Entry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return nil, fmt.Errorf("failed to parse %q: %w", path, err)
+	}
+	return &entry, nil
+}
@@ -0,0 +1,77 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package manifeststore
+
+import (
+	"testing"
+
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"gotest.tools/v3/assert"
+
+	"github.com/containerd/errdefs"
+)
+
+func TestStore(t *testing.T) {
+	store, err := New(t.TempDir(), "default")
+	assert.NilError(t, err)
+
+	const listRef = "example.com/foo/bar:latest"
+	exists, err := store.Exists(listRef)
+	assert.NilError(t, err)
+	assert.Assert(t, !exists)
+	_, err = store.List(listRef)
+	assert.Assert(t, errdefs.IsNotFound(err))
+
+	amd64 := &Entry{
+		Ref: "example.com/foo/bar:amd64",
+		Descriptor: ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Platform:  &ocispec.Platform{OS: "linux", Architecture: "amd64"},
+		},
+		Raw: []byte("{}"),
+	}
+	arm64 := &Entry{
+		Ref: "example.com/foo/bar@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+		Descriptor: ocispec.Descriptor{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Platform:  &ocispec.Platform{OS: "linux", Architecture: "arm64"},
+		},
+	}
+	assert.NilError(t, store.Save(listRef, arm64))
+	assert.NilError(t, store.Save(listRef, amd64))
+	exists, err = store.Exists(listRef)
+	assert.NilError(t, err)
+	assert.Assert(t, exists)
+
+	entries, err := store.List(listRef)
+	assert.NilError(t, err)
+	assert.Equal(t, len(entries), 2)
+	assert.Equal(t, entries[0].Ref, amd64.Ref)
+	assert.Equal(t, entries[1].Ref, arm64.Ref)
+
+	amd64.Descriptor.Platform.Variant = "v3"
+	assert.NilError(t, store.Save(listRef, amd64))
+	entry, err := store.Get(listRef, amd64.Ref)
+	assert.NilError(t, err)
+	assert.Equal(t, entry.Descriptor.Platform.Variant, "v3")
+	assert.DeepEqual(t, entry.Raw, amd64.Raw)
+	_, err = store.Get(listRef, "example.com/foo/bar:s390x")
+	assert.Assert(t, errdefs.IsNotFound(err))
+
+	assert.NilError(t, store.Remove(listRef))
+	assert.Assert(t, errdefs.IsNotFound(store.Remove(listRef)))
+}
@@ -49,6 +49,43 @@ type Processed struct {
 	AnonymousVolume string // anonymous volume name
 	Mode            string
 	Opts            []oci.SpecOpts
+	NoCopy          bool // do not copy the content of the image to the volume
+	VolumeSubpath   *VolumeSubpath
+}
+
+// VolumeSubpath is the subpath of a volume mounted with `--mount type=volume,volume-subpath=<SUBPATH>`.
+type VolumeSubpath struct {
+	// Volume is the path of the volume on the host
+	Volume string `json:"volume"`
+	// Subpath is the path relative to the volume
+	Subpath string `json:"subpath"`
+}
+
+// Resolve returns the path of the subpath on the host.
+// The subpath must exist in the volume, as in Docker, and must not contain symlinks.
+//
+// The volume is writable from the containers, so a container may replace the subpath with a symlink
+// to a path outside of the volume after the container is created.
+// Resolve has to be called again whenever the container starts.
+func (v VolumeSubpath) Resolve() (string, error) {
+	if filepath.IsAbs(v.Subpath) {
+		return "", fmt.Errorf("volume-subpath must be a relative path, got %q", v.Subpath)
+	}
+	if !filepath.IsLocal(v.Subpath) {
+		return "", fmt.Errorf("cannot access volume-subpath %q: the path is outside of the volume", v.Subpath)
+	}
+	p := filepath.Clean(v.Volume)
+	for _, elem := range strings.Split(filepath.Clean(v.Subpath), pathSeparator) {
+		p = filepath.Join(p, elem)
+		st, err := os.Lstat(p)
+		if err != nil {
+			return "", fmt.Errorf("cannot access volume-subpath %q: %w", v.Subpath, err)
+		}
+		if st.Mode()&os.ModeSymlink != 0 {
+			return "", fmt.Errorf("volume-subpath %q must not contain symlinks, got %q", v.Subpath, p)
+		}
+	}
+	return p, nil
 }
 
 type volumeSpec struct {
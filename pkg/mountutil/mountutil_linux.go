@@ -35,6 +35,7 @@ import (
 	"github.com/containerd/containerd/v2/pkg/oci"
 	"github.com/containerd/log"
 	"github.com/containerd/nerdctl/v2/pkg/mountutil/volumestore"
+	"github.com/containerd/nerdctl/v2/pkg/strutil"
 )
 
 /*
@@ -301,6 +302,8 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 		rwOption         string
 		tmpfsSize        int64
 		tmpfsMode        os.FileMode
+		volumeNoCopy     bool
+		volumeSubpath    string
 		err              error
 	)
 
@@ -311,7 +314,7 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 	// three types of mount(and examples):
 	// --mount type=bind,source="$(pwd)"/target,target=/app2,readonly,bind-propagation=shared
 	// --mount type=tmpfs,destination=/app,tmpfs-mode=1770,tmpfs-size=1MB
-	// --mount type=volume,src=vol-1,dst=/app,readonly
+	// --mount type=volume,src=vol-1,dst=/app,readonly,volume-nocopy,volume-subpath=dir
 	// if type not specified, default will be set to volume
 	// --mount src=`pwd`/tmp,target=/app
 
@@ -327,6 +330,9 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 			case "bind-nonrecursive":
 				bindNonRecursive = true
 				continue
+			case "volume-nocopy":
+				volumeNoCopy = true
+				continue
 			}
 		}
 
@@ -367,6 +373,20 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 			if err != nil {
 				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
 			}
+		case "volume-nocopy":
+			volumeNoCopy, err = strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+			}
+		case "volume-subpath":
+			volumeSubpath = value
+		case "consistency":
+			// consistency is only meaningful on Docker Desktop for Mac, and ignored as in Docker on Linux
+			switch value {
+			case "default", "consistent", "cached", "delegated":
+			default:
+				return nil, fmt.Errorf("invalid value for %s: %s", key, value)
+			}
 		case "tmpfs-size":
 			tmpfsSize, err = units.RAMInBytes(value)
 			if err != nil {
@@ -383,6 +403,13 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 		}
 	}
 
+	if mountType != Volume && (volumeNoCopy || volumeSubpath != "") {
+		return nil, fmt.Errorf("volume-nocopy and volume-subpath are only supported for volume mounts")
+	}
+	if volumeSubpath != "" && src == "" {
+		return nil, fmt.Errorf("volume-subpath is not supported for anonymous volumes")
+	}
+
 	// compose new fileds and join into a string
 	// to call legacy ProcessFlagTmpfs or ProcessFlagV function
 	fields = []string{}
@@ -429,12 +456,46 @@ func ProcessFlagMount(s string, volStore volumestore.VolumeStore) (*Processed, e
 	case Tmpfs:
 		return ProcessFlagTmpfs(fieldsStr)
 	case Volume, Bind:
+		if mountType == Volume && src == "" {
+			return processAnonymousVolumeMount(dst, options, volStore, volumeNoCopy)
+		}
 		// createDir=false for --mount option to disallow creating directories on host if not found
-		return ProcessFlagV(fieldsStr, volStore, false)
+		res, err := ProcessFlagV(fieldsStr, volStore, false)
+		if err != nil {
+			return nil, err
+		}
+		res.NoCopy = volumeNoCopy
+		if volumeSubpath != "" {
+			res.VolumeSubpath = &VolumeSubpath{Volume: res.Mount.Source, Subpath: volumeSubpath}
+			if res.Mount.Source, err = res.VolumeSubpath.Resolve(); err != nil {
+				return nil, err
+			}
+		}
+		return res, nil
 	}
 	return nil, fmt.Errorf("invalid mount type '%s' must be a volume/bind/tmpfs", mountType)
 }
 
+// processAnonymousVolumeMount processes `--mount type=volume,dst=<DST>` without the source.
+// ProcessFlagV does not take the options of anonymous volumes, so the options are parsed here.
+func processAnonymousVolumeMount(dst string, options []string, volStore volumestore.VolumeStore, noCopy bool) (*Processed, error) {
+	res, err := ProcessFlagV(dst, volStore, false)
+	if err != nil {
+		return nil, err
+	}
+	res.NoCopy = noCopy
+	if len(options) > 0 {
+		res.Mode = strings.Join(options, ",")
+		opts, specOpts, err := getVolumeOptions(res.Mount.Source, res.Type, res.Mode)
+		if err != nil {
+			return nil, err
+		}
+		res.Mount.Options = strutil.DedupeStrSlice(append(opts, res.Mount.Options...))
+		res.Opts = append(res.Opts, specOpts...)
+	}
+	return res, nil
+}
+
 // copy from https://github.com/moby/moby/blob/085c6a98d54720e70b28354ccec6da9b1b9e7fcf/volume/mounts/linux_parser.go#L375
 func getTmpfsSize(size int64) string {
 	// calculate suffix here, making this linux specific, but that is
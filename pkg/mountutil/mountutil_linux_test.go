@@ -18,6 +18,8 @@ package mountutil
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
@@ -378,3 +380,106 @@ func TestProcessFlagVAnonymousVolumes(t *testing.T) {
 		})
 	}
 }
+
+func TestProcessFlagMountVolumeOptions(t *testing.T) {
+	volumeDir := t.TempDir()
+	assert.NilError(t, os.Mkdir(filepath.Join(volumeDir, "sub"), 0755))
+	assert.NilError(t, os.Symlink("/", filepath.Join(volumeDir, "symlink")))
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockVolumeStore := mocks.NewMockVolumeStore(ctrl)
+	mockVolumeStore.
+		EXPECT().
+		Create(gomock.Any(), gomock.Any()).
+		Return(&native.Volume{Name: "test_volume", Mountpoint: volumeDir}, nil).
+		AnyTimes()
+
+	tests := []struct {
+		rawSpec     string
+		wantSource  string
+		wantNoCopy  bool
+		wantOptions []string
+		err         string
+	}{
+		{
+			rawSpec:    "type=volume,src=test_volume,dst=/mnt/foo,volume-nocopy",
+			wantSource: volumeDir,
+			wantNoCopy: true,
+		},
+		{
+			rawSpec:    "type=volume,src=test_volume,dst=/mnt/foo,volume-nocopy=false,consistency=cached",
+			wantSource: volumeDir,
+		},
+		{
+			rawSpec:    "type=volume,src=test_volume,dst=/mnt/foo,volume-subpath=sub",
+			wantSource: filepath.Join(volumeDir, "sub"),
+		},
+		{
+			rawSpec:     "type=volume,dst=/mnt/foo,readonly,volume-nocopy",
+			wantSource:  volumeDir,
+			wantNoCopy:  true,
+			wantOptions: []string{"ro", "rbind"},
+		},
+		{
+			rawSpec: "type=volume,src=test_volume,dst=/mnt/foo,volume-subpath=nonexistent",
+			err:     "cannot access volume-subpath",
+		},
+		{
+			rawSpec: "type=volume,src=test_volume,dst=/mnt/foo,volume-subpath=../escape",
+			err:     "cannot access volume-subpath",
+		},
+		{
+			rawSpec: "type=volume,src=test_volume,dst=/mnt/foo,volume-subpath=symlink",
+			err:     "must not contain symlinks",
+		},
+		{
+			rawSpec: "type=bind,src=/mnt,dst=/mnt/foo,volume-nocopy",
+			err:     "only supported for volume mounts",
+		},
+		{
+			rawSpec: "type=volume,src=test_volume,dst=/mnt/foo,consistency=invalid",
+			err:     "invalid value for consistency",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rawSpec, func(t *testing.T) {
+			x, err := ProcessFlagMount(tt.rawSpec, mockVolumeStore)
+			if tt.err != "" {
+				assert.ErrorContains(t, err, tt.err)
+				return
+			}
+			assert.NilError(t, err)
+			assert.Equal(t, x.Type, "volume")
+			assert.Equal(t, x.Mount.Source, tt.wantSource)
+			assert.Equal(t, x.NoCopy, tt.wantNoCopy)
+			if tt.wantOptions != nil {
+				assert.DeepEqual(t, x.Mount.Options, tt.wantOptions)
+			}
+		})
+	}
+}
+
+func TestVolumeSubpathResolve(t *testing.T) {
+	volumeDir := t.TempDir()
+	assert.NilError(t, os.MkdirAll(filepath.Join(volumeDir, "foo", "bar"), 0755))
+	v := VolumeSubpath{Volume: volumeDir, Subpath: "foo/bar"}
+	p, err := v.Resolve()
+	assert.NilError(t, err)
+	assert.Equal(t, p, filepath.Join(volumeDir, "foo", "bar"))
+
+	// The container replaces the subpath with a symlink after the container is created
+	assert.NilError(t, os.Remove(filepath.Join(volumeDir, "foo", "bar")))
+	assert.NilError(t, os.Symlink("/", filepath.Join(volumeDir, "foo", "bar")))
+	_, err = v.Resolve()
+	assert.ErrorContains(t, err, "must not contain symlinks")
+
+	// The parent directory is replaced with a symlink
+	assert.NilError(t, os.RemoveAll(filepath.Join(volumeDir, "foo")))
+	assert.NilError(t, os.MkdirAll(filepath.Join(volumeDir, "baz", "bar"), 0755))
+	assert.NilError(t, os.Symlink("baz", filepath.Join(volumeDir, "foo")))
+	_, err = v.Resolve()
+	assert.ErrorContains(t, err, "must not contain symlinks")
+}
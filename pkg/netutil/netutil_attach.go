@@ -0,0 +1,74 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package netutil
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/containernetworking/cni/libcni"
+	types100 "github.com/containernetworking/cni/pkg/types/100"
+)
+
+// InterfacePrefix is the prefix of the container interface names, as in go-cni.
+const InterfacePrefix = "eth"
+
+// AttachNetwork runs CNI ADD of a single network.
+// Unlike go-cni, the interface name is specified by the caller in rt, so that a network
+// can be attached to a running container without conflicting with the existing interfaces.
+func (e *CNIEnv) AttachNetwork(ctx context.Context, net *NetworkConfig, rt *libcni.RuntimeConf) (*types100.Result, error) {
+	cniConfig := libcni.NewCNIConfig([]string{e.Path}, nil)
+	res, err := cniConfig.AddNetworkList(ctx, net.NetworkConfigList, rt)
+	if err != nil {
+		return nil, err
+	}
+	return types100.NewResultFromResult(res)
+}
+
+// DetachNetwork runs CNI DEL of a single network.
+func (e *CNIEnv) DetachNetwork(ctx context.Context, net *NetworkConfig, rt *libcni.RuntimeConf) error {
+	cniConfig := libcni.NewCNIConfig([]string{e.Path}, nil)
+	return cniConfig.DelNetworkList(ctx, net.NetworkConfigList, rt)
+}
+
+// ContainerInterfaceName returns the name of the interface created in the container netns,
+// or an empty string if the CNI result does not contain such an interface.
+func ContainerInterfaceName(result *types100.Result) string {
+	if result == nil {
+		return ""
+	}
+	for _, iface := range result.Interfaces {
+		if iface.Sandbox != "" {
+			return iface.Name
+		}
+	}
+	return ""
+}
+
+// NextInterfaceName returns the first interface name ("eth0", "eth1", ...) that is not used in results.
+func NextInterfaceName(results map[string]*types100.Result) string {
+	used := make(map[string]struct{}, len(results))
+	for _, res := range results {
+		used[ContainerInterfaceName(res)] = struct{}{}
+	}
+	for i := 0; ; i++ {
+		ifName := fmt.Sprintf("%s%d", InterfacePrefix, i)
+		if _, ok := used[ifName]; !ok {
+			return ifName
+		}
+	}
+}
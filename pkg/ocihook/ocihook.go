@@ -28,6 +28,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/containernetworking/cni/libcni"
 	types100 "github.com/containernetworking/cni/pkg/types/100"
 	"github.com/opencontainers/runtime-spec/specs-go"
 	b4nndclient "github.com/rootless-containers/bypass4netns/pkg/api/daemon/client"
@@ -37,7 +38,9 @@ import (
 	"github.com/containerd/log"
 	"github.com/containerd/nerdctl/v2/pkg/bypass4netnsutil"
 	"github.com/containerd/nerdctl/v2/pkg/dnsutil/hostsstore"
+	"github.com/containerd/nerdctl/v2/pkg/healthcheck"
 	"github.com/containerd/nerdctl/v2/pkg/labels"
+	"github.com/containerd/nerdctl/v2/pkg/mountutil"
 	"github.com/containerd/nerdctl/v2/pkg/namestore"
 	"github.com/containerd/nerdctl/v2/pkg/netutil"
 	"github.com/containerd/nerdctl/v2/pkg/netutil/nettype"
@@ -58,7 +61,7 @@ const (
 	NetworkNamespace = labels.Prefix + "network-namespace"
 )
 
-func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetconfPath string) error {
+func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetconfPath, address string) error {
 	if stdin == nil || event == "" || dataStore == "" || cniPath == "" || cniNetconfPath == "" {
 		return errors.New("got insufficient args")
 	}
@@ -90,10 +93,11 @@ func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetcon
 		}
 	}()
 
-	opts, err := newHandlerOpts(&state, dataStore, cniPath, cniNetconfPath)
+	opts, err := newHandlerOpts(&state, event, dataStore, cniPath, cniNetconfPath)
 	if err != nil {
 		return err
 	}
+	opts.address = address
 
 	switch event {
 	case "createRuntime":
@@ -107,7 +111,7 @@ func Run(stdin io.Reader, stderr io.Writer, event, dataStore, cniPath, cniNetcon
 	}
 }
 
-func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath string) (*handlerOpts, error) {
+func newHandlerOpts(state *specs.State, event, dataStore, cniPath, cniNetconfPath string) (*handlerOpts, error) {
 	o := &handlerOpts{
 		state:     state,
 		dataStore: dataStore,
@@ -156,16 +160,24 @@ func newHandlerOpts(state *specs.State, dataStore, cniPath, cniNetconfPath strin
 		if err != nil {
 			return nil, err
 		}
+		o.cniEnv = e
 		cniOpts := []gocni.Opt{
 			gocni.WithPluginDir([]string{cniPath}),
 		}
-		netMap, err := e.NetworkMap()
+		o.netMap, err = e.NetworkMap()
 		if err != nil {
 			return nil, err
 		}
 		for _, netstr := range networks {
-			net, ok := netMap[netstr]
+			net, ok := o.netMap[netstr]
 			if !ok {
+				if event == "postStop" {
+					// The network may have been disconnected with `nerdctl network disconnect`
+					// and removed while the container was running.
+					// onPostStop tears down the networks recorded in the hosts store in that case.
+					log.L.Warnf("no such network: %q", netstr)
+					continue
+				}
 				return nil, fmt.Errorf("no such network: %q", netstr)
 			}
 			cniOpts = append(cniOpts, gocni.WithConfListBytes(net.Bytes))
@@ -236,6 +248,8 @@ type handlerOpts struct {
 	ports             []gocni.PortMapping
 	cni               gocni.CNI
 	cniNames          []string
+	cniEnv            *netutil.CNIEnv
+	netMap            map[string]*netutil.NetworkConfig
 	fullID            string
 	rootlessKitClient rlkclient.Client
 	bypassClient      b4nndclient.Client
@@ -243,6 +257,7 @@ type handlerOpts struct {
 	containerIP       string
 	containerMAC      string
 	containerIP6      string
+	address           string
 }
 
 // hookSpec is from https://github.com/containerd/containerd/blob/v1.4.3/cmd/containerd/command/oci-hook.go#L59-L64
@@ -306,9 +321,21 @@ func getNetNSPath(state *specs.State) (string, error) {
 }
 
 func getPortMapOpts(opts *handlerOpts) ([]gocni.NamespaceOpts, error) {
+	ports, err := getPortMappings(opts)
+	if err != nil {
+		return nil, err
+	}
+	if len(ports) > 0 {
+		return []gocni.NamespaceOpts{gocni.WithCapabilityPortMap(ports)}, nil
+	}
+	return nil, nil
+}
+
+// getPortMappings returns the port mappings to be passed to the CNI plugins.
+func getPortMappings(opts *handlerOpts) ([]gocni.PortMapping, error) {
 	if len(opts.ports) > 0 {
 		if !rootlessutil.IsRootlessChild() {
-			return []gocni.NamespaceOpts{gocni.WithCapabilityPortMap(opts.ports)}, nil
+			return opts.ports, nil
 		}
 		var (
 			childIP                            net.IP
@@ -344,7 +371,7 @@ func getPortMapOpts(opts *handlerOpts) ([]gocni.NamespaceOpts, error) {
 			}
 			ports[i] = p
 		}
-		return []gocni.NamespaceOpts{gocni.WithCapabilityPortMap(ports)}, nil
+		return ports, nil
 	}
 	return nil, nil
 }
@@ -485,12 +512,36 @@ func applyNetworkSettings(opts *handlerOpts) error {
 func onCreateRuntime(opts *handlerOpts) error {
 	loadAppArmor()
 
+	if err := checkVolumeSubpaths(opts.state); err != nil {
+		return err
+	}
+
 	if opts.cni != nil {
 		return applyNetworkSettings(opts)
 	}
 	return nil
 }
 
+// checkVolumeSubpaths resolves the volume subpaths again, as the container may have replaced them with symlinks
+// after they were resolved on creating the container.
+// The hook fails so that the container does not start with a bind mount of the path outside of the volume.
+func checkVolumeSubpaths(state *specs.State) error {
+	subpathsJSON := state.Annotations[labels.VolumeSubpaths]
+	if subpathsJSON == "" {
+		return nil
+	}
+	var subpaths []mountutil.VolumeSubpath
+	if err := json.Unmarshal([]byte(subpathsJSON), &subpaths); err != nil {
+		return err
+	}
+	for _, subpath := range subpaths {
+		if _, err := subpath.Resolve(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 func onStartContainer(opts *handlerOpts) error {
 	name := opts.state.Annotations[labels.Name]
 	ns := opts.state.Annotations[labels.Namespace]
@@ -509,14 +560,27 @@ func onStartContainer(opts *handlerOpts) error {
 
 	// Set StartedAt
 	lf := state.NewLifecycleState(opts.state.Annotations[labels.StateDir])
-	return lf.WithLock(func() error {
+	if err := lf.WithLock(func() error {
 		err := lf.Load()
 		if err != nil {
 			return err
 		}
 		lf.StartedAt = time.Now()
 		return lf.Save()
-	})
+	}); err != nil {
+		return err
+	}
+
+	// Spawn the healthcheck monitor, as the task may have been started by the restart manager of containerd,
+	// e.g., after a reboot of the host
+	if hc, err := healthcheck.FromLabels(opts.state.Annotations); err != nil {
+		log.L.WithError(err).Warn("failed to parse the healthcheck of the container")
+	} else if !hc.Disabled() {
+		if err := healthcheck.SpawnMonitor(context.Background(), opts.address, ns, opts.state.ID); err != nil {
+			log.L.WithError(err).Warn("failed to spawn the healthcheck monitor")
+		}
+	}
+	return nil
 }
 
 func onPostStop(opts *handlerOpts) error {
@@ -566,14 +630,24 @@ func onPostStop(opts *handlerOpts) error {
 		namespaceOpts = append(namespaceOpts, ipAddressOpts...)
 		namespaceOpts = append(namespaceOpts, macAddressOpts...)
 		namespaceOpts = append(namespaceOpts, ip6AddressOpts...)
-		if err := opts.cni.Remove(ctx, opts.fullID, "", namespaceOpts...); err != nil {
-			log.L.WithError(err).Errorf("failed to call cni.Remove")
-			return err
-		}
 		hs, err := hostsstore.NewStore(opts.dataStore)
 		if err != nil {
 			return err
 		}
+		hsMeta, err := hs.Load(ns, opts.state.ID)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if hsMeta != nil && !networksAttachedByHook(opts.cniNames, hsMeta) {
+			// The networks were modified with `nerdctl network (connect|disconnect)` while the container was running.
+			if err := removeAttachedNetworks(ctx, opts, hsMeta); err != nil {
+				log.L.WithError(err).Errorf("failed to remove the attached networks")
+				return err
+			}
+		} else if err := opts.cni.Remove(ctx, opts.fullID, "", namespaceOpts...); err != nil {
+			log.L.WithError(err).Errorf("failed to call cni.Remove")
+			return err
+		}
 		if err := hs.Release(ns, opts.state.ID); err != nil {
 			return err
 		}
@@ -589,6 +663,60 @@ func onPostStop(opts *handlerOpts) error {
 	return nil
 }
 
+// networksAttachedByHook returns true if the networks recorded in the hosts store are exactly
+// the networks attached by applyNetworkSettings, with the interface names assigned by go-cni.
+func networksAttachedByHook(cniNames []string, hsMeta *hostsstore.Meta) bool {
+	if len(cniNames) != len(hsMeta.Networks) {
+		return false
+	}
+	for i, cniName := range cniNames {
+		res, ok := hsMeta.Networks[cniName]
+		if !ok {
+			return false
+		}
+		if ifName := netutil.ContainerInterfaceName(res); ifName != "" && ifName != fmt.Sprintf("%s%d", netutil.InterfacePrefix, i) {
+			return false
+		}
+	}
+	return true
+}
+
+// removeAttachedNetworks removes the networks recorded in the hosts store, using the recorded interface names.
+func removeAttachedNetworks(ctx context.Context, opts *handlerOpts, hsMeta *hostsstore.Meta) error {
+	ports, err := getPortMappings(opts)
+	if err != nil {
+		return err
+	}
+	for cniName, res := range hsMeta.Networks {
+		net, ok := opts.netMap[cniName]
+		if !ok {
+			log.L.Warnf("no such network: %q", cniName)
+			continue
+		}
+		ifName := netutil.ContainerInterfaceName(res)
+		if ifName == "" {
+			log.L.Warnf("unknown interface name for network %q", cniName)
+			continue
+		}
+		rt := &libcni.RuntimeConf{
+			ContainerID: opts.fullID,
+			IfName:      ifName,
+			Args:        [][2]string{{"IgnoreUnknown", "1"}},
+		}
+		if len(ports) > 0 {
+			rt.CapabilityArgs = map[string]interface{}{"portMappings": ports}
+		}
+		if err := opts.cniEnv.DetachNetwork(ctx, net, rt); err != nil {
+			// Same as go-cni, ignore the errors for the already removed resources
+			if strings.Contains(err.Error(), "no such file or directory") || strings.Contains(err.Error(), "not found") {
+				continue
+			}
+			return err
+		}
+	}
+	return nil
+}
+
 // writePidFile writes the pid atomically to a file.
 // From https://github.com/containerd/containerd/blob/v1.7.0-rc.2/cmd/ctr/commands/commands.go#L265-L282
 func writePidFile(path string, pid int) error {
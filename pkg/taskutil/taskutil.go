@@ -42,7 +42,8 @@ import (
 
 // NewTask is from https://github.com/containerd/containerd/blob/v1.4.3/cmd/ctr/commands/tasks/tasks_unix.go#L70-L108
 func NewTask(ctx context.Context, client *containerd.Client, container containerd.Container,
-	attachStreamOpt []string, flagI, flagT, flagD bool, con console.Console, logURI, detachKeys, namespace string, detachC chan<- struct{}) (containerd.Task, error) {
+	attachStreamOpt []string, flagI, flagT, flagD bool, con console.Console, logURI, detachKeys, namespace string, detachC chan<- struct{},
+	taskOpts ...containerd.NewTaskOpts) (containerd.Task, error) {
 
 	var t containerd.Task
 	closer := func() {
@@ -144,7 +145,7 @@ func NewTask(ctx context.Context, client *containerd.Client, container container
 		}
 		ioCreator = cioutil.NewContainerIO(namespace, logURI, false, in, os.Stdout, os.Stderr)
 	}
-	t, err := container.NewTask(ctx, ioCreator)
+	t, err := container.NewTask(ctx, ioCreator, taskOpts...)
 	if err != nil {
 		return nil, err
 	}